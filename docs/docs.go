@@ -15,19 +15,36 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/application": {
-            "post": {
-                "description": "Only CPSK user can access this endpoint",
-                "consumes": [
+        "/.well-known/jwks.json": {
+            "get": {
+                "description": "Keys are identified by kid header of the token. HS256 tokens can't be verified with this endpoint",
+                "produces": [
                     "application/json"
                 ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "JSON Web Key Set of access token signing keys",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/auth.jwksResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/appeals": {
+            "get": {
+                "description": "Only user with user:punish permission can access this endpoint",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Application"
+                    "Admin"
                 ],
-                "summary": "Create job application",
+                "summary": "Get punishment appeals",
                 "parameters": [
                     {
                         "type": "string",
@@ -38,24 +55,36 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Application information",
-                        "name": "application",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/model.Application"
-                        }
+                        "type": "string",
+                        "default": "pending",
+                        "description": "Only pending, accepted or rejected",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number, start from 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Entries per page, max 100",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Successfully apply job post",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.CPSKUser"
+                            "$ref": "#/definitions/appeal.appealListResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid authorization header, request body",
+                        "description": "Invalid query",
                         "schema": {
                             "$ref": "#/definitions/utilities.ErrorResponse"
                         }
@@ -67,7 +96,7 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "Not logged in as CPSK, User is banned or suspended",
+                        "description": "Do not have permission",
                         "schema": {
                             "$ref": "#/definitions/utilities.ErrorResponse"
                         }
@@ -81,36 +110,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/auth/google/callback": {
-            "get": {
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Auth"
-                ],
-                "summary": "Retrieves a query parameter named \"code\" from the request and returns it in a JSON response",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Authentication code from google",
-                        "name": "Code",
-                        "in": "query"
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/auth.code"
-                        }
-                    }
-                }
-            }
-        },
-        "/auth/google/company": {
-            "post": {
-                "description": "Checks and creates user in the database, generates an access token",
+        "/admin/appeals/{id}": {
+            "patch": {
+                "description": "Only user with user:punish permission can access this endpoint\nstatus must be 'accepted' or 'rejected', admin_note is shown to the user",
                 "consumes": [
                     "application/json"
                 ],
@@ -118,35 +120,68 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Auth"
+                    "Admin"
                 ],
-                "summary": "Handles Google login authentication for company role, exchanges code for user",
+                "summary": "Review punishment appeal",
                 "parameters": [
                     {
-                        "description": "Authentication code from google",
-                        "name": "Code",
+                        "type": "string",
+                        "default": "Bearer \u003cyour access token\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID of the appeal",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Decision and note to the user",
+                        "name": "Decision",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/auth.code"
+                            "$ref": "#/definitions/appeal.reviewAppealRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Login success",
+                        "description": "Reviewed appeal",
                         "schema": {
-                            "$ref": "#/definitions/model.CompanyResponse"
+                            "$ref": "#/definitions/model.PunishmentAppeal"
                         }
                     },
-                    "201": {
-                        "description": "Register success",
+                    "400": {
+                        "description": "Invalid request body",
                         "schema": {
-                            "$ref": "#/definitions/model.CompanyResponse"
+                            "$ref": "#/definitions/utilities.ErrorResponse"
                         }
                     },
-                    "400": {
-                        "description": "Fail to receive token or fetch user info",
+                    "401": {
+                        "description": "Invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Do not have permission",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Appeal not found",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Appeal has already been reviewed",
                         "schema": {
                             "$ref": "#/definitions/utilities.ErrorResponse"
                         }
@@ -160,45 +195,103 @@ const docTemplate = `{
                 }
             }
         },
-        "/auth/google/cpsk": {
-            "post": {
-                "description": "Checks and creates user in the database, generates an access token",
-                "consumes": [
-                    "application/json"
-                ],
+        "/admin/audit": {
+            "get": {
+                "description": "Only user with audit:view permission can access this endpoint\nfrom and to must be in 'YYYY-MM-DDTHH:mm:ssZ' format",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Auth"
+                    "Admin"
                 ],
-                "summary": "Handles Google login authentication for cpsk role, exchanges code for user",
+                "summary": "Get audit log",
                 "parameters": [
                     {
-                        "description": "Authentication code from google",
-                        "name": "Code",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/auth.code"
-                        }
+                        "type": "string",
+                        "default": "Bearer \u003cyour access token\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID of user who took the action",
+                        "name": "actor_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Action, e.g. user.punish, company.verify, report.update_status, job_post.edit",
+                        "name": "action",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Type of the target, e.g. user, company, report, job_post",
+                        "name": "target_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID of the target, report ID is '\u003ctype\u003e/\u003cid\u003e'",
+                        "name": "target_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID of the request",
+                        "name": "request_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only actions at or after this time",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only actions before this time",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number, start from 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Entries per page, max 100",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Login success",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.CPSKResponse"
+                            "$ref": "#/definitions/admin.auditLogListResponse"
                         }
                     },
-                    "201": {
-                        "description": "Register success",
+                    "400": {
+                        "description": "Invalid query",
                         "schema": {
-                            "$ref": "#/definitions/model.CPSKResponse"
+                            "$ref": "#/definitions/utilities.ErrorResponse"
                         }
                     },
-                    "400": {
-                        "description": "Fail to receive token or fetch user info",
+                    "401": {
+                        "description": "Invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Do not have permission",
                         "schema": {
                             "$ref": "#/definitions/utilities.ErrorResponse"
                         }
@@ -212,45 +305,37 @@ const docTemplate = `{
                 }
             }
         },
-        "/auth/google/visitor": {
-            "post": {
-                "description": "Checks and creates user in the database, generates an access token",
-                "consumes": [
-                    "application/json"
-                ],
+        "/appeal": {
+            "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Auth"
+                    "Appeal"
                 ],
-                "summary": "Handles Google login authentication for visitor role, exchanges code for user",
+                "summary": "Get my appeals",
                 "parameters": [
                     {
-                        "description": "Authentication code from google",
-                        "name": "Code",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/auth.code"
-                        }
+                        "type": "string",
+                        "default": "Bearer \u003cyour access token\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Login success",
-                        "schema": {
-                            "$ref": "#/definitions/model.VisitorResponse"
-                        }
-                    },
-                    "201": {
-                        "description": "Register success",
+                        "description": "Appeals of current user",
                         "schema": {
-                            "$ref": "#/definitions/model.VisitorResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.PunishmentAppeal"
+                            }
                         }
                     },
-                    "400": {
-                        "description": "Fail to receive token or fetch user info",
+                    "401": {
+                        "description": "Invalid token",
                         "schema": {
                             "$ref": "#/definitions/utilities.ErrorResponse"
                         }
@@ -262,11 +347,9 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/auth/login": {
+            },
             "post": {
-                "description": "Username must exist and password match",
+                "description": "Each punishment can be appealed once while it is active.\nIf punishment_id is omitted, current active punishment of the user is appealed.",
                 "consumes": [
                     "application/json"
                 ],
@@ -274,87 +357,61 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Auth"
+                    "Appeal"
                 ],
-                "summary": "Handles local login by receiving username and password",
+                "summary": "Appeal punishment",
                 "parameters": [
                     {
-                        "description": "Credentials for login",
-                        "name": "Info",
+                        "type": "string",
+                        "default": "Bearer \u003cyour access token\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Punishment to appeal and message to admin",
+                        "name": "Appeal",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/auth.loginInfo"
+                            "$ref": "#/definitions/appeal.submitAppealRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "If role is cpsk",
+                    "201": {
+                        "description": "Submitted appeal",
                         "schema": {
-                            "$ref": "#/definitions/model.CPSKResponse"
+                            "$ref": "#/definitions/model.PunishmentAppeal"
                         }
                     },
                     "400": {
-                        "description": "Info provided not met the condition",
+                        "description": "Invalid request body, punishment is not active",
                         "schema": {
                             "$ref": "#/definitions/utilities.ErrorResponse"
                         }
                     },
                     "401": {
-                        "description": "Username not exist or password incorrect",
+                        "description": "Invalid token",
                         "schema": {
                             "$ref": "#/definitions/utilities.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Database or password hashing error",
+                    "404": {
+                        "description": "Punishment not found",
                         "schema": {
                             "$ref": "#/definitions/utilities.ErrorResponse"
                         }
-                    }
-                }
-            }
-        },
-        "/auth/register": {
-            "post": {
-                "description": "Username must not already exist and password must longer or equal to 8 characters long",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Auth"
-                ],
-                "summary": "Handles local registration by receiving username and password",
-                "parameters": [
-                    {
-                        "description": "role can be only 'cpsk' or 'company'",
-                        "name": "Info",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/auth.registerInfo"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "If role is visitor",
-                        "schema": {
-                            "$ref": "#/definitions/model.VisitorResponse"
-                        }
                     },
-                    "400": {
-                        "description": "Info provided not met the condition",
+                    "409": {
+                        "description": "Punishment has already been appealed",
                         "schema": {
                             "$ref": "#/definitions/utilities.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Database or password hashing error",
+                        "description": "Database error",
                         "schema": {
                             "$ref": "#/definitions/utilities.ErrorResponse"
                         }
@@ -362,16 +419,19 @@ const docTemplate = `{
                 }
             }
         },
-        "/company/ai-verify": {
+        "/application": {
             "post": {
-                "description": "Company can request AI verification of their own profile. AI analyzes company data and makes verification decision",
+                "description": "Only CPSK user can access this endpoint\nAnswers to custom form of the post are required for its required questions, file answer is ID of file\nuploaded at POST /application/files",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Company"
+                    "Application"
                 ],
-                "summary": "Use AI to verify your company",
+                "summary": "Create job application",
                 "parameters": [
                     {
                         "type": "string",
@@ -380,17 +440,26 @@ const docTemplate = `{
                         "name": "Authorization",
                         "in": "header",
                         "required": true
+                    },
+                    {
+                        "description": "Application information",
+                        "name": "application",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.Application"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Successfully apply job post",
                         "schema": {
-                            "$ref": "#/definitions/verification.aiVerificationResponse"
+                            "$ref": "#/definitions/model.CPSKUser"
                         }
                     },
                     "400": {
-                        "description": "Invalid authorization header",
+                        "description": "Invalid authorization header, request body, or answers not matching the form",
                         "schema": {
                             "$ref": "#/definitions/utilities.ErrorResponse"
                         }
@@ -402,19 +471,13 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "Not logged in as company",
-                        "schema": {
-                            "$ref": "#/definitions/utilities.ErrorResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "Company profile not found",
+                        "description": "Not logged in as CPSK, User is banned or suspended",
                         "schema": {
                             "$ref": "#/definitions/utilities.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Database error or AI service error",
+                        "description": "Database error",
                         "schema": {
                             "$ref": "#/definitions/utilities.ErrorResponse"
                         }
@@ -422,15 +485,19 @@ const docTemplate = `{
                 }
             }
         },
-        "/company/myprofile": {
-            "get": {
+        "/application/files": {
+            "post": {
+                "description": "Only file that smaller than 10 MB with .pdf, .doc, .docx, .jpg, .jpeg, or .png extension is permitted\nReturned file ID is given as answer to file question when applying, only the uploader can use it.\nThe file can be downloaded only by the uploader and the company the application is submitted to",
+                "consumes": [
+                    "multipart/form-data"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Company"
+                    "Application"
                 ],
-                "summary": "Retrieve company profile from database",
+                "summary": "Upload file to answer application form",
                 "parameters": [
                     {
                         "type": "string",
@@ -439,13 +506,20 @@ const docTemplate = `{
                         "name": "Authorization",
                         "in": "header",
                         "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Upload your answer file",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Successfully retrieve company profile",
+                    "201": {
+                        "description": "Successfully upload file",
                         "schema": {
-                            "$ref": "#/definitions/model.CompanyUser"
+                            "$ref": "#/definitions/file.answerFileResponse"
                         }
                     },
                     "400": {
@@ -461,7 +535,19 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "Not logged in as company, User is banned",
+                        "description": "Not logged in as CPSK, User is banned or suspended",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "File size is larger than 10 MB",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "415": {
+                        "description": "File extension is not allowed",
                         "schema": {
                             "$ref": "#/definitions/utilities.ErrorResponse"
                         }
@@ -475,9 +561,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/company/profile": {
+        "/application/{id}/status": {
             "patch": {
-                "description": "Overwrite company profile and save into database\nSensitive field like id, file, verified status, and job post can't be overwritten",
+                "description": "Only owner or recruiter of company organization that own the job post have access to this endpoint\nAllowed transitions:\npending -\u003e in consideration, interview, rejected\nin consideration -\u003e interview, offer, rejected\ninterview -\u003e in consideration, offer, rejected\noffer -\u003e hired, rejected\nhired and rejected are final, withdrawn can only be set by the applicant",
                 "consumes": [
                     "application/json"
                 ],
@@ -485,9 +571,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Company"
+                    "Application"
                 ],
-                "summary": "Edit company profile",
+                "summary": "Update status of an application",
                 "parameters": [
                     {
                         "type": "string",
@@ -498,24 +584,31 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Company info to be written",
-                        "name": "company_profile",
+                        "type": "integer",
+                        "description": "ID of desired application",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New status and optional note",
+                        "name": "Status",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/company.editCompanyUser"
+                            "$ref": "#/definitions/application.statusUpdateRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successfully overwrite",
+                        "description": "Successfully update application status",
                         "schema": {
-                            "$ref": "#/definitions/model.CompanyUser"
+                            "$ref": "#/definitions/model.Application"
                         }
                     },
                     "400": {
-                        "description": "Invalid authorization header or request body",
+                        "description": "Invalid authorization header, request body, or illegal status transition",
                         "schema": {
                             "$ref": "#/definitions/utilities.ErrorResponse"
                         }
@@ -527,7 +620,19 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "Not logged in as company, User is banned",
+                        "description": "Not the owner of the job post, User is banned or suspended",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Application not found",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Application status was changed by another request",
                         "schema": {
                             "$ref": "#/definitions/utilities.ErrorResponse"
                         }
@@ -541,19 +646,19 @@ const docTemplate = `{
                 }
             }
         },
-        "/company/profile/banner": {
+        "/auth/2fa/confirm": {
             "post": {
-                "description": "Only file that smaller than 10 MB with .jpg, .jpeg, or .png extension is permitted",
+                "description": "Recovery codes are shown only once, each can be used once instead of TOTP code",
                 "consumes": [
-                    "multipart/form-data"
+                    "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Company"
+                    "Auth"
                 ],
-                "summary": "Upload banner file for company",
+                "summary": "Confirm TOTP enrollment",
                 "parameters": [
                     {
                         "type": "string",
@@ -564,46 +669,42 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "type": "file",
-                        "description": "Upload your banner file",
-                        "name": "banner",
-                        "in": "formData",
-                        "required": true
+                        "description": "Current TOTP code",
+                        "name": "Code",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/auth.twoFactorCodeRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successfully upload banner",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.CompanyUser"
+                            "$ref": "#/definitions/auth.recoveryCodesResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid authorization header",
+                        "description": "Code is not provided or incorrect",
                         "schema": {
                             "$ref": "#/definitions/utilities.ErrorResponse"
                         }
                     },
                     "401": {
-                        "description": "Invalid token",
-                        "schema": {
-                            "$ref": "#/definitions/utilities.ErrorResponse"
-                        }
-                    },
-                    "403": {
-                        "description": "Not logged in as company, User is banned",
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/utilities.ErrorResponse"
                         }
                     },
-                    "413": {
-                        "description": "File size is larger than 10 MB",
+                    "404": {
+                        "description": "No pending enrollment",
                         "schema": {
                             "$ref": "#/definitions/utilities.ErrorResponse"
                         }
                     },
-                    "415": {
-                        "description": "File extension is not allowed",
+                    "409": {
+                        "description": "Two-factor authentication is already enabled",
                         "schema": {
                             "$ref": "#/definitions/utilities.ErrorResponse"
                         }
@@ -617,19 +718,19 @@ const docTemplate = `{
                 }
             }
         },
-        "/company/profile/logo": {
+        "/auth/2fa/disable": {
             "post": {
-                "description": "Only file that smaller than 10 MB with .jpg, .jpeg, or .png extension is permitted",
+                "description": "Admin can't disable two-factor authentication when it is mandatory",
                 "consumes": [
-                    "multipart/form-data"
+                    "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Company"
+                    "Auth"
                 ],
-                "summary": "Upload logo file for company",
+                "summary": "Disable two-factor authentication",
                 "parameters": [
                     {
                         "type": "string",
@@ -640,46 +741,45 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "type": "file",
-                        "description": "Upload your logo file",
-                        "name": "logo",
-                        "in": "formData",
-                        "required": true
+                        "description": "Current TOTP code or recovery code",
+                        "name": "Code",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/auth.twoFactorCodeRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successfully upload logo",
+                        "description": "Two-factor authentication disabled",
                         "schema": {
-                            "$ref": "#/definitions/model.CompanyUser"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "400": {
-                        "description": "Invalid authorization header",
+                        "description": "Code is not provided or incorrect",
                         "schema": {
                             "$ref": "#/definitions/utilities.ErrorResponse"
                         }
                     },
                     "401": {
-                        "description": "Invalid token",
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/utilities.ErrorResponse"
                         }
                     },
                     "403": {
-                        "description": "Not logged in as company, User is banned",
-                        "schema": {
-                            "$ref": "#/definitions/utilities.ErrorResponse"
-                        }
-                    },
-                    "413": {
-                        "description": "File size is larger than 10 MB",
+                        "description": "Two-factor authentication is mandatory for admin",
                         "schema": {
                             "$ref": "#/definitions/utilities.ErrorResponse"
                         }
                     },
-                    "415": {
-                        "description": "File extension is not allowed",
+                    "404": {
+                        "description": "Two-factor authentication is not enabled",
                         "schema": {
                             "$ref": "#/definitions/utilities.ErrorResponse"
                         }
@@ -693,15 +793,16 @@ const docTemplate = `{
                 }
             }
         },
-        "/company/profile/{company_id}": {
-            "get": {
+        "/auth/2fa/enroll": {
+            "post": {
+                "description": "Returns secret and otpauth provisioning URI to be shown as QR code, confirm with /auth/2fa/confirm",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Company"
+                    "Auth"
                 ],
-                "summary": "Retrieve company profile from database by given ID",
+                "summary": "Start TOTP enrollment",
                 "parameters": [
                     {
                         "type": "string",
@@ -710,42 +811,23 @@ const docTemplate = `{
                         "name": "Authorization",
                         "in": "header",
                         "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "ID of company",
-                        "name": "company_id",
-                        "in": "path",
-                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successfully retrieve company profile",
-                        "schema": {
-                            "$ref": "#/definitions/model.CompanyUser"
-                        }
-                    },
-                    "400": {
-                        "description": "Invalid authorization header",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/utilities.ErrorResponse"
+                            "$ref": "#/definitions/auth.totpEnrollResponse"
                         }
                     },
                     "401": {
-                        "description": "Invalid token",
-                        "schema": {
-                            "$ref": "#/definitions/utilities.ErrorResponse"
-                        }
-                    },
-                    "403": {
-                        "description": "User is banned",
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/utilities.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Company not exist",
+                    "409": {
+                        "description": "Two-factor authentication is already enabled",
                         "schema": {
                             "$ref": "#/definitions/utilities.ErrorResponse"
                         }
@@ -759,59 +841,51 @@ const docTemplate = `{
                 }
             }
         },
-        "/company/{company_id}": {
-            "get": {
+        "/auth/2fa/verify": {
+            "post": {
+                "description": "Challenge token is returned from /auth/login when user has two-factor authentication enabled\nRecovery code can be used instead of TOTP code once two-factor authentication is enrolled",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Company"
+                    "Auth"
                 ],
-                "summary": "Retrieve company profile from database by given ID",
+                "summary": "Finish login with second factor",
                 "parameters": [
                     {
-                        "type": "string",
-                        "default": "Bearer \u003cyour access token\u003e",
-                        "description": "Insert your access token",
-                        "name": "Authorization",
-                        "in": "header",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "ID of company",
-                        "name": "company_id",
-                        "in": "path",
-                        "required": true
+                        "description": "Challenge token and code",
+                        "name": "Info",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/auth.twoFactorVerifyRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successfully retrieve company profile",
+                        "description": "If role is cpsk",
                         "schema": {
-                            "$ref": "#/definitions/model.CompanyUser"
+                            "$ref": "#/definitions/model.CPSKResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid authorization header",
+                        "description": "Challenge token or code is not provided",
                         "schema": {
                             "$ref": "#/definitions/utilities.ErrorResponse"
                         }
                     },
                     "401": {
-                        "description": "Invalid token",
-                        "schema": {
-                            "$ref": "#/definitions/utilities.ErrorResponse"
-                        }
-                    },
-                    "403": {
-                        "description": "User is banned",
+                        "description": "Challenge is invalid or expired, or code is incorrect",
                         "schema": {
                             "$ref": "#/definitions/utilities.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Company not exist",
+                    "429": {
+                        "description": "Too many failed attempts",
                         "schema": {
                             "$ref": "#/definitions/utilities.ErrorResponse"
                         }
@@ -825,15 +899,19 @@ const docTemplate = `{
                 }
             }
         },
-        "/cpsk/myprofile": {
-            "get": {
+        "/auth/email": {
+            "post": {
+                "description": "Email is assigned to the user after the link sent to it is used with /auth/email/verify",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "CPSK"
+                    "Auth"
                 ],
-                "summary": "Retrieve CPSK profile from database",
+                "summary": "Add or change email of current user",
                 "parameters": [
                     {
                         "type": "string",
@@ -842,35 +920,44 @@ const docTemplate = `{
                         "name": "Authorization",
                         "in": "header",
                         "required": true
+                    },
+                    {
+                        "description": "Email to verify",
+                        "name": "Email",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/auth.emailRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Successfully retrieve CPSK profile",
+                    "202": {
+                        "description": "Verification link sent",
                         "schema": {
-                            "$ref": "#/definitions/model.CPSKUser"
+                            "$ref": "#/definitions/utilities.MessageResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid authorization header",
+                        "description": "Email is not provided or invalid",
                         "schema": {
                             "$ref": "#/definitions/utilities.ErrorResponse"
                         }
                     },
                     "401": {
-                        "description": "Invalid token",
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/utilities.ErrorResponse"
                         }
                     },
-                    "403": {
-                        "description": "Not logged in as CPSK, User is banned",
+                    "409": {
+                        "description": "Email is already used by another account",
                         "schema": {
                             "$ref": "#/definitions/utilities.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Database error",
+                        "description": "Database or mail error",
                         "schema": {
                             "$ref": "#/definitions/utilities.ErrorResponse"
                         }
@@ -878,9 +965,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/cpsk/profile": {
-            "patch": {
-                "description": "Overwrite CPSK profile and save into database\nSensitive field like id, file, and application can't be overwritten",
+        "/auth/email/verify": {
+            "post": {
+                "description": "Verification token can be used once",
                 "consumes": [
                     "application/json"
                 ],
@@ -888,49 +975,35 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "CPSK"
+                    "Auth"
                 ],
-                "summary": "Edit CPSK profile",
+                "summary": "Verify email",
                 "parameters": [
                     {
-                        "type": "string",
-                        "default": "Bearer \u003cyour access token\u003e",
-                        "description": "Insert your access token",
-                        "name": "Authorization",
-                        "in": "header",
-                        "required": true
-                    },
-                    {
-                        "description": "CPSK info to be written",
-                        "name": "cpsk_profile",
+                        "description": "Token from verification link",
+                        "name": "Token",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/cpsk.editCPSKUser"
+                            "$ref": "#/definitions/auth.verifyEmailRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successfully overwrite",
+                        "description": "Email verified",
                         "schema": {
-                            "$ref": "#/definitions/model.CPSKUser"
+                            "$ref": "#/definitions/utilities.MessageResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid authorization header or request body",
-                        "schema": {
-                            "$ref": "#/definitions/utilities.ErrorResponse"
-                        }
-                    },
-                    "401": {
-                        "description": "Invalid token",
+                        "description": "Token is not provided, invalid or expired",
                         "schema": {
                             "$ref": "#/definitions/utilities.ErrorResponse"
                         }
                     },
-                    "403": {
-                        "description": "Not logged in as CPSK, User is banned",
+                    "409": {
+                        "description": "Email is already used by another account",
                         "schema": {
                             "$ref": "#/definitions/utilities.ErrorResponse"
                         }
@@ -944,69 +1017,90 @@ const docTemplate = `{
                 }
             }
         },
-        "/cpsk/profile/resume": {
-            "post": {
-                "description": "Only file that smaller than 10 MB with .pdf extension is permitted",
-                "consumes": [
-                    "multipart/form-data"
-                ],
+        "/auth/google/callback": {
+            "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "CPSK"
+                    "Auth"
                 ],
-                "summary": "Upload resume file for CPSK",
+                "summary": "Retrieves query parameters named \"code\" and \"state\" from the request and returns them in a JSON response",
                 "parameters": [
                     {
                         "type": "string",
-                        "default": "Bearer \u003cyour access token\u003e",
-                        "description": "Insert your access token",
-                        "name": "Authorization",
-                        "in": "header",
-                        "required": true
+                        "description": "Authentication code from google",
+                        "name": "Code",
+                        "in": "query"
                     },
                     {
-                        "type": "file",
-                        "description": "Upload your resume file",
-                        "name": "resume",
-                        "in": "formData",
-                        "required": true
+                        "type": "string",
+                        "description": "State from /auth/google/start",
+                        "name": "State",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successfully upload resume",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.CPSKUser"
+                            "$ref": "#/definitions/auth.googleCallbackResponse"
                         }
-                    },
-                    "400": {
-                        "description": "Invalid authorization header",
+                    }
+                }
+            }
+        },
+        "/auth/google/company": {
+            "post": {
+                "description": "Checks and creates user in the database, generates an access token",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Handles Google login authentication for company role, exchanges code for user",
+                "parameters": [
+                    {
+                        "description": "Authentication code from google with state and code verifier from /auth/google/start",
+                        "name": "Code",
+                        "in": "body",
+                        "required": true,
                         "schema": {
-                            "$ref": "#/definitions/utilities.ErrorResponse"
+                            "$ref": "#/definitions/auth.oauthCode"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Login success",
+                        "schema": {
+                            "$ref": "#/definitions/model.CompanyResponse"
                         }
                     },
-                    "401": {
-                        "description": "Invalid token",
+                    "201": {
+                        "description": "Register success",
                         "schema": {
-                            "$ref": "#/definitions/utilities.ErrorResponse"
+                            "$ref": "#/definitions/model.CompanyResponse"
                         }
                     },
-                    "403": {
-                        "description": "Not logged in as CPSK, User is banned",
+                    "202": {
+                        "description": "User has two-factor authentication, finish login with /auth/2fa/verify",
                         "schema": {
-                            "$ref": "#/definitions/utilities.ErrorResponse"
+                            "$ref": "#/definitions/auth.twoFactorChallengeResponse"
                         }
                     },
-                    "413": {
-                        "description": "File size is larger than 10 MB",
+                    "400": {
+                        "description": "Invalid state, fail to receive token or fetch user info",
                         "schema": {
                             "$ref": "#/definitions/utilities.ErrorResponse"
                         }
                     },
-                    "415": {
-                        "description": "File extension is not allowed",
+                    "403": {
+                        "description": "Email domain is not allowed for the role and downgrade is disabled",
                         "schema": {
                             "$ref": "#/definitions/utilities.ErrorResponse"
                         }
@@ -1020,65 +1114,63 @@ const docTemplate = `{
                 }
             }
         },
-        "/file/{id}": {
-            "get": {
+        "/auth/google/cpsk": {
+            "post": {
+                "description": "Checks and creates user in the database, generates an access token\nAccount outside CPSK_ALLOWED_EMAIL_DOMAINS is rejected or signed in as visitor depending on EMAIL_DOMAIN_MISMATCH",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
-                    "application/octet-stream"
+                    "application/json"
                 ],
                 "tags": [
-                    "File"
+                    "Auth"
                 ],
-                "summary": "Retrieve dowloadable attachment",
+                "summary": "Handles Google login authentication for cpsk role, exchanges code for user",
                 "parameters": [
                     {
-                        "type": "string",
-                        "default": "Bearer \u003cyour access token\u003e",
-                        "description": "Insert your access token",
-                        "name": "Authorization",
-                        "in": "header",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "ID of wanted file",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Authentication code from google with state and code verifier from /auth/google/start",
+                        "name": "Code",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/auth.oauthCode"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successfully retrieve file",
+                        "description": "Login success",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/model.CPSKResponse"
                         }
                     },
-                    "400": {
-                        "description": "Invalid authorization header",
+                    "201": {
+                        "description": "Register success",
                         "schema": {
-                            "$ref": "#/definitions/utilities.ErrorResponse"
+                            "$ref": "#/definitions/model.CPSKResponse"
                         }
                     },
-                    "401": {
-                        "description": "Invalid token",
+                    "202": {
+                        "description": "User has two-factor authentication, finish login with /auth/2fa/verify",
                         "schema": {
-                            "$ref": "#/definitions/utilities.ErrorResponse"
+                            "$ref": "#/definitions/auth.twoFactorChallengeResponse"
                         }
                     },
-                    "403": {
-                        "description": "User is banned",
+                    "400": {
+                        "description": "Invalid state, fail to receive token or fetch user info",
                         "schema": {
                             "$ref": "#/definitions/utilities.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Given file id not found",
+                    "403": {
+                        "description": "Email domain is not allowed for the role and downgrade is disabled",
                         "schema": {
                             "$ref": "#/definitions/utilities.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Fail to send file content",
+                        "description": "Database error",
                         "schema": {
                             "$ref": "#/definitions/utilities.ErrorResponse"
                         }
@@ -1086,70 +1178,46 @@ const docTemplate = `{
                 }
             }
         },
-        "/get-companies": {
+        "/auth/google/start": {
             "get": {
-                "description": "Only admin can access this endpoints\nIf no query given, the server will return all companies",
+                "description": "State expires in 10 minutes and only work with the endpoint of the purpose\nState is bound to the browser by HttpOnly cookie, which must be sent with the login or link request",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Admin"
+                    "Auth"
                 ],
-                "summary": "Get companies based on given query",
+                "summary": "Start Google sign in",
                 "parameters": [
                     {
+                        "enum": [
+                            "cpsk",
+                            "company",
+                            "visitor",
+                            "link"
+                        ],
                         "type": "string",
-                        "default": "Bearer \u003cyour access token\u003e",
-                        "description": "Insert your access token",
-                        "name": "Authorization",
-                        "in": "header",
+                        "description": "Role to sign in as, or link to link Google account",
+                        "name": "purpose",
+                        "in": "query",
                         "required": true
-                    },
-                    {
-                        "type": "string",
-                        "example": "pending unverified",
-                        "description": "Only pending, unverified, or verified with case insensitive",
-                        "name": "verify",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "example": "ban suspend",
-                        "description": "Only ban, or suspend with case insensitive",
-                        "name": "punishment",
-                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/model.CompanyUser"
-                            }
+                            "$ref": "#/definitions/auth.oauthStartResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid authorization header",
-                        "schema": {
-                            "$ref": "#/definitions/utilities.ErrorResponse"
-                        }
-                    },
-                    "401": {
-                        "description": "Invalid token",
-                        "schema": {
-                            "$ref": "#/definitions/utilities.ErrorResponse"
-                        }
-                    },
-                    "403": {
-                        "description": "Do not logged in as admin",
+                        "description": "Invalid purpose",
                         "schema": {
                             "$ref": "#/definitions/utilities.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Database error",
+                        "description": "Failed to issue state",
                         "schema": {
                             "$ref": "#/definitions/utilities.ErrorResponse"
                         }
@@ -1157,57 +1225,51 @@ const docTemplate = `{
                 }
             }
         },
-        "/get-cpsk": {
-            "get": {
-                "description": "Only admin can access this endpoints\nIf no query given, the server will return all CPSK",
+        "/auth/google/visitor": {
+            "post": {
+                "description": "Checks and creates user in the database, generates an access token",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Admin"
+                    "Auth"
                 ],
-                "summary": "Get CPSK based on given query",
+                "summary": "Handles Google login authentication for visitor role, exchanges code for user",
                 "parameters": [
                     {
-                        "type": "string",
-                        "default": "Bearer \u003cyour access token\u003e",
-                        "description": "Insert your access token",
-                        "name": "Authorization",
-                        "in": "header",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "example": "ban suspend",
-                        "description": "Only ban, or suspend with case insensitive",
-                        "name": "punishment",
-                        "in": "query"
+                        "description": "Authentication code from google with state and code verifier from /auth/google/start",
+                        "name": "Code",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/auth.oauthCode"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Login success",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/model.CPSKUser"
-                            }
+                            "$ref": "#/definitions/model.VisitorResponse"
                         }
                     },
-                    "400": {
-                        "description": "Invalid authorization header",
+                    "201": {
+                        "description": "Register success",
                         "schema": {
-                            "$ref": "#/definitions/utilities.ErrorResponse"
+                            "$ref": "#/definitions/model.VisitorResponse"
                         }
                     },
-                    "401": {
-                        "description": "Invalid token",
+                    "202": {
+                        "description": "User has two-factor authentication, finish login with /auth/2fa/verify",
                         "schema": {
-                            "$ref": "#/definitions/utilities.ErrorResponse"
+                            "$ref": "#/definitions/auth.twoFactorChallengeResponse"
                         }
                     },
-                    "403": {
-                        "description": "Do not logged in as admin",
+                    "400": {
+                        "description": "Invalid state, fail to receive token or fetch user info",
                         "schema": {
                             "$ref": "#/definitions/utilities.ErrorResponse"
                         }
@@ -1221,16 +1283,19 @@ const docTemplate = `{
                 }
             }
         },
-        "/get-visitors": {
-            "get": {
-                "description": "Only admin can access this endpoints\nIf no query given, the server will return all visitors",
+        "/auth/link/google": {
+            "post": {
+                "description": "Google account must not be linked to another user. Email of the user is set from Google if the user has no verified email",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Admin"
+                    "Auth"
                 ],
-                "summary": "Get visitors based on given query",
+                "summary": "Link Google account to current user",
                 "parameters": [
                     {
                         "type": "string",
@@ -1241,37 +1306,36 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "example": "ban suspend",
-                        "description": "Only ban, or suspend with case insensitive",
-                        "name": "punishment",
-                        "in": "query"
+                        "description": "Authentication code from google with state and code verifier from /auth/google/start?purpose=link",
+                        "name": "Code",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/auth.oauthCode"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Google account linked",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/model.VisitorUser"
-                            }
+                            "$ref": "#/definitions/utilities.MessageResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid authorization header",
+                        "description": "Invalid state, fail to receive token or fetch user info",
                         "schema": {
                             "$ref": "#/definitions/utilities.ErrorResponse"
                         }
                     },
                     "401": {
-                        "description": "Invalid token",
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/utilities.ErrorResponse"
                         }
                     },
-                    "403": {
-                        "description": "Do not logged in as admin",
+                    "409": {
+                        "description": "User already has Google account or Google account is linked to another user",
                         "schema": {
                             "$ref": "#/definitions/utilities.ErrorResponse"
                         }
@@ -1283,18 +1347,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/jobpost": {
-            "get": {
-                "description": "Every query are not required, but they have specific use defined in their description",
+            },
+            "delete": {
+                "description": "User must have password set so they can still log in",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Jobpost"
+                    "Auth"
                 ],
-                "summary": "Get non-expired job posts based on query",
+                "summary": "Unlink Google account from current user",
                 "parameters": [
                     {
                         "type": "string",
@@ -1303,86 +1365,29 @@ const docTemplate = `{
                         "name": "Authorization",
                         "in": "header",
                         "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Search from job post title with substring matching and case insensitive",
-                        "name": "search",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Job type field with substring matching and case insensitive",
-                        "name": "type",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Search if tags field contain tag param, no substring matching and case insensitive",
-                        "name": "tag",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Salary field, must exactly match to get result",
-                        "name": "salary",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Exp_lvl field, must exactly match to get result",
-                        "name": "exp",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Search from company name with substring matching and case insensitive",
-                        "name": "company",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Search from industry of company with substring matching and case insensitive",
-                        "name": "industry",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Search from location with substring matching and case insensitive",
-                        "name": "location",
-                        "in": "query"
-                    },
-                    {
-                        "type": "boolean",
-                        "description": "Sorting by post time in descending if true, otherwise ascendind",
-                        "name": "desc",
-                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Return non-expired job post(s)",
+                        "description": "Google account unlinked",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/model.JobPostResponse"
-                            }
+                            "$ref": "#/definitions/utilities.MessageResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid authorization header, or invalid job post struct",
+                        "description": "User has no password",
                         "schema": {
                             "$ref": "#/definitions/utilities.ErrorResponse"
                         }
                     },
                     "401": {
-                        "description": "Invalid token",
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/utilities.ErrorResponse"
                         }
                     },
-                    "403": {
-                        "description": "User is banned",
+                    "404": {
+                        "description": "No Google account linked",
                         "schema": {
                             "$ref": "#/definitions/utilities.ErrorResponse"
                         }
@@ -1394,9 +1399,11 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/auth/login": {
             "post": {
-                "description": "Only verified company have access to this endpoint",
+                "description": "Username (or verified email) must exist and password match",
                 "consumes": [
                     "application/json"
                 ],
@@ -1404,55 +1411,53 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Jobpost"
+                    "Auth"
                 ],
-                "summary": "Create job post based on given json structure",
+                "summary": "Handles local login by receiving username and password",
                 "parameters": [
                     {
-                        "type": "string",
-                        "default": "Bearer \u003cyour access token\u003e",
-                        "description": "Insert your access token",
-                        "name": "Authorization",
-                        "in": "header",
-                        "required": true
-                    },
-                    {
-                        "description": "Input jobpost information",
-                        "name": "Jobpost",
+                        "description": "Credentials for login",
+                        "name": "Info",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/jobpost.jobPostCreateRequest"
+                            "$ref": "#/definitions/auth.loginInfo"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Successfully create job post",
+                    "200": {
+                        "description": "If role is cpsk",
                         "schema": {
-                            "$ref": "#/definitions/model.JobPost"
+                            "$ref": "#/definitions/model.CPSKResponse"
+                        }
+                    },
+                    "202": {
+                        "description": "Password accepted, finish login with /auth/2fa/verify",
+                        "schema": {
+                            "$ref": "#/definitions/auth.twoFactorChallengeResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid authorization header, or invalid job post struct",
+                        "description": "Info provided not met the condition",
                         "schema": {
                             "$ref": "#/definitions/utilities.ErrorResponse"
                         }
                     },
                     "401": {
-                        "description": "Invalid token",
+                        "description": "Username not exist or password incorrect",
                         "schema": {
                             "$ref": "#/definitions/utilities.ErrorResponse"
                         }
                     },
-                    "403": {
-                        "description": "Not logged in as verified company, User is banned or suspended",
+                    "429": {
+                        "description": "Too many failed attempts, username or IP is locked out",
                         "schema": {
                             "$ref": "#/definitions/utilities.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Database error",
+                        "description": "Database or password hashing error",
                         "schema": {
                             "$ref": "#/definitions/utilities.ErrorResponse"
                         }
@@ -1460,16 +1465,16 @@ const docTemplate = `{
                 }
             }
         },
-        "/jobpost/{id}": {
-            "get": {
-                "description": "Retrieve a specific job post using its unique ID",
+        "/auth/logout/all": {
+            "post": {
+                "description": "Reject every access token issued before now and revoke every refresh token of the user",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Jobpost"
+                    "Auth"
                 ],
-                "summary": "Get job post by ID",
+                "summary": "Log out from every session",
                 "parameters": [
                     {
                         "type": "string",
@@ -1478,42 +1483,20 @@ const docTemplate = `{
                         "name": "Authorization",
                         "in": "header",
                         "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "ID of desired job post",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Return the job post with the specified ID",
-                        "schema": {
-                            "$ref": "#/definitions/model.JobPostResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Invalid authorization header",
+                        "description": "Successfully logged out from all sessions",
                         "schema": {
-                            "$ref": "#/definitions/utilities.ErrorResponse"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "401": {
-                        "description": "Invalid token",
-                        "schema": {
-                            "$ref": "#/definitions/utilities.ErrorResponse"
-                        }
-                    },
-                    "403": {
-                        "description": "User is banned",
-                        "schema": {
-                            "$ref": "#/definitions/utilities.ErrorResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "Job post not found",
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/utilities.ErrorResponse"
                         }
@@ -1525,60 +1508,103 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Only company that own the post or admin have access to this endpoint",
+            }
+        },
+        "/auth/oidc/providers": {
+            "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Jobpost"
+                    "Auth"
                 ],
-                "summary": "Delete given job post ID",
+                "summary": "List OpenID Connect providers",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/auth.oidcProvidersResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/oidc/{provider}/{role}": {
+            "post": {
+                "description": "Checks and creates user in the database, generates an access token",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Handles sign in with OpenID Connect provider",
                 "parameters": [
                     {
                         "type": "string",
-                        "default": "Bearer \u003cyour access token\u003e",
-                        "description": "Insert your access token",
-                        "name": "Authorization",
-                        "in": "header",
+                        "description": "Provider name from /auth/oidc/providers",
+                        "name": "provider",
+                        "in": "path",
                         "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "ID of desired job post",
-                        "name": "id",
+                        "enum": [
+                            "cpsk",
+                            "company",
+                            "visitor"
+                        ],
+                        "type": "string",
+                        "description": "Role to sign in as",
+                        "name": "role",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Authorization code from the provider with state and code verifier from /auth/oidc/{provider}/{role}/start",
+                        "name": "Code",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/auth.oauthCode"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successfully delete job post",
+                        "description": "Login success, response depend on role",
                         "schema": {
-                            "$ref": "#/definitions/utilities.MessageResponse"
+                            "$ref": "#/definitions/model.CompanyResponse"
                         }
                     },
-                    "400": {
-                        "description": "Invalid authorization header, or invalid job post struct",
+                    "201": {
+                        "description": "Register success, response depend on role",
                         "schema": {
-                            "$ref": "#/definitions/utilities.ErrorResponse"
+                            "$ref": "#/definitions/model.CompanyResponse"
                         }
                     },
-                    "401": {
-                        "description": "Invalid token",
+                    "202": {
+                        "description": "User has two-factor authentication, finish login with /auth/2fa/verify",
+                        "schema": {
+                            "$ref": "#/definitions/auth.twoFactorChallengeResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid role, state, code or ID token",
                         "schema": {
                             "$ref": "#/definitions/utilities.ErrorResponse"
                         }
                     },
                     "403": {
-                        "description": "Do not have permission to delete this post, User is banned",
+                        "description": "Email domain is not allowed for the role",
                         "schema": {
                             "$ref": "#/definitions/utilities.ErrorResponse"
                         }
                     },
                     "404": {
-                        "description": "Post not found",
+                        "description": "Unknown provider",
                         "schema": {
                             "$ref": "#/definitions/utilities.ErrorResponse"
                         }
@@ -1590,78 +1616,60 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "patch": {
-                "description": "Only company that own the post or admin have access to this endpoint",
-                "consumes": [
-                    "application/json"
-                ],
+            }
+        },
+        "/auth/oidc/{provider}/{role}/start": {
+            "get": {
+                "description": "State expires in 10 minutes and only work with the login endpoint of the provider and role\nState is bound to the browser by HttpOnly cookie, which must be sent with the login request",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Jobpost"
+                    "Auth"
                 ],
-                "summary": "Edit job post based on given json structure",
+                "summary": "Start sign in with OpenID Connect provider",
                 "parameters": [
                     {
                         "type": "string",
-                        "default": "Bearer \u003cyour access token\u003e",
-                        "description": "Insert your access token",
-                        "name": "Authorization",
-                        "in": "header",
+                        "description": "Provider name from /auth/oidc/providers",
+                        "name": "provider",
+                        "in": "path",
                         "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "ID of desired job post",
-                        "name": "id",
+                        "enum": [
+                            "cpsk",
+                            "company",
+                            "visitor"
+                        ],
+                        "type": "string",
+                        "description": "Role to sign in as",
+                        "name": "role",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Input jobpost information",
-                        "name": "Jobpost",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/model.EditableJobPostInfo"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successfully update job post",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.JobPost"
+                            "$ref": "#/definitions/auth.oauthStartResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid authorization header, or invalid job post struct",
-                        "schema": {
-                            "$ref": "#/definitions/utilities.ErrorResponse"
-                        }
-                    },
-                    "401": {
-                        "description": "Invalid token",
-                        "schema": {
-                            "$ref": "#/definitions/utilities.ErrorResponse"
-                        }
-                    },
-                    "403": {
-                        "description": "Do not have permission to edit, User is banned",
+                        "description": "Invalid role",
                         "schema": {
                             "$ref": "#/definitions/utilities.ErrorResponse"
                         }
                     },
                     "404": {
-                        "description": "Post not found",
+                        "description": "Unknown provider",
                         "schema": {
                             "$ref": "#/definitions/utilities.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Database error",
+                        "description": "Failed to issue state",
                         "schema": {
                             "$ref": "#/definitions/utilities.ErrorResponse"
                         }
@@ -1669,9 +1677,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/punish/{user_id}": {
-            "put": {
-                "description": "Type of punishment (Only 'ban' or 'suspend' with case insensitive),\n'at' and 'end' fields must be in 'YYYY-MM-DDTHH:mm:ssZ' format.\nOnly 'type' is required 'at' and 'end' are optional\n'at' will be current time by default\n'end' leave empty mean permanent punishment",
+        "/auth/password/change": {
+            "post": {
+                "description": "Old password is required, every session including the current one is logged out on success",
                 "consumes": [
                     "application/json"
                 ],
@@ -1679,9 +1687,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Admin"
+                    "Auth"
                 ],
-                "summary": "Ban or suspend user",
+                "summary": "Change password",
                 "parameters": [
                     {
                         "type": "string",
@@ -1692,113 +1700,76 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "ID of user to be punished",
-                        "name": "user_id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Detail of punishment",
-                        "name": "Detail",
+                        "description": "Old and new password",
+                        "name": "Info",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/model.PunishmentStruct"
+                            "$ref": "#/definitions/auth.changePasswordRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successfully punish a user",
+                        "description": "Password changed",
                         "schema": {
                             "$ref": "#/definitions/utilities.MessageResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid authorization header, request body",
+                        "description": "Info provided not met the condition",
                         "schema": {
                             "$ref": "#/definitions/utilities.ErrorResponse"
                         }
                     },
                     "401": {
-                        "description": "Invalid token",
-                        "schema": {
-                            "$ref": "#/definitions/utilities.ErrorResponse"
-                        }
-                    },
-                    "403": {
-                        "description": "Not logged in as Admin, trying to punish other Admin",
-                        "schema": {
-                            "$ref": "#/definitions/utilities.ErrorResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "User not found",
+                        "description": "Unauthorized or old password is incorrect",
                         "schema": {
                             "$ref": "#/definitions/utilities.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Database error",
+                        "description": "Database or password hashing error",
                         "schema": {
                             "$ref": "#/definitions/utilities.ErrorResponse"
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/auth/password/forgot": {
+            "post": {
+                "description": "Either username or email must be provided, email must be verified and belong to only one account.\nResponse is the same whether the account exist or not",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Admin"
+                    "Auth"
                 ],
-                "summary": "Remove punishment record from user",
+                "summary": "Request password reset link",
                 "parameters": [
                     {
-                        "type": "string",
-                        "default": "Bearer \u003cyour access token\u003e",
-                        "description": "Insert your access token",
-                        "name": "Authorization",
-                        "in": "header",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "ID of user to be unpunished",
-                        "name": "user_id",
-                        "in": "path",
-                        "required": true
+                        "description": "Username or email of the account",
+                        "name": "Info",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/auth.forgotPasswordRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Successfully remove punishment record from user",
+                    "202": {
+                        "description": "Reset link is sent if the account exist",
                         "schema": {
                             "$ref": "#/definitions/utilities.MessageResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid authorization header, request body",
-                        "schema": {
-                            "$ref": "#/definitions/utilities.ErrorResponse"
-                        }
-                    },
-                    "401": {
-                        "description": "Invalid token",
-                        "schema": {
-                            "$ref": "#/definitions/utilities.ErrorResponse"
-                        }
-                    },
-                    "403": {
-                        "description": "Not logged in as Admin",
-                        "schema": {
-                            "$ref": "#/definitions/utilities.ErrorResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "User not found",
+                        "description": "Username or email is not provided",
                         "schema": {
                             "$ref": "#/definitions/utilities.ErrorResponse"
                         }
@@ -1812,60 +1783,45 @@ const docTemplate = `{
                 }
             }
         },
-        "/report": {
-            "get": {
-                "description": "Retrieve reports from database, optionally filtered by status.",
+        "/auth/password/reset": {
+            "post": {
+                "description": "Reset token can be used once, every session of the user is logged out on success",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Report"
+                    "Auth"
                 ],
-                "summary": "Retrieve reports from database",
+                "summary": "Reset password",
                 "parameters": [
                     {
-                        "type": "string",
-                        "default": "Bearer \u003cyour access token\u003e",
-                        "description": "Insert your access token",
-                        "name": "Authorization",
-                        "in": "header",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Filter reports by status (e.g., pending, reviewed, resolved)",
-                        "name": "status",
-                        "in": "query"
+                        "description": "Reset token and new password",
+                        "name": "Info",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/auth.resetPasswordRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successfully retrieve reports",
+                        "description": "Password has been reset",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/utilities.MessageResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid request parameters",
-                        "schema": {
-                            "$ref": "#/definitions/utilities.ErrorResponse"
-                        }
-                    },
-                    "401": {
-                        "description": "Invalid token",
-                        "schema": {
-                            "$ref": "#/definitions/utilities.ErrorResponse"
-                        }
-                    },
-                    "403": {
-                        "description": "User doesn't have permission to access",
+                        "description": "Info provided not met the condition or token is invalid",
                         "schema": {
                             "$ref": "#/definitions/utilities.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Database error",
+                        "description": "Database or password hashing error",
                         "schema": {
                             "$ref": "#/definitions/utilities.ErrorResponse"
                         }
@@ -1873,9 +1829,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/report/post": {
+        "/auth/password/set": {
             "post": {
-                "description": "Create a report against a job post.",
+                "description": "Only for user without password. The user can then log in locally with username or verified email",
                 "consumes": [
                     "application/json"
                 ],
@@ -1883,9 +1839,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Report"
+                    "Auth"
                 ],
-                "summary": "Create a report against a job post",
+                "summary": "Add password to Google account",
                 "parameters": [
                     {
                         "type": "string",
@@ -1896,50 +1852,42 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Report information",
-                        "name": "report",
+                        "description": "New password",
+                        "name": "Info",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/report.PostReportRequest"
+                            "$ref": "#/definitions/auth.setPasswordRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Report created successfully",
+                    "200": {
+                        "description": "Password set",
                         "schema": {
-                            "type": "object",
-                            "properties": {
-                                "message": {
-                                    "type": "string"
-                                },
-                                "report_id": {
-                                    "type": "integer"
-                                }
-                            }
+                            "$ref": "#/definitions/utilities.MessageResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid request body, reported post not found",
+                        "description": "Info provided not met the condition",
                         "schema": {
                             "$ref": "#/definitions/utilities.ErrorResponse"
                         }
                     },
                     "401": {
-                        "description": "Invalid token",
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/utilities.ErrorResponse"
                         }
                     },
-                    "403": {
-                        "description": "User doesn't have permission to access",
+                    "409": {
+                        "description": "User already has password",
                         "schema": {
                             "$ref": "#/definitions/utilities.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Database error",
+                        "description": "Database or password hashing error",
                         "schema": {
                             "$ref": "#/definitions/utilities.ErrorResponse"
                         }
@@ -1947,9 +1895,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/report/user": {
+        "/auth/refresh": {
             "post": {
-                "description": "Create a report against a user. Cannot report admins or users with the same role as the reporter.",
+                "description": "Refresh token can be used only once, reusing an old refresh token revokes every token of that session",
                 "consumes": [
                     "application/json"
                 ],
@@ -1957,57 +1905,35 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Report"
+                    "Auth"
                 ],
-                "summary": "Create a report against a user",
+                "summary": "Exchange refresh token for a new token pair",
                 "parameters": [
                     {
-                        "type": "string",
-                        "default": "Bearer \u003cyour access token\u003e",
-                        "description": "Insert your access token",
-                        "name": "Authorization",
-                        "in": "header",
-                        "required": true
-                    },
-                    {
-                        "description": "Report information",
-                        "name": "report",
+                        "description": "Refresh token from login or previous refresh",
+                        "name": "Token",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/report.UserReportRequest"
+                            "$ref": "#/definitions/auth.refreshRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Report created successfully",
+                    "200": {
+                        "description": "New access and refresh token",
                         "schema": {
-                            "type": "object",
-                            "properties": {
-                                "message": {
-                                    "type": "string"
-                                },
-                                "report_id": {
-                                    "type": "integer"
-                                }
-                            }
+                            "$ref": "#/definitions/auth.tokenPairResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid request body, reported user not found, cannot report this user",
+                        "description": "Refresh token is not provided",
                         "schema": {
                             "$ref": "#/definitions/utilities.ErrorResponse"
                         }
                     },
                     "401": {
-                        "description": "Invalid token",
-                        "schema": {
-                            "$ref": "#/definitions/utilities.ErrorResponse"
-                        }
-                    },
-                    "403": {
-                        "description": "User doesn't have permission to access",
+                        "description": "Refresh token is invalid, expired or already used",
                         "schema": {
                             "$ref": "#/definitions/utilities.ErrorResponse"
                         }
@@ -2021,19 +1947,62 @@ const docTemplate = `{
                 }
             }
         },
-        "/report/{type}/{id}": {
-            "put": {
-                "description": "Update the status of a report (either on a user or a post).",
-                "consumes": [
+        "/auth/register": {
+            "post": {
+                "description": "Username must not already exist and password must longer or equal to 8 characters long",
+                "consumes": [
                     "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Report"
+                    "Auth"
                 ],
-                "summary": "Update the status of a report",
+                "summary": "Handles local registration by receiving username and password",
+                "parameters": [
+                    {
+                        "description": "role can be only 'cpsk' or 'company'",
+                        "name": "Info",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/auth.registerInfo"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "If role is visitor",
+                        "schema": {
+                            "$ref": "#/definitions/model.VisitorResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Info provided not met the condition",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Database or password hashing error",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/company/ai-verify": {
+            "post": {
+                "description": "Company can request AI verification of their own profile. AI analyzes company data and makes verification decision",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Company"
+                ],
+                "summary": "Use AI to verify your company",
                 "parameters": [
                     {
                         "type": "string",
@@ -2042,48 +2011,142 @@ const docTemplate = `{
                         "name": "Authorization",
                         "in": "header",
                         "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/verification.aiVerificationResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid authorization header",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Not logged in as company",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Company profile not found",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
                     },
+                    "500": {
+                        "description": "Database error or AI service error",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/company/myprofile": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Company"
+                ],
+                "summary": "Retrieve company profile from database",
+                "parameters": [
                     {
                         "type": "string",
-                        "description": "ID of the report to update",
-                        "name": "id",
-                        "in": "path",
+                        "default": "Bearer \u003cyour access token\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
                         "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieve company profile",
+                        "schema": {
+                            "$ref": "#/definitions/model.CompanyUser"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid authorization header",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Not logged in as company, User is banned",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
                     },
+                    "500": {
+                        "description": "Database error",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/company/profile": {
+            "patch": {
+                "description": "Overwrite company profile and save into database\nSensitive field like id, file, verified status, and job post can't be overwritten",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Company"
+                ],
+                "summary": "Edit company profile",
+                "parameters": [
                     {
                         "type": "string",
-                        "description": "Type of report to update (user or post)",
-                        "name": "type",
-                        "in": "path",
+                        "default": "Bearer \u003cyour access token\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
                         "required": true
                     },
                     {
-                        "description": "Report status update information",
-                        "name": "report",
+                        "description": "Company info to be written",
+                        "name": "company_profile",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "type": "object",
-                            "properties": {
-                                "admin_note": {
-                                    "type": "string"
-                                },
-                                "status": {
-                                    "type": "string"
-                                }
-                            }
+                            "$ref": "#/definitions/company.editCompanyUser"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Report status updated successfully",
+                        "description": "Successfully overwrite",
                         "schema": {
-                            "$ref": "#/definitions/utilities.MessageResponse"
+                            "$ref": "#/definitions/model.CompanyUser"
                         }
                     },
                     "400": {
-                        "description": "Invalid request body, report not found",
+                        "description": "Invalid authorization header or request body",
                         "schema": {
                             "$ref": "#/definitions/utilities.ErrorResponse"
                         }
@@ -2095,7 +2158,7 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "User doesn't have permission to access",
+                        "description": "Not logged in as company, User is banned",
                         "schema": {
                             "$ref": "#/definitions/utilities.ErrorResponse"
                         }
@@ -2109,16 +2172,19 @@ const docTemplate = `{
                 }
             }
         },
-        "/verify-company/{company_id}": {
-            "patch": {
-                "description": "Only admin can access this endpoints",
+        "/company/profile/banner": {
+            "post": {
+                "description": "Only file that smaller than 10 MB with .jpg, .jpeg, or .png extension is permitted",
+                "consumes": [
+                    "multipart/form-data"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Admin"
+                    "Company"
                 ],
-                "summary": "Verify, or unverify companies",
+                "summary": "Upload banner file for company",
                 "parameters": [
                     {
                         "type": "string",
@@ -2129,29 +2195,22 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "Company ID",
-                        "name": "company_id",
-                        "in": "path",
+                        "type": "file",
+                        "description": "Upload your banner file",
+                        "name": "banner",
+                        "in": "formData",
                         "required": true
-                    },
-                    {
-                        "type": "string",
-                        "default": "verified",
-                        "description": "Status is case insensitive and allow only unverified, or verified (verified by default)",
-                        "name": "status",
-                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Successfully upload banner",
                         "schema": {
                             "$ref": "#/definitions/model.CompanyUser"
                         }
                     },
                     "400": {
-                        "description": "Invalid authorization header, or Invalid request body",
+                        "description": "Invalid authorization header",
                         "schema": {
                             "$ref": "#/definitions/utilities.ErrorResponse"
                         }
@@ -2163,13 +2222,19 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "Do not logged in as admin",
+                        "description": "Not logged in as company, User is banned",
                         "schema": {
                             "$ref": "#/definitions/utilities.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Given company ID not found",
+                    "413": {
+                        "description": "File size is larger than 10 MB",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "415": {
+                        "description": "File extension is not allowed",
                         "schema": {
                             "$ref": "#/definitions/utilities.ErrorResponse"
                         }
@@ -2182,17 +2247,4011 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "auth.code": {
+        },
+        "/company/profile/logo": {
+            "post": {
+                "description": "Only file that smaller than 10 MB with .jpg, .jpeg, or .png extension is permitted",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Company"
+                ],
+                "summary": "Upload logo file for company",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cyour access token\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Upload your logo file",
+                        "name": "logo",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully upload logo",
+                        "schema": {
+                            "$ref": "#/definitions/model.CompanyUser"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid authorization header",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Not logged in as company, User is banned",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "File size is larger than 10 MB",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "415": {
+                        "description": "File extension is not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Database error",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/company/profile/{company_id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Company"
+                ],
+                "summary": "Retrieve company profile from database by given ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cyour access token\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID of company",
+                        "name": "company_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieve company profile",
+                        "schema": {
+                            "$ref": "#/definitions/model.CompanyUser"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid authorization header",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "User is banned",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Company not exist",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Database error",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/company/{company_id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Company"
+                ],
+                "summary": "Retrieve company profile from database by given ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cyour access token\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID of company",
+                        "name": "company_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieve company profile",
+                        "schema": {
+                            "$ref": "#/definitions/model.CompanyUser"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid authorization header",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "User is banned",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Company not exist",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Database error",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/cpsk/applications": {
+            "get": {
+                "description": "Only CPSK user can access this endpoint\nstatus can be multiple value separated by comma, e.g. \"pending,interview\"",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Application"
+                ],
+                "summary": "Get my applications",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cyour access token\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by application status, comma separated",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number, start from 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Number of applications per page (max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Return applications of the user, newest first",
+                        "schema": {
+                            "$ref": "#/definitions/application.myApplicationListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid authorization header, or invalid query",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Not logged in as CPSK, User is banned",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Database error",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/cpsk/applications/{id}": {
+            "get": {
+                "description": "Only CPSK user who made the application can access this endpoint",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Application"
+                ],
+                "summary": "Get my application by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cyour access token\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID of desired application",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Return the application with its status history",
+                        "schema": {
+                            "$ref": "#/definitions/application.myApplicationResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid authorization header",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Not logged in as CPSK, User is banned",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Application not found",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Database error",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/cpsk/applications/{id}/withdraw": {
+            "post": {
+                "description": "Only CPSK user who made the application can access this endpoint\nApplication that is already hired, rejected, or withdrawn can't be withdrawn",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Application"
+                ],
+                "summary": "Withdraw my application",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cyour access token\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID of desired application",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Optional reason of withdrawal",
+                        "name": "Note",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/application.withdrawRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully withdraw application",
+                        "schema": {
+                            "$ref": "#/definitions/application.myApplicationResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid authorization header, request body, or application can't be withdrawn",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Not logged in as CPSK, User is banned",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Application not found",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Application status was changed by another request",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Database error",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/cpsk/myprofile": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "CPSK"
+                ],
+                "summary": "Retrieve CPSK profile from database",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cyour access token\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieve CPSK profile",
+                        "schema": {
+                            "$ref": "#/definitions/model.CPSKUser"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid authorization header",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Not logged in as CPSK, User is banned",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Database error",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/cpsk/profile": {
+            "patch": {
+                "description": "Overwrite CPSK profile and save into database\nSensitive field like id, file, and application can't be overwritten",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "CPSK"
+                ],
+                "summary": "Edit CPSK profile",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cyour access token\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "CPSK info to be written",
+                        "name": "cpsk_profile",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/cpsk.editCPSKUser"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully overwrite",
+                        "schema": {
+                            "$ref": "#/definitions/model.CPSKUser"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid authorization header or request body",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Not logged in as CPSK, User is banned",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Database error",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/cpsk/profile/resume": {
+            "post": {
+                "description": "Only file that smaller than 10 MB with .pdf extension is permitted",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "CPSK"
+                ],
+                "summary": "Upload resume file for CPSK",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cyour access token\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Upload your resume file",
+                        "name": "resume",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully upload resume",
+                        "schema": {
+                            "$ref": "#/definitions/model.CPSKUser"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid authorization header",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Not logged in as CPSK, User is banned",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "File size is larger than 10 MB",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "415": {
+                        "description": "File extension is not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Database error",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/file/{id}": {
+            "get": {
+                "produces": [
+                    "application/octet-stream"
+                ],
+                "tags": [
+                    "File"
+                ],
+                "summary": "Retrieve dowloadable attachment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cyour access token\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID of wanted file",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieve file",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid authorization header",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "User is banned, not allowed to access answer file",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Given file id not found",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Fail to send file content",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/get-companies": {
+            "get": {
+                "description": "Only admin can access this endpoints\nIf no query given, the server will return all companies",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Get companies based on given query",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cyour access token\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "pending unverified",
+                        "description": "Only pending, unverified, or verified with case insensitive",
+                        "name": "verify",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "ban suspend",
+                        "description": "Only ban, or suspend with case insensitive",
+                        "name": "punishment",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.CompanyUser"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid authorization header",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Do not logged in as admin",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Database error",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/get-cpsk": {
+            "get": {
+                "description": "Only admin can access this endpoints\nIf no query given, the server will return all CPSK",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Get CPSK based on given query",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cyour access token\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "ban suspend",
+                        "description": "Only ban, or suspend with case insensitive",
+                        "name": "punishment",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.CPSKUser"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid authorization header",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Do not logged in as admin",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Database error",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/get-visitors": {
+            "get": {
+                "description": "Only admin can access this endpoints\nIf no query given, the server will return all visitors",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Get visitors based on given query",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cyour access token\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "ban suspend",
+                        "description": "Only ban, or suspend with case insensitive",
+                        "name": "punishment",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.VisitorUser"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid authorization header",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Do not logged in as admin",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Database error",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/jobpost": {
+            "get": {
+                "description": "Every query are not required, but they have specific use defined in their description\nOnly published post that has not expired is listed unless status is given.\nDraft and hidden posts are only listed for company organization that own them or admin, archived posts only for admin.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Jobpost"
+                ],
+                "summary": "Get published job posts based on query",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cyour access token\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Full-text search over title, description, requirement and tags, tolerating typo in title",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Job type field with substring matching and case insensitive",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search if tags field contain tag param, no substring matching and case insensitive",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Salary field, must exactly match to get result",
+                        "name": "salary",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Exp_lvl field, must exactly match to get result",
+                        "name": "exp",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search from company name with substring matching and case insensitive",
+                        "name": "company",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search from industry of company with substring matching and case insensitive",
+                        "name": "industry",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search from location with substring matching and case insensitive",
+                        "name": "location",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort by relevance or post_time, relevance is default when search is given",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Sorting by post time in descending if true, otherwise ascendind",
+                        "name": "desc",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "published",
+                        "description": "Status of job post, one of draft, published, closed, hidden or archived",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Return job post(s) with given status",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.JobPostResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid authorization header, invalid job post struct or status",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "User is banned, or not allowed to list posts with given status",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Database error",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Only owner or recruiter of verified company organization have access to this endpoint\nPost is created as draft if status is draft, otherwise it is published\nQuestions define custom application form, see PUT /jobpost/{id}/form for question types",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Jobpost"
+                ],
+                "summary": "Create job post based on given json structure",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cyour access token\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Input jobpost information",
+                        "name": "Jobpost",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/jobpost.jobPostCreateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Successfully create job post",
+                        "schema": {
+                            "$ref": "#/definitions/model.JobPost"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid authorization header, or invalid job post struct",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Not logged in as verified company, User is banned or suspended",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Database error",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/jobpost/search": {
+            "get": {
+                "description": "Accept the same query as GET /jobpost, results are ranked by relevance when search is given.\nsearch uses full-text search over title, description, requirement and tags in English and Thai,\nand also matches title with typo or containing the search term.\nFacets count every matched post by type, exp_lvl, location, industry and tag, up to 20 values each.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Jobpost"
+                ],
+                "summary": "Search job posts with ranking and facets",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cyour access token\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Full-text search term",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Job type field with substring matching and case insensitive",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search if tags field contain tag param, no substring matching and case insensitive",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Salary field, must exactly match to get result",
+                        "name": "salary",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Exp_lvl field, must exactly match to get result",
+                        "name": "exp",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search from company name with substring matching and case insensitive",
+                        "name": "company",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search from industry of company with substring matching and case insensitive",
+                        "name": "industry",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search from location with substring matching and case insensitive",
+                        "name": "location",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "published",
+                        "description": "Status of job post, one of draft, published, closed, hidden or archived",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort by relevance or post_time, relevance is default when search is given",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Sorting by post time in descending if true, otherwise ascendind",
+                        "name": "desc",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number, start from 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Number of posts per page (max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Return matched job posts and facet counts",
+                        "schema": {
+                            "$ref": "#/definitions/jobpost.searchResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid authorization header, or invalid query",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "User is banned, or not allowed to list posts with given status",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Database error",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/jobpost/{id}": {
+            "get": {
+                "description": "Retrieve a specific job post using its unique ID\nDraft and hidden posts are only visible to company organization that own them or admin, archived posts only to admin",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Jobpost"
+                ],
+                "summary": "Get job post by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cyour access token\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID of desired job post",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Return the job post with the specified ID",
+                        "schema": {
+                            "$ref": "#/definitions/model.JobPostResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid authorization header",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "User is banned",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Job post not found or not visible to user",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Database error",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Only owner or recruiter of company organization that own the post, or admin have access to this endpoint\nPost is archived instead of removed, so its applications and reports are kept",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Jobpost"
+                ],
+                "summary": "Delete given job post ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cyour access token\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID of desired job post",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully delete job post",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.MessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid authorization header, or invalid job post struct",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Do not have permission to delete this post, User is banned",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Post not found",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Database error",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Only owner or recruiter of company organization that own the post, or admin have access to this endpoint",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Jobpost"
+                ],
+                "summary": "Edit job post based on given json structure",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cyour access token\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID of desired job post",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Input jobpost information",
+                        "name": "Jobpost",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.EditableJobPostInfo"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully update job post",
+                        "schema": {
+                            "$ref": "#/definitions/model.JobPost"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid authorization header, or invalid job post struct",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Do not have permission to edit, User is banned",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Post not found",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Database error",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/jobpost/{id}/applications": {
+            "get": {
+                "description": "Only member of company organization that own the post have access to this endpoint\nstatus can be multiple value separated by comma, e.g. \"pending,rejected\"",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Application"
+                ],
+                "summary": "Get applications of a job post",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cyour access token\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID of desired job post",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by application status, comma separated",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by applicant program (CPE or SKE)",
+                        "name": "program",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search from applicant first or last name with substring matching and case insensitive",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Sorting by applied time in descending if true, otherwise ascending",
+                        "name": "desc",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number, start from 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Number of applications per page (max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Return applications of the job post",
+                        "schema": {
+                            "$ref": "#/definitions/application.applicantListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid authorization header, or invalid query",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the owner of the post, User is banned",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Job post not found",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Database error",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/jobpost/{id}/applications/export": {
+            "get": {
+                "description": "Only member of company organization that own the post have access to this endpoint\nEach row is an application, with applicant information followed by answer to each question of the form.\nDefault form answers are included if the post uses default form, file answer is given as download link.",
+                "produces": [
+                    "text/csv"
+                ],
+                "tags": [
+                    "Application"
+                ],
+                "summary": "Export application answers of a job post",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cyour access token\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID of desired job post",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "CSV of application answers",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid authorization header",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the owner of the post, User is banned",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Job post not found",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Database error",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/jobpost/{id}/form": {
+            "put": {
+                "description": "Only owner or recruiter of company organization that own the post, or admin have access to this endpoint\nQuestion type is one of short_text, long_text, single_choice, multi_choice, number or file.\nOptions are required for single_choice and multi_choice questions and not allowed otherwise.\nForm can't be changed once the post has received an application.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Jobpost"
+                ],
+                "summary": "Replace application form of job post",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cyour access token\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID of desired job post",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Questions of the form in order they are asked",
+                        "name": "Form",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/jobpost.formRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully replace application form",
+                        "schema": {
+                            "$ref": "#/definitions/model.JobPost"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid authorization header, or invalid question",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Do not have permission to edit, User is banned",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Post not found",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Post has already received applications",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Database error",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/jobpost/{id}/status": {
+            "patch": {
+                "description": "Only owner or recruiter of company organization that own the post, or admin have access to this endpoint\nAllowed transitions:\ndraft -\u003e published\npublished -\u003e closed, hidden\nclosed -\u003e published, hidden\nhidden -\u003e published\nOnly admin can hide post or publish hidden post, expired post can't be published until its expiring is extended.\nPost is archived by deleting it.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Jobpost"
+                ],
+                "summary": "Update status of job post",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cyour access token\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID of desired job post",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New status of job post",
+                        "name": "Status",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/jobpost.jobPostStatusRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully update job post status",
+                        "schema": {
+                            "$ref": "#/definitions/model.JobPost"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid authorization header, request body, or illegal status transition",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Do not have permission to update status, User is banned",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Post not found",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Database error",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/login-lockouts": {
+            "get": {
+                "description": "Only admin can access this endpoints",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Get active local login lockouts",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cyour access token\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.LoginLockout"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid authorization header",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Do not logged in as admin",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Database error",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Only admin can access this endpoints\nEither username or ip must be given",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Unlock local login of username or IP",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cyour access token\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Locked username",
+                        "name": "username",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Locked client IP",
+                        "name": "ip",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Unlocked",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Neither username nor ip given",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Do not logged in as admin",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Given username or IP has no failed attempt",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Database error",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/organization": {
+            "get": {
+                "description": "Company account is always owner of its organization",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Organization"
+                ],
+                "summary": "Get organization of current user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cyour access token\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/organization.organizationResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Not a member of any organization, User is banned",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Database error",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/organization/invitations": {
+            "get": {
+                "description": "Only owner of the organization can access this endpoint",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Organization"
+                ],
+                "summary": "Get pending invitations of organization",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cyour access token\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.OrganizationInvitation"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Do not have permission",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Database error",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Only owner of the organization can access this endpoint\nPrevious invitation to the same email is replaced. Role must be owner, recruiter or viewer",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Organization"
+                ],
+                "summary": "Invite user to organization",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cyour access token\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Email and role of the invited user",
+                        "name": "Invitation",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/organization.inviteRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.OrganizationInvitation"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid email or role",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Do not have permission",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "User is already a member",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Database or mail error",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/organization/invitations/accept": {
+            "post": {
+                "description": "Verified email of the current user must match the invited email. Invitation can be used once\nCompany account and member of another organization can't accept",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Organization"
+                ],
+                "summary": "Accept invitation to organization",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cyour access token\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Token from invitation link",
+                        "name": "Token",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/organization.acceptInvitationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/policy.Membership"
+                        }
+                    },
+                    "400": {
+                        "description": "Token is not provided, invalid or expired",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Invitation was sent to another email, User is banned",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "User is company account or already a member of an organization",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Database error",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/organization/invitations/{id}": {
+            "delete": {
+                "description": "Only owner of the organization can access this endpoint",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Organization"
+                ],
+                "summary": "Revoke invitation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cyour access token\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID of the invitation",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Invitation revoked",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.MessageResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Do not have permission",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Invitation not found",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Database error",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/organization/members/{user_id}": {
+            "delete": {
+                "description": "Owner of the organization can remove any member, other member can only remove themselves to leave\nCompany account can't be removed",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Organization"
+                ],
+                "summary": "Remove organization member",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cyour access token\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID of the member",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Member removed",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.MessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid user ID",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Do not have permission",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Member not found",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Database error",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Only owner of the organization can access this endpoint. Role of company account can't be changed",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Organization"
+                ],
+                "summary": "Change role of organization member",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cyour access token\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID of the member",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New role of the member",
+                        "name": "Role",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/organization.memberRoleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Role updated",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.MessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid user ID or role",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Do not have permission",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Member not found",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Database error",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/punish/{user_id}": {
+            "get": {
+                "description": "Lifted and expired punishments are included, newest first.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Get punishment history of user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cyour access token\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID of user",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Punishment history of user",
+                        "schema": {
+                            "$ref": "#/definitions/punishment.punishmentHistoryResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Not logged in as Admin",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Database error",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Type of punishment (Only 'ban' or 'suspend' with case insensitive),\n'at' and 'end' fields must be in 'YYYY-MM-DDTHH:mm:ssZ' format.\nOnly 'type' is required 'at' and 'end' are optional\n'at' will be current time by default\n'end' leave empty mean permanent punishment\n'report_type' ('user' or 'post') and 'report_id' optionally link report on this user or their post.\nNew punishment is added to punishment history, previous punishments are kept.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Ban or suspend user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cyour access token\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID of user to be punished",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Detail of punishment",
+                        "name": "Detail",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/punishment.punishRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully punish a user",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.MessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid authorization header, request body, report not found",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Not logged in as Admin, trying to punish other Admin",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Database error",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Every active punishment of the user is marked as lifted by current admin.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Lift punishment of user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cyour access token\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID of user to be unpunished",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Reason of lifting the punishment early",
+                        "name": "reason",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully lift punishment of user",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.MessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid authorization header, request body",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Not logged in as Admin",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Database error",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/report": {
+            "get": {
+                "description": "Retrieve reports from database, optionally filtered by status.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Report"
+                ],
+                "summary": "Retrieve reports from database",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cyour access token\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter reports by status (e.g., pending, reviewed, resolved)",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieve reports",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request parameters",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "User doesn't have permission to access",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Database error",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/report/cases": {
+            "get": {
+                "description": "Pending reports on the same user or post are grouped as one case.\nPriority rises with number of distinct reporters, repeated reports and waiting time.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Report"
+                ],
+                "summary": "Get moderation queue",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cyour access token\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only 'user' or 'post' cases",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "'me', 'none' or ID of admin who claimed the case",
+                        "name": "assignee",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "priority",
+                        "description": "priority, oldest, newest or count",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number, start from 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Cases per page, max 100",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/report.reportCaseListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid query",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "User doesn't have permission to access",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Database error",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/report/cases/{type}/{target_id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Report"
+                ],
+                "summary": "Get moderation case",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cyour access token\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Type of reported target (user or post)",
+                        "name": "type",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID of reported user or post",
+                        "name": "target_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/report.reportCaseResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid report type or target ID",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "User doesn't have permission to access",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Case not found",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Database error",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Every pending report on the target gets the status and admin note, and claim of the case is removed.\nCase claimed by another admin can't be resolved.\nOptional action is taken once on the author when status is resolved, recorded on every report\nand every reporter is notified.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Report"
+                ],
+                "summary": "Resolve moderation case",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cyour access token\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Type of reported target (user or post)",
+                        "name": "type",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID of reported user or post",
+                        "name": "target_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Status ('resolved' or 'rejected') and admin note",
+                        "name": "decision",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/report.resolveCaseRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Case resolved",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "message": {
+                                    "type": "string"
+                                },
+                                "resolved_reports": {
+                                    "type": "integer"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body, report type, target ID or action",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "User doesn't have permission to access",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Case not found",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Case is claimed by another admin",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Database error",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/report/cases/{type}/{target_id}/claim": {
+            "post": {
+                "description": "Claiming case already claimed by current admin has no effect.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Report"
+                ],
+                "summary": "Claim moderation case",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cyour access token\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Type of reported target (user or post)",
+                        "name": "type",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID of reported user or post",
+                        "name": "target_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Claim of the case",
+                        "schema": {
+                            "$ref": "#/definitions/model.ReportClaim"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid report type or target ID",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "User doesn't have permission to access",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Case not found",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Case is claimed by another admin",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Database error",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Report"
+                ],
+                "summary": "Release moderation case",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cyour access token\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Type of reported target (user or post)",
+                        "name": "type",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID of reported user or post",
+                        "name": "target_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Case released",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.MessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid report type or target ID",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "User doesn't have permission to access",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Case is not claimed by current admin",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Database error",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/report/post": {
+            "post": {
+                "description": "Create a report against a job post.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Report"
+                ],
+                "summary": "Create a report against a job post",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cyour access token\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Report information",
+                        "name": "report",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/report.PostReportRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Report created successfully",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "message": {
+                                    "type": "string"
+                                },
+                                "report_id": {
+                                    "type": "integer"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body, reported post not found",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "User doesn't have permission to access",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Database error",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/report/user": {
+            "post": {
+                "description": "Create a report against a user. Cannot report admins or users with the same role as the reporter.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Report"
+                ],
+                "summary": "Create a report against a user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cyour access token\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Report information",
+                        "name": "report",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/report.UserReportRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Report created successfully",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "message": {
+                                    "type": "string"
+                                },
+                                "report_id": {
+                                    "type": "integer"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body, reported user not found, cannot report this user",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "User doesn't have permission to access",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Database error",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/report/{type}/{id}": {
+            "put": {
+                "description": "Update the status of a report (either on a user or a post).\nOptional action (warn, hide_post, delete_post, suspend or ban) is taken on the author\nof reported user or post when status is resolved, days is length of suspend or ban.\nThe action is recorded on the report and the reporter is notified. Action is taken once per pending report.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Report"
+                ],
+                "summary": "Update the status of a report",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cyour access token\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID of the report to update",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Type of report to update (user or post)",
+                        "name": "type",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Report status update information",
+                        "name": "report",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "action": {
+                                    "$ref": "#/definitions/report.ActionRequest"
+                                },
+                                "admin_note": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Report status updated successfully",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.MessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body, report not found, invalid action",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "User doesn't have permission to access",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Reported user or post not found",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Report is already resolved or action was already taken",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Database error",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/roles": {
+            "get": {
+                "description": "Only user with role:manage permission can access this endpoint",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Role"
+                ],
+                "summary": "Get roles and their permissions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cyour access token\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/role.rolesResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Do not have permission",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Database error",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/roles/{name}": {
+            "put": {
+                "description": "Only user with role:manage permission can access this endpoint\nName must be lowercase letters, digits, '-' or '_' and not a built-in role",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Role"
+                ],
+                "summary": "Create or update custom role",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cyour access token\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "moderator",
+                        "description": "Role name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Permissions granted by the role",
+                        "name": "Role",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/role.roleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/role.roleInfo"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid name or unknown permission",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Do not have permission",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Database error",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Only user with role:manage permission can access this endpoint",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Role"
+                ],
+                "summary": "Delete custom role",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cyour access token\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Role name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Role deleted",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.MessageResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Do not have permission",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Role not found",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Database error",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/roles/{name}/users/{user_id}": {
+            "put": {
+                "description": "Only user with role:manage permission can access this endpoint",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Role"
+                ],
+                "summary": "Assign custom role to user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cyour access token\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Role name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID of the user",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Role assigned",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.MessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid user ID",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Do not have permission",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Role or user not found",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Database error",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Only user with role:manage permission can access this endpoint",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Role"
+                ],
+                "summary": "Remove custom role from user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cyour access token\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Role name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID of the user",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Role removed",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.MessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid user ID",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Do not have permission",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User doesn't have the role",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Database error",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/verify-company/{company_id}": {
+            "patch": {
+                "description": "Only admin can access this endpoints",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Verify, or unverify companies",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cyour access token\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Company ID",
+                        "name": "company_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "verified",
+                        "description": "Status is case insensitive and allow only unverified, or verified (verified by default)",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.CompanyUser"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid authorization header, or Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Do not logged in as admin",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Given company ID not found",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Database error",
+                        "schema": {
+                            "$ref": "#/definitions/utilities.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "admin.auditLogListResponse": {
+            "type": "object",
+            "properties": {
+                "limit": {
+                    "type": "integer"
+                },
+                "logs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.AuditLog"
+                    }
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "appeal.appealListResponse": {
+            "type": "object",
+            "properties": {
+                "appeals": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.PunishmentAppeal"
+                    }
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "appeal.reviewAppealRequest": {
+            "type": "object",
+            "required": [
+                "status"
+            ],
+            "properties": {
+                "admin_note": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "appeal.submitAppealRequest": {
+            "type": "object",
+            "required": [
+                "message"
+            ],
+            "properties": {
+                "message": {
+                    "type": "string"
+                },
+                "punishment_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "application.applicantListResponse": {
+            "type": "object",
+            "properties": {
+                "applications": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/application.applicantResponse"
+                    }
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "application.applicantResponse": {
+            "type": "object",
+            "required": [
+                "post_id",
+                "resume_id"
+            ],
+            "properties": {
+                "answer": {
+                    "$ref": "#/definitions/model.ApplicationAnswer"
+                },
+                "answer_id": {
+                    "type": "integer"
+                },
+                "answers": {
+                    "description": "Answers holds answer to each question of custom form of the job post",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.FormAnswer"
+                    }
+                },
+                "applied_at": {
+                    "type": "string"
+                },
+                "cpsk_id": {
+                    "description": "CPSKID references CPSKUser.UserID (uuid)",
+                    "type": "string"
+                },
+                "cpsk_user": {
+                    "$ref": "#/definitions/model.CPSKUser"
+                },
+                "history": {
+                    "description": "History holds every status change of the application",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ApplicationHistory"
+                    }
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "post_id": {
+                    "description": "PostID references JobPost.ID",
+                    "type": "integer"
+                },
+                "resume_id": {
+                    "type": "integer"
+                },
+                "resume_url": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "application.jobPostSummary": {
+            "type": "object",
+            "properties": {
+                "company_id": {
+                    "type": "string"
+                },
+                "company_name": {
+                    "type": "string"
+                },
+                "exp_lvl": {
+                    "type": "string"
+                },
+                "expiring": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "location": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "application.myApplicationListResponse": {
+            "type": "object",
+            "properties": {
+                "applications": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/application.myApplicationResponse"
+                    }
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "application.myApplicationResponse": {
+            "type": "object",
+            "properties": {
+                "answer": {
+                    "$ref": "#/definitions/model.ApplicationAnswer"
+                },
+                "answers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.FormAnswer"
+                    }
+                },
+                "applied_at": {
+                    "type": "string"
+                },
+                "history": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ApplicationHistory"
+                    }
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "job_post": {
+                    "$ref": "#/definitions/application.jobPostSummary"
+                },
+                "resume_url": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "application.statusUpdateRequest": {
+            "type": "object",
+            "required": [
+                "status"
+            ],
+            "properties": {
+                "note": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "application.withdrawRequest": {
+            "type": "object",
+            "properties": {
+                "note": {
+                    "type": "string"
+                }
+            }
+        },
+        "auth.changePasswordRequest": {
+            "type": "object",
+            "required": [
+                "new_password",
+                "old_password"
+            ],
+            "properties": {
+                "new_password": {
+                    "type": "string"
+                },
+                "old_password": {
+                    "type": "string"
+                }
+            }
+        },
+        "auth.emailRequest": {
+            "type": "object",
+            "required": [
+                "email"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                }
+            }
+        },
+        "auth.forgotPasswordRequest": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "auth.googleCallbackResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "state": {
+                    "type": "string"
+                }
+            }
+        },
+        "auth.jsonWebKey": {
             "type": "object",
-            "required": [
-                "code"
-            ],
             "properties": {
-                "code": {
+                "alg": {
+                    "type": "string"
+                },
+                "crv": {
+                    "type": "string"
+                },
+                "e": {
                     "type": "string"
+                },
+                "kid": {
+                    "type": "string"
+                },
+                "kty": {
+                    "type": "string"
+                },
+                "n": {
+                    "type": "string"
+                },
+                "use": {
+                    "type": "string"
+                },
+                "x": {
+                    "type": "string"
+                }
+            }
+        },
+        "auth.jwksResponse": {
+            "type": "object",
+            "properties": {
+                "keys": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/auth.jsonWebKey"
+                    }
                 }
             }
         },
@@ -2211,6 +6270,72 @@ const docTemplate = `{
                 }
             }
         },
+        "auth.oauthCode": {
+            "type": "object",
+            "required": [
+                "code",
+                "code_verifier",
+                "state"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "code_verifier": {
+                    "type": "string"
+                },
+                "state": {
+                    "type": "string"
+                }
+            }
+        },
+        "auth.oauthStartResponse": {
+            "type": "object",
+            "properties": {
+                "auth_url": {
+                    "type": "string"
+                },
+                "code_verifier": {
+                    "type": "string"
+                },
+                "state": {
+                    "type": "string"
+                }
+            }
+        },
+        "auth.oidcProvidersResponse": {
+            "type": "object",
+            "properties": {
+                "providers": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "auth.recoveryCodesResponse": {
+            "type": "object",
+            "properties": {
+                "recovery_codes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "auth.refreshRequest": {
+            "type": "object",
+            "required": [
+                "refresh_token"
+            ],
+            "properties": {
+                "refresh_token": {
+                    "type": "string"
+                }
+            }
+        },
         "auth.registerInfo": {
             "type": "object",
             "required": [
@@ -2234,6 +6359,122 @@ const docTemplate = `{
                 }
             }
         },
+        "auth.resetPasswordRequest": {
+            "type": "object",
+            "required": [
+                "new_password",
+                "token"
+            ],
+            "properties": {
+                "new_password": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "auth.setPasswordRequest": {
+            "type": "object",
+            "required": [
+                "password"
+            ],
+            "properties": {
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "auth.tokenPairResponse": {
+            "type": "object",
+            "properties": {
+                "access_token": {
+                    "type": "string"
+                },
+                "refresh_token": {
+                    "type": "string"
+                }
+            }
+        },
+        "auth.totpEnrollResponse": {
+            "type": "object",
+            "properties": {
+                "provisioning_uri": {
+                    "type": "string"
+                },
+                "secret": {
+                    "type": "string"
+                }
+            }
+        },
+        "auth.twoFactorChallengeResponse": {
+            "type": "object",
+            "properties": {
+                "challenge_token": {
+                    "type": "string"
+                },
+                "enrollment_required": {
+                    "description": "Set when admin must enroll before finishing login",
+                    "type": "boolean"
+                },
+                "expires_in": {
+                    "type": "integer"
+                },
+                "provisioning_uri": {
+                    "type": "string"
+                },
+                "recovery_codes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "secret": {
+                    "type": "string"
+                },
+                "two_factor_required": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "auth.twoFactorCodeRequest": {
+            "type": "object",
+            "required": [
+                "code"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string"
+                }
+            }
+        },
+        "auth.twoFactorVerifyRequest": {
+            "type": "object",
+            "required": [
+                "challenge_token",
+                "code"
+            ],
+            "properties": {
+                "challenge_token": {
+                    "type": "string"
+                },
+                "code": {
+                    "description": "Code is either TOTP code or recovery code",
+                    "type": "string"
+                }
+            }
+        },
+        "auth.verifyEmailRequest": {
+            "type": "object",
+            "required": [
+                "token"
+            ],
+            "properties": {
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
         "company.editCompanyUser": {
             "type": "object",
             "properties": {
@@ -2280,6 +6521,17 @@ const docTemplate = `{
                 }
             }
         },
+        "file.answerFileResponse": {
+            "type": "object",
+            "properties": {
+                "file_id": {
+                    "type": "integer"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
         "gorm.DeletedAt": {
             "type": "object",
             "properties": {
@@ -2292,6 +6544,32 @@ const docTemplate = `{
                 }
             }
         },
+        "jobpost.facetCount": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
+        "jobpost.formRequest": {
+            "type": "object",
+            "properties": {
+                "default_form": {
+                    "description": "DefaultForm keeps current value if not given",
+                    "type": "boolean"
+                },
+                "questions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.FormQuestion"
+                    }
+                }
+            }
+        },
         "jobpost.jobPostCreateRequest": {
             "type": "object",
             "properties": {
@@ -2310,29 +6588,109 @@ const docTemplate = `{
                 "location": {
                     "type": "string"
                 },
-                "optional_forms": {
+                "optional_forms": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "questions": {
+                    "description": "Questions is custom application form of the post",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.FormQuestion"
+                    }
+                },
+                "req": {
+                    "type": "string"
+                },
+                "salary": {
+                    "type": "string"
+                },
+                "status": {
+                    "description": "Status is either draft or published, post is published if not given",
+                    "type": "string"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "title": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "jobpost.jobPostStatusRequest": {
+            "type": "object",
+            "required": [
+                "status"
+            ],
+            "properties": {
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "jobpost.searchFacets": {
+            "type": "object",
+            "properties": {
+                "exp_lvl": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/jobpost.facetCount"
+                    }
+                },
+                "industry": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/jobpost.facetCount"
+                    }
+                },
+                "location": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/jobpost.facetCount"
+                    }
+                },
+                "tag": {
                     "type": "array",
                     "items": {
-                        "type": "string"
+                        "$ref": "#/definitions/jobpost.facetCount"
                     }
                 },
-                "req": {
-                    "type": "string"
+                "type": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/jobpost.facetCount"
+                    }
+                }
+            }
+        },
+        "jobpost.searchResponse": {
+            "type": "object",
+            "properties": {
+                "facets": {
+                    "$ref": "#/definitions/jobpost.searchFacets"
                 },
-                "salary": {
-                    "type": "string"
+                "limit": {
+                    "type": "integer"
                 },
-                "tags": {
+                "page": {
+                    "type": "integer"
+                },
+                "posts": {
                     "type": "array",
                     "items": {
-                        "type": "string"
+                        "$ref": "#/definitions/model.JobPostResponse"
                     }
                 },
-                "title": {
-                    "type": "string"
-                },
-                "type": {
-                    "type": "string"
+                "total": {
+                    "type": "integer"
                 }
             }
         },
@@ -2349,6 +6707,13 @@ const docTemplate = `{
                 "answer_id": {
                     "type": "integer"
                 },
+                "answers": {
+                    "description": "Answers holds answer to each question of custom form of the job post",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.FormAnswer"
+                    }
+                },
                 "applied_at": {
                     "type": "string"
                 },
@@ -2359,6 +6724,13 @@ const docTemplate = `{
                 "cpsk_user": {
                     "$ref": "#/definitions/model.CPSKUser"
                 },
+                "history": {
+                    "description": "History holds every status change of the application",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ApplicationHistory"
+                    }
+                },
                 "id": {
                     "type": "integer"
                 },
@@ -2397,12 +6769,82 @@ const docTemplate = `{
                 }
             }
         },
+        "model.ApplicationHistory": {
+            "type": "object",
+            "properties": {
+                "actor_id": {
+                    "type": "string"
+                },
+                "application_id": {
+                    "type": "integer"
+                },
+                "changed_at": {
+                    "type": "string"
+                },
+                "from_status": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "to_status": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.AuditLog": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "actor_id": {
+                    "type": "string"
+                },
+                "actor_name": {
+                    "type": "string"
+                },
+                "after": {
+                    "type": "object"
+                },
+                "before": {
+                    "type": "object"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "diff": {
+                    "type": "object"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "ip": {
+                    "type": "string"
+                },
+                "request_id": {
+                    "type": "string"
+                },
+                "target_id": {
+                    "type": "string"
+                },
+                "target_type": {
+                    "type": "string"
+                }
+            }
+        },
         "model.CPSKResponse": {
             "type": "object",
             "properties": {
                 "access_token": {
                     "type": "string"
                 },
+                "refresh_token": {
+                    "type": "string"
+                },
                 "user": {
                     "$ref": "#/definitions/model.CPSKUser"
                 }
@@ -2453,6 +6895,9 @@ const docTemplate = `{
                 "access_token": {
                     "type": "string"
                 },
+                "refresh_token": {
+                    "type": "string"
+                },
                 "user": {
                     "$ref": "#/definitions/model.CompanyUser"
                 }
@@ -2538,6 +6983,64 @@ const docTemplate = `{
                 }
             }
         },
+        "model.FormAnswer": {
+            "type": "object",
+            "properties": {
+                "application_id": {
+                    "type": "integer"
+                },
+                "choices": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "file_id": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "number": {
+                    "type": "number"
+                },
+                "question_id": {
+                    "type": "integer"
+                },
+                "text": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.FormQuestion": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "label": {
+                    "type": "string"
+                },
+                "options": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "position": {
+                    "type": "integer"
+                },
+                "post_id": {
+                    "type": "integer"
+                },
+                "required": {
+                    "type": "boolean"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
         "model.JobPost": {
             "type": "object",
             "properties": {
@@ -2580,12 +7083,23 @@ const docTemplate = `{
                 "post_time": {
                     "type": "string"
                 },
+                "questions": {
+                    "description": "Questions is custom application form of the post, asked in addition to default form if it is used",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.FormQuestion"
+                    }
+                },
                 "req": {
                     "type": "string"
                 },
                 "salary": {
                     "type": "string"
                 },
+                "status": {
+                    "description": "Status is lifecycle status of the post, only published post is listed and can be applied",
+                    "type": "string"
+                },
                 "tags": {
                     "type": "array",
                     "items": {
@@ -2615,60 +7129,197 @@ const docTemplate = `{
                 "desc": {
                     "type": "string"
                 },
-                "exp_lvl": {
+                "exp_lvl": {
+                    "type": "string"
+                },
+                "expiring": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "location": {
+                    "type": "string"
+                },
+                "optional_forms": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "post_time": {
+                    "type": "string"
+                },
+                "questions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.FormQuestion"
+                    }
+                },
+                "req": {
+                    "type": "string"
+                },
+                "salary": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "title": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "user_apply": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "model.LoginLockout": {
+            "type": "object",
+            "properties": {
+                "failed_count": {
+                    "type": "integer"
+                },
+                "identifier": {
+                    "type": "string"
+                },
+                "last_failed_at": {
+                    "type": "string"
+                },
+                "locked_until": {
+                    "type": "string"
+                },
+                "lockout_count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.OrganizationInvitation": {
+            "type": "object",
+            "properties": {
+                "company_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "invited_by": {
+                    "type": "string"
+                },
+                "role": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.PunishmentAppeal": {
+            "type": "object",
+            "properties": {
+                "admin_note": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "punishment": {
+                    "$ref": "#/definitions/model.PunishmentStruct"
+                },
+                "punishment_id": {
+                    "type": "integer"
+                },
+                "reviewed_at": {
+                    "type": "string"
+                },
+                "reviewed_by": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.PunishmentStruct": {
+            "type": "object",
+            "properties": {
+                "at": {
+                    "type": "string"
+                },
+                "created_at": {
                     "type": "string"
                 },
-                "expiring": {
+                "end": {
                     "type": "string"
                 },
                 "id": {
                     "type": "integer"
                 },
-                "location": {
+                "issued_by": {
                     "type": "string"
                 },
-                "optional_forms": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
+                "lift_reason": {
+                    "type": "string"
                 },
-                "post_time": {
+                "lifted_at": {
                     "type": "string"
                 },
-                "req": {
+                "lifted_by": {
                     "type": "string"
                 },
-                "salary": {
+                "reason": {
                     "type": "string"
                 },
-                "tags": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
+                "report_id": {
+                    "type": "integer"
                 },
-                "title": {
+                "report_type": {
                     "type": "string"
                 },
                 "type": {
                     "type": "string"
                 },
-                "user_apply": {
-                    "type": "boolean"
+                "user_id": {
+                    "type": "string"
                 }
             }
         },
-        "model.PunishmentStruct": {
+        "model.ReportClaim": {
             "type": "object",
             "properties": {
-                "at": {
+                "admin_id": {
                     "type": "string"
                 },
-                "end": {
+                "claimed_at": {
                     "type": "string"
                 },
-                "type": {
+                "target_id": {
+                    "type": "string"
+                },
+                "target_type": {
                     "type": "string"
                 }
             }
@@ -2685,6 +7336,10 @@ const docTemplate = `{
                 "email": {
                     "type": "string"
                 },
+                "email_verified": {
+                    "description": "EmailVerified is true when owner of the user proved they own Email",
+                    "type": "boolean"
+                },
                 "id": {
                     "type": "string"
                 },
@@ -2714,6 +7369,9 @@ const docTemplate = `{
                 "access_token": {
                     "type": "string"
                 },
+                "refresh_token": {
+                    "type": "string"
+                },
                 "user": {
                     "$ref": "#/definitions/model.VisitorUser"
                 }
@@ -2736,6 +7394,204 @@ const docTemplate = `{
                 }
             }
         },
+        "organization.acceptInvitationRequest": {
+            "type": "object",
+            "required": [
+                "token"
+            ],
+            "properties": {
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "organization.inviteRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "role"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "recruiter@example.com"
+                },
+                "role": {
+                    "type": "string",
+                    "example": "recruiter"
+                }
+            }
+        },
+        "organization.memberInfo": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "role": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "organization.memberRoleRequest": {
+            "type": "object",
+            "required": [
+                "role"
+            ],
+            "properties": {
+                "role": {
+                    "type": "string",
+                    "example": "viewer"
+                }
+            }
+        },
+        "organization.organizationResponse": {
+            "type": "object",
+            "properties": {
+                "company": {
+                    "$ref": "#/definitions/model.CompanyUser"
+                },
+                "members": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/organization.memberInfo"
+                    }
+                },
+                "my_role": {
+                    "type": "string"
+                }
+            }
+        },
+        "policy.Membership": {
+            "type": "object",
+            "properties": {
+                "company_id": {
+                    "type": "string"
+                },
+                "role": {
+                    "type": "string"
+                }
+            }
+        },
+        "policy.Permission": {
+            "type": "string",
+            "enum": [
+                "jobpost:create",
+                "jobpost:edit:own",
+                "jobpost:edit:any",
+                "jobpost:delete:own",
+                "jobpost:delete:any",
+                "application:apply",
+                "application:view:own",
+                "application:view:any",
+                "application:review:own",
+                "application:review:any",
+                "company:profile",
+                "company:verify",
+                "cpsk:profile",
+                "report:create:post",
+                "report:view",
+                "report:resolve",
+                "user:list",
+                "user:punish",
+                "login:unlock",
+                "role:manage",
+                "organization:manage",
+                "audit:view"
+            ],
+            "x-enum-varnames": [
+                "JobPostCreate",
+                "JobPostEditOwn",
+                "JobPostEditAny",
+                "JobPostDeleteOwn",
+                "JobPostDeleteAny",
+                "ApplicationApply",
+                "ApplicationViewOwn",
+                "ApplicationViewAny",
+                "ApplicationReviewOwn",
+                "ApplicationReviewAny",
+                "CompanyProfile",
+                "CompanyVerify",
+                "CPSKProfile",
+                "ReportCreatePost",
+                "ReportView",
+                "ReportResolve",
+                "UserList",
+                "UserPunish",
+                "LoginUnlock",
+                "RoleManage",
+                "OrganizationManage",
+                "AuditView"
+            ]
+        },
+        "punishment.punishRequest": {
+            "type": "object",
+            "properties": {
+                "at": {
+                    "type": "string"
+                },
+                "end": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "report_id": {
+                    "type": "integer"
+                },
+                "report_type": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "punishment.punishmentHistoryResponse": {
+            "type": "object",
+            "properties": {
+                "active_punishment_id": {
+                    "type": "integer"
+                },
+                "history": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.PunishmentStruct"
+                    }
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "report.ActionRequest": {
+            "type": "object",
+            "required": [
+                "type"
+            ],
+            "properties": {
+                "days": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "warn",
+                        "hide_post",
+                        "delete_post",
+                        "suspend",
+                        "ban"
+                    ]
+                }
+            }
+        },
         "report.PostReportRequest": {
             "type": "object",
             "required": [
@@ -2751,6 +7607,38 @@ const docTemplate = `{
                 }
             }
         },
+        "report.ReportCase": {
+            "type": "object",
+            "properties": {
+                "assignee_id": {
+                    "type": "string"
+                },
+                "claimed_at": {
+                    "type": "string"
+                },
+                "first_report_time": {
+                    "type": "integer"
+                },
+                "last_report_time": {
+                    "type": "integer"
+                },
+                "priority": {
+                    "type": "number"
+                },
+                "report_count": {
+                    "type": "integer"
+                },
+                "reporter_count": {
+                    "type": "integer"
+                },
+                "target_id": {
+                    "type": "string"
+                },
+                "target_type": {
+                    "type": "string"
+                }
+            }
+        },
         "report.UserReportRequest": {
             "type": "object",
             "required": [
@@ -2766,6 +7654,134 @@ const docTemplate = `{
                 }
             }
         },
+        "report.reportCaseListResponse": {
+            "type": "object",
+            "properties": {
+                "cases": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/report.ReportCase"
+                    }
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "report.reportCaseResponse": {
+            "type": "object",
+            "properties": {
+                "assignee_id": {
+                    "type": "string"
+                },
+                "claimed_at": {
+                    "type": "string"
+                },
+                "first_report_time": {
+                    "type": "integer"
+                },
+                "last_report_time": {
+                    "type": "integer"
+                },
+                "priority": {
+                    "type": "number"
+                },
+                "report_count": {
+                    "type": "integer"
+                },
+                "reporter_count": {
+                    "type": "integer"
+                },
+                "reports": {},
+                "target_id": {
+                    "type": "string"
+                },
+                "target_type": {
+                    "type": "string"
+                }
+            }
+        },
+        "report.resolveCaseRequest": {
+            "type": "object",
+            "required": [
+                "status"
+            ],
+            "properties": {
+                "action": {
+                    "$ref": "#/definitions/report.ActionRequest"
+                },
+                "admin_note": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "resolved",
+                        "rejected"
+                    ]
+                }
+            }
+        },
+        "role.roleInfo": {
+            "type": "object",
+            "properties": {
+                "builtin": {
+                    "type": "boolean"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "permissions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/policy.Permission"
+                    }
+                }
+            }
+        },
+        "role.roleRequest": {
+            "type": "object",
+            "required": [
+                "permissions"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "permissions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/policy.Permission"
+                    }
+                }
+            }
+        },
+        "role.rolesResponse": {
+            "type": "object",
+            "properties": {
+                "permissions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/policy.Permission"
+                    }
+                },
+                "roles": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/role.roleInfo"
+                    }
+                }
+            }
+        },
         "utilities.ErrorResponse": {
             "type": "object",
             "properties": {
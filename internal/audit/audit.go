@@ -0,0 +1,126 @@
+// Package audit records privileged actions such as punishing user or verifying company into append-only audit log.
+package audit
+
+import (
+	"HireMeMaybe-backend/internal/model"
+	"HireMeMaybe-backend/internal/utilities"
+	"encoding/json"
+	"reflect"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// RequestIDKey is key of request ID stored in gin context by RequestID middleware
+const RequestIDKey = "request_id"
+
+// Action names recorded in audit log
+const (
+	ActionPunishUser          = "user.punish"
+	ActionUnpunishUser        = "user.unpunish"
+	ActionVerifyCompany       = "company.verify"
+	ActionUpdateReportStatus  = "report.update_status"
+	ActionResolveReportCase   = "report.resolve_case"
+	ActionEditJobPost         = "job_post.edit"
+	ActionDeleteJobPost       = "job_post.delete"
+	ActionEditJobPostForm     = "job_post.edit_form"
+	ActionUpdateJobPostStatus = "job_post.update_status"
+	ActionReviewAppeal        = "appeal.review"
+)
+
+// Change is value of a field before and after the action
+type Change struct {
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// Diff returns top-level JSON fields that differ between before and after.
+// Nil before or after is treated as empty object, so created or deleted target lists every field.
+func Diff(before, after interface{}) (map[string]Change, error) {
+	b, err := toFields(before)
+	if err != nil {
+		return nil, err
+	}
+	a, err := toFields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := map[string]Change{}
+	for k, bv := range b {
+		if av, ok := a[k]; !ok || !reflect.DeepEqual(bv, av) {
+			diff[k] = Change{Before: bv, After: a[k]}
+		}
+	}
+	for k, av := range a {
+		if _, ok := b[k]; !ok {
+			diff[k] = Change{After: av}
+		}
+	}
+	return diff, nil
+}
+
+func toFields(v interface{}) (map[string]interface{}, error) {
+	fields := map[string]interface{}{}
+	if v == nil {
+		return fields, nil
+	}
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		// Not an object, compare as a whole
+		var whole interface{}
+		if err := json.Unmarshal(raw, &whole); err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"value": whole}, nil
+	}
+	return fields, nil
+}
+
+func toDocument(v interface{}) (model.JSONDocument, error) {
+	if v == nil {
+		return nil, nil
+	}
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return model.JSONDocument(raw), nil
+}
+
+// Log appends action of the current user on target to audit log, with IP and request ID of the request.
+// Pass transaction of the action as tx to record it atomically with the action.
+func Log(tx *gorm.DB, c *gin.Context, action string, targetType string, targetID string, before, after interface{}) error {
+	actor, err := utilities.ExtractUser(c)
+	if err != nil {
+		return err
+	}
+
+	entry := model.AuditLog{
+		ActorID:    actor.ID,
+		ActorName:  actor.Username,
+		Action:     action,
+		TargetType: targetType,
+		TargetID:   targetID,
+		IP:         c.ClientIP(),
+		RequestID:  c.GetString(RequestIDKey),
+	}
+	if entry.Before, err = toDocument(before); err != nil {
+		return err
+	}
+	if entry.After, err = toDocument(after); err != nil {
+		return err
+	}
+	diff, err := Diff(before, after)
+	if err != nil {
+		return err
+	}
+	if entry.Diff, err = toDocument(diff); err != nil {
+		return err
+	}
+
+	return tx.Create(&entry).Error
+}
@@ -0,0 +1,37 @@
+package audit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type post struct {
+	Title  string   `json:"title"`
+	Tags   []string `json:"tags"`
+	Salary string   `json:"salary"`
+}
+
+func TestDiff(t *testing.T) {
+	before := &post{Title: "Intern", Tags: []string{"go"}, Salary: "100"}
+	after := &post{Title: "Backend Intern", Tags: []string{"go"}, Salary: "100"}
+
+	diff, err := Diff(before, after)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]Change{
+		"title": {Before: "Intern", After: "Backend Intern"},
+	}, diff)
+
+	diff, err = Diff(before, nil)
+	assert.NoError(t, err)
+	assert.Len(t, diff, 3, "Deleted target list every field")
+	assert.Nil(t, diff["salary"].After)
+
+	diff, err = Diff(nil, after)
+	assert.NoError(t, err)
+	assert.Equal(t, "Backend Intern", diff["title"].After)
+
+	diff, err = Diff(before, before)
+	assert.NoError(t, err)
+	assert.Empty(t, diff)
+}
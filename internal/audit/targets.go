@@ -0,0 +1,137 @@
+package audit
+
+import (
+	"HireMeMaybe-backend/internal/model"
+	"HireMeMaybe-backend/internal/policy"
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Target describes how to identify and load state of resource changed by request
+type Target struct {
+	// Type is recorded as target type, such as user or job_post
+	Type string
+	// ID returns ID of the target from the request
+	ID func(c *gin.Context) string
+	// Load returns current state of the target, nil if it doesn't exist
+	Load func(db *gorm.DB, c *gin.Context, id string) (interface{}, error)
+	// Skip reports whether successful request should not be recorded, such as company editing own post
+	Skip func(db *gorm.DB, c *gin.Context, before interface{}) bool
+}
+
+// Param returns function reading ID of target from path parameter
+func Param(name string) func(c *gin.Context) string {
+	return func(c *gin.Context) string {
+		return c.Param(name)
+	}
+}
+
+// loadFirst loads first record matching the query into dest, returning nil if not found
+func loadFirst(query *gorm.DB, dest interface{}) (interface{}, error) {
+	err := query.First(dest).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return dest, nil
+}
+
+// UserTarget is user with its punishment, identified by user_id path parameter
+var UserTarget = Target{
+	Type: "user",
+	ID:   Param("user_id"),
+	Load: func(db *gorm.DB, _ *gin.Context, id string) (interface{}, error) {
+		return loadFirst(db.Preload("Punishment").Where("id = ?", id), &model.User{})
+	},
+}
+
+// CompanyTarget is company profile, identified by company_id path parameter
+var CompanyTarget = Target{
+	Type: "company",
+	ID:   Param("company_id"),
+	Load: func(db *gorm.DB, _ *gin.Context, id string) (interface{}, error) {
+		return loadFirst(db.Where("user_id = ?", id), &model.CompanyUser{})
+	},
+}
+
+// ReportTarget is report on user or post, identified by type and id path parameters as "<type>/<id>"
+var ReportTarget = Target{
+	Type: "report",
+	ID: func(c *gin.Context) string {
+		return c.Param("type") + "/" + c.Param("id")
+	},
+	Load: func(db *gorm.DB, c *gin.Context, _ string) (interface{}, error) {
+		query := db.Where("id = ?", c.Param("id"))
+		switch c.Param("type") {
+		case "user":
+			return loadFirst(query, &model.ReportOnUser{})
+		case "post":
+			return loadFirst(query, &model.ReportOnPost{})
+		}
+		return nil, nil
+	},
+}
+
+// ReportCaseTarget is every report on reported user or post,
+// identified by type and target_id path parameters as "<type>/<target_id>"
+var ReportCaseTarget = Target{
+	Type: "report_case",
+	ID: func(c *gin.Context) string {
+		return c.Param("type") + "/" + c.Param("target_id")
+	},
+	Load: func(db *gorm.DB, c *gin.Context, _ string) (interface{}, error) {
+		targetID := c.Param("target_id")
+		switch c.Param("type") {
+		case model.ReportTypeUser:
+			if _, err := uuid.Parse(targetID); err != nil {
+				return nil, nil
+			}
+			var reports []model.ReportOnUser
+			err := db.Where("reported_user_id = ?", targetID).Order("id").Find(&reports).Error
+			return reports, err
+		case model.ReportTypePost:
+			if _, err := strconv.ParseUint(targetID, 10, 64); err != nil {
+				return nil, nil
+			}
+			var reports []model.ReportOnPost
+			err := db.Where("reported_post_id = ?", targetID).Order("id").Find(&reports).Error
+			return reports, err
+		}
+		return nil, nil
+	},
+}
+
+// AppealTarget is punishment appeal with its punishment, identified by id path parameter
+var AppealTarget = Target{
+	Type: "appeal",
+	ID:   Param("id"),
+	Load: func(db *gorm.DB, _ *gin.Context, id string) (interface{}, error) {
+		return loadFirst(db.Preload("Punishment").Where("id = ?", id), &model.PunishmentAppeal{})
+	},
+}
+
+// JobPostTarget is job post, identified by id path parameter.
+// Changes made by organization owning the post are not recorded.
+var JobPostTarget = Target{
+	Type: "job_post",
+	ID:   Param("id"),
+	Load: func(db *gorm.DB, _ *gin.Context, id string) (interface{}, error) {
+		return loadFirst(db.Preload("Questions", func(db *gorm.DB) *gorm.DB {
+			return db.Order("position ASC")
+		}).Where("id = ?", id), &model.JobPost{})
+	},
+	Skip: func(db *gorm.DB, c *gin.Context, before interface{}) bool {
+		post, ok := before.(*model.JobPost)
+		if !ok {
+			return false
+		}
+		org, err := policy.MembershipFromContext(c, db)
+		return err == nil && org.IsMember() && org.CompanyID == post.CompanyUserID
+	},
+}
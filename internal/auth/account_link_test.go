@@ -0,0 +1,185 @@
+package auth
+
+import (
+	"HireMeMaybe-backend/internal/database"
+	"HireMeMaybe-backend/internal/model"
+	"HireMeMaybe-backend/internal/utilities"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdateUserIdentity_WritesCreateOnlyColumns(t *testing.T) {
+	user := createPasswordTestUser(t, "identity_update_user")
+
+	// Update through the model skips create only columns
+	assert.NoError(t, testDB.Model(&model.User{}).Where("id = ?", user.ID).Update("google_id", "ignored-gid").Error)
+
+	assert.NoError(t, updateUserIdentity(testDB.DB, user.ID, map[string]interface{}{
+		"email":          "identity.updated@example.com",
+		"email_verified": true,
+	}))
+
+	var updated model.User
+	assert.NoError(t, testDB.Where("id = ?", user.ID).First(&updated).Error)
+	assert.Empty(t, updated.GoogleID)
+	if assert.NotNil(t, updated.Email) {
+		assert.Equal(t, "identity.updated@example.com", *updated.Email)
+	}
+	assert.True(t, updated.EmailVerified)
+}
+
+func TestEmailVerificationFlow(t *testing.T) {
+	user := createPasswordTestUser(t, "verify_email_user")
+	// Start without email like local registration
+	assert.NoError(t, updateUserIdentity(testDB.DB, user.ID, map[string]interface{}{"email": nil}))
+	user.Email = nil
+
+	sender := &recordingSender{}
+	ec := NewEmailVerificationController(testDB, sender)
+
+	rec, _ := callAsUser(t, ec.RequestEmailVerificationHandler, user, map[string]string{"email": "not-an-email"})
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	// Seeded user already own this email
+	rec, _ = callAsUser(t, ec.RequestEmailVerificationHandler, user, map[string]string{"email": *database.TestUserCPSK1.Email})
+	assert.Equal(t, http.StatusConflict, rec.Code)
+
+	rec, _ = callAsUser(t, ec.RequestEmailVerificationHandler, user, map[string]string{"email": "Verify.Me@Example.com"})
+	assert.Equal(t, http.StatusAccepted, rec.Code, rec.Body.String())
+	if !assert.Len(t, sender.sent, 1) {
+		return
+	}
+	assert.Equal(t, "verify.me@example.com", sender.sent[0].To)
+	token := tokenFromMail(t, sender.sent[0], ec.VerifyURL)
+
+	// Email is not assigned before verification
+	var pending model.User
+	assert.NoError(t, testDB.Where("id = ?", user.ID).First(&pending).Error)
+	assert.Nil(t, pending.Email)
+	assert.False(t, pending.EmailVerified)
+
+	rec, _, err := utilities.SimulateAPICall(ec.VerifyEmailHandler, "/email/verify", http.MethodPost, map[string]string{"token": token})
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	var verified model.User
+	assert.NoError(t, testDB.Where("id = ?", user.ID).First(&verified).Error)
+	if assert.NotNil(t, verified.Email) {
+		assert.Equal(t, "verify.me@example.com", *verified.Email)
+	}
+	assert.True(t, verified.EmailVerified)
+
+	// Token is single use
+	rec, _, err = utilities.SimulateAPICall(ec.VerifyEmailHandler, "/email/verify", http.MethodPost, map[string]string{"token": token})
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	// Verified email can be used to log in
+	handler := NewLocalAuthHandler(testDB)
+	rec, _, err = utilities.SimulateAPICall(handler.LocalLoginHandler, "/login", http.MethodPost, map[string]string{
+		"username": "verify.me@example.com",
+		"password": database.TestSeedPassword,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+}
+
+func TestGoogleLoginLinksVerifiedEmail(t *testing.T) {
+	email := "linked.cpsk@example.com"
+	local := model.CPSKUser{
+		User: model.User{
+			ID:            uuid.New(),
+			Username:      "linked_cpsk_user",
+			Email:         &email,
+			EmailVerified: true,
+			Role:          model.RoleCPSK,
+		},
+	}
+	assert.NoError(t, testDB.Create(&local).Error)
+	t.Cleanup(func() { testDB.Delete(&model.User{}, "id = ?", local.User.ID) })
+
+	mockUser := model.GoogleUserInfo{
+		GID:           "google_link_email_123",
+		Email:         "Linked.CPSK@example.com",
+		EmailVerified: true,
+		FirstName:     "Linked",
+	}
+	mockServer := NewMockOAuth2Server([]model.GoogleUserInfo{mockUser})
+	defer mockServer.Close()
+	handler := NewOauthLoginHandler(testDB, mockServer.Config, mockServer.MockInfoEndpoint)
+
+	body, cookie := mockServer.StartLogin(t, handler, model.RoleCPSK, mockUser.GID)
+	rec, resp, err := utilities.SimulateAPICall(handler.CPSKGoogleLoginHandler, "/auth/google/cpsk", http.MethodPost, body, cookie)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	claims := assertValidAccessToken(t, resp)
+	assert.Equal(t, local.User.ID.String(), claims.Subject)
+
+	var count int64
+	assert.NoError(t, testDB.Model(&model.User{}).Where("google_id = ?", mockUser.GID).Count(&count).Error)
+	assert.Equal(t, int64(1), count)
+}
+
+func TestLinkAndUnlinkGoogle(t *testing.T) {
+	user := createPasswordTestUser(t, "link_google_user")
+	other := createPasswordTestUser(t, "link_google_other")
+
+	mockUsers := []model.GoogleUserInfo{
+		{GID: "google_link_123", Email: "link.google@example.com", EmailVerified: true, FirstName: "Link"},
+	}
+	mockServer := NewMockOAuth2Server(mockUsers)
+	defer mockServer.Close()
+	handler := NewOauthLoginHandler(testDB, mockServer.Config, mockServer.MockInfoEndpoint)
+
+	body, cookie := mockServer.StartLogin(t, handler, oauthPurposeLink, mockUsers[0].GID)
+	rec, _ := callAsUser(t, handler.LinkGoogleHandler, user, body, cookie)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	var linked model.User
+	assert.NoError(t, testDB.Where("id = ?", user.ID).First(&linked).Error)
+	assert.Equal(t, mockUsers[0].GID, linked.GoogleID)
+
+	// Same Google account can't be linked to another user
+	body, cookie = mockServer.StartLogin(t, handler, oauthPurposeLink, mockUsers[0].GID)
+	rec, _ = callAsUser(t, handler.LinkGoogleHandler, other, body, cookie)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+
+	rec, _ = callAsUser(t, handler.UnlinkGoogleHandler, linked, nil)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.NoError(t, testDB.Where("id = ?", user.ID).First(&linked).Error)
+	assert.Empty(t, linked.GoogleID)
+}
+
+func TestSetPasswordForGoogleUser(t *testing.T) {
+	user := createPasswordTestUser(t, "set_password_user")
+	assert.NoError(t, testDB.Model(&model.User{}).Where("id = ?", user.ID).Updates(map[string]interface{}{
+		"password":  "",
+		"google_id": "google_set_password_123",
+	}).Error)
+	user.Password = ""
+
+	pc := NewPasswordController(testDB, &recordingSender{})
+
+	// Can't unlink the only way to log in
+	gAuth := NewOauthLoginHandler(testDB, nil, "")
+	user.GoogleID = "google_set_password_123"
+	rec, _ := callAsUser(t, gAuth.UnlinkGoogleHandler, user, nil)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec, _ = callAsUser(t, pc.SetPasswordHandler, user, map[string]string{"password": "short"})
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec, _ = callAsUser(t, pc.SetPasswordHandler, user, map[string]string{"password": "GooglePass123"})
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	// Second call is rejected
+	rec, _ = callAsUser(t, pc.SetPasswordHandler, user, map[string]string{"password": "GooglePass456"})
+	assert.Equal(t, http.StatusConflict, rec.Code)
+
+	var updated model.User
+	assert.NoError(t, testDB.Where("id = ?", user.ID).First(&updated).Error)
+	assert.True(t, utilities.VerifyPassword("GooglePass123", updated.Password))
+}
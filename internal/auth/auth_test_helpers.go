@@ -8,9 +8,11 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"strings"
 	"testing"
 
+	"github.com/golang-jwt/jwt/v4"
 	"golang.org/x/oauth2"
 )
 
@@ -46,6 +48,9 @@ type mockInternalGoogleUserInfo struct {
 	AuthCode       string `json:"-"`
 	AccessToken    string `json:"-"`
 	TokenExchanged bool   `json:"-"`
+	// CodeChallenge and Nonce are from authorization request of the auth code, if any
+	CodeChallenge string `json:"-"`
+	Nonce         string `json:"-"`
 }
 
 // MockOAuth2Server creates a mock OAuth2 server for testing
@@ -144,6 +149,11 @@ func (m *MockOAuth2Server) handleToken(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if foundUser.CodeChallenge != "" && oauth2.S256ChallengeFromVerifier(r.FormValue("code_verifier")) != foundUser.CodeChallenge {
+		http.Error(w, `{"error":"invalid_grant"}`, http.StatusBadRequest)
+		return
+	}
+
 	foundUser.TokenExchanged = true
 
 	// Return access token
@@ -154,6 +164,17 @@ func (m *MockOAuth2Server) handleToken(w http.ResponseWriter, r *http.Request) {
 		"refresh_token": "mock_refresh_token",
 		"scope":         strings.Join(m.Config.Scopes, " "),
 	}
+	if foundUser.Nonce != "" {
+		idToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+			"sub":   foundUser.GID,
+			"nonce": foundUser.Nonce,
+		}).SignedString([]byte("mock_id_token_key"))
+		if err != nil {
+			http.Error(w, "Failed to sign id_token", http.StatusInternalServerError)
+			return
+		}
+		tokenResponse["id_token"] = idToken
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 	err := json.NewEncoder(w).Encode(tokenResponse)
@@ -226,6 +247,56 @@ func (m *MockOAuth2Server) GetAuthCode(gid string) (string, error) {
 	return "", fmt.Errorf("user with GID %s not found", gid)
 }
 
+// StartLogin starts Google sign in for the purpose with the handler and authorizes user with the GID,
+// returning request body with code, state and code verifier and binding cookie for the login or link endpoint.
+func (m *MockOAuth2Server) StartLogin(t *testing.T, handler *OauthLoginHandler, purpose string, gid string) (map[string]string, *http.Cookie) {
+	t.Helper()
+	rec, resp, err := utilities.SimulateAPICall(handler.StartHandler, "/auth/google/start?purpose="+purpose, http.MethodGet, nil)
+	if err != nil || rec.Code != http.StatusOK {
+		t.Fatalf("failed to start Google sign in: %v %s", err, rec.Body.String())
+	}
+
+	authURL, err := url.Parse(resp["auth_url"].(string))
+	if err != nil {
+		t.Fatalf("invalid auth_url: %v", err)
+	}
+	if authURL.Query().Get("state") != resp["state"] {
+		t.Fatalf("auth_url doesn't carry state")
+	}
+
+	authCode, err := m.GetAuthCode(gid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, user := range m.MockUserInfo {
+		if user.GID == gid {
+			user.CodeChallenge = authURL.Query().Get("code_challenge")
+			user.Nonce = authURL.Query().Get("nonce")
+		}
+	}
+
+	return map[string]string{
+		"code":          authCode,
+		"state":         resp["state"].(string),
+		"code_verifier": resp["code_verifier"].(string),
+	}, bindingCookie(t, rec)
+}
+
+// bindingCookie returns OAuth binding cookie set by start endpoint response
+func bindingCookie(t *testing.T, rec *httptest.ResponseRecorder) *http.Cookie {
+	t.Helper()
+	for _, cookie := range rec.Result().Cookies() {
+		if cookie.Name == oauthBindingCookie {
+			if !cookie.HttpOnly {
+				t.Fatalf("binding cookie must be HttpOnly")
+			}
+			return cookie
+		}
+	}
+	t.Fatalf("start response has no binding cookie")
+	return nil
+}
+
 // AddUserInfo adds a new user info to the mock server with auto-generated codes
 func (m *MockOAuth2Server) AddUserInfo(userInfo model.GoogleUserInfo) {
 	index := len(m.MockUserInfo)
@@ -0,0 +1,45 @@
+package auth
+
+import (
+	"HireMeMaybe-backend/internal/database"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Supported value of JWT_BLACKLIST_STORE environment
+const (
+	BlacklistStoreMemory   = "memory"
+	BlacklistStorePostgres = "postgres"
+	BlacklistStoreRedis    = "redis"
+)
+
+const blacklistPurgeInterval = 5 * time.Minute
+
+// NewBlacklistStoreFromEnv creates JwtBlacklistStore chosen by JWT_BLACKLIST_STORE environment.
+// "memory" (default) keep revoked tokens in process, "postgres" use the provided database
+// and "redis" connect to REDIS_URL (e.g. redis://localhost:6379/0).
+func NewBlacklistStoreFromEnv(db *database.DBinstanceStruct) (JwtBlacklistStore, error) {
+	kind := strings.ToLower(strings.TrimSpace(os.Getenv("JWT_BLACKLIST_STORE")))
+
+	switch kind {
+	case "", BlacklistStoreMemory:
+		return NewInMemoryBlacklistStore(), nil
+
+	case BlacklistStorePostgres:
+		return NewPostgresBlacklistStore(db, blacklistPurgeInterval), nil
+
+	case BlacklistStoreRedis:
+		opt, err := redis.ParseURL(os.Getenv("REDIS_URL"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid REDIS_URL: %w", err)
+		}
+		return NewRedisBlacklistStore(redis.NewClient(opt)), nil
+
+	default:
+		return nil, fmt.Errorf("unknown JWT_BLACKLIST_STORE '%s'", kind)
+	}
+}
@@ -0,0 +1,64 @@
+package auth
+
+import (
+	"HireMeMaybe-backend/internal/database"
+	"HireMeMaybe-backend/internal/model"
+	"log"
+	"time"
+
+	"gorm.io/gorm/clause"
+)
+
+// PostgresBlacklistStore is a PostgreSQL implementation of JwtBlacklistStore.
+// Revoked token IDs are shared between every replica using the same database.
+type PostgresBlacklistStore struct {
+	DB *database.DBinstanceStruct
+}
+
+// NewPostgresBlacklistStore creates a new instance of PostgresBlacklistStore and
+// starts purging expired entries every purgeInterval.
+func NewPostgresBlacklistStore(db *database.DBinstanceStruct, purgeInterval time.Duration) *PostgresBlacklistStore {
+	store := &PostgresBlacklistStore{
+		DB: db,
+	}
+	if purgeInterval > 0 {
+		go periodiclyPurge(store, purgeInterval)
+	}
+	return store
+}
+
+func periodiclyPurge(store *PostgresBlacklistStore, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if err := store.CleanUpExpired(); err != nil {
+			log.Printf("failed to purge expired blacklisted tokens: %v", err)
+		}
+	}
+}
+
+// CleanUpExpired removes expired JWT IDs from the blacklist.
+func (s *PostgresBlacklistStore) CleanUpExpired() error {
+	return s.DB.Where("expires_at < ?", time.Now()).Delete(&model.RevokedToken{}).Error
+}
+
+// IsBlacklisted checks if the given JWT ID (jti) is blacklisted.
+func (s *PostgresBlacklistStore) IsBlacklisted(jti string) (bool, error) {
+	var count int64
+	err := s.DB.Model(&model.RevokedToken{}).
+		Where("jti = ? AND expires_at > ?", jti, time.Now()).
+		Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// AddToBlacklist adds the given JWT ID (jti) to the blacklist with an expiration time.
+func (s *PostgresBlacklistStore) AddToBlacklist(jti string, exp time.Time) error {
+	return s.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "jti"}},
+		DoUpdates: clause.AssignmentColumns([]string{"expires_at"}),
+	}).Create(&model.RevokedToken{JTI: jti, ExpiresAt: exp}).Error
+}
@@ -0,0 +1,42 @@
+package auth
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const redisBlacklistPrefix = "jwt:blacklist:"
+
+// RedisBlacklistStore is an implementation of JwtBlacklistStore for any server speaking Redis protocol.
+// Entries expire by Redis TTL so no clean up is needed.
+type RedisBlacklistStore struct {
+	Client redis.UniversalClient
+}
+
+// NewRedisBlacklistStore creates a new instance of RedisBlacklistStore using the provided client.
+func NewRedisBlacklistStore(client redis.UniversalClient) *RedisBlacklistStore {
+	return &RedisBlacklistStore{
+		Client: client,
+	}
+}
+
+// IsBlacklisted checks if the given JWT ID (jti) is blacklisted.
+func (s *RedisBlacklistStore) IsBlacklisted(jti string) (bool, error) {
+	n, err := s.Client.Exists(context.Background(), redisBlacklistPrefix+jti).Result()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+// AddToBlacklist adds the given JWT ID (jti) to the blacklist with an expiration time.
+func (s *RedisBlacklistStore) AddToBlacklist(jti string, exp time.Time) error {
+	ttl := time.Until(exp)
+	if ttl <= 0 {
+		// Token already expired, nothing to revoke
+		return nil
+	}
+	return s.Client.Set(context.Background(), redisBlacklistPrefix+jti, 1, ttl).Err()
+}
@@ -0,0 +1,117 @@
+package auth
+
+import (
+	"HireMeMaybe-backend/internal/model"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestRedisStore(t *testing.T) (*RedisBlacklistStore, *miniredis.Miniredis) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	return NewRedisBlacklistStore(client), mr
+}
+
+func TestRedisBlacklistStore_AddAndCheck(t *testing.T) {
+	store, _ := newTestRedisStore(t)
+
+	isBlacklisted, err := store.IsBlacklisted("redis-token")
+	assert.NoError(t, err)
+	assert.False(t, isBlacklisted)
+
+	assert.NoError(t, store.AddToBlacklist("redis-token", time.Now().Add(time.Hour)))
+
+	isBlacklisted, err = store.IsBlacklisted("redis-token")
+	assert.NoError(t, err)
+	assert.True(t, isBlacklisted)
+}
+
+func TestRedisBlacklistStore_Expire(t *testing.T) {
+	store, mr := newTestRedisStore(t)
+
+	assert.NoError(t, store.AddToBlacklist("short-token", time.Now().Add(time.Minute)))
+	mr.FastForward(2 * time.Minute)
+
+	isBlacklisted, err := store.IsBlacklisted("short-token")
+	assert.NoError(t, err)
+	assert.False(t, isBlacklisted)
+}
+
+func TestRedisBlacklistStore_AlreadyExpired(t *testing.T) {
+	store, _ := newTestRedisStore(t)
+
+	assert.NoError(t, store.AddToBlacklist("expired-token", time.Now().Add(-time.Minute)))
+
+	isBlacklisted, err := store.IsBlacklisted("expired-token")
+	assert.NoError(t, err)
+	assert.False(t, isBlacklisted)
+}
+
+func TestRedisBlacklistStore_ConnectionError(t *testing.T) {
+	store, mr := newTestRedisStore(t)
+	mr.Close()
+
+	_, err := store.IsBlacklisted("any-token")
+	assert.Error(t, err)
+}
+
+func TestPostgresBlacklistStore_AddAndCheck(t *testing.T) {
+	store := NewPostgresBlacklistStore(testDB, 0)
+
+	isBlacklisted, err := store.IsBlacklisted("pg-token")
+	assert.NoError(t, err)
+	assert.False(t, isBlacklisted)
+
+	assert.NoError(t, store.AddToBlacklist("pg-token", time.Now().Add(time.Hour)))
+	// Adding same jti again only update expiry
+	assert.NoError(t, store.AddToBlacklist("pg-token", time.Now().Add(2*time.Hour)))
+
+	isBlacklisted, err = store.IsBlacklisted("pg-token")
+	assert.NoError(t, err)
+	assert.True(t, isBlacklisted)
+}
+
+func TestPostgresBlacklistStore_CleanUpExpired(t *testing.T) {
+	store := NewPostgresBlacklistStore(testDB, 0)
+
+	assert.NoError(t, store.AddToBlacklist("pg-expired-token", time.Now().Add(-time.Minute)))
+
+	isBlacklisted, err := store.IsBlacklisted("pg-expired-token")
+	assert.NoError(t, err)
+	assert.False(t, isBlacklisted)
+
+	assert.NoError(t, store.CleanUpExpired())
+
+	var count int64
+	testDB.Model(&model.RevokedToken{}).Where("jti = ?", "pg-expired-token").Count(&count)
+	assert.Equal(t, int64(0), count)
+}
+
+func TestNewBlacklistStoreFromEnv(t *testing.T) {
+	t.Setenv("JWT_BLACKLIST_STORE", "")
+	store, err := NewBlacklistStoreFromEnv(testDB)
+	assert.NoError(t, err)
+	assert.IsType(t, &InMemoryBlacklistStore{}, store)
+
+	t.Setenv("JWT_BLACKLIST_STORE", "postgres")
+	store, err = NewBlacklistStoreFromEnv(testDB)
+	assert.NoError(t, err)
+	assert.IsType(t, &PostgresBlacklistStore{}, store)
+
+	mr := miniredis.RunT(t)
+	t.Setenv("JWT_BLACKLIST_STORE", "redis")
+	t.Setenv("REDIS_URL", "redis://"+mr.Addr())
+	store, err = NewBlacklistStoreFromEnv(testDB)
+	assert.NoError(t, err)
+	assert.IsType(t, &RedisBlacklistStore{}, store)
+
+	t.Setenv("JWT_BLACKLIST_STORE", "unknown")
+	_, err = NewBlacklistStoreFromEnv(testDB)
+	assert.Error(t, err)
+}
@@ -0,0 +1,85 @@
+package auth
+
+import (
+	"HireMeMaybe-backend/internal/model"
+	"os"
+	"strings"
+)
+
+// Supported value of EMAIL_DOMAIN_MISMATCH environment
+const (
+	DomainMismatchReject    = "reject"
+	DomainMismatchDowngrade = "downgrade"
+)
+
+// EmailDomainPolicy restricts which Google accounts can sign in as each role by their email domain.
+// Role without entry in AllowedDomains accept any account.
+type EmailDomainPolicy struct {
+	AllowedDomains map[string][]string
+	// Downgrade sign in non-matching account as visitor instead of rejecting it
+	Downgrade bool
+}
+
+// NewEmailDomainPolicyFromEnv creates EmailDomainPolicy from comma-separated
+// CPSK_ALLOWED_EMAIL_DOMAINS and COMPANY_ALLOWED_EMAIL_DOMAINS environment (e.g. "ku.th"),
+// non-matching account is rejected unless EMAIL_DOMAIN_MISMATCH is "downgrade".
+func NewEmailDomainPolicyFromEnv() *EmailDomainPolicy {
+	policy := &EmailDomainPolicy{
+		AllowedDomains: map[string][]string{},
+		Downgrade:      strings.ToLower(strings.TrimSpace(os.Getenv("EMAIL_DOMAIN_MISMATCH"))) == DomainMismatchDowngrade,
+	}
+
+	for role, env := range map[string]string{
+		model.RoleCPSK:    "CPSK_ALLOWED_EMAIL_DOMAINS",
+		model.RoleCompany: "COMPANY_ALLOWED_EMAIL_DOMAINS",
+	} {
+		var domains []string
+		for _, d := range strings.Split(os.Getenv(env), ",") {
+			if d = strings.ToLower(strings.TrimSpace(d)); d != "" {
+				domains = append(domains, d)
+			}
+		}
+		if len(domains) > 0 {
+			policy.AllowedDomains[role] = domains
+		}
+	}
+	return policy
+}
+
+// Allows reports whether the Google account can sign in as the role, and the reason if it can't.
+// Account matches when its hosted domain (hd) or verified email is in an allowed domain or its subdomain.
+func (p *EmailDomainPolicy) Allows(role string, uInfo model.GoogleUserInfo) (bool, string) {
+	if p == nil {
+		return true, ""
+	}
+	domains, ok := p.AllowedDomains[role]
+	if !ok {
+		return true, ""
+	}
+
+	if uInfo.HostedDomain != "" && domainAllowed(uInfo.HostedDomain, domains) {
+		return true, ""
+	}
+
+	at := strings.LastIndex(uInfo.Email, "@")
+	if at < 0 {
+		return false, "email is missing"
+	}
+	if !domainAllowed(uInfo.Email[at+1:], domains) {
+		return false, "email domain " + strings.ToLower(uInfo.Email[at+1:]) + " is not allowed for role " + role
+	}
+	if !uInfo.EmailVerified {
+		return false, "email is not verified by Google"
+	}
+	return true, ""
+}
+
+func domainAllowed(domain string, allowed []string) bool {
+	domain = strings.ToLower(strings.TrimSpace(domain))
+	for _, a := range allowed {
+		if domain == a || strings.HasSuffix(domain, "."+a) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,82 @@
+package auth
+
+import (
+	"HireMeMaybe-backend/internal/model"
+	"HireMeMaybe-backend/internal/utilities"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEmailDomainPolicyAllows(t *testing.T) {
+	policy := &EmailDomainPolicy{AllowedDomains: map[string][]string{model.RoleCPSK: {"ku.th"}}}
+
+	cases := []struct {
+		name    string
+		info    model.GoogleUserInfo
+		allowed bool
+	}{
+		{"verified ku email", model.GoogleUserInfo{Email: "student@ku.th", EmailVerified: true}, true},
+		{"subdomain", model.GoogleUserInfo{Email: "staff@cpe.ku.th", EmailVerified: true}, true},
+		{"upper case", model.GoogleUserInfo{Email: "Student@KU.TH", EmailVerified: true}, true},
+		{"hosted domain", model.GoogleUserInfo{Email: "student@ku.th", HostedDomain: "ku.th"}, true},
+		{"unverified email", model.GoogleUserInfo{Email: "student@ku.th"}, false},
+		{"gmail", model.GoogleUserInfo{Email: "student@gmail.com", EmailVerified: true}, false},
+		{"lookalike domain", model.GoogleUserInfo{Email: "student@evilku.th", EmailVerified: true}, false},
+		{"missing email", model.GoogleUserInfo{}, false},
+	}
+	for _, tc := range cases {
+		allowed, reason := policy.Allows(model.RoleCPSK, tc.info)
+		assert.Equal(t, tc.allowed, allowed, tc.name)
+		if !tc.allowed {
+			assert.NotEmpty(t, reason, tc.name)
+		}
+	}
+
+	// Role without allow-list accept anyone
+	allowed, _ := policy.Allows(model.RoleVisitor, model.GoogleUserInfo{Email: "someone@gmail.com"})
+	assert.True(t, allowed)
+}
+
+func TestNewEmailDomainPolicyFromEnv(t *testing.T) {
+	t.Setenv("CPSK_ALLOWED_EMAIL_DOMAINS", " KU.TH, ku.ac.th ,")
+	t.Setenv("COMPANY_ALLOWED_EMAIL_DOMAINS", "")
+	t.Setenv("EMAIL_DOMAIN_MISMATCH", "downgrade")
+
+	policy := NewEmailDomainPolicyFromEnv()
+	assert.Equal(t, []string{"ku.th", "ku.ac.th"}, policy.AllowedDomains[model.RoleCPSK])
+	assert.NotContains(t, policy.AllowedDomains, model.RoleCompany)
+	assert.True(t, policy.Downgrade)
+}
+
+func TestCPSKGoogleLoginDomainMismatch(t *testing.T) {
+	mockUsers := []model.GoogleUserInfo{
+		{GID: "google_domain_reject", Email: "outsider@gmail.com", EmailVerified: true, FirstName: "Outsider"},
+		{GID: "google_domain_downgrade", Email: "another@gmail.com", EmailVerified: true, FirstName: "Another"},
+	}
+	mockServer := NewMockOAuth2Server(mockUsers)
+	defer mockServer.Close()
+
+	handler := NewOauthLoginHandler(testDB, mockServer.Config, mockServer.MockInfoEndpoint)
+	handler.DomainPolicy = &EmailDomainPolicy{AllowedDomains: map[string][]string{model.RoleCPSK: {"ku.th"}}}
+
+	body, cookie := mockServer.StartLogin(t, handler, model.RoleCPSK, mockUsers[0].GID)
+	rec, _, err := utilities.SimulateAPICall(handler.CPSKGoogleLoginHandler, "/auth/google/cpsk", http.MethodPost, body, cookie)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusForbidden, rec.Code, rec.Body.String())
+
+	var count int64
+	assert.NoError(t, testDB.Model(&model.User{}).Where("google_id = ?", mockUsers[0].GID).Count(&count).Error)
+	assert.Zero(t, count)
+
+	handler.DomainPolicy.Downgrade = true
+	body, cookie = mockServer.StartLogin(t, handler, model.RoleCPSK, mockUsers[1].GID)
+	rec, _, err = utilities.SimulateAPICall(handler.CPSKGoogleLoginHandler, "/auth/google/cpsk", http.MethodPost, body, cookie)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+
+	var user model.User
+	assert.NoError(t, testDB.Where("google_id = ?", mockUsers[1].GID).First(&user).Error)
+	assert.Equal(t, model.RoleVisitor, user.Role)
+}
@@ -0,0 +1,227 @@
+package auth
+
+import (
+	"HireMeMaybe-backend/internal/database"
+	"HireMeMaybe-backend/internal/mail"
+	"HireMeMaybe-backend/internal/model"
+	"HireMeMaybe-backend/internal/utilities"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// EmailVerificationTokenDuration is how long email verification link stay valid
+const EmailVerificationTokenDuration = 24 * time.Hour
+
+var (
+	errInvalidVerificationToken = errors.New("invalid or expired verification token")
+	errEmailTaken               = errors.New("email is already used by another account")
+)
+
+// EmailVerificationController handles adding and verifying email of local accounts
+type EmailVerificationController struct {
+	DB     *database.DBinstanceStruct
+	Mailer mail.Sender
+	// VerifyURL is frontend page receiving verification token as "token" query parameter
+	VerifyURL string
+}
+
+// NewEmailVerificationController creates a new instance of EmailVerificationController.
+// Verification link point to EMAIL_VERIFY_URL environment.
+func NewEmailVerificationController(db *database.DBinstanceStruct, mailer mail.Sender) *EmailVerificationController {
+	verifyURL := os.Getenv("EMAIL_VERIFY_URL")
+	if verifyURL == "" {
+		verifyURL = "http://localhost:3000/verify-email"
+	}
+	return &EmailVerificationController{
+		DB:        db,
+		Mailer:    mailer,
+		VerifyURL: verifyURL,
+	}
+}
+
+type emailRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+type verifyEmailRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// emailTaken reports whether other user than exceptID already has the email
+func emailTaken(tx *gorm.DB, email string, exceptID uuid.UUID) (bool, error) {
+	var count int64
+	err := tx.Model(&model.User{}).Where("LOWER(email) = ? AND id <> ?", normalizeEmail(email), exceptID).Count(&count).Error
+	return count > 0, err
+}
+
+// updateUserIdentity writes email or google_id columns of user.
+// They are create only in User model so profile saves can't change them,
+// which also makes GORM skip them on update through the model, so they are written to the table directly.
+func updateUserIdentity(tx *gorm.DB, userID uuid.UUID, columns map[string]interface{}) error {
+	columns["updated_at"] = time.Now()
+	return tx.Table("users").Where("id = ?", userID).Updates(columns).Error
+}
+
+// RequestEmailVerificationHandler sends verification link to the email to be added to current user
+// @Summary Add or change email of current user
+// @Description Email is assigned to the user after the link sent to it is used with /auth/email/verify
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Insert your access token" default(Bearer <your access token>)
+// @Param Email body emailRequest true "Email to verify"
+// @Success 202 {object} utilities.MessageResponse "Verification link sent"
+// @Failure 400 {object} utilities.ErrorResponse "Email is not provided or invalid"
+// @Failure 401 {object} utilities.ErrorResponse "Unauthorized"
+// @Failure 409 {object} utilities.ErrorResponse "Email is already used by another account"
+// @Failure 500 {object} utilities.ErrorResponse "Database or mail error"
+// @Router /auth/email [post]
+func (ec *EmailVerificationController) RequestEmailVerificationHandler(c *gin.Context) {
+	user, err := utilities.ExtractUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, utilities.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	var req emailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utilities.ErrorResponse{Error: "Valid email must be provided"})
+		return
+	}
+	email := normalizeEmail(req.Email)
+
+	taken, err := emailTaken(ec.DB.DB, email, user.ID)
+	if err != nil {
+		utilities.RespondDBError(c, err)
+		return
+	}
+	if taken {
+		c.JSON(http.StatusConflict, utilities.ErrorResponse{Error: "Email is already used by another account"})
+		return
+	}
+
+	raw, err := NewOpaqueToken()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utilities.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	err = ec.DB.Transaction(func(tx *gorm.DB) error {
+		// Only the latest link can be used
+		if err := tx.Where("user_id = ?", user.ID).Delete(&model.EmailVerificationToken{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(&model.EmailVerificationToken{
+			UserID:    user.ID,
+			Email:     email,
+			TokenHash: HashToken(raw),
+			ExpiresAt: time.Now().Add(EmailVerificationTokenDuration),
+		}).Error
+	})
+	if err != nil {
+		utilities.RespondDBError(c, err)
+		return
+	}
+
+	link := ec.VerifyURL + "?token=" + url.QueryEscape(raw)
+	err = ec.Mailer.Send(mail.Message{
+		To:      email,
+		Subject: "Verify your HireMeMaybe email",
+		Body: fmt.Sprintf("Hi %s,\n\nUse the link below to verify your email. The link expires in %d hours.\n\n%s\n\nIf you did not request this, you can ignore this email.",
+			user.Username, int(EmailVerificationTokenDuration.Hours()), link),
+	})
+	if err != nil {
+		LogAuthAttempt("error", "Local", "Fail", user.Username, fmt.Sprintf("Failed to send verification email: %s", err.Error()))
+		c.JSON(http.StatusInternalServerError, utilities.ErrorResponse{Error: "Failed to send verification email"})
+		return
+	}
+
+	LogAuthAttempt("info", "Local", "Success", user.Username, "Email verification link sent")
+	c.JSON(http.StatusAccepted, utilities.MessageResponse{Message: "Verification link has been sent to the email"})
+}
+
+// VerifyEmailHandler assigns verified email to the user owning the verification token
+// @Summary Verify email
+// @Description Verification token can be used once
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param Token body verifyEmailRequest true "Token from verification link"
+// @Success 200 {object} utilities.MessageResponse "Email verified"
+// @Failure 400 {object} utilities.ErrorResponse "Token is not provided, invalid or expired"
+// @Failure 409 {object} utilities.ErrorResponse "Email is already used by another account"
+// @Failure 500 {object} utilities.ErrorResponse "Database error"
+// @Router /auth/email/verify [post]
+func (ec *EmailVerificationController) VerifyEmailHandler(c *gin.Context) {
+	var req verifyEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utilities.ErrorResponse{Error: "Token must be provided"})
+		return
+	}
+
+	var record model.EmailVerificationToken
+	err := ec.DB.Transaction(func(tx *gorm.DB) error {
+		err := tx.Preload("User").
+			Where("token_hash = ? AND expires_at > ?", HashToken(req.Token), time.Now()).
+			First(&record).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errInvalidVerificationToken
+		}
+		if err != nil {
+			return err
+		}
+
+		result := tx.Delete(&model.EmailVerificationToken{}, record.ID)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			// Used by another request
+			return errInvalidVerificationToken
+		}
+
+		taken, err := emailTaken(tx, record.Email, record.UserID)
+		if err != nil {
+			return err
+		}
+		if taken {
+			return errEmailTaken
+		}
+
+		return updateUserIdentity(tx, record.UserID, map[string]interface{}{
+			"email":          record.Email,
+			"email_verified": true,
+		})
+	})
+
+	switch {
+	case errors.Is(err, errInvalidVerificationToken):
+		LogAuthAttempt("warning", "Local", "Fail", "", "Invalid email verification token")
+		c.JSON(http.StatusBadRequest, utilities.ErrorResponse{Error: "Verification token is invalid or expired"})
+		return
+
+	case errors.Is(err, errEmailTaken):
+		c.JSON(http.StatusConflict, utilities.ErrorResponse{Error: "Email is already used by another account"})
+		return
+
+	case err != nil:
+		utilities.RespondDBError(c, err)
+		return
+	}
+
+	LogAuthAttempt("info", "Local", "Success", record.User.Username, "Email verified")
+	c.JSON(http.StatusOK, utilities.MessageResponse{Message: "Email verified"})
+}
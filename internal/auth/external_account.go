@@ -0,0 +1,195 @@
+package auth
+
+import (
+	"HireMeMaybe-backend/internal/database"
+	"HireMeMaybe-backend/internal/model"
+	"HireMeMaybe-backend/internal/utilities"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ProviderGoogle is provider name of Google sign-in, its subject is stored in User.GoogleID
+const ProviderGoogle = "google"
+
+// externalAccount is account of identity provider signing in.
+// Profile carries standard OpenID Connect profile claims, its GID is only set for Google.
+type externalAccount struct {
+	Provider string
+	Subject  string
+	Profile  model.GoogleUserInfo
+}
+
+func (a externalAccount) authType() string {
+	if a.Provider == ProviderGoogle {
+		return "Google"
+	}
+	return "OIDC:" + a.Provider
+}
+
+// findExternalUser finds user linked to the account.
+func findExternalUser(db *gorm.DB, account externalAccount, user *model.User) error {
+	if account.Provider == ProviderGoogle {
+		return db.Preload("Punishment").Where("google_id = ?", account.Subject).First(user).Error
+	}
+	return db.Preload("Punishment").
+		Where("id IN (?)", db.Model(&model.UserIdentity{}).Select("user_id").
+			Where("provider = ? AND subject = ?", account.Provider, account.Subject)).
+		First(user).Error
+}
+
+// linkExternalAccount links the account to the user.
+func linkExternalAccount(tx *gorm.DB, account externalAccount, userID uuid.UUID) error {
+	if account.Provider == ProviderGoogle {
+		return updateUserIdentity(tx, userID, map[string]interface{}{"google_id": account.Subject})
+	}
+	return tx.Create(&model.UserIdentity{
+		UserID:   userID,
+		Provider: account.Provider,
+		Subject:  account.Subject,
+		Email:    account.Profile.Email,
+	}).Error
+}
+
+// linkByVerifiedEmail links the account to existing user with the same verified email
+// and no account of the same provider, so signing in doesn't create duplicate account.
+// Email of the account must be verified by trusted provider, see OIDCProviderConfig.TrustEmail.
+// gorm.ErrRecordNotFound is returned if there is no such user.
+func linkByVerifiedEmail(db *gorm.DB, account externalAccount, user *model.User) error {
+	query := db.Preload("Punishment").
+		Where("LOWER(email) = ? AND email_verified = ?", normalizeEmail(account.Profile.Email), true)
+	if account.Provider == ProviderGoogle {
+		query = query.Where("(google_id = '' OR google_id IS NULL)")
+	} else {
+		query = query.Where("NOT EXISTS (?)", db.Model(&model.UserIdentity{}).Select("1").
+			Where("user_identities.user_id = users.id AND provider = ?", account.Provider))
+	}
+	if err := query.First(user).Error; err != nil {
+		return err
+	}
+
+	if err := linkExternalAccount(db, account, user.ID); err != nil {
+		return err
+	}
+	if account.Provider == ProviderGoogle {
+		user.GoogleID = account.Subject
+	}
+
+	LogAuthAttempt("info", account.authType(), "Success", account.Profile.Email, fmt.Sprintf("Account linked to %s by verified email", user.Username))
+	return nil
+}
+
+// signInExternalUser logs in user linked to the account, or registers a new userModel for it,
+// and responds with token pair. User with two-factor authentication gets login challenge instead,
+// the same as local login.
+func signInExternalUser(c *gin.Context, db *database.DBinstanceStruct, policy *EmailDomainPolicy, requireAdmin2FA bool, account externalAccount, userModel model.UserModel) {
+	authType := account.authType()
+	uinfo := account.Profile
+
+	if allowed, reason := policy.Allows(userModel.GetRole(), uinfo); !allowed {
+		if !policy.Downgrade {
+			LogAuthAttempt("warning", authType, "Fail", uinfo.Email, "Rejected: "+reason)
+			c.JSON(http.StatusForbidden, utilities.ErrorResponse{
+				Error: fmt.Sprintf("This account can't sign in as %s: %s", userModel.GetRole(), reason),
+			})
+			return
+		}
+		LogAuthAttempt("warning", authType, "Fail", uinfo.Email, "Downgraded to visitor: "+reason)
+		userModel = &model.VisitorUser{}
+	}
+
+	var user model.User
+	respStatus := http.StatusOK
+
+	err := findExternalUser(db.DB, account, &user)
+	if errors.Is(err, gorm.ErrRecordNotFound) && uinfo.EmailVerified && uinfo.Email != "" {
+		err = linkByVerifiedEmail(db.DB, account, &user)
+	}
+
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+
+		userModel.FillGoogleInfo(uinfo)
+
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Create(userModel).Error; err != nil {
+				return err
+			}
+			if account.Provider == ProviderGoogle {
+				// Already stored by FillGoogleInfo
+				return nil
+			}
+			return linkExternalAccount(tx, account, userModel.GetID())
+		})
+		if err != nil {
+			LogAuthAttempt("error", authType, "Fail", uinfo.Email, "Failed to create user")
+			c.JSON(http.StatusInternalServerError, utilities.ErrorResponse{
+				Error: fmt.Sprintf("Failed to create user: %v", err.Error()),
+			})
+			return
+		}
+
+		respStatus = http.StatusCreated
+	case err == nil:
+
+		if msg, status, err := database.RemovePunishment(&user, db); err != nil {
+			if status == http.StatusInternalServerError {
+				LogAuthAttempt("error", authType, "Fail", uinfo.Email, "RemovePunishment failed")
+				c.JSON(http.StatusInternalServerError, utilities.ErrorResponse{
+					Error: msg,
+				})
+				return
+			}
+		}
+
+		if err := db.Preload("User").Preload("User.Punishment").Where("user_id = ?", user.ID).First(userModel).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				LogAuthAttempt("error", authType, "Fail", uinfo.Email, "User type mismatch")
+				c.JSON(http.StatusInternalServerError, utilities.ErrorResponse{
+					Error: "You already registered as a different user type",
+				})
+				return
+			}
+			LogAuthAttempt("error", authType, "Fail", uinfo.Email, "Failed to retrieve user data")
+			c.JSON(http.StatusInternalServerError, utilities.ErrorResponse{
+				Error: fmt.Sprintf("Failed to retrieve user data: %v", err.Error()),
+			})
+			return
+		}
+
+		required, err := twoFactorRequired(db.DB, user, requireAdmin2FA)
+		if err != nil {
+			LogAuthAttempt("error", authType, "Fail", uinfo.Email, "Failed to retrieve two-factor setting")
+			utilities.RespondDBError(c, err)
+			return
+		}
+		if required {
+			// Identity provider only replaces the password, second factor is still required
+			startTwoFactorChallenge(c, db.DB, user, authType)
+			return
+		}
+	default:
+		utilities.RespondDBError(c, err)
+		return
+	}
+
+	accessToken, refreshToken, err := GenerateStandardToken(db, userModel.GetID())
+	if err != nil {
+		LogAuthAttempt("error", authType, "Fail", uinfo.Email, "Failed to generate access token")
+		c.JSON(http.StatusInternalServerError, utilities.ErrorResponse{
+			Error: fmt.Sprintf("Failed to generate access token: %s", err.Error()),
+		})
+		return
+	}
+
+	// successful OAuth login/register
+	LogAuthAttempt("info", authType, "Success", uinfo.Email, "OAuth authenticated")
+
+	resp := userModel.GetLoginResponse(accessToken, refreshToken)
+
+	c.JSON(respStatus, resp)
+}
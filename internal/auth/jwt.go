@@ -1,6 +1,7 @@
 package auth
 
 import (
+	"HireMeMaybe-backend/internal/database"
 	"fmt"
 	"os"
 	"time"
@@ -16,38 +17,66 @@ var (
 	secretKey = os.Getenv("SECRET_KEY")
 	// JwtIssuer is the issuer field in the JWT token.
 	JwtIssuer = os.Getenv("JWT_ISSUER")
+
+	// tokenKeys sign and verify access tokens, HS256 with SECRET_KEY until UseKeySet is called
+	tokenKeys = &KeySet{hmacSecret: []byte(secretKey), acceptHS256: true, keys: map[string]*SigningKey{}}
 )
 
-// GenerateStandardToken creates a JWT with default 1 hour duration.
-func GenerateStandardToken(uuid uuid.UUID) (string, string, error) {
+// UseKeySet sets keys signing and verifying access tokens. It must be called before serving requests.
+func UseKeySet(ks *KeySet) {
+	tokenKeys = ks
+}
+
+// Claims is the claims of access token issued by this service.
+// SessionID refer to the refresh token family the access token was issued with.
+type Claims struct {
+	jwt.RegisteredClaims
+	SessionID string `json:"sid,omitempty"`
+}
+
+// GenerateStandardToken creates a JWT with default 1 hour duration and a refresh token
+// that starts a new session.
+func GenerateStandardToken(db *database.DBinstanceStruct, id uuid.UUID) (string, string, error) {
+	familyID := uuid.New()
+
+	refreshToken, err := NewRefreshTokenStore(db).Issue(id, familyID)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to issue refresh token: %w", err)
+	}
 
-	return GenerateTokenWithDuration(uuid, time.Hour, JwtIssuer)
+	accessToken, err := generateAccessToken(id, familyID.String(), time.Hour, JwtIssuer)
+	if err != nil {
+		return "", "", err
+	}
 
+	return accessToken, refreshToken, nil
 }
 
 // GenerateTokenWithDuration creates a JWT that expires after the provided duration (can be negative for tests).
-func GenerateTokenWithDuration(id uuid.UUID, d time.Duration, issuer string) (string, string, error) {
+// The token is not bound to any refresh session.
+func GenerateTokenWithDuration(id uuid.UUID, d time.Duration, issuer string) (string, error) {
+	return generateAccessToken(id, "", d, issuer)
+}
+
+func generateAccessToken(id uuid.UUID, sessionID string, d time.Duration, issuer string) (string, error) {
 	exp := time.Now().Add(d)
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
-		Issuer:    issuer,
-		Subject:   id.String(),
-		ExpiresAt: jwt.NewNumericDate(exp),
-		IssuedAt:  jwt.NewNumericDate(time.Now()),
+	signed, err := tokenKeys.Sign(Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			Issuer:    issuer,
+			Subject:   id.String(),
+			ExpiresAt: jwt.NewNumericDate(exp),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
+		SessionID: sessionID,
 	})
-	signed, err := token.SignedString([]byte(secretKey))
 	if err != nil {
-		return "", "", fmt.Errorf("failed to sign token: %w", err)
+		return "", fmt.Errorf("failed to sign token: %w", err)
 	}
-	return signed, "", nil
+	return signed, nil
 }
 
-// ValidatedToken parses and validates a JWT token using a secret key.
+// ValidatedToken parses and validates a JWT token using key of its algorithm and kid.
 func ValidatedToken(encodeToken string) (*jwt.Token, error) {
-	return jwt.ParseWithClaims(encodeToken, &jwt.RegisteredClaims{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("invalid token")
-
-		}
-		return []byte(secretKey), nil
-	})
+	return jwt.ParseWithClaims(encodeToken, &Claims{}, tokenKeys.Keyfunc)
 }
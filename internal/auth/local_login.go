@@ -6,9 +6,12 @@ import (
 	"HireMeMaybe-backend/internal/utilities"
 	"errors"
 	"fmt"
+	"math"
 	"net/http"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
@@ -16,13 +19,18 @@ import (
 
 // LocalRegisterHandler holds DB reference for handler methods.
 type LocalRegisterHandler struct {
-	DB *database.DBinstanceStruct
+	DB    *database.DBinstanceStruct
+	Guard *LoginGuard
+	// RequireAdmin2FA forces admin to log in with TOTP, admin without TOTP must enroll on next login
+	RequireAdmin2FA bool
 }
 
 // NewLocalAuthHandler creates a new instance of LocalRegisterHandler with the provided database connection.
 func NewLocalAuthHandler(db *database.DBinstanceStruct) *LocalRegisterHandler {
 	return &LocalRegisterHandler{
-		DB: db,
+		DB:              db,
+		Guard:           NewLoginGuard(db),
+		RequireAdmin2FA: requireAdmin2FAFromEnv(),
 	}
 }
 
@@ -38,8 +46,9 @@ type loginInfo struct {
 }
 
 type adminResponse struct {
-	User        model.User `json:"user"`
-	AccessToken string     `json:"access_token"`
+	User         model.User `json:"user"`
+	AccessToken  string     `json:"access_token"`
+	RefreshToken string     `json:"refresh_token"`
 }
 
 // LocalRegisterHandler function handles local registration by receiving username and password
@@ -85,9 +94,9 @@ func (lh *LocalRegisterHandler) LocalRegisterHandler(c *gin.Context) {
 		return
 	}
 
-	if len(info.Password) < 8 {
+	if len(info.Password) < minPasswordLength {
 		c.JSON(http.StatusBadRequest, utilities.ErrorResponse{
-			Error: "Password should longer or equal to 8 characters",
+			Error: errPasswordTooShort,
 		})
 		return
 	}
@@ -116,7 +125,7 @@ func (lh *LocalRegisterHandler) LocalRegisterHandler(c *gin.Context) {
 			return
 		}
 
-		accessToken, _, err := GenerateStandardToken(cpskUser.UserID)
+		accessToken, refreshToken, err := GenerateStandardToken(lh.DB, cpskUser.UserID)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, utilities.ErrorResponse{
 				Error: fmt.Sprintf("Failed to generate access token: %s", err.Error()),
@@ -125,8 +134,9 @@ func (lh *LocalRegisterHandler) LocalRegisterHandler(c *gin.Context) {
 		}
 
 		c.JSON(http.StatusCreated, model.CPSKResponse{
-			User:        cpskUser,
-			AccessToken: accessToken,
+			User:         cpskUser,
+			AccessToken:  accessToken,
+			RefreshToken: refreshToken,
 		})
 	case "company":
 		verified := model.StatusPending
@@ -149,7 +159,7 @@ func (lh *LocalRegisterHandler) LocalRegisterHandler(c *gin.Context) {
 			return
 		}
 
-		accessToken, _, err := GenerateStandardToken(companyUser.UserID)
+		accessToken, refreshToken, err := GenerateStandardToken(lh.DB, companyUser.UserID)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, utilities.ErrorResponse{
 				Error: fmt.Sprintf("Failed to generate access token: %s", err.Error()),
@@ -158,8 +168,9 @@ func (lh *LocalRegisterHandler) LocalRegisterHandler(c *gin.Context) {
 		}
 
 		c.JSON(http.StatusCreated, model.CompanyResponse{
-			User:        companyUser,
-			AccessToken: accessToken,
+			User:         companyUser,
+			AccessToken:  accessToken,
+			RefreshToken: refreshToken,
 		})
 	default:
 		c.JSON(http.StatusBadRequest, utilities.ErrorResponse{
@@ -172,15 +183,17 @@ func (lh *LocalRegisterHandler) LocalRegisterHandler(c *gin.Context) {
 // do nothing if username does not exist in the database
 // do nothing if password is incorrect
 // @Summary Handles local login by receiving username and password
-// @Description Username must exist and password match
+// @Description Username (or verified email) must exist and password match
 // @Tags Auth
 // @Accept json
 // @Produce json
 // @Param Info body loginInfo true "Credentials for login"
 // @Success 200 {object} model.CompanyResponse "If role is company"
 // @Success 200 {object} model.CPSKResponse "If role is cpsk"
+// @Success 202 {object} twoFactorChallengeResponse "Password accepted, finish login with /auth/2fa/verify"
 // @Failure 400 {object} utilities.ErrorResponse "Info provided not met the condition"
 // @Failure 401 {object} utilities.ErrorResponse "Username not exist or password incorrect"
+// @Failure 429 {object} utilities.ErrorResponse "Too many failed attempts, username or IP is locked out"
 // @Failure 500 {object} utilities.ErrorResponse "Database or password hashing error"
 // @Router /auth/login [post]
 func (lh *LocalRegisterHandler) LocalLoginHandler(c *gin.Context) {
@@ -194,12 +207,33 @@ func (lh *LocalRegisterHandler) LocalLoginHandler(c *gin.Context) {
 		return
 	}
 
+	lockedUntil, err := lh.Guard.LockedUntil(UsernameLockoutKey(info.Username), IPLockoutKey(c.ClientIP()))
+	if err != nil {
+		LogAuthAttempt("error", "Local", "Fail", info.Username, fmt.Sprintf("Database error: %s", err.Error()))
+		utilities.RespondDBError(c, err)
+		return
+	}
+	if !lockedUntil.IsZero() {
+		LogAuthAttempt("warning", "Local", "Fail", info.Username, fmt.Sprintf("Locked out until %s, IP %s", lockedUntil.UTC().Format(time.RFC3339), c.ClientIP()))
+		respondLockedOut(c, lockedUntil)
+		return
+	}
+
 	var user model.User
-	err := lh.DB.Preload("Punishment").Where("username = ?", info.Username).First(&user).Error
+	err = lh.DB.Preload("Punishment").Where("username = ?", info.Username).First(&user).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) && strings.Contains(info.Username, "@") {
+		// Account linked from Google may log in with its verified email
+		err = lh.DB.Preload("Punishment").
+			Where("LOWER(email) = ? AND email_verified = ?", normalizeEmail(info.Username), true).
+			First(&user).Error
+	}
 
 	switch {
 	case errors.Is(err, gorm.ErrRecordNotFound):
 		LogAuthAttempt("warning", "Local", "Fail", info.Username, "Username not found")
+		if lh.recordLoginFailure(c, info.Username, "") {
+			return
+		}
 		c.JSON(http.StatusUnauthorized, utilities.ErrorResponse{
 			Error: "Username or password is incorrect",
 		})
@@ -214,7 +248,22 @@ func (lh *LocalRegisterHandler) LocalLoginHandler(c *gin.Context) {
 		return
 	}
 
-	if msg, status, err := database.RemovePunishment(user, lh.DB); err != nil {
+	if user.Username != info.Username {
+		// Logging in with email must not bypass lockout of the username
+		lockedUntil, err := lh.Guard.LockedUntil(UsernameLockoutKey(user.Username))
+		if err != nil {
+			LogAuthAttempt("error", "Local", "Fail", user.Username, fmt.Sprintf("Database error: %s", err.Error()))
+			utilities.RespondDBError(c, err)
+			return
+		}
+		if !lockedUntil.IsZero() {
+			LogAuthAttempt("warning", "Local", "Fail", user.Username, fmt.Sprintf("Locked out until %s, IP %s", lockedUntil.UTC().Format(time.RFC3339), c.ClientIP()))
+			respondLockedOut(c, lockedUntil)
+			return
+		}
+	}
+
+	if msg, status, err := database.RemovePunishment(&user, lh.DB); err != nil {
 		if status == http.StatusInternalServerError {
 			LogAuthAttempt("error", "Local", "Fail", user.Username, "RemovePunishment failed")
 			c.JSON(http.StatusInternalServerError, utilities.ErrorResponse{
@@ -226,6 +275,9 @@ func (lh *LocalRegisterHandler) LocalLoginHandler(c *gin.Context) {
 
 	if user.Password == "" {
 		LogAuthAttempt("warning", "Local", "Fail", info.Username, "No password set")
+		if lh.recordLoginFailure(c, user.Username, user.Role) {
+			return
+		}
 		c.JSON(http.StatusUnauthorized, utilities.ErrorResponse{
 			Error: "Username or password is incorrect",
 		})
@@ -234,12 +286,38 @@ func (lh *LocalRegisterHandler) LocalLoginHandler(c *gin.Context) {
 
 	if !utilities.VerifyPassword(info.Password, user.Password) {
 		LogAuthAttempt("warning", "Local", "Fail", info.Username, "Invalid password")
+		if lh.recordLoginFailure(c, user.Username, user.Role) {
+			return
+		}
 		c.JSON(http.StatusUnauthorized, utilities.ErrorResponse{
 			Error: "Username or password is incorrect",
 		})
 		return
 	}
 
+	required, err := twoFactorRequired(lh.DB.DB, user, lh.RequireAdmin2FA)
+	if err != nil {
+		LogAuthAttempt("error", "Local", "Fail", user.Username, "Failed to retrieve two-factor setting")
+		utilities.RespondDBError(c, err)
+		return
+	}
+	if required {
+		// Failed attempts are kept until the second factor succeed
+		startTwoFactorChallenge(c, lh.DB.DB, user, "Local")
+		return
+	}
+
+	if err := lh.Guard.Reset(UsernameLockoutKey(user.Username)); err != nil {
+		LogAuthAttempt("error", "Local", "Fail", info.Username, "Failed to reset failed login attempts")
+		utilities.RespondDBError(c, err)
+		return
+	}
+
+	lh.respondLoginSuccess(c, user)
+}
+
+// respondLoginSuccess issues token pair and respond with the user profile of their role
+func (lh *LocalRegisterHandler) respondLoginSuccess(c *gin.Context, user model.User) {
 	switch user.Role {
 	case model.RoleCPSK:
 		var cpskUser model.CPSKUser
@@ -251,7 +329,7 @@ func (lh *LocalRegisterHandler) LocalLoginHandler(c *gin.Context) {
 			return
 		}
 
-		accessToken, _, err := GenerateStandardToken(cpskUser.UserID)
+		accessToken, refreshToken, err := GenerateStandardToken(lh.DB, cpskUser.UserID)
 		if err != nil {
 			LogAuthAttempt("error", "Local", "Fail", user.Username, "Failed to generate access token")
 			c.JSON(http.StatusInternalServerError, utilities.ErrorResponse{
@@ -262,8 +340,9 @@ func (lh *LocalRegisterHandler) LocalLoginHandler(c *gin.Context) {
 
 		LogAuthAttempt("info", "Local", "Success", user.Username, "User authenticated as CPSK")
 		c.JSON(http.StatusOK, model.CPSKResponse{
-			User:        cpskUser,
-			AccessToken: accessToken,
+			User:         cpskUser,
+			AccessToken:  accessToken,
+			RefreshToken: refreshToken,
 		})
 
 	case model.RoleCompany:
@@ -276,7 +355,7 @@ func (lh *LocalRegisterHandler) LocalLoginHandler(c *gin.Context) {
 			return
 		}
 
-		accessToken, _, err := GenerateStandardToken(companyUser.UserID)
+		accessToken, refreshToken, err := GenerateStandardToken(lh.DB, companyUser.UserID)
 		if err != nil {
 			LogAuthAttempt("error", "Local", "Fail", user.Username, "Failed to generate access token")
 			c.JSON(http.StatusInternalServerError, utilities.ErrorResponse{
@@ -287,11 +366,12 @@ func (lh *LocalRegisterHandler) LocalLoginHandler(c *gin.Context) {
 
 		LogAuthAttempt("info", "Local", "Success", user.Username, "User authenticated as Company")
 		c.JSON(http.StatusOK, model.CompanyResponse{
-			User:        companyUser,
-			AccessToken: accessToken,
+			User:         companyUser,
+			AccessToken:  accessToken,
+			RefreshToken: refreshToken,
 		})
 	default:
-		accessToken, _, err := GenerateStandardToken(user.ID)
+		accessToken, refreshToken, err := GenerateStandardToken(lh.DB, user.ID)
 		if err != nil {
 			LogAuthAttempt("error", "Local", "Fail", user.Username, "Failed to generate access token")
 			c.JSON(http.StatusInternalServerError, utilities.ErrorResponse{
@@ -302,8 +382,55 @@ func (lh *LocalRegisterHandler) LocalLoginHandler(c *gin.Context) {
 
 		LogAuthAttempt("info", "Local", "Success", user.Username, "User authenticated as Admin")
 		c.JSON(http.StatusOK, adminResponse{
-			User:        user,
-			AccessToken: accessToken,
+			User:         user,
+			AccessToken:  accessToken,
+			RefreshToken: refreshToken,
 		})
 	}
 }
+
+// recordLoginFailure counts failed attempt for username and client IP, log and respond 429
+// if this attempt trigger a lockout. It returns true if response has been written.
+// Username without account (empty role) is counted by IP only, so guessed usernames don't fill the lockout table.
+func (lh *LocalRegisterHandler) recordLoginFailure(c *gin.Context, username string, role string) bool {
+	keys := []string{IPLockoutKey(c.ClientIP())}
+	if role != "" {
+		keys = append(keys, UsernameLockoutKey(username))
+	}
+
+	var lockedUntil time.Time
+	for _, key := range keys {
+		until, err := lh.Guard.RecordFailure(key)
+		if err != nil {
+			LogAuthAttempt("error", "Local", "Fail", username, fmt.Sprintf("Failed to record failed attempt: %s", err.Error()))
+			utilities.RespondDBError(c, err)
+			return true
+		}
+		if until == nil {
+			continue
+		}
+
+		msg := fmt.Sprintf("Lockout %s until %s", key, until.UTC().Format(time.RFC3339))
+		if role != "" {
+			msg += fmt.Sprintf(", role %s", role)
+		}
+		LogAuthAttempt("warning", "Local", "Fail", username, msg)
+		if until.After(lockedUntil) {
+			lockedUntil = *until
+		}
+	}
+
+	if lockedUntil.IsZero() {
+		return false
+	}
+	respondLockedOut(c, lockedUntil)
+	return true
+}
+
+func respondLockedOut(c *gin.Context, until time.Time) {
+	retryAfter := int(math.Ceil(time.Until(until).Seconds()))
+	c.Header("Retry-After", strconv.Itoa(retryAfter))
+	c.JSON(http.StatusTooManyRequests, utilities.ErrorResponse{
+		Error: fmt.Sprintf("Too many failed login attempts, try again in %d seconds", retryAfter),
+	})
+}
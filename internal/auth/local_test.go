@@ -1,19 +1,23 @@
 package auth
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/http/httptest"
 
 	"os"
+	"sync"
 	"testing"
 	"time"
 
 	"HireMeMaybe-backend/internal/database"
+	"HireMeMaybe-backend/internal/model"
 	"HireMeMaybe-backend/internal/utilities"
 
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v4"
 	"github.com/stretchr/testify/assert"
 	"github.com/testcontainers/testcontainers-go"
 )
@@ -23,6 +27,10 @@ var testTeardown func(context.Context, ...testcontainers.TerminateOption) error
 
 func TestMain(m *testing.M) {
 	gin.SetMode(gin.TestMode)
+	// OAuth state is not signed without secret
+	if oauthStateSecret == "" && secretKey == "" {
+		oauthStateSecret = "test-oauth-state-key"
+	}
 
 	var err error
 	testTeardown, testDB, err = database.GetTestDB()
@@ -41,14 +49,14 @@ func TestMain(m *testing.M) {
 }
 
 // Helper: validate access token in response and return claims.
-func assertValidAccessToken(t *testing.T, resp map[string]interface{}) *jwt.RegisteredClaims {
+func assertValidAccessToken(t *testing.T, resp map[string]interface{}) *Claims {
 	t.Helper()
 	tokenStr, ok := resp["access_token"].(string)
 	assert.True(t, ok, "access_token not a string")
 	token, err := ValidatedToken(tokenStr)
 	assert.NoError(t, err)
 	assert.True(t, token.Valid)
-	claims, ok := token.Claims.(*jwt.RegisteredClaims)
+	claims, ok := token.Claims.(*Claims)
 	assert.True(t, ok, "claims type mismatch")
 	assert.NotEmpty(t, claims.Subject, "token subject empty")
 	return claims
@@ -234,4 +242,111 @@ func TestLoginUserNotFound(t *testing.T) {
 
 	errMsg, _ := resp["error"].(string)
 	assert.Equal(t, "Username or password is incorrect", errMsg)
+
+	// Unknown username is counted by IP only
+	var count int64
+	testDB.Model(&model.LoginLockout{}).Where("identifier = ?", UsernameLockoutKey("non_existent_user_xyz")).Count(&count)
+	assert.Zero(t, count)
+}
+
+// Helper: call local login from the given client IP.
+func loginFromIP(handler *LocalRegisterHandler, ip string, username string, password string) *httptest.ResponseRecorder {
+	body, _ := json.Marshal(map[string]string{"username": username, "password": password})
+	rec := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(rec)
+	c.Request, _ = http.NewRequest(http.MethodPost, "/login", bytes.NewReader(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Request.RemoteAddr = ip + ":12345"
+	handler.LocalLoginHandler(c)
+	return rec
+}
+
+func TestLoginLockoutAfterFailedAttempts(t *testing.T) {
+	handler := NewLocalAuthHandler(testDB)
+	ip := "10.0.0.8"
+	username := database.TestUserCPSK2.Username
+	defer func() {
+		_ = handler.Guard.Unlock(UsernameLockoutKey(username))
+		_ = handler.Guard.Unlock(IPLockoutKey(ip))
+	}()
+
+	for i := 1; i < handler.Guard.MaxFailedAttempts; i++ {
+		rec := loginFromIP(handler, ip, username, "WrongPass999!")
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	}
+
+	rec := loginFromIP(handler, ip, username, "WrongPass999!")
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.NotEmpty(t, rec.Header().Get("Retry-After"))
+
+	// Correct password is rejected while locked out
+	rec = loginFromIP(handler, ip, username, database.TestSeedPassword)
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+
+	// Admin unlock username and IP
+	assert.NoError(t, handler.Guard.Unlock(UsernameLockoutKey(username)))
+	assert.NoError(t, handler.Guard.Unlock(IPLockoutKey(ip)))
+
+	rec = loginFromIP(handler, ip, username, database.TestSeedPassword)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+}
+
+func TestLoginLockoutIPThreshold(t *testing.T) {
+	guard := NewLoginGuard(testDB)
+	key := IPLockoutKey("10.0.0.9")
+	defer func() { _ = guard.Unlock(key) }()
+
+	// Failures of different users behind the same IP don't lock it at username threshold
+	for i := 1; i < guard.MaxFailedAttemptsPerIP; i++ {
+		until, err := guard.RecordFailure(key)
+		assert.NoError(t, err)
+		assert.Nil(t, until, "IP locked after %d failures", i)
+	}
+
+	until, err := guard.RecordFailure(key)
+	assert.NoError(t, err)
+	assert.NotNil(t, until)
+}
+
+func TestLoginLockoutConcurrentFirstFailure(t *testing.T) {
+	guard := NewLoginGuard(testDB)
+	key := UsernameLockoutKey("concurrent_user")
+	defer func() { _ = guard.Unlock(key) }()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 5)
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := guard.RecordFailure(key)
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		assert.NoError(t, err)
+	}
+
+	var lockout model.LoginLockout
+	assert.NoError(t, testDB.Where("identifier = ?", key).First(&lockout).Error)
+	assert.Equal(t, 1, lockout.LockoutCount, "Every failure is counted")
+}
+
+func TestLoginLockoutExponentialBackoff(t *testing.T) {
+	guard := NewLoginGuard(testDB)
+	guard.MaxFailedAttempts = 1
+	key := UsernameLockoutKey("backoff_user")
+	defer func() { _ = guard.Unlock(key) }()
+
+	first, err := guard.RecordFailure(key)
+	assert.NoError(t, err)
+	assert.NotNil(t, first)
+	assert.WithinDuration(t, time.Now().Add(guard.BaseLockout), *first, 5*time.Second)
+
+	second, err := guard.RecordFailure(key)
+	assert.NoError(t, err)
+	assert.NotNil(t, second)
+	assert.WithinDuration(t, time.Now().Add(2*guard.BaseLockout), *second, 5*time.Second)
 }
@@ -0,0 +1,164 @@
+package auth
+
+import (
+	"HireMeMaybe-backend/internal/database"
+	"HireMeMaybe-backend/internal/model"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Default setting of LoginGuard
+const (
+	DefaultMaxFailedAttempts = 5
+	DefaultBaseLockout       = time.Minute
+	DefaultMaxLockout        = 24 * time.Hour
+	// Many users may share one IP behind NAT or proxy, so the IP gets much higher threshold than username
+	DefaultMaxFailedAttemptsPerIP = 50
+	// Failed attempts older than this window are forgotten
+	DefaultFailureWindow = 15 * time.Minute
+)
+
+// LoginGuard tracks failed local login attempts per username and per IP, and locks them out
+// with exponential backoff after MaxFailedAttempts failures of username or MaxFailedAttemptsPerIP failures of IP.
+type LoginGuard struct {
+	DB                     *database.DBinstanceStruct
+	MaxFailedAttempts      int
+	MaxFailedAttemptsPerIP int
+	BaseLockout            time.Duration
+	MaxLockout             time.Duration
+	FailureWindow          time.Duration
+}
+
+// NewLoginGuard creates a new instance of LoginGuard with default setting.
+func NewLoginGuard(db *database.DBinstanceStruct) *LoginGuard {
+	return &LoginGuard{
+		DB:                     db,
+		MaxFailedAttempts:      DefaultMaxFailedAttempts,
+		MaxFailedAttemptsPerIP: DefaultMaxFailedAttemptsPerIP,
+		BaseLockout:            DefaultBaseLockout,
+		MaxLockout:             DefaultMaxLockout,
+		FailureWindow:          DefaultFailureWindow,
+	}
+}
+
+// UsernameLockoutKey returns lockout key of the username.
+func UsernameLockoutKey(username string) string {
+	return "username:" + strings.ToLower(strings.TrimSpace(username))
+}
+
+// IPLockoutKey returns lockout key of the client IP.
+func IPLockoutKey(ip string) string {
+	return ipLockoutPrefix + ip
+}
+
+const ipLockoutPrefix = "ip:"
+
+// LockedUntil returns the latest lockout end among the given keys,
+// zero time is returned if none of them is locked.
+func (g *LoginGuard) LockedUntil(keys ...string) (time.Time, error) {
+	var lockouts []model.LoginLockout
+	if err := g.DB.Where("identifier IN ? AND locked_until > ?", keys, time.Now()).Find(&lockouts).Error; err != nil {
+		return time.Time{}, err
+	}
+
+	var until time.Time
+	for _, l := range lockouts {
+		if l.LockedUntil.After(until) {
+			until = *l.LockedUntil
+		}
+	}
+	return until, nil
+}
+
+// RecordFailure count a failed attempt for the key. If the key reach its threshold,
+// it is locked for BaseLockout doubled by every previous lockout (up to MaxLockout),
+// and the lockout end is returned.
+func (g *LoginGuard) RecordFailure(key string) (*time.Time, error) {
+	var lockedUntil *time.Time
+
+	err := g.DB.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+
+		// Concurrent first failures of the key wait for each other here instead of inserting twice
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&model.LoginLockout{Identifier: key, LastFailedAt: now}).Error; err != nil {
+			return err
+		}
+
+		var lockout model.LoginLockout
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("identifier = ?", key).First(&lockout).Error; err != nil {
+			return err
+		}
+
+		if now.Sub(lockout.LastFailedAt) > g.FailureWindow {
+			lockout.FailedCount = 0
+		}
+		if lockout.LockedUntil != nil && now.Sub(*lockout.LockedUntil) > g.MaxLockout {
+			// Backoff is forgotten after a long time without lockout
+			lockout.LockoutCount = 0
+		}
+
+		lockout.FailedCount++
+		lockout.LastFailedAt = now
+
+		if lockout.FailedCount >= g.maxFailedAttempts(key) {
+			until := now.Add(g.lockoutDuration(lockout.LockoutCount))
+			lockout.LockoutCount++
+			lockout.FailedCount = 0
+			lockout.LockedUntil = &until
+			lockedUntil = &until
+		}
+
+		return tx.Save(&lockout).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return lockedUntil, nil
+}
+
+// maxFailedAttempts returns number of failed attempts locking the key
+func (g *LoginGuard) maxFailedAttempts(key string) int {
+	if strings.HasPrefix(key, ipLockoutPrefix) {
+		return g.MaxFailedAttemptsPerIP
+	}
+	return g.MaxFailedAttempts
+}
+
+func (g *LoginGuard) lockoutDuration(previousLockouts int) time.Duration {
+	d := g.BaseLockout
+	for i := 0; i < previousLockouts; i++ {
+		d *= 2
+		if d >= g.MaxLockout {
+			return g.MaxLockout
+		}
+	}
+	return d
+}
+
+// Reset clears failed attempts and backoff of the key, used after successful login.
+func (g *LoginGuard) Reset(key string) error {
+	return g.DB.Where("identifier = ?", key).Delete(&model.LoginLockout{}).Error
+}
+
+// Unlock removes lockout of the key, returns gorm.ErrRecordNotFound if the key has no record.
+func (g *LoginGuard) Unlock(key string) error {
+	result := g.DB.Where("identifier = ?", key).Delete(&model.LoginLockout{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// ActiveLockouts returns every key currently locked.
+func (g *LoginGuard) ActiveLockouts() ([]model.LoginLockout, error) {
+	var lockouts []model.LoginLockout
+	err := g.DB.Where("locked_until > ?", time.Now()).Order("locked_until DESC").Find(&lockouts).Error
+	return lockouts, err
+}
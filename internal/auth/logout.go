@@ -1,31 +1,35 @@
 package auth
 
 import (
+	"HireMeMaybe-backend/internal/model"
 	"HireMeMaybe-backend/internal/utilities"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
-// LogoutController handles user logout by blacklisting JWT tokens
+// LogoutController handles user logout by blacklisting JWT tokens and revoking refresh tokens
 type LogoutController struct {
 	BlacklistStore JwtBlacklistStore
+	RefreshStore   *RefreshTokenStore
 }
 
 // NewLogoutController creates a new instance of LogoutController
-func NewLogoutController(blacklistStore JwtBlacklistStore) *LogoutController {
+func NewLogoutController(blacklistStore JwtBlacklistStore, refreshStore *RefreshTokenStore) *LogoutController {
 	return &LogoutController{
 		BlacklistStore: blacklistStore,
+		RefreshStore:   refreshStore,
 	}
 }
 
-// LogoutHandler handles user logout by blacklisting the JWT token
+// LogoutHandler handles user logout by blacklisting the JWT token and revoking its refresh token family
 func (lc *LogoutController) LogoutHandler(c *gin.Context) {
 
-	tokenString, err := utilities.ExtractBearerToken(c)
-	if err != nil {
+	if _, err := utilities.ExtractBearerToken(c); err != nil {
 		c.JSON(http.StatusUnauthorized, utilities.ErrorResponse{Error: err.Error()})
 		return
 	}
@@ -36,22 +40,76 @@ func (lc *LogoutController) LogoutHandler(c *gin.Context) {
 		return
 	}
 
-	err = lc.BlacklistStore.AddToBlacklist(tokenString, claims.ExpiresAt.Time)
+	if claims.ID == "" {
+		c.JSON(http.StatusUnauthorized, utilities.ErrorResponse{Error: "token does not have an id"})
+		return
+	}
+
+	err = lc.BlacklistStore.AddToBlacklist(claims.ID, claims.ExpiresAt.Time)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, utilities.ErrorResponse{Error: "Failed to logout"})
 		return
 	}
 
+	if claims.SessionID != "" && lc.RefreshStore != nil {
+		familyID, err := uuid.Parse(claims.SessionID)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, utilities.ErrorResponse{Error: "invalid session id"})
+			return
+		}
+		if err := lc.RefreshStore.RevokeFamily(familyID); err != nil {
+			c.JSON(http.StatusInternalServerError, utilities.ErrorResponse{Error: "Failed to logout"})
+			return
+		}
+	}
+
 	c.JSON(http.StatusOK, gin.H{"message": "Successfully logged out"})
 }
 
-func extractClaims(c *gin.Context) (*jwt.RegisteredClaims, error) {
+// LogoutAllHandler revokes every token issued to the user before now
+// @Summary Log out from every session
+// @Description Reject every access token issued before now and revoke every refresh token of the user
+// @Tags Auth
+// @Produce json
+// @Param Authorization header string true "Insert your access token" default(Bearer <your access token>)
+// @Success 200 {object} map[string]string "Successfully logged out from all sessions"
+// @Failure 401 {object} utilities.ErrorResponse "Unauthorized"
+// @Failure 500 {object} utilities.ErrorResponse "Database error"
+// @Router /auth/logout/all [post]
+func (lc *LogoutController) LogoutAllHandler(c *gin.Context) {
+	user, err := utilities.ExtractUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, utilities.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	err = lc.RefreshStore.DB.Transaction(func(tx *gorm.DB) error {
+		return invalidateUserSessions(tx, user.ID)
+	})
+	if err != nil {
+		utilities.RespondDBError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Successfully logged out from all sessions"})
+}
+
+// invalidateUserSessions rejects every access token issued to the user before now
+// and revokes every refresh token of the user.
+func invalidateUserSessions(tx *gorm.DB, userID uuid.UUID) error {
+	if err := tx.Model(&model.User{}).Where("id = ?", userID).Update("tokens_valid_after", time.Now()).Error; err != nil {
+		return err
+	}
+	return revokeUserRefreshTokens(tx, userID)
+}
+
+func extractClaims(c *gin.Context) (*Claims, error) {
 	claims, ok := c.Get("claims")
 	if !ok {
 		return nil, fmt.Errorf("invalid token claims")
 	}
 
-	realClaims, okCast := claims.(*jwt.RegisteredClaims)
+	realClaims, okCast := claims.(*Claims)
 	if !okCast {
 		return nil, fmt.Errorf("invalid token claims type")
 	}
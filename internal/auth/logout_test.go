@@ -2,6 +2,7 @@ package auth
 
 import (
 	"HireMeMaybe-backend/internal/database"
+	"HireMeMaybe-backend/internal/model"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -22,7 +23,7 @@ func TestLogoutSuccess(t *testing.T) {
 
 	// Create logout controller with blacklist store
 	blacklistStore := NewInMemoryBlacklistStore()
-	logoutController := NewLogoutController(blacklistStore)
+	logoutController := NewLogoutController(blacklistStore, NewRefreshTokenStore(testDB))
 
 	// Create a test context with the access token
 	rec := httptest.NewRecorder()
@@ -34,7 +35,7 @@ func TestLogoutSuccess(t *testing.T) {
 	// Parse and set claims in context (simulating middleware behavior)
 	token, err := ValidatedToken(accessToken)
 	assert.NoError(t, err)
-	claims, ok := token.Claims.(*jwt.RegisteredClaims)
+	claims, ok := token.Claims.(*Claims)
 	assert.True(t, ok)
 	c.Set("claims", claims)
 
@@ -50,7 +51,7 @@ func TestLogoutSuccess(t *testing.T) {
 	assert.Equal(t, "Successfully logged out", resp["message"])
 
 	// Verify token is blacklisted
-	isBlacklisted, err := blacklistStore.IsBlacklisted(accessToken)
+	isBlacklisted, err := blacklistStore.IsBlacklisted(claims.ID)
 	assert.NoError(t, err)
 	assert.True(t, isBlacklisted, "Token should be blacklisted after logout")
 }
@@ -58,7 +59,7 @@ func TestLogoutSuccess(t *testing.T) {
 func TestLogoutMissingToken(t *testing.T) {
 	// Create logout controller
 	blacklistStore := NewInMemoryBlacklistStore()
-	logoutController := NewLogoutController(blacklistStore)
+	logoutController := NewLogoutController(blacklistStore, NewRefreshTokenStore(testDB))
 
 	// Create a test context without authorization header
 	rec := httptest.NewRecorder()
@@ -82,7 +83,7 @@ func TestLogoutMissingToken(t *testing.T) {
 func TestLogoutInvalidTokenFormat(t *testing.T) {
 	// Create logout controller
 	blacklistStore := NewInMemoryBlacklistStore()
-	logoutController := NewLogoutController(blacklistStore)
+	logoutController := NewLogoutController(blacklistStore, NewRefreshTokenStore(testDB))
 
 	// Create a test context with invalid token format
 	rec := httptest.NewRecorder()
@@ -112,7 +113,7 @@ func TestLogoutMissingClaims(t *testing.T) {
 
 	// Create logout controller
 	blacklistStore := NewInMemoryBlacklistStore()
-	logoutController := NewLogoutController(blacklistStore)
+	logoutController := NewLogoutController(blacklistStore, NewRefreshTokenStore(testDB))
 
 	// Create a test context with token but without claims in context
 	rec := httptest.NewRecorder()
@@ -143,7 +144,7 @@ func TestLogoutInvalidClaimsType(t *testing.T) {
 
 	// Create logout controller
 	blacklistStore := NewInMemoryBlacklistStore()
-	logoutController := NewLogoutController(blacklistStore)
+	logoutController := NewLogoutController(blacklistStore, NewRefreshTokenStore(testDB))
 
 	// Create a test context with token but with wrong claims type
 	rec := httptest.NewRecorder()
@@ -177,7 +178,7 @@ func TestLogoutBlacklistStoreError(t *testing.T) {
 	mockStore := &MockBlacklistStore{
 		addError: fmt.Errorf("database connection failed"),
 	}
-	logoutController := NewLogoutController(mockStore)
+	logoutController := NewLogoutController(mockStore, NewRefreshTokenStore(testDB))
 
 	// Create a test context with the access token
 	rec := httptest.NewRecorder()
@@ -189,7 +190,7 @@ func TestLogoutBlacklistStoreError(t *testing.T) {
 	// Parse and set claims in context
 	token, err := ValidatedToken(accessToken)
 	assert.NoError(t, err)
-	claims, ok := token.Claims.(*jwt.RegisteredClaims)
+	claims, ok := token.Claims.(*Claims)
 	assert.True(t, ok)
 	c.Set("claims", claims)
 
@@ -215,7 +216,7 @@ func TestLogoutMultipleTokens(t *testing.T) {
 
 	// Create logout controller with shared blacklist store
 	blacklistStore := NewInMemoryBlacklistStore()
-	logoutController := NewLogoutController(blacklistStore)
+	logoutController := NewLogoutController(blacklistStore, NewRefreshTokenStore(testDB))
 
 	// Logout first token
 	rec1 := httptest.NewRecorder()
@@ -226,7 +227,7 @@ func TestLogoutMultipleTokens(t *testing.T) {
 
 	parsedToken1, err := ValidatedToken(token1)
 	assert.NoError(t, err)
-	claims1, ok := parsedToken1.Claims.(*jwt.RegisteredClaims)
+	claims1, ok := parsedToken1.Claims.(*Claims)
 	assert.True(t, ok)
 	c1.Set("claims", claims1)
 
@@ -242,7 +243,7 @@ func TestLogoutMultipleTokens(t *testing.T) {
 
 	parsedToken2, err := ValidatedToken(token2)
 	assert.NoError(t, err)
-	claims2, ok := parsedToken2.Claims.(*jwt.RegisteredClaims)
+	claims2, ok := parsedToken2.Claims.(*Claims)
 	assert.True(t, ok)
 	c2.Set("claims", claims2)
 
@@ -250,21 +251,24 @@ func TestLogoutMultipleTokens(t *testing.T) {
 	assert.Equal(t, http.StatusOK, rec2.Code)
 
 	// Verify both tokens are blacklisted
-	isBlacklisted1, err := blacklistStore.IsBlacklisted(token1)
+	isBlacklisted1, err := blacklistStore.IsBlacklisted(claims1.ID)
 	assert.NoError(t, err)
 	assert.True(t, isBlacklisted1)
 
-	isBlacklisted2, err := blacklistStore.IsBlacklisted(token2)
+	isBlacklisted2, err := blacklistStore.IsBlacklisted(claims2.ID)
 	assert.NoError(t, err)
 	assert.True(t, isBlacklisted2)
 }
 
 func TestLogoutExpiredTokenHandling(t *testing.T) {
 	// Create a token that will expire soon
-	claims := &jwt.RegisteredClaims{
-		Subject:   database.TestUserCPSK1.ID.String(),
-		ExpiresAt: jwt.NewNumericDate(time.Now().Add(2 * time.Second)),
-		IssuedAt:  jwt.NewNumericDate(time.Now()),
+	claims := &Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        "expiring-token-id",
+			Subject:   database.TestUserCPSK1.ID.String(),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(2 * time.Second)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
@@ -273,7 +277,7 @@ func TestLogoutExpiredTokenHandling(t *testing.T) {
 
 	// Create logout controller
 	blacklistStore := NewInMemoryBlacklistStore()
-	logoutController := NewLogoutController(blacklistStore)
+	logoutController := NewLogoutController(blacklistStore, NewRefreshTokenStore(testDB))
 
 	// Create a test context with the token
 	rec := httptest.NewRecorder()
@@ -288,7 +292,7 @@ func TestLogoutExpiredTokenHandling(t *testing.T) {
 	assert.Equal(t, http.StatusOK, rec.Code)
 
 	// Verify token is blacklisted
-	isBlacklisted, err := blacklistStore.IsBlacklisted(tokenString)
+	isBlacklisted, err := blacklistStore.IsBlacklisted(claims.ID)
 	assert.NoError(t, err)
 	assert.True(t, isBlacklisted)
 
@@ -296,7 +300,7 @@ func TestLogoutExpiredTokenHandling(t *testing.T) {
 	time.Sleep(3 * time.Second)
 
 	// Token should still be in blacklist (cleanup happens periodically)
-	isBlacklistedAfter, err := blacklistStore.IsBlacklisted(tokenString)
+	isBlacklistedAfter, err := blacklistStore.IsBlacklisted(claims.ID)
 	assert.NoError(t, err)
 	// Note: May or may not be blacklisted depending on cleanup timing
 	_ = isBlacklistedAfter
@@ -309,9 +313,11 @@ func TestExtractClaims(t *testing.T) {
 		c, _ := gin.CreateTestContext(rec)
 		c.Request, _ = http.NewRequest(http.MethodGet, "/", nil)
 
-		expectedClaims := &jwt.RegisteredClaims{
-			Subject:   "test-user-id",
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		expectedClaims := &Claims{
+			RegisteredClaims: jwt.RegisteredClaims{
+				Subject:   "test-user-id",
+				ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+			},
 		}
 		c.Set("claims", expectedClaims)
 
@@ -374,3 +380,56 @@ func (m *MockBlacklistStore) AddToBlacklist(jti string, exp time.Time) error {
 	m.blacklisted[jti] = exp
 	return nil
 }
+
+func TestGeneratedTokensHaveUniqueID(t *testing.T) {
+	token1, err := GenerateTokenWithDuration(database.TestUserCPSK1.ID, time.Hour, JwtIssuer)
+	assert.NoError(t, err)
+	token2, err := GenerateTokenWithDuration(database.TestUserCPSK1.ID, time.Hour, JwtIssuer)
+	assert.NoError(t, err)
+
+	parsed1, err := ValidatedToken(token1)
+	assert.NoError(t, err)
+	parsed2, err := ValidatedToken(token2)
+	assert.NoError(t, err)
+
+	claims1 := parsed1.Claims.(*Claims)
+	claims2 := parsed2.Claims.(*Claims)
+	assert.NotEmpty(t, claims1.ID)
+	assert.NotEqual(t, claims1.ID, claims2.ID)
+}
+
+func TestLogoutAllSessions(t *testing.T) {
+	_, refreshToken := loginTokenPair(t, database.TestUserCompany1.Username)
+
+	var user model.User
+	assert.NoError(t, testDB.Where("id = ?", database.TestUserCompany1.ID).First(&user).Error)
+	defer testDB.Model(&model.User{}).Where("id = ?", user.ID).Update("tokens_valid_after", nil)
+
+	logoutController := NewLogoutController(NewInMemoryBlacklistStore(), NewRefreshTokenStore(testDB))
+
+	rec := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(rec)
+	c.Request, _ = http.NewRequest(http.MethodPost, "/logout/all", nil)
+	c.Set("user", user)
+
+	logoutController.LogoutAllHandler(c)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	var updated model.User
+	assert.NoError(t, testDB.Where("id = ?", user.ID).First(&updated).Error)
+	assert.NotNil(t, updated.TokensValidAfter)
+
+	refreshRec, _ := callRefresh(t, refreshToken)
+	assert.Equal(t, http.StatusUnauthorized, refreshRec.Code)
+}
+
+func TestLogoutAllNoUser(t *testing.T) {
+	logoutController := NewLogoutController(NewInMemoryBlacklistStore(), NewRefreshTokenStore(testDB))
+
+	rec := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(rec)
+	c.Request, _ = http.NewRequest(http.MethodPost, "/logout/all", nil)
+
+	logoutController.LogoutAllHandler(c)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
@@ -7,7 +7,6 @@ import (
 	"HireMeMaybe-backend/internal/utilities"
 	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"io"
 	"log"
@@ -17,7 +16,6 @@ import (
 	// Auto load .env file
 	_ "github.com/joho/godotenv/autoload"
 	"golang.org/x/oauth2"
-	"gorm.io/gorm"
 )
 
 // OauthLoginHandler struct holds the database connection and OAuth2 configuration for handling OAuth login.
@@ -25,10 +23,9 @@ type OauthLoginHandler struct {
 	DB               *database.DBinstanceStruct
 	OauthConfig      *oauth2.Config
 	UserInfoEndpoint string
-}
-
-type code struct {
-	Code string `json:"code" binding:"required"`
+	DomainPolicy     *EmailDomainPolicy
+	// RequireAdmin2FA forces admin to log in with TOTP, see LocalRegisterHandler.RequireAdmin2FA
+	RequireAdmin2FA bool
 }
 
 // NewOauthLoginHandler creates a new instance of OauthLoginHandler with the provided database connection and OAuth2 configuration.
@@ -37,20 +34,19 @@ func NewOauthLoginHandler(db *database.DBinstanceStruct, oauthConfig *oauth2.Con
 		DB:               db,
 		OauthConfig:      oauthConfig,
 		UserInfoEndpoint: userInfoEndpoint,
+		DomainPolicy:     NewEmailDomainPolicyFromEnv(),
+		RequireAdmin2FA:  requireAdmin2FAFromEnv(),
 	}
 }
 
-func (h *OauthLoginHandler) getUserInfo(c *gin.Context) (model.GoogleUserInfo, error) {
+// getUserInfo validates state and code verifier issued for the purpose by StartHandler,
+// then exchanges the authorization code and fetches Google user info.
+func (h *OauthLoginHandler) getUserInfo(c *gin.Context, purpose string) (model.GoogleUserInfo, error) {
 
-	var code code
 	var uInfo model.GoogleUserInfo
 
-	// check does body has code
-	if err := c.ShouldBindJSON(&code); err != nil {
-		LogAuthAttempt("warning", "Google", "Fail", "", "No authorization code provided")
-		c.JSON(http.StatusBadRequest, utilities.ErrorResponse{
-			Error: fmt.Sprintf("No authorization code provided: %v", err.Error()),
-		})
+	code, state, err := bindOAuthCallback(c, "Google", purpose)
+	if err != nil {
 		return uInfo, err
 	}
 
@@ -58,6 +54,7 @@ func (h *OauthLoginHandler) getUserInfo(c *gin.Context) (model.GoogleUserInfo, e
 	token, err := h.OauthConfig.Exchange(
 		context.Background(),
 		code.Code,
+		oauth2.VerifierOption(code.CodeVerifier),
 	)
 	if err != nil {
 		LogAuthAttempt("warning", "Google", "Fail", "", "Token exchange failed")
@@ -67,6 +64,12 @@ func (h *OauthLoginHandler) getUserInfo(c *gin.Context) (model.GoogleUserInfo, e
 		return uInfo, err
 	}
 
+	if err := verifyIDTokenNonce(token, state.Nonce); err != nil {
+		LogAuthAttempt("warning", "Google", "Fail", "", err.Error())
+		c.JSON(http.StatusBadRequest, utilities.ErrorResponse{Error: err.Error()})
+		return uInfo, err
+	}
+
 	client := h.OauthConfig.Client(context.Background(), token)
 	resp, err := client.Get(h.UserInfoEndpoint)
 	if err != nil {
@@ -113,73 +116,9 @@ func (h *OauthLoginHandler) getUserInfo(c *gin.Context) (model.GoogleUserInfo, e
 func (h *OauthLoginHandler) loginOrRegisterUser(userModel model.UserModel, uinfo model.GoogleUserInfo, c *gin.Context) {
 	log.Printf("User Info: %+v\n", uinfo)
 
-	var user model.User
-	respStatus := http.StatusOK
-
-	err := h.DB.Preload("Punishment").Where("google_id = ?", uinfo.GID).First(&user).Error
-
-	switch {
-	case errors.Is(err, gorm.ErrRecordNotFound):
-
-		userModel.FillGoogleInfo(uinfo)
-
-		if err := h.DB.Create(userModel).Error; err != nil {
-			LogAuthAttempt("error", "Google", "Fail", uinfo.Email, "Failed to create user")
-			c.JSON(http.StatusInternalServerError, utilities.ErrorResponse{
-				Error: fmt.Sprintf("Failed to create user: %v", err.Error()),
-			})
-			return
-		}
-
-		respStatus = http.StatusCreated
-	case err == nil:
-
-		if msg, status, err := database.RemovePunishment(user, h.DB); err != nil {
-			if status == http.StatusInternalServerError {
-				LogAuthAttempt("error", "Google", "Fail", uinfo.Email, "RemovePunishment failed")
-				c.JSON(http.StatusInternalServerError, utilities.ErrorResponse{
-					Error: msg,
-				})
-				return
-			}
-		}
-
-		if err := h.DB.Preload("User").Preload("User.Punishment").Where("user_id = ?", user.ID).First(userModel).Error; err != nil {
-			if errors.Is(err, gorm.ErrRecordNotFound) {
-				LogAuthAttempt("error", "Google", "Fail", uinfo.Email, "User type mismatch")
-				c.JSON(http.StatusInternalServerError, utilities.ErrorResponse{
-					Error: "You already registered as a different user type",
-				})
-				return
-			}
-			LogAuthAttempt("error", "Google", "Fail", uinfo.Email, "Failed to retrieve user data")
-			c.JSON(http.StatusInternalServerError, utilities.ErrorResponse{
-				Error: fmt.Sprintf("Failed to retrieve user data: %v", err.Error()),
-			})
-			return
-		}
-	default:
-		utilities.RespondDBError(c, err)
-		return
-	}
-
-	var accessToken string
-
-	var _ string
-
-	accessToken, _, err = GenerateStandardToken(userModel.GetID())
-	if err != nil {
-		LogAuthAttempt("error", "Google", "Fail", uinfo.Email, "Failed to generate access token")
-		c.JSON(http.StatusInternalServerError, utilities.ErrorResponse{
-			Error: fmt.Sprintf("Failed to generate access token: %s", err.Error()),
-		})
-		return
-	}
-
-	// successful OAuth login/register
-	LogAuthAttempt("info", "Google", "Success", uinfo.Email, "OAuth authenticated")
-
-	resp := userModel.GetLoginResponse(accessToken)
-
-	c.JSON(respStatus, resp)
+	signInExternalUser(c, h.DB, h.DomainPolicy, h.RequireAdmin2FA, externalAccount{
+		Provider: ProviderGoogle,
+		Subject:  uinfo.GID,
+		Profile:  uinfo,
+	}, userModel)
 }
@@ -0,0 +1,111 @@
+package auth
+
+import (
+	"HireMeMaybe-backend/internal/model"
+	"HireMeMaybe-backend/internal/utilities"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LinkGoogleHandler links Google account to current user so they can also sign in with Google
+// @Summary Link Google account to current user
+// @Description Google account must not be linked to another user. Email of the user is set from Google if the user has no verified email
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Insert your access token" default(Bearer <your access token>)
+// @Param Code body oauthCode true "Authentication code from google with state and code verifier from /auth/google/start?purpose=link"
+// @Success 200 {object} utilities.MessageResponse "Google account linked"
+// @Failure 400 {object} utilities.ErrorResponse "Invalid state, fail to receive token or fetch user info"
+// @Failure 401 {object} utilities.ErrorResponse "Unauthorized"
+// @Failure 409 {object} utilities.ErrorResponse "User already has Google account or Google account is linked to another user"
+// @Failure 500 {object} utilities.ErrorResponse "Database error"
+// @Router /auth/link/google [post]
+func (h *OauthLoginHandler) LinkGoogleHandler(c *gin.Context) {
+	user, err := utilities.ExtractUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, utilities.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	if user.GoogleID != "" {
+		c.JSON(http.StatusConflict, utilities.ErrorResponse{Error: "Google account is already linked"})
+		return
+	}
+
+	uInfo, err := h.getUserInfo(c, oauthPurposeLink)
+	if err != nil {
+		return
+	}
+
+	var count int64
+	if err := h.DB.Model(&model.User{}).Where("google_id = ?", uInfo.GID).Count(&count).Error; err != nil {
+		utilities.RespondDBError(c, err)
+		return
+	}
+	if count > 0 {
+		LogAuthAttempt("warning", "Google", "Fail", uInfo.Email, "Google account is linked to another user")
+		c.JSON(http.StatusConflict, utilities.ErrorResponse{Error: "Google account is linked to another user"})
+		return
+	}
+
+	updates := map[string]interface{}{"google_id": uInfo.GID}
+	if !user.EmailVerified && uInfo.EmailVerified && uInfo.Email != "" {
+		taken, err := emailTaken(h.DB.DB, uInfo.Email, user.ID)
+		if err != nil {
+			utilities.RespondDBError(c, err)
+			return
+		}
+		if !taken {
+			updates["email"] = normalizeEmail(uInfo.Email)
+			updates["email_verified"] = true
+		}
+	}
+
+	if err := updateUserIdentity(h.DB.DB, user.ID, updates); err != nil {
+		utilities.RespondDBError(c, err)
+		return
+	}
+
+	LogAuthAttempt("info", "Google", "Success", user.Username, "Google account linked")
+	c.JSON(http.StatusOK, utilities.MessageResponse{Message: "Google account linked"})
+}
+
+// UnlinkGoogleHandler removes Google account from current user
+// @Summary Unlink Google account from current user
+// @Description User must have password set so they can still log in
+// @Tags Auth
+// @Produce json
+// @Param Authorization header string true "Insert your access token" default(Bearer <your access token>)
+// @Success 200 {object} utilities.MessageResponse "Google account unlinked"
+// @Failure 400 {object} utilities.ErrorResponse "User has no password"
+// @Failure 401 {object} utilities.ErrorResponse "Unauthorized"
+// @Failure 404 {object} utilities.ErrorResponse "No Google account linked"
+// @Failure 500 {object} utilities.ErrorResponse "Database error"
+// @Router /auth/link/google [delete]
+func (h *OauthLoginHandler) UnlinkGoogleHandler(c *gin.Context) {
+	user, err := utilities.ExtractUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, utilities.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	if user.GoogleID == "" {
+		c.JSON(http.StatusNotFound, utilities.ErrorResponse{Error: "No Google account linked"})
+		return
+	}
+
+	if user.Password == "" {
+		c.JSON(http.StatusBadRequest, utilities.ErrorResponse{Error: "Set a password before unlinking Google account"})
+		return
+	}
+
+	if err := updateUserIdentity(h.DB.DB, user.ID, map[string]interface{}{"google_id": ""}); err != nil {
+		utilities.RespondDBError(c, err)
+		return
+	}
+
+	LogAuthAttempt("info", "Google", "Success", user.Username, "Google account unlinked")
+	c.JSON(http.StatusOK, utilities.MessageResponse{Message: "Google account unlinked"})
+}
@@ -2,6 +2,7 @@ package auth
 
 import (
 	"HireMeMaybe-backend/internal/model"
+	"HireMeMaybe-backend/internal/utilities"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -14,18 +15,21 @@ import (
 // information with the access token.
 // @Summary Handles Google login authentication for cpsk role, exchanges code for user
 // @Description Checks and creates user in the database, generates an access token
+// @Description Account outside CPSK_ALLOWED_EMAIL_DOMAINS is rejected or signed in as visitor depending on EMAIL_DOMAIN_MISMATCH
 // @Tags Auth
 // @Accept json
 // @Produce json
-// @Param Code body code true "Authentication code from google"
+// @Param Code body oauthCode true "Authentication code from google with state and code verifier from /auth/google/start"
 // @Success 200 {object} model.CPSKResponse "Login success"
 // @Success 201 {object} model.CPSKResponse "Register success"
-// @Failure 400 {object} utilities.ErrorResponse "Fail to receive token or fetch user info"
+// @Success 202 {object} twoFactorChallengeResponse "User has two-factor authentication, finish login with /auth/2fa/verify"
+// @Failure 400 {object} utilities.ErrorResponse "Invalid state, fail to receive token or fetch user info"
+// @Failure 403 {object} utilities.ErrorResponse "Email domain is not allowed for the role and downgrade is disabled"
 // @Failure 500 {object} utilities.ErrorResponse "Database error"
 // @Router /auth/google/cpsk [post]
 func (h *OauthLoginHandler) CPSKGoogleLoginHandler(c *gin.Context) {
 
-	uInfo, err := h.getUserInfo(c)
+	uInfo, err := h.getUserInfo(c, model.RoleCPSK)
 	if err != nil {
 		return
 	}
@@ -41,15 +45,17 @@ func (h *OauthLoginHandler) CPSKGoogleLoginHandler(c *gin.Context) {
 // @Tags Auth
 // @Accept json
 // @Produce json
-// @Param Code body code true "Authentication code from google"
+// @Param Code body oauthCode true "Authentication code from google with state and code verifier from /auth/google/start"
 // @Success 200 {object} model.CompanyResponse "Login success"
 // @Success 201 {object} model.CompanyResponse "Register success"
-// @Failure 400 {object} utilities.ErrorResponse "Fail to receive token or fetch user info"
+// @Success 202 {object} twoFactorChallengeResponse "User has two-factor authentication, finish login with /auth/2fa/verify"
+// @Failure 400 {object} utilities.ErrorResponse "Invalid state, fail to receive token or fetch user info"
+// @Failure 403 {object} utilities.ErrorResponse "Email domain is not allowed for the role and downgrade is disabled"
 // @Failure 500 {object} utilities.ErrorResponse "Database error"
 // @Router /auth/google/company [post]
 func (h *OauthLoginHandler) CompanyGoogleLoginHandler(c *gin.Context) {
 
-	uInfo, err := h.getUserInfo(c)
+	uInfo, err := h.getUserInfo(c, model.RoleCompany)
 	if err != nil {
 		return
 	}
@@ -65,15 +71,16 @@ func (h *OauthLoginHandler) CompanyGoogleLoginHandler(c *gin.Context) {
 // @Tags Auth
 // @Accept json
 // @Produce json
-// @Param Code body code true "Authentication code from google"
+// @Param Code body oauthCode true "Authentication code from google with state and code verifier from /auth/google/start"
 // @Success 200 {object} model.VisitorResponse "Login success"
 // @Success 201 {object} model.VisitorResponse "Register success"
-// @Failure 400 {object} utilities.ErrorResponse "Fail to receive token or fetch user info"
+// @Success 202 {object} twoFactorChallengeResponse "User has two-factor authentication, finish login with /auth/2fa/verify"
+// @Failure 400 {object} utilities.ErrorResponse "Invalid state, fail to receive token or fetch user info"
 // @Failure 500 {object} utilities.ErrorResponse "Database error"
 // @Router /auth/google/visitor [post]
 func (h *OauthLoginHandler) VisitorGoogleLoginHandler(c *gin.Context) {
 
-	uInfo, err := h.getUserInfo(c)
+	uInfo, err := h.getUserInfo(c, model.RoleVisitor)
 	if err != nil {
 		return
 	}
@@ -81,17 +88,50 @@ func (h *OauthLoginHandler) VisitorGoogleLoginHandler(c *gin.Context) {
 	h.loginOrRegisterUser(&model.VisitorUser{}, uInfo, c)
 }
 
-// Callback function in Go retrieves a query parameter named "code" from the request and returns it
-// in a JSON response.
-// @Summary Retrieves a query parameter named "code" from the request and returns it in a JSON response
+type googleCallbackResponse struct {
+	Code  string `json:"code"`
+	State string `json:"state"`
+}
+
+// StartHandler starts Google sign in for the purpose by issuing signed state and PKCE code verifier.
+// Client redirects user to auth_url, keeps code_verifier, and sends it with the returned code and
+// state to the login or link endpoint of the same purpose.
+// @Summary Start Google sign in
+// @Description State expires in 10 minutes and only work with the endpoint of the purpose
+// @Description State is bound to the browser by HttpOnly cookie, which must be sent with the login or link request
+// @Tags Auth
+// @Produce json
+// @Param purpose query string true "Role to sign in as, or link to link Google account" Enums(cpsk, company, visitor, link)
+// @Success 200 {object} oauthStartResponse
+// @Failure 400 {object} utilities.ErrorResponse "Invalid purpose"
+// @Failure 500 {object} utilities.ErrorResponse "Failed to issue state"
+// @Router /auth/google/start [get]
+func (h *OauthLoginHandler) StartHandler(c *gin.Context) {
+	purpose := c.Query("purpose")
+	switch purpose {
+	case model.RoleCPSK, model.RoleCompany, model.RoleVisitor, oauthPurposeLink:
+	default:
+		c.JSON(http.StatusBadRequest, utilities.ErrorResponse{
+			Error: "Purpose must be 'cpsk', 'company', 'visitor' or 'link'",
+		})
+		return
+	}
+
+	startOAuth(c, h.OauthConfig, purpose)
+}
+
+// Callback function in Go retrieves query parameters named "code" and "state" from the request and
+// returns them in a JSON response.
+// @Summary Retrieves query parameters named "code" and "state" from the request and returns them in a JSON response
 // @Tags Auth
 // @Produce json
 // @Param Code query string false "Authentication code from google"
-// @Success 200 {object} code
+// @Param State query string false "State from /auth/google/start"
+// @Success 200 {object} googleCallbackResponse
 // @Router /auth/google/callback [get]
 func (h *OauthLoginHandler) Callback(c *gin.Context) {
-	aCode := c.Query("code")
-	c.JSON(http.StatusOK, code{
-		Code: aCode,
+	c.JSON(http.StatusOK, googleCallbackResponse{
+		Code:  c.Query("code"),
+		State: c.Query("state"),
 	})
 }
@@ -0,0 +1,235 @@
+package auth
+
+import (
+	"HireMeMaybe-backend/internal/utilities"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v4"
+	"golang.org/x/oauth2"
+)
+
+// oauthStateTTL is how long user has to finish signing in with Google or OpenID Connect provider after starting
+const oauthStateTTL = 10 * time.Minute
+
+const (
+	// oauthPurposeLink is purpose of state issued for linking Google account to signed in user
+	oauthPurposeLink   = "link"
+	oauthStateAudience = "oauth_state"
+	// oauthBindingCookie holds random value binding state to the browser that started signing in
+	oauthBindingCookie = "oauth_binding"
+)
+
+var (
+	errInvalidOAuthState = errors.New("invalid or expired state")
+	errNoOAuthStateKey   = errors.New("OAUTH_STATE_KEY or SECRET_KEY must be set to sign OAuth state")
+
+	// oauthStateSecret signs state, SECRET_KEY is used if it is empty
+	oauthStateSecret = os.Getenv("OAUTH_STATE_KEY")
+)
+
+// oauthStateClaims is the claims of signed state sent through authorization request.
+// Purpose is the role the user is signing in as, or link, prefixed by provider name for OpenID Connect providers. Challenge is S256 PKCE challenge
+// of the code verifier kept by the client, Nonce must match nonce of the returned ID token,
+// and Binding is hash of the binding cookie set on the browser starting sign in.
+type oauthStateClaims struct {
+	jwt.RegisteredClaims
+	Purpose   string `json:"purpose"`
+	Challenge string `json:"challenge"`
+	Nonce     string `json:"nonce"`
+	Binding   string `json:"binding"`
+}
+
+// oauthStart is state issued for starting sign in with values it is bound to.
+// Verifier is returned to the client, Binding is only set as HttpOnly cookie.
+type oauthStart struct {
+	State    string
+	Verifier string
+	Nonce    string
+	Binding  string
+}
+
+// oauthStartResponse is returned by start endpoints of Google and OpenID Connect sign in
+type oauthStartResponse struct {
+	AuthURL      string `json:"auth_url"`
+	State        string `json:"state"`
+	CodeVerifier string `json:"code_verifier"`
+}
+
+// oauthCode is authorization code from identity provider with state and code verifier from start endpoint
+type oauthCode struct {
+	Code         string `json:"code" binding:"required"`
+	State        string `json:"state" binding:"required"`
+	CodeVerifier string `json:"code_verifier" binding:"required"`
+}
+
+// oauthStateKey derives key for signing state so state can't be used as access token.
+// Error is returned if there is no secret, key derived from empty secret would let anyone forge state.
+func oauthStateKey() ([]byte, error) {
+	secret := oauthStateSecret
+	if secret == "" {
+		secret = secretKey
+	}
+	if secret == "" {
+		return nil, errNoOAuthStateKey
+	}
+	key := sha256.Sum256([]byte("oauth_state:" + secret))
+	return key[:], nil
+}
+
+// CheckOAuthStateKey returns error if there is no secret to sign state of Google and OpenID Connect sign in.
+// It should be called on startup so the server doesn't start without it.
+func CheckOAuthStateKey() error {
+	_, err := oauthStateKey()
+	return err
+}
+
+func hashOAuthBinding(binding string) string {
+	sum := sha256.Sum256([]byte(binding))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// newOAuthState issues signed state for the purpose, PKCE code verifier, nonce and browser binding it is bound to.
+func newOAuthState(purpose string) (oauthStart, error) {
+	nonce, err := NewOpaqueToken()
+	if err != nil {
+		return oauthStart{}, err
+	}
+	binding, err := NewOpaqueToken()
+	if err != nil {
+		return oauthStart{}, err
+	}
+	verifier := oauth2.GenerateVerifier()
+	key, err := oauthStateKey()
+	if err != nil {
+		return oauthStart{}, err
+	}
+
+	now := time.Now()
+	state, err := jwt.NewWithClaims(jwt.SigningMethodHS256, oauthStateClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Audience:  jwt.ClaimStrings{oauthStateAudience},
+			ExpiresAt: jwt.NewNumericDate(now.Add(oauthStateTTL)),
+			IssuedAt:  jwt.NewNumericDate(now),
+		},
+		Purpose:   purpose,
+		Challenge: oauth2.S256ChallengeFromVerifier(verifier),
+		Nonce:     nonce,
+		Binding:   hashOAuthBinding(binding),
+	}).SignedString(key)
+	if err != nil {
+		return oauthStart{}, fmt.Errorf("failed to sign state: %w", err)
+	}
+	return oauthStart{State: state, Verifier: verifier, Nonce: nonce, Binding: binding}, nil
+}
+
+// parseOAuthState validates the state was issued by this service for the purpose, the verifier and the binding.
+func parseOAuthState(state string, purpose string, verifier string, binding string) (*oauthStateClaims, error) {
+	claims := &oauthStateClaims{}
+	_, err := jwt.ParseWithClaims(state, claims, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, errInvalidOAuthState
+		}
+		return oauthStateKey()
+	})
+	if err != nil || !claims.VerifyAudience(oauthStateAudience, true) {
+		return nil, errInvalidOAuthState
+	}
+
+	if claims.Purpose != purpose {
+		return nil, fmt.Errorf("state was issued for '%s', not '%s'", claims.Purpose, purpose)
+	}
+	if subtle.ConstantTimeCompare([]byte(oauth2.S256ChallengeFromVerifier(verifier)), []byte(claims.Challenge)) != 1 {
+		return nil, errors.New("code verifier does not match state")
+	}
+	if binding == "" || subtle.ConstantTimeCompare([]byte(hashOAuthBinding(binding)), []byte(claims.Binding)) != 1 {
+		return nil, errors.New("state was issued to another browser")
+	}
+	return claims, nil
+}
+
+// setOAuthBindingCookie sets cookie binding the state to the browser, or removes it if binding is empty.
+func setOAuthBindingCookie(c *gin.Context, binding string) {
+	maxAge := int(oauthStateTTL.Seconds())
+	if binding == "" {
+		maxAge = -1
+	}
+	secure := c.Request.TLS != nil || strings.EqualFold(c.GetHeader("X-Forwarded-Proto"), "https")
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(oauthBindingCookie, binding, maxAge, "/", "", secure, true)
+}
+
+// startOAuth issues state for the purpose and responds with authorization URL of the config.
+func startOAuth(c *gin.Context, config *oauth2.Config, purpose string, opts ...oauth2.AuthCodeOption) {
+	start, err := newOAuthState(purpose)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utilities.ErrorResponse{
+			Error: fmt.Sprintf("Failed to issue state: %s", err.Error()),
+		})
+		return
+	}
+
+	setOAuthBindingCookie(c, start.Binding)
+	opts = append(opts,
+		oauth2.S256ChallengeOption(start.Verifier),
+		oauth2.SetAuthURLParam("nonce", start.Nonce),
+	)
+	c.JSON(http.StatusOK, oauthStartResponse{
+		AuthURL:      config.AuthCodeURL(start.State, opts...),
+		State:        start.State,
+		CodeVerifier: start.Verifier,
+	})
+}
+
+// bindOAuthCallback reads code, state and code verifier of the request and validates the state
+// was issued for the purpose to this browser. Binding cookie is removed so the state is used once.
+// It writes error response if the request is invalid.
+func bindOAuthCallback(c *gin.Context, authType string, purpose string) (oauthCode, *oauthStateClaims, error) {
+	var code oauthCode
+	if err := c.ShouldBindJSON(&code); err != nil {
+		LogAuthAttempt("warning", authType, "Fail", "", "No authorization code provided")
+		c.JSON(http.StatusBadRequest, utilities.ErrorResponse{
+			Error: fmt.Sprintf("Authorization code, state and code verifier must be provided: %v", err.Error()),
+		})
+		return code, nil, err
+	}
+
+	binding, _ := c.Cookie(oauthBindingCookie)
+	setOAuthBindingCookie(c, "")
+
+	state, err := parseOAuthState(code.State, purpose, code.CodeVerifier, binding)
+	if err != nil {
+		LogAuthAttempt("warning", authType, "Fail", "", fmt.Sprintf("Invalid state: %v", err))
+		c.JSON(http.StatusBadRequest, utilities.ErrorResponse{
+			Error: fmt.Sprintf("Invalid state: %v", err.Error()),
+		})
+		return code, nil, err
+	}
+	return code, state, nil
+}
+
+// verifyIDTokenNonce checks nonce of ID token returned with the token.
+// The token comes straight from the token endpoint so its signature is not checked here.
+func verifyIDTokenNonce(token *oauth2.Token, nonce string) error {
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok || rawIDToken == "" {
+		return errors.New("token response has no id_token")
+	}
+
+	claims := jwt.MapClaims{}
+	if _, _, err := jwt.NewParser().ParseUnverified(rawIDToken, claims); err != nil {
+		return fmt.Errorf("malformed id_token: %w", err)
+	}
+	if got, _ := claims["nonce"].(string); subtle.ConstantTimeCompare([]byte(got), []byte(nonce)) != 1 {
+		return errors.New("id_token nonce does not match state")
+	}
+	return nil
+}
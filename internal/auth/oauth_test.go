@@ -5,7 +5,9 @@ import (
 	"HireMeMaybe-backend/internal/utilities"
 	"net/http"
 	"testing"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -25,19 +27,15 @@ func TestLoginOrRegisterUser_NewCPSKUser(t *testing.T) {
 	handler := NewOauthLoginHandler(testDB, mockServer.Config, mockServer.MockInfoEndpoint)
 
 	// Get auth code for the test user
-	authCode, err := mockServer.GetAuthCode(mockUser.GID)
-	assert.NoError(t, err)
-
 	// Simulate OAuth login request
-	body := map[string]string{
-		"code": authCode,
-	}
+	body, cookie := mockServer.StartLogin(t, handler, model.RoleCPSK, mockUser.GID)
 
 	rec, resp, err := utilities.SimulateAPICall(
 		handler.CPSKGoogleLoginHandler,
 		"/auth/google/cpsk",
 		http.MethodPost,
 		body,
+		cookie,
 	)
 
 	// Assert response
@@ -95,19 +93,15 @@ func TestLoginOrRegisterUser_ExistingCPSKUser(t *testing.T) {
 	handler := NewOauthLoginHandler(testDB, mockServer.Config, mockServer.MockInfoEndpoint)
 
 	// Get auth code for the test user
-	authCode, err := mockServer.GetAuthCode(mockUser.GID)
-	assert.NoError(t, err)
-
 	// Simulate OAuth login request
-	body := map[string]string{
-		"code": authCode,
-	}
+	body, cookie := mockServer.StartLogin(t, handler, model.RoleCPSK, mockUser.GID)
 
 	rec, resp, err := utilities.SimulateAPICall(
 		handler.CPSKGoogleLoginHandler,
 		"/auth/google/cpsk",
 		http.MethodPost,
 		body,
+		cookie,
 	)
 
 	// Assert response
@@ -141,19 +135,15 @@ func TestLoginOrRegisterUser_NewCompanyUser(t *testing.T) {
 	handler := NewOauthLoginHandler(testDB, mockServer.Config, mockServer.MockInfoEndpoint)
 
 	// Get auth code for the test user
-	authCode, err := mockServer.GetAuthCode(mockUser.GID)
-	assert.NoError(t, err)
-
 	// Simulate OAuth login request
-	body := map[string]string{
-		"code": authCode,
-	}
+	body, cookie := mockServer.StartLogin(t, handler, model.RoleCompany, mockUser.GID)
 
 	rec, resp, err := utilities.SimulateAPICall(
 		handler.CompanyGoogleLoginHandler,
 		"/auth/google/company",
 		http.MethodPost,
 		body,
+		cookie,
 	)
 
 	// Assert response
@@ -208,18 +198,15 @@ func TestLoginOrRegisterUser_MultipleUsers(t *testing.T) {
 
 	// Test login for each user
 	for _, user := range users {
-		authCode, err := mockServer.GetAuthCode(user.GID)
-		assert.NoError(t, err)
-
-		body := map[string]string{
-			"code": authCode,
-		}
+		// Simulate OAuth login request
+		body, cookie := mockServer.StartLogin(t, handler, model.RoleCPSK, user.GID)
 
 		rec, resp, err := utilities.SimulateAPICall(
 			handler.CPSKGoogleLoginHandler,
 			"/auth/google/cpsk",
 			http.MethodPost,
 			body,
+			cookie,
 		)
 
 		assert.NoError(t, err)
@@ -254,15 +241,15 @@ func TestLoginOrRegisterUser_InvalidAuthCode(t *testing.T) {
 	handler := NewOauthLoginHandler(testDB, mockServer.Config, mockServer.MockInfoEndpoint)
 
 	// Use invalid auth code
-	body := map[string]string{
-		"code": "invalid_auth_code_12345",
-	}
+	body, cookie := mockServer.StartLogin(t, handler, model.RoleCPSK, mockUser.GID)
+	body["code"] = "invalid_auth_code_12345"
 
 	rec, _, err := utilities.SimulateAPICall(
 		handler.CPSKGoogleLoginHandler,
 		"/auth/google/cpsk",
 		http.MethodPost,
 		body,
+		cookie,
 	)
 
 	// Should fail with bad request
@@ -295,3 +282,97 @@ func TestLoginOrRegisterUser_MissingAuthCode(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, http.StatusBadRequest, rec.Code, "Should return 400 for missing auth code")
 }
+
+func TestParseOAuthState(t *testing.T) {
+	start, err := newOAuthState(model.RoleCPSK)
+	assert.NoError(t, err)
+
+	claims, err := parseOAuthState(start.State, model.RoleCPSK, start.Verifier, start.Binding)
+	assert.NoError(t, err)
+	assert.Equal(t, start.Nonce, claims.Nonce)
+
+	_, err = parseOAuthState(start.State, model.RoleCompany, start.Verifier, start.Binding)
+	assert.Error(t, err, "State is bound to the role")
+
+	_, err = parseOAuthState(start.State, model.RoleCPSK, start.Verifier+"x", start.Binding)
+	assert.Error(t, err, "State is bound to the code verifier")
+
+	_, err = parseOAuthState(start.State, model.RoleCPSK, start.Verifier, start.Binding+"x")
+	assert.Error(t, err, "State is bound to the browser")
+
+	_, err = parseOAuthState(start.State, model.RoleCPSK, start.Verifier, "")
+	assert.Error(t, err, "Binding cookie is required")
+
+	_, err = parseOAuthState(start.State+"x", model.RoleCPSK, start.Verifier, start.Binding)
+	assert.Error(t, err, "State signature must be valid")
+
+	accessToken, err := GenerateTokenWithDuration(uuid.New(), time.Hour, JwtIssuer)
+	assert.NoError(t, err)
+	_, err = parseOAuthState(accessToken, model.RoleCPSK, start.Verifier, start.Binding)
+	assert.Error(t, err, "Access token is not a state")
+}
+
+func TestOAuthStateRequiresSecret(t *testing.T) {
+	start, err := newOAuthState(model.RoleCPSK)
+	assert.NoError(t, err)
+
+	previousStateSecret, previousSecret := oauthStateSecret, secretKey
+	t.Cleanup(func() { oauthStateSecret, secretKey = previousStateSecret, previousSecret })
+	oauthStateSecret, secretKey = "", ""
+
+	assert.ErrorIs(t, CheckOAuthStateKey(), errNoOAuthStateKey)
+	_, err = newOAuthState(model.RoleCPSK)
+	assert.Error(t, err, "State is not issued without secret")
+	_, err = parseOAuthState(start.State, model.RoleCPSK, start.Verifier, start.Binding)
+	assert.Error(t, err, "State is not accepted without secret")
+
+	oauthStateSecret = "dedicated state key"
+	assert.NoError(t, CheckOAuthStateKey())
+}
+
+func TestGoogleLoginRejectInvalidState(t *testing.T) {
+	mockUser := model.GoogleUserInfo{GID: "google_state_test", Email: "state@example.com"}
+	mockServer := NewMockOAuth2Server([]model.GoogleUserInfo{mockUser})
+	defer mockServer.Close()
+	handler := NewOauthLoginHandler(testDB, mockServer.Config, mockServer.MockInfoEndpoint)
+
+	login := func(body map[string]string, cookies ...*http.Cookie) int {
+		rec, _, err := utilities.SimulateAPICall(handler.CPSKGoogleLoginHandler, "/auth/google/cpsk", http.MethodPost, body, cookies...)
+		assert.NoError(t, err)
+		return rec.Code
+	}
+
+	// No state
+	body, cookie := mockServer.StartLogin(t, handler, model.RoleCPSK, mockUser.GID)
+	assert.Equal(t, http.StatusBadRequest, login(map[string]string{"code": body["code"]}, cookie))
+
+	// State issued for another role
+	body, cookie = mockServer.StartLogin(t, handler, model.RoleCompany, mockUser.GID)
+	assert.Equal(t, http.StatusBadRequest, login(body, cookie))
+	assert.False(t, mockServer.IsUserTokenExchanged(mockUser.GID))
+
+	// Code, state and verifier of attacker replayed from victim browser without the binding cookie
+	body, _ = mockServer.StartLogin(t, handler, model.RoleCPSK, mockUser.GID)
+	assert.Equal(t, http.StatusBadRequest, login(body))
+	_, victimCookie := mockServer.StartLogin(t, handler, model.RoleCPSK, mockUser.GID)
+	assert.Equal(t, http.StatusBadRequest, login(body, victimCookie))
+	assert.False(t, mockServer.IsUserTokenExchanged(mockUser.GID))
+
+	// Code injected into another sign in, its PKCE challenge doesn't match
+	body, _ = mockServer.StartLogin(t, handler, model.RoleCPSK, mockUser.GID)
+	start, err := newOAuthState(model.RoleCPSK)
+	assert.NoError(t, err)
+	body["state"], body["code_verifier"] = start.State, start.Verifier
+	assert.Equal(t, http.StatusBadRequest, login(body, &http.Cookie{Name: oauthBindingCookie, Value: start.Binding}))
+	assert.False(t, mockServer.IsUserTokenExchanged(mockUser.GID))
+
+	// ID token nonce from another sign in
+	body, cookie = mockServer.StartLogin(t, handler, model.RoleCPSK, mockUser.GID)
+	mockServer.MockUserInfo[0].Nonce = "other_nonce"
+	assert.Equal(t, http.StatusBadRequest, login(body, cookie))
+
+	// No ID token returned
+	body, cookie = mockServer.StartLogin(t, handler, model.RoleCPSK, mockUser.GID)
+	mockServer.MockUserInfo[0].Nonce = ""
+	assert.Equal(t, http.StatusBadRequest, login(body, cookie))
+}
@@ -0,0 +1,393 @@
+package auth
+
+import (
+	"HireMeMaybe-backend/internal/database"
+	"HireMeMaybe-backend/internal/model"
+	"HireMeMaybe-backend/internal/utilities"
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"github.com/gin-gonic/gin"
+	"golang.org/x/oauth2"
+)
+
+// ClaimMapping names ID token claims holding each profile field.
+type ClaimMapping struct {
+	Subject       string
+	Email         string
+	EmailVerified string
+	FirstName     string
+	LastName      string
+	Picture       string
+	HostedDomain  string
+}
+
+// DefaultClaimMapping returns standard OpenID Connect claim names.
+func DefaultClaimMapping() ClaimMapping {
+	return ClaimMapping{
+		Subject:       "sub",
+		Email:         "email",
+		EmailVerified: "email_verified",
+		FirstName:     "given_name",
+		LastName:      "family_name",
+		Picture:       "picture",
+		HostedDomain:  "hd",
+	}
+}
+
+// OIDCProviderConfig is setting of OpenID Connect provider.
+type OIDCProviderConfig struct {
+	Name         string
+	IssuerURL    string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+	Claims       ClaimMapping
+	// TrustEmail accepts email_verified claim of the provider, so signing in can be linked to
+	// existing account with the same verified email. Only set for provider that verifies email itself.
+	TrustEmail bool
+}
+
+// OIDCProvider signs user in with OpenID Connect issuer, endpoints and signing keys
+// are loaded from the issuer discovery document.
+type OIDCProvider struct {
+	Name        string
+	OauthConfig *oauth2.Config
+	Verifier    *oidc.IDTokenVerifier
+	Claims      ClaimMapping
+	// TrustEmail is false if email of the provider is always treated as unverified
+	TrustEmail bool
+}
+
+// NewOIDCProvider creates OIDCProvider by fetching discovery document of the issuer.
+func NewOIDCProvider(ctx context.Context, cfg OIDCProviderConfig) (*OIDCProvider, error) {
+	if cfg.Name == "" || cfg.Name == ProviderGoogle {
+		return nil, fmt.Errorf("invalid OIDC provider name '%s'", cfg.Name)
+	}
+	if cfg.IssuerURL == "" || cfg.ClientID == "" {
+		return nil, fmt.Errorf("OIDC provider '%s' require issuer and client ID", cfg.Name)
+	}
+
+	provider, err := oidc.NewProvider(ctx, cfg.IssuerURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover OIDC provider '%s': %w", cfg.Name, err)
+	}
+
+	scopes := cfg.Scopes
+	if len(scopes) == 0 {
+		scopes = []string{oidc.ScopeOpenID, "email", "profile"}
+	}
+
+	return &OIDCProvider{
+		Name: cfg.Name,
+		OauthConfig: &oauth2.Config{
+			ClientID:     cfg.ClientID,
+			ClientSecret: cfg.ClientSecret,
+			RedirectURL:  cfg.RedirectURL,
+			Endpoint:     provider.Endpoint(),
+			Scopes:       scopes,
+		},
+		Verifier:   provider.Verifier(&oidc.Config{ClientID: cfg.ClientID}),
+		Claims:     cfg.Claims,
+		TrustEmail: cfg.TrustEmail,
+	}, nil
+}
+
+// Authenticate exchanges authorization code with PKCE code verifier and returns account from the validated ID token.
+// Nonce of the ID token must match nonce of the authorization request.
+func (p *OIDCProvider) Authenticate(ctx context.Context, code string, verifier string, nonce string) (externalAccount, error) {
+	token, err := p.OauthConfig.Exchange(ctx, code, oauth2.VerifierOption(verifier))
+	if err != nil {
+		return externalAccount{}, fmt.Errorf("failed to receive token: %w", err)
+	}
+
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok || rawIDToken == "" {
+		return externalAccount{}, errors.New("token response has no id_token")
+	}
+
+	idToken, err := p.Verifier.Verify(ctx, rawIDToken)
+	if err != nil {
+		return externalAccount{}, fmt.Errorf("invalid id_token: %w", err)
+	}
+	if nonce == "" || subtle.ConstantTimeCompare([]byte(idToken.Nonce), []byte(nonce)) != 1 {
+		return externalAccount{}, errors.New("id_token nonce does not match state")
+	}
+
+	var claims map[string]interface{}
+	if err := idToken.Claims(&claims); err != nil {
+		return externalAccount{}, fmt.Errorf("failed to decode id_token claims: %w", err)
+	}
+
+	return p.mapClaims(claims)
+}
+
+func (p *OIDCProvider) mapClaims(claims map[string]interface{}) (externalAccount, error) {
+	account := externalAccount{
+		Provider: p.Name,
+		Subject:  claimString(claims, p.Claims.Subject),
+		Profile: model.GoogleUserInfo{
+			Email:          claimString(claims, p.Claims.Email),
+			EmailVerified:  p.TrustEmail && claimBool(claims, p.Claims.EmailVerified),
+			FirstName:      claimString(claims, p.Claims.FirstName),
+			LastName:       claimString(claims, p.Claims.LastName),
+			ProfilePicture: claimString(claims, p.Claims.Picture),
+			HostedDomain:   claimString(claims, p.Claims.HostedDomain),
+		},
+	}
+	if account.Subject == "" {
+		return externalAccount{}, fmt.Errorf("id_token has no '%s' claim", p.Claims.Subject)
+	}
+	return account, nil
+}
+
+func claimString(claims map[string]interface{}, name string) string {
+	if name == "" {
+		return ""
+	}
+	switch v := claims[name].(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return ""
+	}
+}
+
+func claimBool(claims map[string]interface{}, name string) bool {
+	if name == "" {
+		return false
+	}
+	switch v := claims[name].(type) {
+	case bool:
+		return v
+	case string:
+		return strings.EqualFold(v, "true")
+	default:
+		return false
+	}
+}
+
+// OIDCRegistry holds configured OpenID Connect providers by name.
+type OIDCRegistry struct {
+	providers map[string]*OIDCProvider
+}
+
+// NewOIDCRegistry creates a new instance of OIDCRegistry with the provided providers.
+func NewOIDCRegistry(providers ...*OIDCProvider) *OIDCRegistry {
+	r := &OIDCRegistry{providers: map[string]*OIDCProvider{}}
+	for _, p := range providers {
+		r.providers[p.Name] = p
+	}
+	return r
+}
+
+// Get returns provider with the name.
+func (r *OIDCRegistry) Get(name string) (*OIDCProvider, bool) {
+	p, ok := r.providers[name]
+	return p, ok
+}
+
+// Names returns sorted name of every provider.
+func (r *OIDCRegistry) Names() []string {
+	names := make([]string, 0, len(r.providers))
+	for name := range r.providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NewOIDCRegistryFromEnv creates OIDCRegistry with providers listed in comma-separated OIDC_PROVIDERS
+// environment. Each provider NAME is configured by OIDC_<NAME>_ISSUER, OIDC_<NAME>_CLIENT_ID,
+// OIDC_<NAME>_CLIENT_SECRET, OIDC_<NAME>_REDIRECT_URL, optional OIDC_<NAME>_SCOPES,
+// OIDC_<NAME>_TRUST_EMAIL and OIDC_<NAME>_CLAIM_<FIELD> overriding claim name of SUBJECT, EMAIL,
+// EMAIL_VERIFIED, FIRST_NAME, LAST_NAME, PICTURE and HOSTED_DOMAIN.
+func NewOIDCRegistryFromEnv(ctx context.Context) (*OIDCRegistry, error) {
+	registry := NewOIDCRegistry()
+
+	for _, name := range strings.Split(os.Getenv("OIDC_PROVIDERS"), ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		prefix := "OIDC_" + strings.ToUpper(name) + "_"
+
+		claims := DefaultClaimMapping()
+		for field, target := range map[string]*string{
+			"SUBJECT":        &claims.Subject,
+			"EMAIL":          &claims.Email,
+			"EMAIL_VERIFIED": &claims.EmailVerified,
+			"FIRST_NAME":     &claims.FirstName,
+			"LAST_NAME":      &claims.LastName,
+			"PICTURE":        &claims.Picture,
+			"HOSTED_DOMAIN":  &claims.HostedDomain,
+		} {
+			if v, ok := os.LookupEnv(prefix + "CLAIM_" + field); ok {
+				*target = strings.TrimSpace(v)
+			}
+		}
+
+		trustEmail := false
+		if v := strings.TrimSpace(os.Getenv(prefix + "TRUST_EMAIL")); v != "" {
+			parsed, err := strconv.ParseBool(v)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %sTRUST_EMAIL '%s'", prefix, v)
+			}
+			trustEmail = parsed
+		}
+
+		provider, err := NewOIDCProvider(ctx, OIDCProviderConfig{
+			Name:         name,
+			IssuerURL:    os.Getenv(prefix + "ISSUER"),
+			ClientID:     os.Getenv(prefix + "CLIENT_ID"),
+			ClientSecret: os.Getenv(prefix + "CLIENT_SECRET"),
+			RedirectURL:  os.Getenv(prefix + "REDIRECT_URL"),
+			Scopes:       strings.Fields(strings.ReplaceAll(os.Getenv(prefix+"SCOPES"), ",", " ")),
+			Claims:       claims,
+			TrustEmail:   trustEmail,
+		})
+		if err != nil {
+			return nil, err
+		}
+		registry.providers[name] = provider
+	}
+	return registry, nil
+}
+
+// OIDCLoginHandler handles sign in through OpenID Connect providers other than Google.
+type OIDCLoginHandler struct {
+	DB           *database.DBinstanceStruct
+	Providers    *OIDCRegistry
+	DomainPolicy *EmailDomainPolicy
+	// RequireAdmin2FA forces admin to log in with TOTP, see LocalRegisterHandler.RequireAdmin2FA
+	RequireAdmin2FA bool
+}
+
+// NewOIDCLoginHandler creates a new instance of OIDCLoginHandler with the provided database connection and providers.
+func NewOIDCLoginHandler(db *database.DBinstanceStruct, providers *OIDCRegistry) *OIDCLoginHandler {
+	return &OIDCLoginHandler{
+		DB:              db,
+		Providers:       providers,
+		DomainPolicy:    NewEmailDomainPolicyFromEnv(),
+		RequireAdmin2FA: requireAdmin2FAFromEnv(),
+	}
+}
+
+type oidcProvidersResponse struct {
+	Providers []string `json:"providers"`
+}
+
+// ListProvidersHandler returns name of configured OpenID Connect providers
+// @Summary List OpenID Connect providers
+// @Tags Auth
+// @Produce json
+// @Success 200 {object} oidcProvidersResponse
+// @Router /auth/oidc/providers [get]
+func (h *OIDCLoginHandler) ListProvidersHandler(c *gin.Context) {
+	c.JSON(http.StatusOK, oidcProvidersResponse{Providers: h.Providers.Names()})
+}
+
+// oidcPurpose is purpose of state for signing in with the provider as the role
+func oidcPurpose(provider string, role string) string {
+	return provider + ":" + role
+}
+
+// userModelForRole returns empty user model of role that can sign in with OpenID Connect provider
+func userModelForRole(role string) (model.UserModel, bool) {
+	switch role {
+	case model.RoleCPSK:
+		return &model.CPSKUser{}, true
+	case model.RoleCompany:
+		return &model.CompanyUser{}, true
+	case model.RoleVisitor:
+		return &model.VisitorUser{}, true
+	}
+	return nil, false
+}
+
+// StartHandler starts sign in with the provider as the role by issuing signed state, PKCE code verifier and nonce.
+// Client redirects user to auth_url, keeps code_verifier, and sends it with the returned code and
+// state to the login endpoint of the same provider and role.
+// @Summary Start sign in with OpenID Connect provider
+// @Description State expires in 10 minutes and only work with the login endpoint of the provider and role
+// @Description State is bound to the browser by HttpOnly cookie, which must be sent with the login request
+// @Tags Auth
+// @Produce json
+// @Param provider path string true "Provider name from /auth/oidc/providers"
+// @Param role path string true "Role to sign in as" Enums(cpsk, company, visitor)
+// @Success 200 {object} oauthStartResponse
+// @Failure 400 {object} utilities.ErrorResponse "Invalid role"
+// @Failure 404 {object} utilities.ErrorResponse "Unknown provider"
+// @Failure 500 {object} utilities.ErrorResponse "Failed to issue state"
+// @Router /auth/oidc/{provider}/{role}/start [get]
+func (h *OIDCLoginHandler) StartHandler(c *gin.Context) {
+	provider, ok := h.Providers.Get(c.Param("provider"))
+	if !ok {
+		c.JSON(http.StatusNotFound, utilities.ErrorResponse{Error: "Unknown provider"})
+		return
+	}
+	if _, ok := userModelForRole(c.Param("role")); !ok {
+		c.JSON(http.StatusBadRequest, utilities.ErrorResponse{Error: "Role must be 'cpsk', 'company' or 'visitor'"})
+		return
+	}
+
+	startOAuth(c, provider.OauthConfig, oidcPurpose(provider.Name, c.Param("role")))
+}
+
+// LoginHandler validates state from StartHandler, exchanges authorization code of the provider,
+// validates its ID token, and logs in or registers user with the role
+// @Summary Handles sign in with OpenID Connect provider
+// @Description Checks and creates user in the database, generates an access token
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param provider path string true "Provider name from /auth/oidc/providers"
+// @Param role path string true "Role to sign in as" Enums(cpsk, company, visitor)
+// @Param Code body oauthCode true "Authorization code from the provider with state and code verifier from /auth/oidc/{provider}/{role}/start"
+// @Success 200 {object} model.CompanyResponse "Login success, response depend on role"
+// @Success 201 {object} model.CompanyResponse "Register success, response depend on role"
+// @Success 202 {object} twoFactorChallengeResponse "User has two-factor authentication, finish login with /auth/2fa/verify"
+// @Failure 400 {object} utilities.ErrorResponse "Invalid role, state, code or ID token"
+// @Failure 403 {object} utilities.ErrorResponse "Email domain is not allowed for the role"
+// @Failure 404 {object} utilities.ErrorResponse "Unknown provider"
+// @Failure 500 {object} utilities.ErrorResponse "Database error"
+// @Router /auth/oidc/{provider}/{role} [post]
+func (h *OIDCLoginHandler) LoginHandler(c *gin.Context) {
+	provider, ok := h.Providers.Get(c.Param("provider"))
+	if !ok {
+		c.JSON(http.StatusNotFound, utilities.ErrorResponse{Error: "Unknown provider"})
+		return
+	}
+
+	userModel, ok := userModelForRole(c.Param("role"))
+	if !ok {
+		c.JSON(http.StatusBadRequest, utilities.ErrorResponse{Error: "Role must be 'cpsk', 'company' or 'visitor'"})
+		return
+	}
+
+	authType := "OIDC:" + provider.Name
+	body, state, err := bindOAuthCallback(c, authType, oidcPurpose(provider.Name, c.Param("role")))
+	if err != nil {
+		return
+	}
+
+	account, err := provider.Authenticate(c.Request.Context(), body.Code, body.CodeVerifier, state.Nonce)
+	if err != nil {
+		LogAuthAttempt("warning", authType, "Fail", "", err.Error())
+		c.JSON(http.StatusBadRequest, utilities.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	signInExternalUser(c, h.DB, h.DomainPolicy, h.RequireAdmin2FA, account, userModel)
+}
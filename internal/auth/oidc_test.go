@@ -0,0 +1,332 @@
+package auth
+
+import (
+	"HireMeMaybe-backend/internal/model"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeOIDCServer is a minimal OpenID Connect issuer serving discovery, JWKS and token endpoints.
+// Authorization code is looked up in Codes to get claims of the issued ID token.
+type fakeOIDCServer struct {
+	Server *httptest.Server
+	Key    *rsa.PrivateKey
+	// SigningKey sign ID tokens, differ from Key to simulate forged token
+	SigningKey *rsa.PrivateKey
+	ClientID   string
+	Codes      map[string]jwt.MapClaims
+}
+
+func newFakeOIDCServer(t *testing.T) *fakeOIDCServer {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	f := &fakeOIDCServer{
+		Key:        key,
+		SigningKey: key,
+		ClientID:   "fake_client_id",
+		Codes:      map[string]jwt.MapClaims{},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"issuer":                                f.Server.URL,
+			"authorization_endpoint":                f.Server.URL + "/authorize",
+			"token_endpoint":                        f.Server.URL + "/token",
+			"jwks_uri":                              f.Server.URL + "/jwks",
+			"id_token_signing_alg_values_supported": []string{"RS256"},
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []map[string]string{{
+				"kty": "RSA",
+				"alg": "RS256",
+				"use": "sig",
+				"kid": "fake-key",
+				"n":   base64.RawURLEncoding.EncodeToString(f.Key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(f.Key.E)).Bytes()),
+			}},
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, "invalid form", http.StatusBadRequest)
+			return
+		}
+		claims, ok := f.Codes[r.FormValue("code")]
+		if !ok {
+			http.Error(w, `{"error":"invalid_grant"}`, http.StatusBadRequest)
+			return
+		}
+
+		idClaims := jwt.MapClaims{
+			"iss": f.Server.URL,
+			"aud": f.ClientID,
+			"iat": time.Now().Unix(),
+			"exp": time.Now().Add(time.Hour).Unix(),
+		}
+		for k, v := range claims {
+			idClaims[k] = v
+		}
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, idClaims)
+		token.Header["kid"] = "fake-key"
+		idToken, err := token.SignedString(f.SigningKey)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token": "fake_access_token",
+			"token_type":   "Bearer",
+			"expires_in":   3600,
+			"id_token":     idToken,
+		})
+	})
+	f.Server = httptest.NewServer(mux)
+	t.Cleanup(f.Server.Close)
+	return f
+}
+
+func (f *fakeOIDCServer) provider(t *testing.T, name string, claims ClaimMapping) *OIDCProvider {
+	t.Helper()
+	provider, err := NewOIDCProvider(context.Background(), OIDCProviderConfig{
+		Name:         name,
+		IssuerURL:    f.Server.URL,
+		ClientID:     f.ClientID,
+		ClientSecret: "fake_client_secret",
+		Claims:       claims,
+	})
+	assert.NoError(t, err)
+	return provider
+}
+
+func TestOIDCProviderAuthenticate(t *testing.T) {
+	fake := newFakeOIDCServer(t)
+	fake.Codes["code_1"] = jwt.MapClaims{
+		"nonce":              "nonce_1",
+		"sub":                "azure-user-1",
+		"preferred_username": "jane@partner.example.com",
+		"given_name":         "Jane",
+		"family_name":        "Doe",
+	}
+
+	claims := DefaultClaimMapping()
+	claims.Email = "preferred_username"
+	provider := fake.provider(t, "microsoft", claims)
+
+	account, err := provider.Authenticate(context.Background(), "code_1", "verifier", "nonce_1")
+	assert.NoError(t, err)
+	assert.Equal(t, "microsoft", account.Provider)
+	assert.Equal(t, "azure-user-1", account.Subject)
+	assert.Equal(t, "jane@partner.example.com", account.Profile.Email)
+	assert.Equal(t, "Jane", account.Profile.FirstName)
+	assert.False(t, account.Profile.EmailVerified)
+	assert.Empty(t, account.Profile.GID)
+
+	_, err = provider.Authenticate(context.Background(), "code_1", "verifier", "other_nonce")
+	assert.Error(t, err, "ID token nonce must match state")
+
+	_, err = provider.Authenticate(context.Background(), "unknown_code", "verifier", "nonce_1")
+	assert.Error(t, err)
+}
+
+func TestOIDCProviderTrustEmail(t *testing.T) {
+	fake := newFakeOIDCServer(t)
+	fake.Codes["code_1"] = jwt.MapClaims{
+		"nonce":          "nonce_1",
+		"sub":            "user-1",
+		"email":          "jane@example.com",
+		"email_verified": true,
+	}
+
+	untrusted := fake.provider(t, "partner", DefaultClaimMapping())
+	account, err := untrusted.Authenticate(context.Background(), "code_1", "verifier", "nonce_1")
+	assert.NoError(t, err)
+	assert.False(t, account.Profile.EmailVerified, "email_verified of untrusted provider is ignored")
+
+	trusted := fake.provider(t, "partner", DefaultClaimMapping())
+	trusted.TrustEmail = true
+	account, err = trusted.Authenticate(context.Background(), "code_1", "verifier", "nonce_1")
+	assert.NoError(t, err)
+	assert.True(t, account.Profile.EmailVerified)
+}
+
+func TestOIDCProviderRejectInvalidIDToken(t *testing.T) {
+	fake := newFakeOIDCServer(t)
+	fake.Codes["code_1"] = jwt.MapClaims{"sub": "user-1", "nonce": "nonce_1"}
+	provider := fake.provider(t, "partner", DefaultClaimMapping())
+
+	// Signed by key not in JWKS
+	forged, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	fake.SigningKey = forged
+	_, err = provider.Authenticate(context.Background(), "code_1", "verifier", "nonce_1")
+	assert.Error(t, err)
+
+	// Issued for other client
+	fake.SigningKey = fake.Key
+	fake.Codes["code_2"] = jwt.MapClaims{"sub": "user-1", "nonce": "nonce_1", "aud": "other_client"}
+	_, err = provider.Authenticate(context.Background(), "code_2", "verifier", "nonce_1")
+	assert.Error(t, err)
+
+	// Expired
+	fake.Codes["code_3"] = jwt.MapClaims{"sub": "user-1", "nonce": "nonce_1", "exp": time.Now().Add(-time.Hour).Unix()}
+	_, err = provider.Authenticate(context.Background(), "code_3", "verifier", "nonce_1")
+	assert.Error(t, err)
+}
+
+func TestNewOIDCRegistryFromEnv(t *testing.T) {
+	fake := newFakeOIDCServer(t)
+	t.Setenv("OIDC_PROVIDERS", "Microsoft, ")
+	t.Setenv("OIDC_MICROSOFT_ISSUER", fake.Server.URL)
+	t.Setenv("OIDC_MICROSOFT_CLIENT_ID", fake.ClientID)
+	t.Setenv("OIDC_MICROSOFT_CLIENT_SECRET", "secret")
+	t.Setenv("OIDC_MICROSOFT_SCOPES", "openid,email")
+	t.Setenv("OIDC_MICROSOFT_CLAIM_EMAIL", "upn")
+
+	registry, err := NewOIDCRegistryFromEnv(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"microsoft"}, registry.Names())
+
+	provider, ok := registry.Get("microsoft")
+	if assert.True(t, ok) {
+		assert.Equal(t, "upn", provider.Claims.Email)
+		assert.Equal(t, "sub", provider.Claims.Subject)
+		assert.Equal(t, []string{"openid", "email"}, provider.OauthConfig.Scopes)
+		assert.Equal(t, fake.Server.URL+"/token", provider.OauthConfig.Endpoint.TokenURL)
+		assert.False(t, provider.TrustEmail)
+	}
+
+	t.Setenv("OIDC_MICROSOFT_TRUST_EMAIL", "true")
+	registry, err = NewOIDCRegistryFromEnv(context.Background())
+	assert.NoError(t, err)
+	if provider, ok := registry.Get("microsoft"); assert.True(t, ok) {
+		assert.True(t, provider.TrustEmail)
+	}
+
+	t.Setenv("OIDC_MICROSOFT_TRUST_EMAIL", "maybe")
+	_, err = NewOIDCRegistryFromEnv(context.Background())
+	assert.Error(t, err)
+	t.Setenv("OIDC_MICROSOFT_TRUST_EMAIL", "")
+
+	t.Setenv("OIDC_PROVIDERS", "google")
+	_, err = NewOIDCRegistryFromEnv(context.Background())
+	assert.Error(t, err)
+}
+
+func TestOIDCLogin(t *testing.T) {
+	fake := newFakeOIDCServer(t)
+	handler := NewOIDCLoginHandler(testDB, NewOIDCRegistry(fake.provider(t, "partner", DefaultClaimMapping())))
+	handler.DomainPolicy = &EmailDomainPolicy{}
+
+	r := gin.New()
+	r.GET("/auth/oidc/:provider/:role/start", handler.StartHandler)
+	r.POST("/auth/oidc/:provider/:role", handler.LoginHandler)
+
+	// start begins sign in and authorizes the partner user with nonce of the authorization request,
+	// returning login request body and binding cookie
+	start := func(provider string, role string) (map[string]string, *http.Cookie) {
+		rec := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/auth/oidc/"+provider+"/"+role+"/start", nil)
+		r.ServeHTTP(rec, req)
+		if !assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String()) {
+			return map[string]string{"code": "code_1"}, nil
+		}
+
+		var resp oauthStartResponse
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		authURL, err := url.Parse(resp.AuthURL)
+		assert.NoError(t, err)
+		assert.Equal(t, resp.State, authURL.Query().Get("state"))
+		assert.NotEmpty(t, authURL.Query().Get("code_challenge"))
+
+		fake.Codes["code_1"] = jwt.MapClaims{
+			"sub":         "partner-user-1",
+			"email":       "recruiter@partner.example.com",
+			"given_name":  "Recruiter",
+			"family_name": "Partner",
+			"nonce":       authURL.Query().Get("nonce"),
+		}
+		return map[string]string{
+			"code":          "code_1",
+			"state":         resp.State,
+			"code_verifier": resp.CodeVerifier,
+		}, bindingCookie(t, rec)
+	}
+
+	login := func(provider string, role string, body map[string]string, cookie *http.Cookie) (*httptest.ResponseRecorder, map[string]interface{}) {
+		payload, _ := json.Marshal(body)
+		rec := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, "/auth/oidc/"+provider+"/"+role, bytes.NewReader(payload))
+		req.Header.Set("Content-Type", "application/json")
+		if cookie != nil {
+			req.AddCookie(cookie)
+		}
+		r.ServeHTTP(rec, req)
+
+		var resp map[string]interface{}
+		_ = json.Unmarshal(rec.Body.Bytes(), &resp)
+		return rec, resp
+	}
+
+	body, cookie := start("partner", model.RoleCompany)
+	rec, resp := login("partner", model.RoleCompany, body, cookie)
+	assert.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	claims := assertValidAccessToken(t, resp)
+
+	var identity model.UserIdentity
+	assert.NoError(t, testDB.Where("provider = ? AND subject = ?", "partner", "partner-user-1").First(&identity).Error)
+	assert.Equal(t, claims.Subject, identity.UserID.String())
+
+	var user model.User
+	assert.NoError(t, testDB.Where("id = ?", identity.UserID).First(&user).Error)
+	assert.Empty(t, user.GoogleID)
+	assert.Equal(t, model.RoleCompany, user.Role)
+
+	// Second sign in find the same user
+	body, cookie = start("partner", model.RoleCompany)
+	rec, resp = login("partner", model.RoleCompany, body, cookie)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, claims.Subject, assertValidAccessToken(t, resp).Subject)
+
+	// Raw code without state, or state of another browser or role, is rejected
+	body, cookie = start("partner", model.RoleCompany)
+	rec, _ = login("partner", model.RoleCompany, map[string]string{"code": "code_1"}, cookie)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec, _ = login("partner", model.RoleCompany, body, nil)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	body, cookie = start("partner", model.RoleCompany)
+	rec, _ = login("partner", model.RoleVisitor, body, cookie)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	// ID token nonce from another sign in
+	body, cookie = start("partner", model.RoleCompany)
+	fake.Codes["code_1"]["nonce"] = "other_nonce"
+	rec, _ = login("partner", model.RoleCompany, body, cookie)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec, _ = login("unknown", model.RoleCompany, body, cookie)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec, _ = login("partner", model.RoleAdmin, body, cookie)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
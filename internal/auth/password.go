@@ -0,0 +1,363 @@
+package auth
+
+import (
+	"HireMeMaybe-backend/internal/database"
+	"HireMeMaybe-backend/internal/mail"
+	"HireMeMaybe-backend/internal/model"
+	"HireMeMaybe-backend/internal/utilities"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	minPasswordLength   = 8
+	errPasswordTooShort = "Password should longer or equal to 8 characters"
+
+	// PasswordResetTokenDuration is how long password reset link stay valid
+	PasswordResetTokenDuration = 30 * time.Minute
+)
+
+var errInvalidResetToken = errors.New("invalid or expired reset token")
+
+// PasswordController handles password change and password reset of local accounts
+type PasswordController struct {
+	DB     *database.DBinstanceStruct
+	Mailer mail.Sender
+	// ResetURL is frontend page receiving reset token as "token" query parameter
+	ResetURL string
+}
+
+// NewPasswordController creates a new instance of PasswordController.
+// Reset link point to PASSWORD_RESET_URL environment.
+func NewPasswordController(db *database.DBinstanceStruct, mailer mail.Sender) *PasswordController {
+	resetURL := os.Getenv("PASSWORD_RESET_URL")
+	if resetURL == "" {
+		resetURL = "http://localhost:3000/reset-password"
+	}
+	return &PasswordController{
+		DB:       db,
+		Mailer:   mailer,
+		ResetURL: resetURL,
+	}
+}
+
+type changePasswordRequest struct {
+	OldPassword string `json:"old_password" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required"`
+}
+
+type setPasswordRequest struct {
+	Password string `json:"password" binding:"required"`
+}
+
+type forgotPasswordRequest struct {
+	Username string `json:"username"`
+	Email    string `json:"email"`
+}
+
+type resetPasswordRequest struct {
+	Token       string `json:"token" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required"`
+}
+
+// setPassword stores new hashed password of the user, then invalidate every session of the user
+func setPassword(tx *gorm.DB, userID uuid.UUID, hashedPassword string) error {
+	if err := tx.Model(&model.User{}).Where("id = ?", userID).Update("password", hashedPassword).Error; err != nil {
+		return err
+	}
+	return invalidateUserSessions(tx, userID)
+}
+
+func respondHashError(c *gin.Context, err error) {
+	c.JSON(http.StatusInternalServerError, utilities.ErrorResponse{
+		Error: fmt.Sprintf("Failed hash password: %s", err.Error()),
+	})
+}
+
+// ChangePasswordHandler changes password of current user
+// @Summary Change password
+// @Description Old password is required, every session including the current one is logged out on success
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Insert your access token" default(Bearer <your access token>)
+// @Param Info body changePasswordRequest true "Old and new password"
+// @Success 200 {object} utilities.MessageResponse "Password changed"
+// @Failure 400 {object} utilities.ErrorResponse "Info provided not met the condition"
+// @Failure 401 {object} utilities.ErrorResponse "Unauthorized or old password is incorrect"
+// @Failure 500 {object} utilities.ErrorResponse "Database or password hashing error"
+// @Router /auth/password/change [post]
+func (pc *PasswordController) ChangePasswordHandler(c *gin.Context) {
+	user, err := utilities.ExtractUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, utilities.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	var req changePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utilities.ErrorResponse{
+			Error: "Old password and new password must be provided",
+		})
+		return
+	}
+
+	if user.Password == "" || !utilities.VerifyPassword(req.OldPassword, user.Password) {
+		LogAuthAttempt("warning", "Local", "Fail", user.Username, "Password change with incorrect old password")
+		c.JSON(http.StatusUnauthorized, utilities.ErrorResponse{Error: "Old password is incorrect"})
+		return
+	}
+
+	if len(req.NewPassword) < minPasswordLength {
+		c.JSON(http.StatusBadRequest, utilities.ErrorResponse{Error: errPasswordTooShort})
+		return
+	}
+
+	hashedPassword, err := utilities.HashPassword(req.NewPassword)
+	if err != nil {
+		respondHashError(c, err)
+		return
+	}
+
+	err = pc.DB.Transaction(func(tx *gorm.DB) error {
+		return setPassword(tx, user.ID, hashedPassword)
+	})
+	if err != nil {
+		utilities.RespondDBError(c, err)
+		return
+	}
+
+	LogAuthAttempt("info", "Local", "Success", user.Username, "Password changed")
+	c.JSON(http.StatusOK, utilities.MessageResponse{Message: "Password changed, please log in again"})
+}
+
+// SetPasswordHandler adds password to current user signed up with Google
+// @Summary Add password to Google account
+// @Description Only for user without password. The user can then log in locally with username or verified email
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Insert your access token" default(Bearer <your access token>)
+// @Param Info body setPasswordRequest true "New password"
+// @Success 200 {object} utilities.MessageResponse "Password set"
+// @Failure 400 {object} utilities.ErrorResponse "Info provided not met the condition"
+// @Failure 401 {object} utilities.ErrorResponse "Unauthorized"
+// @Failure 409 {object} utilities.ErrorResponse "User already has password"
+// @Failure 500 {object} utilities.ErrorResponse "Database or password hashing error"
+// @Router /auth/password/set [post]
+func (pc *PasswordController) SetPasswordHandler(c *gin.Context) {
+	user, err := utilities.ExtractUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, utilities.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	if user.Password != "" {
+		c.JSON(http.StatusConflict, utilities.ErrorResponse{Error: "Password is already set, use /auth/password/change instead"})
+		return
+	}
+
+	var req setPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utilities.ErrorResponse{Error: "Password must be provided"})
+		return
+	}
+
+	if len(req.Password) < minPasswordLength {
+		c.JSON(http.StatusBadRequest, utilities.ErrorResponse{Error: errPasswordTooShort})
+		return
+	}
+
+	hashedPassword, err := utilities.HashPassword(req.Password)
+	if err != nil {
+		respondHashError(c, err)
+		return
+	}
+
+	// Condition on empty password so concurrent request can't overwrite each other
+	result := pc.DB.Model(&model.User{}).Where("id = ? AND password = ''", user.ID).Update("password", hashedPassword)
+	if result.Error != nil {
+		utilities.RespondDBError(c, result.Error)
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusConflict, utilities.ErrorResponse{Error: "Password is already set, use /auth/password/change instead"})
+		return
+	}
+
+	LogAuthAttempt("info", "Local", "Success", user.Username, "Password set for Google account")
+	c.JSON(http.StatusOK, utilities.MessageResponse{Message: "Password set"})
+}
+
+// ForgotPasswordHandler sends password reset link to email of the local account
+// @Summary Request password reset link
+// @Description Either username or email must be provided, email must be verified and belong to only one account.
+// @Description Response is the same whether the account exist or not
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param Info body forgotPasswordRequest true "Username or email of the account"
+// @Success 202 {object} utilities.MessageResponse "Reset link is sent if the account exist"
+// @Failure 400 {object} utilities.ErrorResponse "Username or email is not provided"
+// @Failure 500 {object} utilities.ErrorResponse "Database error"
+// @Router /auth/password/forgot [post]
+func (pc *PasswordController) ForgotPasswordHandler(c *gin.Context) {
+	var req forgotPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil || (req.Username == "" && req.Email == "") {
+		c.JSON(http.StatusBadRequest, utilities.ErrorResponse{Error: "Username or email must be provided"})
+		return
+	}
+
+	accepted := utilities.MessageResponse{Message: "If the account exist, password reset link has been sent to its email"}
+
+	query := pc.DB.Where("password <> ''")
+	if req.Username != "" {
+		query = query.Where("username = ?", req.Username)
+	} else {
+		// Same as login by email, only verified email identifies the account
+		query = query.Where("LOWER(email) = ? AND email_verified = ?", normalizeEmail(req.Email), true)
+	}
+
+	var users []model.User
+	if err := query.Limit(2).Find(&users).Error; err != nil {
+		utilities.RespondDBError(c, err)
+		return
+	}
+	switch len(users) {
+	case 0:
+		LogAuthAttempt("warning", "Local", "Fail", req.Username+req.Email, "Password reset for unknown local account")
+		c.JSON(http.StatusAccepted, accepted)
+		return
+
+	case 1:
+		// Do nothing
+
+	default:
+		// Email is not unique, link could be sent for the wrong account
+		LogAuthAttempt("warning", "Local", "Fail", req.Email, "Password reset for email of several accounts")
+		c.JSON(http.StatusAccepted, accepted)
+		return
+	}
+	user := users[0]
+
+	if user.Email == nil || *user.Email == "" {
+		LogAuthAttempt("warning", "Local", "Fail", user.Username, "Password reset for account without email")
+		c.JSON(http.StatusAccepted, accepted)
+		return
+	}
+
+	raw, err := NewOpaqueToken()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utilities.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	err = pc.DB.Transaction(func(tx *gorm.DB) error {
+		// Only the latest link can be used
+		if err := tx.Where("user_id = ? AND used_at IS NULL", user.ID).Delete(&model.PasswordResetToken{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(&model.PasswordResetToken{
+			UserID:    user.ID,
+			TokenHash: HashToken(raw),
+			ExpiresAt: time.Now().Add(PasswordResetTokenDuration),
+		}).Error
+	})
+	if err != nil {
+		utilities.RespondDBError(c, err)
+		return
+	}
+
+	link := pc.ResetURL + "?token=" + url.QueryEscape(raw)
+	err = pc.Mailer.Send(mail.Message{
+		To:      *user.Email,
+		Subject: "Reset your HireMeMaybe password",
+		Body: fmt.Sprintf("Hi %s,\n\nUse the link below to reset your password. The link expires in %d minutes.\n\n%s\n\nIf you did not request this, you can ignore this email.",
+			user.Username, int(PasswordResetTokenDuration.Minutes()), link),
+	})
+	if err != nil {
+		LogAuthAttempt("error", "Local", "Fail", user.Username, fmt.Sprintf("Failed to send password reset email: %s", err.Error()))
+	} else {
+		LogAuthAttempt("info", "Local", "Success", user.Username, "Password reset link sent")
+	}
+
+	c.JSON(http.StatusAccepted, accepted)
+}
+
+// ResetPasswordHandler sets new password using token from password reset link
+// @Summary Reset password
+// @Description Reset token can be used once, every session of the user is logged out on success
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param Info body resetPasswordRequest true "Reset token and new password"
+// @Success 200 {object} utilities.MessageResponse "Password has been reset"
+// @Failure 400 {object} utilities.ErrorResponse "Info provided not met the condition or token is invalid"
+// @Failure 500 {object} utilities.ErrorResponse "Database or password hashing error"
+// @Router /auth/password/reset [post]
+func (pc *PasswordController) ResetPasswordHandler(c *gin.Context) {
+	var req resetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utilities.ErrorResponse{Error: "Token and new password must be provided"})
+		return
+	}
+
+	if len(req.NewPassword) < minPasswordLength {
+		c.JSON(http.StatusBadRequest, utilities.ErrorResponse{Error: errPasswordTooShort})
+		return
+	}
+
+	hashedPassword, err := utilities.HashPassword(req.NewPassword)
+	if err != nil {
+		respondHashError(c, err)
+		return
+	}
+
+	var user model.User
+	err = pc.DB.Transaction(func(tx *gorm.DB) error {
+		var record model.PasswordResetToken
+		err := tx.Preload("User").
+			Where("token_hash = ? AND used_at IS NULL AND expires_at > ?", HashToken(req.Token), time.Now()).
+			First(&record).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errInvalidResetToken
+		}
+		if err != nil {
+			return err
+		}
+
+		result := tx.Model(&model.PasswordResetToken{}).
+			Where("id = ? AND used_at IS NULL", record.ID).
+			Update("used_at", time.Now())
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			// Used by another request
+			return errInvalidResetToken
+		}
+
+		user = record.User
+		return setPassword(tx, user.ID, hashedPassword)
+	})
+	if errors.Is(err, errInvalidResetToken) {
+		LogAuthAttempt("warning", "Local", "Fail", "", "Invalid password reset token")
+		c.JSON(http.StatusBadRequest, utilities.ErrorResponse{Error: "Reset token is invalid or expired"})
+		return
+	}
+	if err != nil {
+		utilities.RespondDBError(c, err)
+		return
+	}
+
+	LogAuthAttempt("info", "Local", "Success", user.Username, "Password reset")
+	c.JSON(http.StatusOK, utilities.MessageResponse{Message: "Password has been reset, please log in again"})
+}
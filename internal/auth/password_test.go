@@ -0,0 +1,209 @@
+package auth
+
+import (
+	"HireMeMaybe-backend/internal/database"
+	"HireMeMaybe-backend/internal/mail"
+	"HireMeMaybe-backend/internal/model"
+	"HireMeMaybe-backend/internal/utilities"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingSender struct {
+	sent []mail.Message
+}
+
+func (s *recordingSender) Send(msg mail.Message) error {
+	s.sent = append(s.sent, msg)
+	return nil
+}
+
+// Helper: create local user with email and seed password, removed when test end.
+func createPasswordTestUser(t *testing.T, username string) model.User {
+	t.Helper()
+	hashed, err := utilities.HashPassword(database.TestSeedPassword)
+	assert.NoError(t, err)
+	email := username + "@example.com"
+	user := model.User{
+		ID:       uuid.New(),
+		Username: username,
+		Email:    &email,
+		Password: hashed,
+		Role:     model.RoleAdmin,
+	}
+	assert.NoError(t, testDB.Create(&user).Error)
+	t.Cleanup(func() { testDB.Delete(&model.User{}, "id = ?", user.ID) })
+	return user
+}
+
+// Helper: call handler with JSON body and cookies as the user authenticated by RequireAuth.
+func callAsUser(t *testing.T, handler gin.HandlerFunc, user model.User, body interface{}, cookies ...*http.Cookie) (*httptest.ResponseRecorder, map[string]interface{}) {
+	t.Helper()
+	payload, err := json.Marshal(body)
+	assert.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(rec)
+	c.Request, _ = http.NewRequest(http.MethodPost, "/", bytes.NewReader(payload))
+	c.Request.Header.Set("Content-Type", "application/json")
+	for _, cookie := range cookies {
+		c.Request.AddCookie(cookie)
+	}
+	c.Set("user", user)
+
+	handler(c)
+
+	var resp map[string]interface{}
+	_ = json.Unmarshal(rec.Body.Bytes(), &resp)
+	return rec, resp
+}
+
+func callChangePassword(t *testing.T, pc *PasswordController, user model.User, body map[string]string) *httptest.ResponseRecorder {
+	t.Helper()
+	rec, _ := callAsUser(t, pc.ChangePasswordHandler, user, body)
+	return rec
+}
+
+// Helper: extract "token" query parameter of the link to baseURL in the mail body.
+func tokenFromMail(t *testing.T, msg mail.Message, baseURL string) string {
+	t.Helper()
+	start := strings.Index(msg.Body, baseURL)
+	if !assert.GreaterOrEqual(t, start, 0, "link not found in mail body") {
+		return ""
+	}
+	link, err := url.Parse(strings.Fields(msg.Body[start:])[0])
+	assert.NoError(t, err)
+	token := link.Query().Get("token")
+	assert.NotEmpty(t, token)
+	return token
+}
+
+func TestChangePassword(t *testing.T) {
+	user := createPasswordTestUser(t, "change_password_user")
+	_, refreshToken := loginTokenPair(t, user.Username)
+	pc := NewPasswordController(testDB, &recordingSender{})
+
+	rec := callChangePassword(t, pc, user, map[string]string{
+		"old_password": "wrong-password",
+		"new_password": "NewPassword123",
+	})
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	rec = callChangePassword(t, pc, user, map[string]string{
+		"old_password": database.TestSeedPassword,
+		"new_password": "short",
+	})
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = callChangePassword(t, pc, user, map[string]string{
+		"old_password": database.TestSeedPassword,
+		"new_password": "NewPassword123",
+	})
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	var updated model.User
+	assert.NoError(t, testDB.Where("id = ?", user.ID).First(&updated).Error)
+	assert.True(t, utilities.VerifyPassword("NewPassword123", updated.Password))
+	assert.NotNil(t, updated.TokensValidAfter)
+
+	// Old session is revoked
+	refreshRec, _ := callRefresh(t, refreshToken)
+	assert.Equal(t, http.StatusUnauthorized, refreshRec.Code)
+}
+
+func TestPasswordResetFlow(t *testing.T) {
+	user := createPasswordTestUser(t, "reset_password_user")
+	assert.NoError(t, updateUserIdentity(testDB.DB, user.ID, map[string]interface{}{"email_verified": true}))
+	_, refreshToken := loginTokenPair(t, user.Username)
+	sender := &recordingSender{}
+	pc := NewPasswordController(testDB, sender)
+
+	rec, _, err := utilities.SimulateAPICall(pc.ForgotPasswordHandler, "/password/forgot", http.MethodPost, map[string]string{
+		"email": strings.ToUpper(*user.Email),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusAccepted, rec.Code, rec.Body.String())
+	if !assert.Len(t, sender.sent, 1) {
+		return
+	}
+	assert.Equal(t, *user.Email, sender.sent[0].To)
+
+	token := tokenFromMail(t, sender.sent[0], pc.ResetURL)
+
+	var record model.PasswordResetToken
+	assert.NoError(t, testDB.Where("user_id = ?", user.ID).First(&record).Error)
+	assert.NotEqual(t, token, record.TokenHash, "raw token must not be stored")
+
+	reset := func(token string) *httptest.ResponseRecorder {
+		rec, _, err := utilities.SimulateAPICall(pc.ResetPasswordHandler, "/password/reset", http.MethodPost, map[string]string{
+			"token":        token,
+			"new_password": "ResetPassword123",
+		})
+		assert.NoError(t, err)
+		return rec
+	}
+
+	assert.Equal(t, http.StatusOK, reset(token).Code)
+	// Token is single use
+	assert.Equal(t, http.StatusBadRequest, reset(token).Code)
+	assert.Equal(t, http.StatusBadRequest, reset("not-a-real-token").Code)
+
+	var updated model.User
+	assert.NoError(t, testDB.Where("id = ?", user.ID).First(&updated).Error)
+	assert.True(t, utilities.VerifyPassword("ResetPassword123", updated.Password))
+
+	refreshRec, _ := callRefresh(t, refreshToken)
+	assert.Equal(t, http.StatusUnauthorized, refreshRec.Code)
+}
+
+func TestForgotPasswordUnknownAccount(t *testing.T) {
+	sender := &recordingSender{}
+	pc := NewPasswordController(testDB, sender)
+
+	rec, _, err := utilities.SimulateAPICall(pc.ForgotPasswordHandler, "/password/forgot", http.MethodPost, map[string]string{
+		"username": "no_such_user",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusAccepted, rec.Code)
+	assert.Empty(t, sender.sent)
+
+	rec, _, err = utilities.SimulateAPICall(pc.ForgotPasswordHandler, "/password/forgot", http.MethodPost, map[string]string{})
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestForgotPasswordRequireVerifiedUniqueEmail(t *testing.T) {
+	sender := &recordingSender{}
+	pc := NewPasswordController(testDB, sender)
+	forgot := func(email string) {
+		t.Helper()
+		rec, _, err := utilities.SimulateAPICall(pc.ForgotPasswordHandler, "/password/forgot", http.MethodPost, map[string]string{
+			"email": email,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusAccepted, rec.Code)
+	}
+
+	// Email typed by user who doesn't own it is not verified
+	unverified := createPasswordTestUser(t, "reset_unverified_user")
+	forgot(*unverified.Email)
+	assert.Empty(t, sender.sent)
+
+	first := createPasswordTestUser(t, "reset_shared_email_1")
+	second := createPasswordTestUser(t, "reset_shared_email_2")
+	shared := "reset.shared@example.com"
+	for _, user := range []model.User{first, second} {
+		assert.NoError(t, updateUserIdentity(testDB.DB, user.ID, map[string]interface{}{"email": shared, "email_verified": true}))
+	}
+	forgot(shared)
+	assert.Empty(t, sender.sent, "Link is not sent when email match several accounts")
+}
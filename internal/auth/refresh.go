@@ -0,0 +1,102 @@
+package auth
+
+import (
+	"HireMeMaybe-backend/internal/database"
+	"HireMeMaybe-backend/internal/model"
+	"HireMeMaybe-backend/internal/utilities"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// RefreshController handles exchanging refresh token for a new token pair.
+type RefreshController struct {
+	DB    *database.DBinstanceStruct
+	Store *RefreshTokenStore
+}
+
+// NewRefreshController creates a new instance of RefreshController with the provided database connection.
+func NewRefreshController(db *database.DBinstanceStruct) *RefreshController {
+	return &RefreshController{
+		DB:    db,
+		Store: NewRefreshTokenStore(db),
+	}
+}
+
+type refreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+type tokenPairResponse struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+// RefreshHandler exchanges refresh token for a new access token and rotated refresh token
+// @Summary Exchange refresh token for a new token pair
+// @Description Refresh token can be used only once, reusing an old refresh token revokes every token of that session
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param Token body refreshRequest true "Refresh token from login or previous refresh"
+// @Success 200 {object} tokenPairResponse "New access and refresh token"
+// @Failure 400 {object} utilities.ErrorResponse "Refresh token is not provided"
+// @Failure 401 {object} utilities.ErrorResponse "Refresh token is invalid, expired or already used"
+// @Failure 500 {object} utilities.ErrorResponse "Database error"
+// @Router /auth/refresh [post]
+func (rc *RefreshController) RefreshHandler(c *gin.Context) {
+	var req refreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utilities.ErrorResponse{
+			Error: "Refresh token is not provided",
+		})
+		return
+	}
+
+	record, refreshToken, err := rc.Store.Rotate(req.RefreshToken)
+	switch {
+	case err == nil:
+		// Do nothing
+
+	case errors.Is(err, ErrRefreshTokenReused):
+		LogAuthAttempt("warning", "Refresh", "Fail", "", "Refresh token reused, session revoked")
+		c.JSON(http.StatusUnauthorized, utilities.ErrorResponse{Error: err.Error()})
+		return
+
+	case errors.Is(err, ErrInvalidRefreshToken), errors.Is(err, ErrRefreshTokenExpired):
+		c.JSON(http.StatusUnauthorized, utilities.ErrorResponse{Error: err.Error()})
+		return
+
+	default:
+		utilities.RespondDBError(c, err)
+		return
+	}
+
+	var user model.User
+	if err := rc.DB.Where("id = ?", record.UserID).First(&user).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusUnauthorized, utilities.ErrorResponse{Error: "User not exist"})
+			return
+		}
+		utilities.RespondDBError(c, err)
+		return
+	}
+
+	accessToken, err := generateAccessToken(user.ID, record.FamilyID.String(), time.Hour, JwtIssuer)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utilities.ErrorResponse{
+			Error: fmt.Sprintf("Failed to generate access token: %s", err.Error()),
+		})
+		return
+	}
+
+	LogAuthAttempt("info", "Refresh", "Success", user.Username, "Token refreshed")
+	c.JSON(http.StatusOK, tokenPairResponse{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+	})
+}
@@ -0,0 +1,112 @@
+package auth
+
+import (
+	"HireMeMaybe-backend/internal/database"
+	"HireMeMaybe-backend/internal/utilities"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// Helper: login seeded user and return access and refresh token.
+func loginTokenPair(t *testing.T, username string) (string, string) {
+	t.Helper()
+	handler := NewLocalAuthHandler(testDB)
+	rec, resp, err := utilities.SimulateAPICall(handler.LocalLoginHandler, "/login", http.MethodPost, map[string]string{
+		"username": username,
+		"password": database.TestSeedPassword,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code, "body: %s", rec.Body.String())
+
+	accessToken, _ := resp["access_token"].(string)
+	refreshToken, _ := resp["refresh_token"].(string)
+	assert.NotEmpty(t, accessToken)
+	assert.NotEmpty(t, refreshToken)
+	return accessToken, refreshToken
+}
+
+func callRefresh(t *testing.T, refreshToken string) (*httptest.ResponseRecorder, map[string]interface{}) {
+	t.Helper()
+	controller := NewRefreshController(testDB)
+	rec, resp, err := utilities.SimulateAPICall(controller.RefreshHandler, "/refresh", http.MethodPost, map[string]string{
+		"refresh_token": refreshToken,
+	})
+	assert.NoError(t, err)
+	return rec, resp
+}
+
+func TestRefreshRotatesToken(t *testing.T) {
+	_, refreshToken := loginTokenPair(t, database.TestUserCPSK1.Username)
+
+	rec, resp := callRefresh(t, refreshToken)
+	assert.Equal(t, http.StatusOK, rec.Code, "body: %s", rec.Body.String())
+
+	claims := assertValidAccessToken(t, resp)
+	assert.Equal(t, database.TestUserCPSK1.ID.String(), claims.Subject)
+	assert.NotEmpty(t, claims.SessionID)
+
+	newRefresh, _ := resp["refresh_token"].(string)
+	assert.NotEmpty(t, newRefresh)
+	assert.NotEqual(t, refreshToken, newRefresh)
+
+	// Rotated token can be used again
+	rec, _ = callRefresh(t, newRefresh)
+	assert.Equal(t, http.StatusOK, rec.Code, "body: %s", rec.Body.String())
+}
+
+func TestRefreshReuseRevokesFamily(t *testing.T) {
+	_, refreshToken := loginTokenPair(t, database.TestUserCPSK1.Username)
+
+	rec, resp := callRefresh(t, refreshToken)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	newRefresh, _ := resp["refresh_token"].(string)
+
+	// Reuse old token
+	rec, resp = callRefresh(t, refreshToken)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Equal(t, ErrRefreshTokenReused.Error(), resp["error"])
+
+	// Newer token of the same family is revoked as well
+	rec, _ = callRefresh(t, newRefresh)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestRefreshInvalidToken(t *testing.T) {
+	rec, resp := callRefresh(t, "not-a-real-token")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Equal(t, ErrInvalidRefreshToken.Error(), resp["error"])
+}
+
+func TestRefreshMissingToken(t *testing.T) {
+	controller := NewRefreshController(testDB)
+	rec, _, err := utilities.SimulateAPICall(controller.RefreshHandler, "/refresh", http.MethodPost, map[string]string{})
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestLogoutRevokesRefreshFamily(t *testing.T) {
+	accessToken, refreshToken := loginTokenPair(t, database.TestUserCPSK1.Username)
+
+	logoutController := NewLogoutController(NewInMemoryBlacklistStore(), NewRefreshTokenStore(testDB))
+
+	rec := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(rec)
+	var err error
+	c.Request, err = http.NewRequest(http.MethodPost, "/logout", nil)
+	assert.NoError(t, err)
+	c.Request.Header.Set("Authorization", "Bearer "+accessToken)
+
+	token, err := ValidatedToken(accessToken)
+	assert.NoError(t, err)
+	c.Set("claims", token.Claims)
+
+	logoutController.LogoutHandler(c)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	refreshRec, _ := callRefresh(t, refreshToken)
+	assert.Equal(t, http.StatusUnauthorized, refreshRec.Code)
+}
@@ -0,0 +1,150 @@
+package auth
+
+import (
+	"HireMeMaybe-backend/internal/database"
+	"HireMeMaybe-backend/internal/model"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// RefreshTokenDuration is how long an issued refresh token stay valid.
+const RefreshTokenDuration = 30 * 24 * time.Hour
+
+var (
+	// ErrInvalidRefreshToken is returned when the refresh token is unknown.
+	ErrInvalidRefreshToken = errors.New("invalid refresh token")
+	// ErrRefreshTokenExpired is returned when the refresh token is expired.
+	ErrRefreshTokenExpired = errors.New("refresh token expired")
+	// ErrRefreshTokenReused is returned when an already rotated or revoked refresh token is used again.
+	// The whole token family is revoked when this happen.
+	ErrRefreshTokenReused = errors.New("refresh token has already been used")
+)
+
+// RefreshTokenStore issues, rotates and revokes refresh tokens stored in DB.
+type RefreshTokenStore struct {
+	DB *database.DBinstanceStruct
+}
+
+// NewRefreshTokenStore creates a new instance of RefreshTokenStore with the provided database connection.
+func NewRefreshTokenStore(db *database.DBinstanceStruct) *RefreshTokenStore {
+	return &RefreshTokenStore{
+		DB: db,
+	}
+}
+
+// HashToken returns hex encoded SHA-256 of the raw token, used to store token in DB.
+func HashToken(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
+
+// NewOpaqueToken returns random URL safe token with 256 bits of entropy.
+func NewOpaqueToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate random token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// Issue creates a new refresh token for the user in the given family and returns the raw token.
+func (s *RefreshTokenStore) Issue(userID uuid.UUID, familyID uuid.UUID) (string, error) {
+	return issueRefreshToken(s.DB.DB, userID, familyID)
+}
+
+func issueRefreshToken(tx *gorm.DB, userID uuid.UUID, familyID uuid.UUID) (string, error) {
+	raw, err := NewOpaqueToken()
+	if err != nil {
+		return "", err
+	}
+
+	record := model.RefreshToken{
+		UserID:    userID,
+		FamilyID:  familyID,
+		TokenHash: HashToken(raw),
+		ExpiresAt: time.Now().Add(RefreshTokenDuration),
+	}
+	if err := tx.Create(&record).Error; err != nil {
+		return "", err
+	}
+	return raw, nil
+}
+
+// Rotate marks the given refresh token as used and issues a new one in the same family.
+// Using a token that was already rotated or revoked revokes the whole family.
+func (s *RefreshTokenStore) Rotate(raw string) (*model.RefreshToken, string, error) {
+	var record model.RefreshToken
+	if err := s.DB.Where("token_hash = ?", HashToken(raw)).First(&record).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, "", ErrInvalidRefreshToken
+		}
+		return nil, "", err
+	}
+
+	if record.UsedAt != nil || record.RevokedAt != nil {
+		if err := s.RevokeFamily(record.FamilyID); err != nil {
+			return nil, "", err
+		}
+		return nil, "", ErrRefreshTokenReused
+	}
+
+	if time.Now().After(record.ExpiresAt) {
+		return nil, "", ErrRefreshTokenExpired
+	}
+
+	var newToken string
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.RefreshToken{}).
+			Where("id = ? AND used_at IS NULL AND revoked_at IS NULL", record.ID).
+			Update("used_at", time.Now())
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			// Another request rotated this token first
+			return ErrRefreshTokenReused
+		}
+
+		var err error
+		newToken, err = issueRefreshToken(tx, record.UserID, record.FamilyID)
+		return err
+	})
+
+	if errors.Is(err, ErrRefreshTokenReused) {
+		if err := s.RevokeFamily(record.FamilyID); err != nil {
+			return nil, "", err
+		}
+		return nil, "", ErrRefreshTokenReused
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	return &record, newToken, nil
+}
+
+// RevokeFamily revokes every not yet revoked refresh token in the family.
+func (s *RefreshTokenStore) RevokeFamily(familyID uuid.UUID) error {
+	return s.DB.Model(&model.RefreshToken{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Update("revoked_at", time.Now()).Error
+}
+
+// RevokeUser revokes every not yet revoked refresh token of the user.
+func (s *RefreshTokenStore) RevokeUser(userID uuid.UUID) error {
+	return revokeUserRefreshTokens(s.DB.DB, userID)
+}
+
+func revokeUserRefreshTokens(tx *gorm.DB, userID uuid.UUID) error {
+	return tx.Model(&model.RefreshToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now()).Error
+}
@@ -0,0 +1,248 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// minRSAKeyBits is the smallest RSA key accepted for signing access tokens
+const minRSAKeyBits = 2048
+
+// SigningKey is asymmetric key identified by kid in token header.
+// Private is nil for key that only verifies tokens, such as retired or upcoming key.
+type SigningKey struct {
+	ID      string
+	Method  jwt.SigningMethod
+	Private crypto.Signer
+	Public  crypto.PublicKey
+}
+
+// NewSigningKey creates SigningKey from RSA (RS256) or Ed25519 (EdDSA) private or public key.
+func NewSigningKey(id string, key interface{}) (*SigningKey, error) {
+	if id == "" {
+		return nil, errors.New("signing key ID must not be empty")
+	}
+
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		if k.N.BitLen() < minRSAKeyBits {
+			return nil, fmt.Errorf("RSA key '%s' must be at least %d bits", id, minRSAKeyBits)
+		}
+		return &SigningKey{ID: id, Method: jwt.SigningMethodRS256, Private: k, Public: &k.PublicKey}, nil
+	case *rsa.PublicKey:
+		if k.N.BitLen() < minRSAKeyBits {
+			return nil, fmt.Errorf("RSA key '%s' must be at least %d bits", id, minRSAKeyBits)
+		}
+		return &SigningKey{ID: id, Method: jwt.SigningMethodRS256, Public: k}, nil
+	case ed25519.PrivateKey:
+		return &SigningKey{ID: id, Method: jwt.SigningMethodEdDSA, Private: k, Public: k.Public()}, nil
+	case ed25519.PublicKey:
+		return &SigningKey{ID: id, Method: jwt.SigningMethodEdDSA, Public: k}, nil
+	default:
+		return nil, fmt.Errorf("key '%s' is not RSA or Ed25519 key", id)
+	}
+}
+
+// KeySet holds keys for signing and verifying access tokens.
+// Tokens are signed by the signing key with its kid, or with HS256 using the HMAC secret
+// if there is no signing key. Tokens signed by any key in the set are accepted, HS256 tokens
+// are accepted only when acceptHS256 is set.
+type KeySet struct {
+	hmacSecret  []byte
+	acceptHS256 bool
+	signing     *SigningKey
+	keys        map[string]*SigningKey
+}
+
+// NewKeySet creates KeySet signing with key of signingKeyID, or HS256 if signingKeyID is empty.
+// acceptHS256 is ignored and HS256 tokens are always accepted when signing with HS256.
+// HMAC secret must not be empty when HS256 is used, otherwise anyone could sign valid token.
+func NewKeySet(hmacSecret string, acceptHS256 bool, signingKeyID string, keys ...*SigningKey) (*KeySet, error) {
+	ks := &KeySet{
+		hmacSecret:  []byte(hmacSecret),
+		acceptHS256: acceptHS256 || signingKeyID == "",
+		keys:        map[string]*SigningKey{},
+	}
+	if ks.acceptHS256 && hmacSecret == "" {
+		return nil, errors.New("SECRET_KEY must be set to sign or accept HS256 tokens")
+	}
+	for _, k := range keys {
+		if _, ok := ks.keys[k.ID]; ok {
+			return nil, fmt.Errorf("duplicate signing key ID '%s'", k.ID)
+		}
+		ks.keys[k.ID] = k
+	}
+
+	if signingKeyID == "" {
+		return ks, nil
+	}
+
+	signing, ok := ks.keys[signingKeyID]
+	if !ok {
+		return nil, fmt.Errorf("signing key '%s' not found", signingKeyID)
+	}
+	if signing.Private == nil {
+		return nil, fmt.Errorf("signing key '%s' has no private key", signingKeyID)
+	}
+	ks.signing = signing
+	return ks, nil
+}
+
+// LoadKeySetFromEnv creates KeySet from PEM files in JWT_KEYS_DIR, named <kid>.pem, signing with
+// key of JWT_SIGNING_KEY_ID. Without JWT_SIGNING_KEY_ID tokens are signed with HS256 using SECRET_KEY.
+// HS256 tokens are no longer accepted after switching to asymmetric key unless JWT_ACCEPT_HS256 is true,
+// which keeps tokens issued before switching valid until they expire.
+func LoadKeySetFromEnv() (*KeySet, error) {
+	var keys []*SigningKey
+	if dir := strings.TrimSpace(os.Getenv("JWT_KEYS_DIR")); dir != "" {
+		var err error
+		keys, err = loadSigningKeys(dir)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	signingKeyID := strings.TrimSpace(os.Getenv("JWT_SIGNING_KEY_ID"))
+	acceptHS256 := strings.ToLower(strings.TrimSpace(os.Getenv("JWT_ACCEPT_HS256"))) == "true"
+
+	return NewKeySet(secretKey, acceptHS256, signingKeyID, keys...)
+}
+
+func loadSigningKeys(dir string) ([]*SigningKey, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid JWT_KEYS_DIR: %w", err)
+	}
+
+	keys := make([]*SigningKey, 0, len(paths))
+	for _, path := range paths {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read signing key: %w", err)
+		}
+		key, err := parsePEMKey(b)
+		if err != nil {
+			return nil, fmt.Errorf("invalid signing key %s: %w", path, err)
+		}
+		sk, err := NewSigningKey(strings.TrimSuffix(filepath.Base(path), ".pem"), key)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, sk)
+	}
+	return keys, nil
+}
+
+func parsePEMKey(b []byte) (interface{}, error) {
+	block, _ := pem.Decode(b)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	switch block.Type {
+	case "PRIVATE KEY":
+		return x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		return x509.ParsePKIXPublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported PEM block type '%s'", block.Type)
+	}
+}
+
+// Sign signs the claims with the signing key, or HS256 if there is no signing key.
+func (ks *KeySet) Sign(claims jwt.Claims) (string, error) {
+	if ks.signing == nil {
+		return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(ks.hmacSecret)
+	}
+	token := jwt.NewWithClaims(ks.signing.Method, claims)
+	token.Header["kid"] = ks.signing.ID
+	return token.SignedString(ks.signing.Private)
+}
+
+// Keyfunc returns key verifying the token, picked by its algorithm and kid.
+func (ks *KeySet) Keyfunc(token *jwt.Token) (interface{}, error) {
+	if _, ok := token.Method.(*jwt.SigningMethodHMAC); ok {
+		if !ks.acceptHS256 || token.Method.Alg() != jwt.SigningMethodHS256.Alg() {
+			return nil, fmt.Errorf("invalid token")
+		}
+		return ks.hmacSecret, nil
+	}
+
+	kid, _ := token.Header["kid"].(string)
+	key, ok := ks.keys[kid]
+	if !ok || key.Method.Alg() != token.Method.Alg() {
+		return nil, fmt.Errorf("invalid token")
+	}
+	return key.Public, nil
+}
+
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+type jwksResponse struct {
+	Keys []jsonWebKey `json:"keys"`
+}
+
+// JWKS returns public keys of the set as JSON Web Key Set, sorted by kid.
+func (ks *KeySet) JWKS() jwksResponse {
+	ids := make([]string, 0, len(ks.keys))
+	for id := range ks.keys {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	resp := jwksResponse{Keys: make([]jsonWebKey, 0, len(ids))}
+	for _, id := range ids {
+		key := ks.keys[id]
+		jwk := jsonWebKey{Kid: key.ID, Alg: key.Method.Alg(), Use: "sig"}
+		switch pub := key.Public.(type) {
+		case *rsa.PublicKey:
+			jwk.Kty = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.Kty = "OKP"
+			jwk.Crv = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+		}
+		resp.Keys = append(resp.Keys, jwk)
+	}
+	return resp
+}
+
+// JWKSHandler publishes public keys verifying access tokens so other services can verify them
+// @Summary JSON Web Key Set of access token signing keys
+// @Description Keys are identified by kid header of the token. HS256 tokens can't be verified with this endpoint
+// @Tags Auth
+// @Produce json
+// @Success 200 {object} jwksResponse
+// @Router /.well-known/jwks.json [get]
+func (ks *KeySet) JWKSHandler(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, ks.JWKS())
+}
@@ -0,0 +1,204 @@
+package auth
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// useTestKeySet sets token keys for the test and restores the previous keys after it
+func useTestKeySet(t *testing.T, ks *KeySet) {
+	t.Helper()
+	previous := tokenKeys
+	UseKeySet(ks)
+	t.Cleanup(func() { UseKeySet(previous) })
+}
+
+func writePEMKey(t *testing.T, dir string, name string, blockType string, der []byte) {
+	t.Helper()
+	b := pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der})
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, name), b, 0o600))
+}
+
+func TestKeySetRotation(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+
+	oldKey, err := NewSigningKey("2025-01", rsaKey)
+	assert.NoError(t, err)
+	newKey, err := NewSigningKey("2025-02", edKey)
+	assert.NoError(t, err)
+
+	// Sign with old key
+	ks, err := NewKeySet("secret", false, "2025-01", oldKey)
+	assert.NoError(t, err)
+	useTestKeySet(t, ks)
+	oldToken, err := GenerateTokenWithDuration(uuid.New(), time.Hour, JwtIssuer)
+	assert.NoError(t, err)
+	parsed, err := ValidatedToken(oldToken)
+	assert.NoError(t, err)
+	assert.Equal(t, "RS256", parsed.Method.Alg())
+	assert.Equal(t, "2025-01", parsed.Header["kid"])
+
+	// Rotate to new key, old key only verify
+	oldVerifyOnly, err := NewSigningKey("2025-01", &rsaKey.PublicKey)
+	assert.NoError(t, err)
+	ks, err = NewKeySet("secret", false, "2025-02", oldVerifyOnly, newKey)
+	assert.NoError(t, err)
+	UseKeySet(ks)
+	newToken, err := GenerateTokenWithDuration(uuid.New(), time.Hour, JwtIssuer)
+	assert.NoError(t, err)
+	parsed, err = ValidatedToken(newToken)
+	assert.NoError(t, err)
+	assert.Equal(t, "EdDSA", parsed.Method.Alg())
+	_, err = ValidatedToken(oldToken)
+	assert.NoError(t, err, "Token of previous key must still be valid")
+
+	// Retire old key
+	ks, err = NewKeySet("secret", false, "2025-02", newKey)
+	assert.NoError(t, err)
+	UseKeySet(ks)
+	_, err = ValidatedToken(oldToken)
+	assert.Error(t, err)
+	_, err = ValidatedToken(newToken)
+	assert.NoError(t, err)
+
+	_, err = NewKeySet("secret", false, "2025-01", oldVerifyOnly)
+	assert.Error(t, err, "Key without private key can't sign")
+	_, err = NewKeySet("secret", false, "missing", newKey)
+	assert.Error(t, err)
+}
+
+func TestKeySetRejectHS256(t *testing.T) {
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+	key, err := NewSigningKey("ed", edKey)
+	assert.NoError(t, err)
+
+	hmacToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
+		Subject:   uuid.NewString(),
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+	}).SignedString([]byte("secret"))
+	assert.NoError(t, err)
+
+	ks, err := NewKeySet("secret", true, "ed", key)
+	assert.NoError(t, err)
+	useTestKeySet(t, ks)
+	_, err = ValidatedToken(hmacToken)
+	assert.NoError(t, err, "HS256 token is accepted during migration")
+
+	ks, err = NewKeySet("secret", false, "ed", key)
+	assert.NoError(t, err)
+	UseKeySet(ks)
+	_, err = ValidatedToken(hmacToken)
+	assert.Error(t, err)
+
+	// Token claiming the kid but signed with other algorithm
+	forged := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{Subject: uuid.NewString()})
+	forged.Header["kid"] = "ed"
+	forgedString, err := forged.SignedString([]byte(edKey.Public().(ed25519.PublicKey)))
+	assert.NoError(t, err)
+	_, err = ValidatedToken(forgedString)
+	assert.Error(t, err)
+}
+
+func TestKeySetRejectsEmptyHMACSecret(t *testing.T) {
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+	key, err := NewSigningKey("ed", edKey)
+	assert.NoError(t, err)
+
+	_, err = NewKeySet("", false, "")
+	assert.Error(t, err, "HS256 signing needs secret")
+	_, err = NewKeySet("", true, "ed", key)
+	assert.Error(t, err, "HS256 acceptance needs secret")
+
+	ks, err := NewKeySet("", false, "ed", key)
+	assert.NoError(t, err)
+	useTestKeySet(t, ks)
+
+	// HS256 token signed with empty key is valid HMAC signature of empty secret
+	forged, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
+		Subject:   uuid.NewString(),
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+	}).SignedString([]byte{})
+	assert.NoError(t, err)
+	_, err = ValidatedToken(forged)
+	assert.Error(t, err)
+}
+
+func TestLoadKeySetFromEnv(t *testing.T) {
+	dir := t.TempDir()
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	edPub, edKey, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+
+	writePEMKey(t, dir, "rsa-1.pem", "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(rsaKey))
+	der, err := x509.MarshalPKCS8PrivateKey(edKey)
+	assert.NoError(t, err)
+	writePEMKey(t, dir, "ed-1.pem", "PRIVATE KEY", der)
+	der, err = x509.MarshalPKIXPublicKey(edPub)
+	assert.NoError(t, err)
+	writePEMKey(t, dir, "ed-0.pem", "PUBLIC KEY", der)
+
+	t.Setenv("JWT_KEYS_DIR", dir)
+	t.Setenv("JWT_SIGNING_KEY_ID", "ed-1")
+	ks, err := LoadKeySetFromEnv()
+	assert.NoError(t, err)
+	assert.Equal(t, "ed-1", ks.signing.ID)
+	assert.False(t, ks.acceptHS256, "HS256 is not accepted by default after switching to asymmetric key")
+	assert.Len(t, ks.keys, 3)
+
+	r := gin.New()
+	r.GET("/.well-known/jwks.json", ks.JWKSHandler)
+	rec := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil)
+	r.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var jwks jwksResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &jwks))
+	if assert.Len(t, jwks.Keys, 3) {
+		assert.Equal(t, jsonWebKey{
+			Kty: "OKP", Kid: "ed-0", Alg: "EdDSA", Use: "sig", Crv: "Ed25519",
+			X: base64.RawURLEncoding.EncodeToString(edPub),
+		}, jwks.Keys[0])
+		assert.Equal(t, "RSA", jwks.Keys[2].Kty)
+		assert.Equal(t, "AQAB", jwks.Keys[2].E)
+	}
+	assert.NotContains(t, rec.Body.String(), `"d"`, "Private key must not be published")
+
+	t.Setenv("JWT_SIGNING_KEY_ID", "ed-0")
+	_, err = LoadKeySetFromEnv()
+	assert.Error(t, err, "Public key can't sign")
+
+	writePEMKey(t, dir, "small.pem", "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(mustRSAKey(t, 1024)))
+	t.Setenv("JWT_SIGNING_KEY_ID", "ed-1")
+	_, err = LoadKeySetFromEnv()
+	assert.Error(t, err, "Weak RSA key is rejected")
+}
+
+func mustRSAKey(t *testing.T, bits int) *rsa.PrivateKey {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, bits)
+	assert.NoError(t, err)
+	return key
+}
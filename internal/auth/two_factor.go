@@ -0,0 +1,528 @@
+package auth
+
+import (
+	"HireMeMaybe-backend/internal/model"
+	"HireMeMaybe-backend/internal/utilities"
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// TwoFactorChallengeDuration is how long user has to submit the second factor after password login
+	TwoFactorChallengeDuration = 5 * time.Minute
+	// Challenge is discarded after this many wrong codes
+	maxTwoFactorAttempts = 5
+	recoveryCodeCount    = 10
+)
+
+var errInvalidTwoFactorCode = errors.New("invalid two-factor code")
+
+type twoFactorChallengeResponse struct {
+	TwoFactorRequired bool   `json:"two_factor_required"`
+	ChallengeToken    string `json:"challenge_token"`
+	ExpiresIn         int    `json:"expires_in"`
+	// Set when admin must enroll before finishing login
+	EnrollmentRequired bool     `json:"enrollment_required,omitempty"`
+	Secret             string   `json:"secret,omitempty"`
+	ProvisioningURI    string   `json:"provisioning_uri,omitempty"`
+	RecoveryCodes      []string `json:"recovery_codes,omitempty"`
+}
+
+type twoFactorVerifyRequest struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+	// Code is either TOTP code or recovery code
+	Code string `json:"code" binding:"required"`
+}
+
+type twoFactorCodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+type totpEnrollResponse struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+type recoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+func totpIssuer() string {
+	if issuer := os.Getenv("TOTP_ISSUER"); issuer != "" {
+		return issuer
+	}
+	return "HireMeMaybe"
+}
+
+// twoFactorRequired reports whether user must pass the second factor to log in.
+// requireAdmin2FAFromEnv reports whether REQUIRE_ADMIN_2FA forces admin to log in with TOTP.
+func requireAdmin2FAFromEnv() bool {
+	return strings.ToLower(strings.TrimSpace(os.Getenv("REQUIRE_ADMIN_2FA"))) == "true"
+}
+
+func twoFactorRequired(db *gorm.DB, user model.User, requireAdmin2FA bool) (bool, error) {
+	if requireAdmin2FA && user.Role == model.RoleAdmin {
+		return true, nil
+	}
+
+	var count int64
+	err := db.Model(&model.TOTPCredential{}).Where("user_id = ? AND enabled = ?", user.ID, true).Count(&count).Error
+	return count > 0, err
+}
+
+// startTwoFactorChallenge creates login challenge for user who passed password check or signed in with identity provider.
+// Admin required to use 2FA without enrolled credential get a new secret to enroll with.
+func startTwoFactorChallenge(c *gin.Context, db *gorm.DB, user model.User, authType string) {
+	var resp twoFactorChallengeResponse
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var credential model.TOTPCredential
+		err := tx.Where("user_id = ?", user.ID).First(&credential).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		if err != nil || !credential.Enabled {
+			secret, err := savePendingTOTP(tx, user.ID)
+			if err != nil {
+				return err
+			}
+			codes, err := replaceRecoveryCodes(tx, user.ID)
+			if err != nil {
+				return err
+			}
+			resp.EnrollmentRequired = true
+			resp.Secret = secret
+			resp.ProvisioningURI = TOTPProvisioningURI(secret, totpIssuer(), user.Username)
+			resp.RecoveryCodes = codes
+		}
+
+		raw, err := NewOpaqueToken()
+		if err != nil {
+			return err
+		}
+		resp.ChallengeToken = raw
+		return tx.Create(&model.TwoFactorChallenge{
+			UserID:    user.ID,
+			TokenHash: HashToken(raw),
+			ExpiresAt: time.Now().Add(TwoFactorChallengeDuration),
+		}).Error
+	})
+	if err != nil {
+		LogAuthAttempt("error", authType, "Fail", user.Username, "Failed to create two-factor challenge")
+		utilities.RespondDBError(c, err)
+		return
+	}
+
+	resp.TwoFactorRequired = true
+	resp.ExpiresIn = int(TwoFactorChallengeDuration.Seconds())
+
+	LogAuthAttempt("info", authType, "Success", user.Username, "First factor accepted, waiting for second factor")
+	c.JSON(http.StatusAccepted, resp)
+}
+
+func savePendingTOTP(tx *gorm.DB, userID uuid.UUID) (string, error) {
+	secret, err := NewTOTPSecret()
+	if err != nil {
+		return "", err
+	}
+	credential := model.TOTPCredential{
+		UserID: userID,
+		Secret: secret,
+	}
+	if err := tx.Save(&credential).Error; err != nil {
+		return "", err
+	}
+	return secret, nil
+}
+
+func newRecoveryCode() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate recovery code: %w", err)
+	}
+	code := strings.ToLower(base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(b))
+	return code[:5] + "-" + code[5:10], nil
+}
+
+func normalizeRecoveryCode(code string) string {
+	return strings.ToLower(strings.TrimSpace(code))
+}
+
+// replaceRecoveryCodes delete old recovery codes of the user and returns new raw codes.
+func replaceRecoveryCodes(tx *gorm.DB, userID uuid.UUID) ([]string, error) {
+	if err := tx.Where("user_id = ?", userID).Delete(&model.RecoveryCode{}).Error; err != nil {
+		return nil, err
+	}
+
+	codes := make([]string, 0, recoveryCodeCount)
+	records := make([]model.RecoveryCode, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		code, err := newRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+		codes = append(codes, code)
+		records = append(records, model.RecoveryCode{UserID: userID, CodeHash: HashToken(code)})
+	}
+	if err := tx.Create(&records).Error; err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// consumeSecondFactor checks code as TOTP code, or as unused recovery code if allowRecovery is true,
+// and marks it as used. errInvalidTwoFactorCode is returned if code is not accepted.
+func consumeSecondFactor(tx *gorm.DB, credential *model.TOTPCredential, code string, allowRecovery bool) error {
+	if step, ok := ValidateTOTP(credential.Secret, code, time.Now(), credential.LastUsedStep); ok {
+		result := tx.Model(&model.TOTPCredential{}).
+			Where("user_id = ? AND last_used_step < ?", credential.UserID, step).
+			Update("last_used_step", step)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			// Same code used by another request
+			return errInvalidTwoFactorCode
+		}
+		credential.LastUsedStep = step
+		return nil
+	}
+
+	if !allowRecovery {
+		return errInvalidTwoFactorCode
+	}
+
+	result := tx.Model(&model.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", credential.UserID, HashToken(normalizeRecoveryCode(code))).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errInvalidTwoFactorCode
+	}
+	return nil
+}
+
+// VerifyTwoFactorHandler finishes two-step login by checking TOTP or recovery code of the challenge
+// @Summary Finish login with second factor
+// @Description Challenge token is returned from /auth/login when user has two-factor authentication enabled
+// @Description Recovery code can be used instead of TOTP code once two-factor authentication is enrolled
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param Info body twoFactorVerifyRequest true "Challenge token and code"
+// @Success 200 {object} model.CompanyResponse "If role is company"
+// @Success 200 {object} model.CPSKResponse "If role is cpsk"
+// @Failure 400 {object} utilities.ErrorResponse "Challenge token or code is not provided"
+// @Failure 401 {object} utilities.ErrorResponse "Challenge is invalid or expired, or code is incorrect"
+// @Failure 429 {object} utilities.ErrorResponse "Too many failed attempts"
+// @Failure 500 {object} utilities.ErrorResponse "Database error"
+// @Router /auth/2fa/verify [post]
+func (lh *LocalRegisterHandler) VerifyTwoFactorHandler(c *gin.Context) {
+	var req twoFactorVerifyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utilities.ErrorResponse{
+			Error: "Challenge token and code must be provided",
+		})
+		return
+	}
+
+	var challenge model.TwoFactorChallenge
+	err := lh.DB.Preload("User").Preload("User.Punishment").
+		Where("token_hash = ? AND expires_at > ?", HashToken(req.ChallengeToken), time.Now()).
+		First(&challenge).Error
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		LogAuthAttempt("warning", "TOTP", "Fail", "", "Invalid or expired challenge")
+		c.JSON(http.StatusUnauthorized, utilities.ErrorResponse{Error: "Invalid or expired challenge"})
+		return
+
+	case err == nil:
+		// Do nothing
+
+	default:
+		utilities.RespondDBError(c, err)
+		return
+	}
+	user := challenge.User
+
+	lockedUntil, err := lh.Guard.LockedUntil(UsernameLockoutKey(user.Username), IPLockoutKey(c.ClientIP()))
+	if err != nil {
+		utilities.RespondDBError(c, err)
+		return
+	}
+	if !lockedUntil.IsZero() {
+		LogAuthAttempt("warning", "TOTP", "Fail", user.Username, fmt.Sprintf("Locked out until %s, IP %s", lockedUntil.UTC().Format(time.RFC3339), c.ClientIP()))
+		respondLockedOut(c, lockedUntil)
+		return
+	}
+
+	// Attempt is counted before checking the code so concurrent guesses can't go over the limit
+	counted := lh.DB.Model(&challenge).Clauses(clause.Returning{Columns: []clause.Column{{Name: "attempts"}}}).
+		Where("attempts < ?", maxTwoFactorAttempts).
+		Update("attempts", gorm.Expr("attempts + 1"))
+	if counted.Error != nil {
+		utilities.RespondDBError(c, counted.Error)
+		return
+	}
+	if counted.RowsAffected == 0 {
+		if err := lh.DB.Delete(&challenge).Error; err != nil {
+			utilities.RespondDBError(c, err)
+			return
+		}
+		LogAuthAttempt("warning", "TOTP", "Fail", user.Username, "Too many attempts on challenge")
+		c.JSON(http.StatusUnauthorized, utilities.ErrorResponse{Error: "Invalid or expired challenge"})
+		return
+	}
+
+	err = lh.DB.Transaction(func(tx *gorm.DB) error {
+		var credential model.TOTPCredential
+		if err := tx.Where("user_id = ?", user.ID).First(&credential).Error; err != nil {
+			return err
+		}
+
+		// Recovery codes are usable only after enrollment is confirmed
+		if err := consumeSecondFactor(tx, &credential, req.Code, credential.Enabled); err != nil {
+			return err
+		}
+
+		if !credential.Enabled {
+			now := time.Now()
+			if err := tx.Model(&credential).Updates(map[string]interface{}{"enabled": true, "enabled_at": now}).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Delete(&challenge).Error
+	})
+
+	switch {
+	case err == nil:
+		// Do nothing
+
+	case errors.Is(err, errInvalidTwoFactorCode):
+		LogAuthAttempt("warning", "TOTP", "Fail", user.Username, "Invalid two-factor code")
+		if challenge.Attempts >= maxTwoFactorAttempts {
+			if err := lh.DB.Delete(&challenge).Error; err != nil {
+				utilities.RespondDBError(c, err)
+				return
+			}
+		}
+		if lh.recordLoginFailure(c, user.Username, user.Role) {
+			return
+		}
+		c.JSON(http.StatusUnauthorized, utilities.ErrorResponse{Error: "Two-factor code is incorrect"})
+		return
+
+	default:
+		utilities.RespondDBError(c, err)
+		return
+	}
+
+	if err := lh.Guard.Reset(UsernameLockoutKey(user.Username)); err != nil {
+		utilities.RespondDBError(c, err)
+		return
+	}
+
+	LogAuthAttempt("info", "TOTP", "Success", user.Username, "Second factor accepted")
+	lh.respondLoginSuccess(c, user)
+}
+
+// EnrollTwoFactorHandler generates new TOTP secret for current user
+// @Summary Start TOTP enrollment
+// @Description Returns secret and otpauth provisioning URI to be shown as QR code, confirm with /auth/2fa/confirm
+// @Tags Auth
+// @Produce json
+// @Param Authorization header string true "Insert your access token" default(Bearer <your access token>)
+// @Success 200 {object} totpEnrollResponse
+// @Failure 401 {object} utilities.ErrorResponse "Unauthorized"
+// @Failure 409 {object} utilities.ErrorResponse "Two-factor authentication is already enabled"
+// @Failure 500 {object} utilities.ErrorResponse "Database error"
+// @Router /auth/2fa/enroll [post]
+func (lh *LocalRegisterHandler) EnrollTwoFactorHandler(c *gin.Context) {
+	user, err := utilities.ExtractUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, utilities.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	var credential model.TOTPCredential
+	err = lh.DB.Where("user_id = ?", user.ID).First(&credential).Error
+	switch {
+	case err == nil && credential.Enabled:
+		c.JSON(http.StatusConflict, utilities.ErrorResponse{Error: "Two-factor authentication is already enabled"})
+		return
+
+	case err == nil, errors.Is(err, gorm.ErrRecordNotFound):
+		// Do nothing
+
+	default:
+		utilities.RespondDBError(c, err)
+		return
+	}
+
+	secret, err := savePendingTOTP(lh.DB.DB, user.ID)
+	if err != nil {
+		utilities.RespondDBError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, totpEnrollResponse{
+		Secret:          secret,
+		ProvisioningURI: TOTPProvisioningURI(secret, totpIssuer(), user.Username),
+	})
+}
+
+// ConfirmTwoFactorHandler enables pending TOTP secret after checking a code from it
+// @Summary Confirm TOTP enrollment
+// @Description Recovery codes are shown only once, each can be used once instead of TOTP code
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Insert your access token" default(Bearer <your access token>)
+// @Param Code body twoFactorCodeRequest true "Current TOTP code"
+// @Success 200 {object} recoveryCodesResponse
+// @Failure 400 {object} utilities.ErrorResponse "Code is not provided or incorrect"
+// @Failure 401 {object} utilities.ErrorResponse "Unauthorized"
+// @Failure 404 {object} utilities.ErrorResponse "No pending enrollment"
+// @Failure 409 {object} utilities.ErrorResponse "Two-factor authentication is already enabled"
+// @Failure 500 {object} utilities.ErrorResponse "Database error"
+// @Router /auth/2fa/confirm [post]
+func (lh *LocalRegisterHandler) ConfirmTwoFactorHandler(c *gin.Context) {
+	user, err := utilities.ExtractUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, utilities.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	var req twoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utilities.ErrorResponse{Error: "Code must be provided"})
+		return
+	}
+
+	var credential model.TOTPCredential
+	err = lh.DB.Where("user_id = ?", user.ID).First(&credential).Error
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, utilities.ErrorResponse{Error: "No pending two-factor enrollment"})
+		return
+
+	case err == nil && credential.Enabled:
+		c.JSON(http.StatusConflict, utilities.ErrorResponse{Error: "Two-factor authentication is already enabled"})
+		return
+
+	case err == nil:
+		// Do nothing
+
+	default:
+		utilities.RespondDBError(c, err)
+		return
+	}
+
+	var codes []string
+	err = lh.DB.Transaction(func(tx *gorm.DB) error {
+		if err := consumeSecondFactor(tx, &credential, req.Code, false); err != nil {
+			return err
+		}
+		now := time.Now()
+		if err := tx.Model(&credential).Updates(map[string]interface{}{"enabled": true, "enabled_at": now}).Error; err != nil {
+			return err
+		}
+		codes, err = replaceRecoveryCodes(tx, user.ID)
+		return err
+	})
+	if errors.Is(err, errInvalidTwoFactorCode) {
+		c.JSON(http.StatusBadRequest, utilities.ErrorResponse{Error: "Two-factor code is incorrect"})
+		return
+	}
+	if err != nil {
+		utilities.RespondDBError(c, err)
+		return
+	}
+
+	LogAuthAttempt("info", "TOTP", "Success", user.Username, "Two-factor authentication enabled")
+	c.JSON(http.StatusOK, recoveryCodesResponse{RecoveryCodes: codes})
+}
+
+// DisableTwoFactorHandler removes TOTP and recovery codes of current user
+// @Summary Disable two-factor authentication
+// @Description Admin can't disable two-factor authentication when it is mandatory
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Insert your access token" default(Bearer <your access token>)
+// @Param Code body twoFactorCodeRequest true "Current TOTP code or recovery code"
+// @Success 200 {object} map[string]string "Two-factor authentication disabled"
+// @Failure 400 {object} utilities.ErrorResponse "Code is not provided or incorrect"
+// @Failure 401 {object} utilities.ErrorResponse "Unauthorized"
+// @Failure 403 {object} utilities.ErrorResponse "Two-factor authentication is mandatory for admin"
+// @Failure 404 {object} utilities.ErrorResponse "Two-factor authentication is not enabled"
+// @Failure 500 {object} utilities.ErrorResponse "Database error"
+// @Router /auth/2fa/disable [post]
+func (lh *LocalRegisterHandler) DisableTwoFactorHandler(c *gin.Context) {
+	user, err := utilities.ExtractUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, utilities.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	if lh.RequireAdmin2FA && user.Role == model.RoleAdmin {
+		c.JSON(http.StatusForbidden, utilities.ErrorResponse{Error: "Two-factor authentication is mandatory for admin"})
+		return
+	}
+
+	var req twoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utilities.ErrorResponse{Error: "Code must be provided"})
+		return
+	}
+
+	var credential model.TOTPCredential
+	err = lh.DB.Where("user_id = ? AND enabled = ?", user.ID, true).First(&credential).Error
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, utilities.ErrorResponse{Error: "Two-factor authentication is not enabled"})
+		return
+
+	case err == nil:
+		// Do nothing
+
+	default:
+		utilities.RespondDBError(c, err)
+		return
+	}
+
+	err = lh.DB.Transaction(func(tx *gorm.DB) error {
+		if err := consumeSecondFactor(tx, &credential, req.Code, true); err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", user.ID).Delete(&model.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&credential).Error
+	})
+	if errors.Is(err, errInvalidTwoFactorCode) {
+		c.JSON(http.StatusBadRequest, utilities.ErrorResponse{Error: "Two-factor code is incorrect"})
+		return
+	}
+	if err != nil {
+		utilities.RespondDBError(c, err)
+		return
+	}
+
+	LogAuthAttempt("info", "TOTP", "Success", user.Username, "Two-factor authentication disabled")
+	c.JSON(http.StatusOK, gin.H{"message": "Two-factor authentication disabled"})
+}
@@ -0,0 +1,224 @@
+package auth
+
+import (
+	"HireMeMaybe-backend/internal/database"
+	"HireMeMaybe-backend/internal/model"
+	"HireMeMaybe-backend/internal/utilities"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// RFC 6238 test secret "12345678901234567890" in base32
+const rfcTOTPSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestTOTPCodeRFC6238(t *testing.T) {
+	// Last 6 digits of RFC 6238 SHA1 test vectors
+	cases := map[int64]string{
+		59:         "287082",
+		1111111109: "081804",
+		1234567890: "005924",
+		2000000000: "279037",
+	}
+	for unix, expected := range cases {
+		code, err := TOTPCode(rfcTOTPSecret, time.Unix(unix, 0))
+		assert.NoError(t, err)
+		assert.Equal(t, expected, code, "time %d", unix)
+	}
+}
+
+func TestValidateTOTP(t *testing.T) {
+	now := time.Unix(1234567890, 0)
+	code, err := TOTPCode(rfcTOTPSecret, now)
+	assert.NoError(t, err)
+
+	step, ok := ValidateTOTP(rfcTOTPSecret, code, now, 0)
+	assert.True(t, ok)
+	assert.Equal(t, now.Unix()/totpPeriod, step)
+
+	// Previous period is still accepted for clock skew
+	_, ok = ValidateTOTP(rfcTOTPSecret, code, now.Add(totpPeriod*time.Second), 0)
+	assert.True(t, ok)
+
+	// Replay of already used step
+	_, ok = ValidateTOTP(rfcTOTPSecret, code, now, step)
+	assert.False(t, ok)
+
+	_, ok = ValidateTOTP(rfcTOTPSecret, code, now.Add(5*time.Minute), 0)
+	assert.False(t, ok)
+
+	_, ok = ValidateTOTP(rfcTOTPSecret, "12345", now, 0)
+	assert.False(t, ok)
+}
+
+func startLogin(t *testing.T, handler *LocalRegisterHandler, username string) map[string]interface{} {
+	t.Helper()
+	rec, resp, err := utilities.SimulateAPICall(handler.LocalLoginHandler, "/login", http.MethodPost, map[string]string{
+		"username": username,
+		"password": database.TestSeedPassword,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusAccepted, rec.Code, "body: %s", rec.Body.String())
+	assert.Equal(t, true, resp["two_factor_required"])
+	assert.NotContains(t, resp, "access_token")
+	return resp
+}
+
+func verifyTwoFactor(t *testing.T, handler *LocalRegisterHandler, challenge string, code string) (*httptest.ResponseRecorder, map[string]interface{}) {
+	t.Helper()
+	rec, resp, err := utilities.SimulateAPICall(handler.VerifyTwoFactorHandler, "/2fa/verify", http.MethodPost, map[string]string{
+		"challenge_token": challenge,
+		"code":            code,
+	})
+	assert.NoError(t, err)
+	return rec, resp
+}
+
+func TestTwoFactorLogin(t *testing.T) {
+	handler := NewLocalAuthHandler(testDB)
+	user := database.TestUserCompany2
+
+	secret, err := NewTOTPSecret()
+	assert.NoError(t, err)
+	assert.NoError(t, testDB.Create(&model.TOTPCredential{UserID: user.ID, Secret: secret, Enabled: true}).Error)
+	codes, err := replaceRecoveryCodes(testDB.DB, user.ID)
+	assert.NoError(t, err)
+	t.Cleanup(func() {
+		testDB.Where("user_id = ?", user.ID).Delete(&model.RecoveryCode{})
+		testDB.Where("user_id = ?", user.ID).Delete(&model.TOTPCredential{})
+	})
+
+	resp := startLogin(t, handler, user.Username)
+	challenge, _ := resp["challenge_token"].(string)
+	assert.NotEmpty(t, challenge)
+	assert.NotContains(t, resp, "secret")
+
+	rec, _ := verifyTwoFactor(t, handler, challenge, "000000")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	code, err := TOTPCode(secret, time.Now())
+	assert.NoError(t, err)
+	rec, resp = verifyTwoFactor(t, handler, challenge, code)
+	assert.Equal(t, http.StatusOK, rec.Code, "body: %s", rec.Body.String())
+	assertValidAccessToken(t, resp)
+
+	// Challenge can't be used twice
+	rec, _ = verifyTwoFactor(t, handler, challenge, code)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	// Same TOTP code can't be replayed on new challenge
+	resp = startLogin(t, handler, user.Username)
+	challenge, _ = resp["challenge_token"].(string)
+	rec, _ = verifyTwoFactor(t, handler, challenge, code)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	// Recovery code works once
+	rec, _ = verifyTwoFactor(t, handler, challenge, codes[0])
+	assert.Equal(t, http.StatusOK, rec.Code, "body: %s", rec.Body.String())
+
+	resp = startLogin(t, handler, user.Username)
+	challenge, _ = resp["challenge_token"].(string)
+	rec, _ = verifyTwoFactor(t, handler, challenge, codes[0])
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestTwoFactorChallengeAttemptLimit(t *testing.T) {
+	handler := NewLocalAuthHandler(testDB)
+	handler.Guard.MaxFailedAttempts = maxTwoFactorAttempts + 1
+	user := database.TestUserCPSK2
+
+	secret, err := NewTOTPSecret()
+	assert.NoError(t, err)
+	assert.NoError(t, testDB.Create(&model.TOTPCredential{UserID: user.ID, Secret: secret, Enabled: true}).Error)
+	t.Cleanup(func() {
+		testDB.Where("user_id = ?", user.ID).Delete(&model.TOTPCredential{})
+		_ = handler.Guard.Unlock(UsernameLockoutKey(user.Username))
+	})
+
+	resp := startLogin(t, handler, user.Username)
+	challenge, _ := resp["challenge_token"].(string)
+	for i := 0; i < maxTwoFactorAttempts; i++ {
+		rec, _ := verifyTwoFactor(t, handler, challenge, "000000")
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	}
+
+	// Challenge is spent after too many wrong codes
+	code, err := TOTPCode(secret, time.Now())
+	assert.NoError(t, err)
+	rec, _ := verifyTwoFactor(t, handler, challenge, code)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	var count int64
+	testDB.Model(&model.TwoFactorChallenge{}).Where("token_hash = ?", HashToken(challenge)).Count(&count)
+	assert.Zero(t, count)
+}
+
+func TestTwoFactorRequiredForAdmin(t *testing.T) {
+	handler := NewLocalAuthHandler(testDB)
+	handler.RequireAdmin2FA = true
+	user := database.TestAdminUser
+	t.Cleanup(func() {
+		testDB.Where("user_id = ?", user.ID).Delete(&model.RecoveryCode{})
+		testDB.Where("user_id = ?", user.ID).Delete(&model.TOTPCredential{})
+	})
+
+	resp := startLogin(t, handler, user.Username)
+	assert.Equal(t, true, resp["enrollment_required"])
+	secret, _ := resp["secret"].(string)
+	assert.NotEmpty(t, secret)
+	assert.NotEmpty(t, resp["recovery_codes"])
+
+	challenge, _ := resp["challenge_token"].(string)
+	code, err := TOTPCode(secret, time.Now())
+	assert.NoError(t, err)
+	rec, resp := verifyTwoFactor(t, handler, challenge, code)
+	assert.Equal(t, http.StatusOK, rec.Code, "body: %s", rec.Body.String())
+	assertValidAccessToken(t, resp)
+
+	var credential model.TOTPCredential
+	assert.NoError(t, testDB.Where("user_id = ?", user.ID).First(&credential).Error)
+	assert.True(t, credential.Enabled)
+
+	// Enrolled admin is no longer asked to enroll
+	resp = startLogin(t, handler, user.Username)
+	assert.NotContains(t, resp, "enrollment_required")
+	assert.NotContains(t, resp, "secret")
+}
+
+func TestGoogleLoginRequiresTwoFactor(t *testing.T) {
+	user := model.CPSKUser{
+		User: model.User{
+			ID:       uuid.New(),
+			Username: "google_two_factor_user",
+			GoogleID: "google_two_factor_123",
+			Role:     model.RoleCPSK,
+		},
+	}
+	assert.NoError(t, testDB.Create(&user).Error)
+	secret, err := NewTOTPSecret()
+	assert.NoError(t, err)
+	assert.NoError(t, testDB.Create(&model.TOTPCredential{UserID: user.User.ID, Secret: secret, Enabled: true}).Error)
+	t.Cleanup(func() {
+		testDB.Where("user_id = ?", user.User.ID).Delete(&model.TwoFactorChallenge{})
+		testDB.Where("user_id = ?", user.User.ID).Delete(&model.TOTPCredential{})
+		testDB.Delete(&model.User{}, "id = ?", user.User.ID)
+	})
+
+	mockUser := model.GoogleUserInfo{GID: user.User.GoogleID, Email: "google.two.factor@example.com"}
+	mockServer := NewMockOAuth2Server([]model.GoogleUserInfo{mockUser})
+	defer mockServer.Close()
+	handler := NewOauthLoginHandler(testDB, mockServer.Config, mockServer.MockInfoEndpoint)
+
+	body, cookie := mockServer.StartLogin(t, handler, model.RoleCPSK, mockUser.GID)
+	rec, resp, err := utilities.SimulateAPICall(handler.CPSKGoogleLoginHandler, "/auth/google/cpsk", http.MethodPost, body, cookie)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusAccepted, rec.Code, rec.Body.String())
+	assert.Equal(t, true, resp["two_factor_required"])
+	assert.NotEmpty(t, resp["challenge_token"])
+	assert.NotContains(t, resp, "access_token")
+	assert.NotContains(t, resp, "refresh_token")
+}
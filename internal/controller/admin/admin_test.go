@@ -1,10 +1,12 @@
 package admin
 
 import (
+	"HireMeMaybe-backend/internal/audit"
 	"HireMeMaybe-backend/internal/auth"
 	"HireMeMaybe-backend/internal/database"
 	"HireMeMaybe-backend/internal/middleware"
 	"HireMeMaybe-backend/internal/model"
+	"HireMeMaybe-backend/internal/policy"
 	"HireMeMaybe-backend/internal/testutil"
 	"context"
 	"encoding/json"
@@ -465,3 +467,108 @@ func TestGetVisitors_NoPunishmentFilter(t *testing.T) {
 	// Should return all visitor users from test seed
 	assert.GreaterOrEqual(t, len(visitorList), 0)
 }
+
+func TestUnlockLogin(t *testing.T) {
+	adminToken, err := auth.GetAccessToken(t, testDB, database.TestAdminUser.Username, database.TestSeedPassword)
+	assert.NoError(t, err)
+
+	guard := auth.NewLoginGuard(testDB)
+	guard.MaxFailedAttempts = 1
+	until, err := guard.RecordFailure(auth.UsernameLockoutKey("locked_user"))
+	assert.NoError(t, err)
+	assert.NotNil(t, until)
+
+	r := gin.Default()
+	jc := NewAdminController(testDB)
+	r.GET("/login-lockouts", middleware.RequireAuth(testDB), middleware.CheckRole(model.RoleAdmin), jc.GetLoginLockouts)
+	r.DELETE("/login-lockouts", middleware.RequireAuth(testDB), middleware.CheckRole(model.RoleAdmin), jc.UnlockLogin)
+
+	rec, _ := testutil.MakeJSONRequest(nil, adminToken, r, "/login-lockouts", http.MethodGet)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var lockouts []model.LoginLockout
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &lockouts))
+	found := false
+	for _, l := range lockouts {
+		if l.Identifier == auth.UsernameLockoutKey("locked_user") {
+			found = true
+		}
+	}
+	assert.True(t, found)
+
+	rec, _ = testutil.MakeJSONRequest(nil, adminToken, r, "/login-lockouts?username=locked_user", http.MethodDelete)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	lockedUntil, err := guard.LockedUntil(auth.UsernameLockoutKey("locked_user"))
+	assert.NoError(t, err)
+	assert.True(t, lockedUntil.IsZero())
+
+	// Unlock again should not found any record
+	rec, _ = testutil.MakeJSONRequest(nil, adminToken, r, "/login-lockouts?username=locked_user", http.MethodDelete)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestUnlockLogin_MissingQuery(t *testing.T) {
+	adminToken, err := auth.GetAccessToken(t, testDB, database.TestAdminUser.Username, database.TestSeedPassword)
+	assert.NoError(t, err)
+
+	r := gin.Default()
+	jc := NewAdminController(testDB)
+	r.DELETE("/login-lockouts", middleware.RequireAuth(testDB), middleware.CheckRole(model.RoleAdmin), jc.UnlockLogin)
+
+	rec, resp := testutil.MakeJSONRequest(nil, adminToken, r, "/login-lockouts", http.MethodDelete)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, "Username or ip must be provided", resp["error"])
+}
+
+func TestAuditLogVerifyCompany(t *testing.T) {
+	adminToken, err := auth.GetAccessToken(t, testDB, database.TestAdminUser.Username, database.TestSeedPassword)
+	assert.NoError(t, err)
+	companyID := database.TestUserCompany2.ID.String()
+	t.Cleanup(func() {
+		testDB.Model(&model.CompanyUser{}).Where("user_id = ?", companyID).Update("verified_status", database.TestCompany2.VerifiedStatus)
+	})
+
+	r := gin.Default()
+	r.Use(middleware.RequestID())
+	jc := NewAdminController(testDB)
+	r.PATCH("/verify-company/:company_id", middleware.RequireAuth(testDB), middleware.RequirePermission(testDB, policy.CompanyVerify),
+		middleware.Audit(testDB, audit.ActionVerifyCompany, audit.CompanyTarget), jc.VerifyCompany)
+	r.GET("/admin/audit", middleware.RequireAuth(testDB), middleware.RequirePermission(testDB, policy.AuditView), jc.GetAuditLogs)
+
+	rec, _ := testutil.MakeJSONRequest(nil, adminToken, r, "/verify-company/"+companyID+"?status=verified", http.MethodPatch)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	requestID := rec.Header().Get(middleware.RequestIDHeader)
+	assert.NotEmpty(t, requestID)
+
+	// Failed request is not recorded
+	rec, _ = testutil.MakeJSONRequest(nil, adminToken, r, "/verify-company/"+companyID+"?status=bogus", http.MethodPatch)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec, _ = testutil.MakeJSONRequest(nil, adminToken, r, "/admin/audit?target_type=company&target_id="+companyID, http.MethodGet)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var resp auditLogListResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	if assert.Len(t, resp.Logs, 1) {
+		entry := resp.Logs[0]
+		assert.Equal(t, database.TestAdminUser.ID, entry.ActorID)
+		assert.Equal(t, audit.ActionVerifyCompany, entry.Action)
+		assert.Equal(t, requestID, entry.RequestID)
+		assert.NotEmpty(t, entry.IP)
+
+		var diff map[string]audit.Change
+		assert.NoError(t, json.Unmarshal(entry.Diff, &diff))
+		assert.Equal(t, audit.Change{Before: model.StatusPending, After: model.StatusVerified}, diff["verified_status"])
+
+		// Audit log can't be changed
+		assert.ErrorIs(t, testDB.Model(&entry).Update("action", "nothing").Error, model.ErrAuditLogAppendOnly)
+		assert.ErrorIs(t, testDB.Delete(&entry).Error, model.ErrAuditLogAppendOnly)
+	}
+
+	rec, _ = testutil.MakeJSONRequest(nil, adminToken, r, "/admin/audit?from=yesterday", http.MethodGet)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	cpskToken, err := auth.GetAccessToken(t, testDB, database.TestUserCPSK1.Username, database.TestSeedPassword)
+	assert.NoError(t, err)
+	rec, _ = testutil.MakeJSONRequest(nil, cpskToken, r, "/admin/audit", http.MethodGet)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+}
@@ -1,6 +1,7 @@
 package admin
 
 import (
+	"HireMeMaybe-backend/internal/auth"
 	"HireMeMaybe-backend/internal/database"
 	"HireMeMaybe-backend/internal/model"
 	"HireMeMaybe-backend/internal/utilities"
@@ -11,6 +12,7 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
@@ -222,3 +224,164 @@ func (jc *AdminController) VerifyCompany(c *gin.Context) {
 
 	c.JSON(http.StatusOK, company)
 }
+
+// GetLoginLockouts function returns every username and IP currently locked out from local login
+// @Summary Get active local login lockouts
+// @Description Only admin can access this endpoints
+// @Tags Admin
+// @Produce json
+// @Param Authorization header string true "Insert your access token" default(Bearer <your access token>)
+// @Success 200 {array} model.LoginLockout
+// @Failure 400 {object} utilities.ErrorResponse "Invalid authorization header"
+// @Failure 401 {object} utilities.ErrorResponse "Invalid token"
+// @Failure 403 {object} utilities.ErrorResponse "Do not logged in as admin"
+// @Failure 500 {object} utilities.ErrorResponse "Database error"
+// @Router /login-lockouts [get]
+func (jc *AdminController) GetLoginLockouts(c *gin.Context) {
+	lockouts, err := auth.NewLoginGuard(jc.DB).ActiveLockouts()
+	if err != nil {
+		utilities.RespondDBError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, lockouts)
+}
+
+// UnlockLogin function removes failed attempts and lockout of given username or IP
+// @Summary Unlock local login of username or IP
+// @Description Only admin can access this endpoints
+// @Description Either username or ip must be given
+// @Tags Admin
+// @Produce json
+// @Param Authorization header string true "Insert your access token" default(Bearer <your access token>)
+// @Param username query string false "Locked username"
+// @Param ip query string false "Locked client IP"
+// @Success 200 {object} map[string]string "Unlocked"
+// @Failure 400 {object} utilities.ErrorResponse "Neither username nor ip given"
+// @Failure 401 {object} utilities.ErrorResponse "Invalid token"
+// @Failure 403 {object} utilities.ErrorResponse "Do not logged in as admin"
+// @Failure 404 {object} utilities.ErrorResponse "Given username or IP has no failed attempt"
+// @Failure 500 {object} utilities.ErrorResponse "Database error"
+// @Router /login-lockouts [delete]
+func (jc *AdminController) UnlockLogin(c *gin.Context) {
+	admin, err := utilities.ExtractUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, utilities.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	var key string
+	switch {
+	case c.Query("username") != "":
+		key = auth.UsernameLockoutKey(c.Query("username"))
+	case c.Query("ip") != "":
+		key = auth.IPLockoutKey(c.Query("ip"))
+	default:
+		c.JSON(http.StatusBadRequest, utilities.ErrorResponse{
+			Error: "Username or ip must be provided",
+		})
+		return
+	}
+
+	err = auth.NewLoginGuard(jc.DB).Unlock(key)
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, utilities.ErrorResponse{
+			Error: fmt.Sprintf("%s has no failed login attempt", key),
+		})
+		return
+
+	case err == nil:
+		// Do nothing
+
+	default:
+		utilities.RespondDBError(c, err)
+		return
+	}
+
+	auth.LogAuthAttempt("info", "Local", "Success", key, fmt.Sprintf("Unlocked by admin %s", admin.Username))
+	c.JSON(http.StatusOK, gin.H{"message": fmt.Sprintf("%s unlocked", key)})
+}
+
+// auditLogListResponse is paginated list of audit log entries
+type auditLogListResponse struct {
+	Logs []model.AuditLog `json:"logs"`
+	utilities.Pagination
+}
+
+// GetAuditLogs function returns audit log of privileged actions, newest first
+// @Summary Get audit log
+// @Description Only user with audit:view permission can access this endpoint
+// @Description from and to must be in 'YYYY-MM-DDTHH:mm:ssZ' format
+// @Tags Admin
+// @Produce json
+// @Param Authorization header string true "Insert your access token" default(Bearer <your access token>)
+// @Param actor_id query string false "ID of user who took the action"
+// @Param action query string false "Action, e.g. user.punish, company.verify, report.update_status, job_post.edit"
+// @Param target_type query string false "Type of the target, e.g. user, company, report, job_post"
+// @Param target_id query string false "ID of the target, report ID is '<type>/<id>'"
+// @Param request_id query string false "ID of the request"
+// @Param from query string false "Only actions at or after this time"
+// @Param to query string false "Only actions before this time"
+// @Param page query integer false "Page number, start from 1" default(1)
+// @Param limit query integer false "Entries per page, max 100" default(20)
+// @Success 200 {object} auditLogListResponse
+// @Failure 400 {object} utilities.ErrorResponse "Invalid query"
+// @Failure 401 {object} utilities.ErrorResponse "Invalid token"
+// @Failure 403 {object} utilities.ErrorResponse "Do not have permission"
+// @Failure 500 {object} utilities.ErrorResponse "Database error"
+// @Router /admin/audit [get]
+func (jc *AdminController) GetAuditLogs(c *gin.Context) {
+	pagination, err := utilities.ParsePagination(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utilities.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	if rawActor := c.Query("actor_id"); rawActor != "" {
+		if _, err := uuid.Parse(rawActor); err != nil {
+			c.JSON(http.StatusBadRequest, utilities.ErrorResponse{Error: "Invalid actor_id"})
+			return
+		}
+	}
+
+	query := jc.DB.Model(&model.AuditLog{})
+	for _, field := range []string{"actor_id", "action", "target_type", "target_id", "request_id"} {
+		if value := c.Query(field); value != "" {
+			query = query.Where(field+" = ?", value)
+		}
+	}
+	for param, cond := range map[string]string{"from": "created_at >= ?", "to": "created_at < ?"} {
+		raw := c.Query(param)
+		if raw == "" {
+			continue
+		}
+		at, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, utilities.ErrorResponse{
+				Error: fmt.Sprintf("%s must be in RFC3339 format", param),
+			})
+			return
+		}
+		query = query.Where(cond, at)
+	}
+
+	// Make query reusable for both counting and fetching
+	query = query.Session(&gorm.Session{})
+
+	if err := query.Count(&pagination.Total).Error; err != nil {
+		utilities.RespondDBError(c, err)
+		return
+	}
+
+	resp := auditLogListResponse{Logs: []model.AuditLog{}, Pagination: pagination}
+	if err := query.Order("created_at DESC, id DESC").
+		Offset(pagination.Offset()).
+		Limit(pagination.Limit).
+		Find(&resp.Logs).Error; err != nil {
+		utilities.RespondDBError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,191 @@
+package appeal
+
+import (
+	"HireMeMaybe-backend/internal/auth"
+	"HireMeMaybe-backend/internal/database"
+	"HireMeMaybe-backend/internal/middleware"
+	"HireMeMaybe-backend/internal/model"
+	"HireMeMaybe-backend/internal/policy"
+	"HireMeMaybe-backend/internal/testutil"
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/testcontainers/testcontainers-go"
+)
+
+var testDB *database.DBinstanceStruct
+
+func TestMain(m *testing.M) {
+	gin.SetMode(gin.TestMode)
+	var err error
+	var midTeardown func(context.Context, ...testcontainers.TerminateOption) error
+	midTeardown, testDB, err = database.GetTestDB()
+	if err != nil {
+		os.Exit(1)
+	}
+	m.Run()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if midTeardown != nil {
+		_ = midTeardown(ctx)
+	}
+}
+
+func appealRouter() *gin.Engine {
+	r := gin.Default()
+	ac := NewAppealController(testDB)
+
+	r.POST("/appeal", middleware.RequireAuth(testDB), ac.SubmitAppeal)
+	r.GET("/appeal", middleware.RequireAuth(testDB), ac.GetMyAppeals)
+	r.GET("/admin/appeals", middleware.RequireAuth(testDB), middleware.RequirePermission(testDB, policy.UserPunish), ac.GetAppeals)
+	r.PATCH("/admin/appeals/:id", middleware.RequireAuth(testDB), middleware.RequirePermission(testDB, policy.UserPunish), ac.ReviewAppeal)
+	r.GET("/check", middleware.RequireAuth(testDB), middleware.CheckPunishment(testDB, model.BanPunishment), func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"ok": true})
+	})
+	return r
+}
+
+// Helper: ban user permanently, punishment history is removed when test end.
+func banUser(t *testing.T, userID uuid.UUID) model.PunishmentStruct {
+	t.Helper()
+	now := time.Now()
+	punishment := model.PunishmentStruct{
+		UserID:         &userID,
+		PunishmentType: model.BanPunishment,
+		PunishAt:       &now,
+		Reason:         "Spam",
+		IssuedByID:     &database.TestAdminUser.ID,
+	}
+	assert.NoError(t, testDB.Create(&punishment).Error)
+	_, err := database.RefreshActivePunishment(testDB.DB, userID)
+	assert.NoError(t, err)
+
+	t.Cleanup(func() {
+		testDB.Model(&model.User{}).Where("id = ?", userID).Update("punishment_id", nil)
+		testDB.Where("user_id = ?", userID).Delete(&model.PunishmentAppeal{})
+		testDB.Where("user_id = ?", userID).Delete(&model.PunishmentStruct{})
+	})
+	return punishment
+}
+
+func TestAcceptedAppealLiftsBan(t *testing.T) {
+	r := appealRouter()
+	punishment := banUser(t, database.TestUserCPSK2.ID)
+
+	userToken, err := auth.GetAccessToken(t, testDB, database.TestUserCPSK2.Username, database.TestSeedPassword)
+	assert.NoError(t, err)
+	adminToken, err := auth.GetAccessToken(t, testDB, database.TestAdminUser.Username, database.TestSeedPassword)
+	assert.NoError(t, err)
+
+	rec, _ := testutil.MakeJSONRequest(nil, userToken, r, "/check", http.MethodGet)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	// Active punishment is appealed when punishment_id is omitted
+	rec, resp := testutil.MakeJSONRequest(gin.H{"message": "I did not spam"}, userToken, r, "/appeal", http.MethodPost)
+	assert.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	assert.Equal(t, model.AppealStatusPending, resp["status"])
+	assert.EqualValues(t, punishment.ID, resp["punishment_id"])
+	appealID := resp["id"]
+
+	rec, _ = testutil.MakeJSONRequest(gin.H{"punishment_id": punishment.ID, "message": "Again"}, userToken, r, "/appeal", http.MethodPost)
+	assert.Equal(t, http.StatusConflict, rec.Code, "Punishment can be appealed once")
+
+	rec, resp = testutil.MakeJSONRequest(nil, adminToken, r, "/admin/appeals", http.MethodGet)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.EqualValues(t, 1, resp["total"])
+
+	endpoint := fmt.Sprintf("/admin/appeals/%v", appealID)
+	rec, _ = testutil.MakeJSONRequest(gin.H{"status": "pending"}, adminToken, r, endpoint, http.MethodPatch)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec, resp = testutil.MakeJSONRequest(gin.H{"status": "accepted", "admin_note": "Sorry for the mistake"}, adminToken, r, endpoint, http.MethodPatch)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, database.TestAdminUser.ID.String(), resp["reviewed_by"])
+	rec, _ = testutil.MakeJSONRequest(gin.H{"status": "rejected"}, adminToken, r, endpoint, http.MethodPatch)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+
+	rec, _ = testutil.MakeJSONRequest(nil, userToken, r, "/check", http.MethodGet)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	var lifted model.PunishmentStruct
+	assert.NoError(t, testDB.Where("id = ?", punishment.ID).First(&lifted).Error)
+	assert.NotNil(t, lifted.LiftedAt)
+	assert.Equal(t, database.TestAdminUser.ID, *lifted.LiftedByID)
+
+	rec, _ = testutil.MakeJSONRequest(nil, userToken, r, "/appeal", http.MethodGet)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Contains(t, rec.Body.String(), `"status":"accepted"`)
+	assert.Contains(t, rec.Body.String(), "Sorry for the mistake")
+}
+
+func TestRejectedAppealKeepsBan(t *testing.T) {
+	r := appealRouter()
+	punishment := banUser(t, database.TestUserCPSK2.ID)
+
+	userToken, err := auth.GetAccessToken(t, testDB, database.TestUserCPSK2.Username, database.TestSeedPassword)
+	assert.NoError(t, err)
+	adminToken, err := auth.GetAccessToken(t, testDB, database.TestAdminUser.Username, database.TestSeedPassword)
+	assert.NoError(t, err)
+
+	rec, _ := testutil.MakeJSONRequest(gin.H{"message": "  "}, userToken, r, "/appeal", http.MethodPost)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	otherToken, err := auth.GetAccessToken(t, testDB, database.TestUserCPSK1.Username, database.TestSeedPassword)
+	assert.NoError(t, err)
+	rec, _ = testutil.MakeJSONRequest(gin.H{"punishment_id": punishment.ID, "message": "Not mine"}, otherToken, r, "/appeal", http.MethodPost)
+	assert.Equal(t, http.StatusNotFound, rec.Code, "Punishment of other user can't be appealed")
+
+	rec, resp := testutil.MakeJSONRequest(gin.H{"punishment_id": punishment.ID, "message": "Please"}, userToken, r, "/appeal", http.MethodPost)
+	assert.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+
+	rec, _ = testutil.MakeJSONRequest(gin.H{"status": "rejected", "admin_note": "Spam is confirmed"}, userToken, r,
+		fmt.Sprintf("/admin/appeals/%v", resp["id"]), http.MethodPatch)
+	assert.Equal(t, http.StatusForbidden, rec.Code, "User can't review own appeal")
+
+	rec, resp = testutil.MakeJSONRequest(gin.H{"status": "rejected", "admin_note": "Spam is confirmed"}, adminToken, r,
+		fmt.Sprintf("/admin/appeals/%v", resp["id"]), http.MethodPatch)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, model.AppealStatusRejected, resp["status"])
+
+	rec, _ = testutil.MakeJSONRequest(nil, userToken, r, "/check", http.MethodGet)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+}
+
+func TestConcurrentAppealsConflict(t *testing.T) {
+	r := appealRouter()
+	punishment := banUser(t, database.TestUserCPSK2.ID)
+
+	userToken, err := auth.GetAccessToken(t, testDB, database.TestUserCPSK2.Username, database.TestSeedPassword)
+	assert.NoError(t, err)
+
+	const attempts = 5
+	codes := make(chan int, attempts)
+	var wg sync.WaitGroup
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			rec, _ := testutil.MakeJSONRequest(gin.H{"punishment_id": punishment.ID, "message": "Please"}, userToken, r, "/appeal", http.MethodPost)
+			codes <- rec.Code
+		}()
+	}
+	wg.Wait()
+	close(codes)
+
+	created := 0
+	for code := range codes {
+		if code == http.StatusCreated {
+			created++
+			continue
+		}
+		assert.Equal(t, http.StatusConflict, code, "Duplicate appeal must be rejected as conflict")
+	}
+	assert.Equal(t, 1, created)
+}
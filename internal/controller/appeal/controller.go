@@ -0,0 +1,320 @@
+// Package appeal provides HTTP handlers for punished users to appeal their punishment and for admin to review appeals.
+package appeal
+
+import (
+	"HireMeMaybe-backend/internal/database"
+	"HireMeMaybe-backend/internal/model"
+	"HireMeMaybe-backend/internal/utilities"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+var errAppealReviewed = errors.New("appeal has already been reviewed")
+
+// AppealController handles punishment appeals
+type AppealController struct {
+	DB *database.DBinstanceStruct
+}
+
+// NewAppealController creates a new instance of AppealController
+func NewAppealController(db *database.DBinstanceStruct) *AppealController {
+	return &AppealController{
+		DB: db,
+	}
+}
+
+// submitAppealRequest is appeal written by punished user
+type submitAppealRequest struct {
+	PunishmentID *int   `json:"punishment_id"`
+	Message      string `json:"message" binding:"required"`
+}
+
+// reviewAppealRequest is decision of admin on appeal
+type reviewAppealRequest struct {
+	Status    string `json:"status" binding:"required"`
+	AdminNote string `json:"admin_note"`
+}
+
+// appealListResponse is paginated list of appeals
+type appealListResponse struct {
+	Appeals []model.PunishmentAppeal `json:"appeals"`
+	utilities.Pagination
+}
+
+// SubmitAppeal lets punished user appeal their punishment, even while banned
+// @Summary Appeal punishment
+// @Description Each punishment can be appealed once while it is active.
+// @Description If punishment_id is omitted, current active punishment of the user is appealed.
+// @Tags Appeal
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Insert your access token" default(Bearer <your access token>)
+// @Param Appeal body submitAppealRequest true "Punishment to appeal and message to admin"
+// @Success 201 {object} model.PunishmentAppeal "Submitted appeal"
+// @Failure 400 {object} utilities.ErrorResponse "Invalid request body, punishment is not active"
+// @Failure 401 {object} utilities.ErrorResponse "Invalid token"
+// @Failure 404 {object} utilities.ErrorResponse "Punishment not found"
+// @Failure 409 {object} utilities.ErrorResponse "Punishment has already been appealed"
+// @Failure 500 {object} utilities.ErrorResponse "Database error"
+// @Router /appeal [post]
+func (ac *AppealController) SubmitAppeal(c *gin.Context) {
+	user, err := utilities.ExtractUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, utilities.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	req := submitAppealRequest{}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utilities.ErrorResponse{
+			Error: fmt.Sprintf("Invalid request body: %s", err.Error()),
+		})
+		return
+	}
+	message := strings.TrimSpace(req.Message)
+	if message == "" {
+		c.JSON(http.StatusBadRequest, utilities.ErrorResponse{Error: "Invalid request body: message is required"})
+		return
+	}
+
+	punishmentID := req.PunishmentID
+	if punishmentID == nil {
+		punishmentID = user.PunishmentID
+	}
+	if punishmentID == nil {
+		c.JSON(http.StatusNotFound, utilities.ErrorResponse{Error: "You don't have active punishment"})
+		return
+	}
+
+	// Punishment issued before history was kept is only linked from the user
+	var punishment model.PunishmentStruct
+	query := ac.DB.Where("id = ?", *punishmentID)
+	if user.PunishmentID != nil && *user.PunishmentID == *punishmentID {
+		query = query.Where("user_id = ? OR user_id IS NULL", user.ID)
+	} else {
+		query = query.Where("user_id = ?", user.ID)
+	}
+	if err := query.First(&punishment).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, utilities.ErrorResponse{Error: "Punishment not found"})
+			return
+		}
+		utilities.RespondDBError(c, err)
+		return
+	}
+
+	if !punishment.IsActive(time.Now()) {
+		c.JSON(http.StatusBadRequest, utilities.ErrorResponse{Error: "Only active punishment can be appealed"})
+		return
+	}
+
+	var count int64
+	if err := ac.DB.Model(&model.PunishmentAppeal{}).Where("punishment_id = ?", punishment.ID).Count(&count).Error; err != nil {
+		utilities.RespondDBError(c, err)
+		return
+	}
+	if count > 0 {
+		c.JSON(http.StatusConflict, utilities.ErrorResponse{Error: "Punishment has already been appealed"})
+		return
+	}
+
+	appeal := model.PunishmentAppeal{
+		PunishmentID: punishment.ID,
+		UserID:       user.ID,
+		Message:      message,
+		Status:       model.AppealStatusPending,
+	}
+	if err := ac.DB.Create(&appeal).Error; err != nil {
+		var pqErr *pgconn.PgError
+		// Concurrent appeal of the same punishment is rejected by unique index after the count above
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			c.JSON(http.StatusConflict, utilities.ErrorResponse{Error: "Punishment has already been appealed"})
+			return
+		}
+		utilities.RespondDBError(c, err)
+		return
+	}
+	appeal.Punishment = punishment
+
+	c.JSON(http.StatusCreated, appeal)
+}
+
+// GetMyAppeals lists appeals of current user with their status, newest first
+// @Summary Get my appeals
+// @Tags Appeal
+// @Produce json
+// @Param Authorization header string true "Insert your access token" default(Bearer <your access token>)
+// @Success 200 {array} model.PunishmentAppeal "Appeals of current user"
+// @Failure 401 {object} utilities.ErrorResponse "Invalid token"
+// @Failure 500 {object} utilities.ErrorResponse "Database error"
+// @Router /appeal [get]
+func (ac *AppealController) GetMyAppeals(c *gin.Context) {
+	user, err := utilities.ExtractUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, utilities.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	appeals := []model.PunishmentAppeal{}
+	if err := ac.DB.Preload("Punishment").
+		Where("user_id = ?", user.ID).
+		Order("created_at DESC, id DESC").
+		Find(&appeals).Error; err != nil {
+		utilities.RespondDBError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, appeals)
+}
+
+// GetAppeals lists appeals for admin to review, oldest first
+// @Summary Get punishment appeals
+// @Description Only user with user:punish permission can access this endpoint
+// @Tags Admin
+// @Produce json
+// @Param Authorization header string true "Insert your access token" default(Bearer <your access token>)
+// @Param status query string false "Only pending, accepted or rejected" default(pending)
+// @Param page query integer false "Page number, start from 1" default(1)
+// @Param limit query integer false "Entries per page, max 100" default(20)
+// @Success 200 {object} appealListResponse
+// @Failure 400 {object} utilities.ErrorResponse "Invalid query"
+// @Failure 401 {object} utilities.ErrorResponse "Invalid token"
+// @Failure 403 {object} utilities.ErrorResponse "Do not have permission"
+// @Failure 500 {object} utilities.ErrorResponse "Database error"
+// @Router /admin/appeals [get]
+func (ac *AppealController) GetAppeals(c *gin.Context) {
+	pagination, err := utilities.ParsePagination(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utilities.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	status := strings.ToLower(c.DefaultQuery("status", model.AppealStatusPending))
+	if status != model.AppealStatusPending && status != model.AppealStatusAccepted && status != model.AppealStatusRejected {
+		c.JSON(http.StatusBadRequest, utilities.ErrorResponse{Error: "status can be only 'pending', 'accepted' or 'rejected'"})
+		return
+	}
+
+	// Make query reusable for both counting and fetching
+	query := ac.DB.Model(&model.PunishmentAppeal{}).Where("status = ?", status).Session(&gorm.Session{})
+
+	if err := query.Count(&pagination.Total).Error; err != nil {
+		utilities.RespondDBError(c, err)
+		return
+	}
+
+	resp := appealListResponse{Appeals: []model.PunishmentAppeal{}, Pagination: pagination}
+	if err := query.Preload("Punishment").
+		Order("created_at ASC, id ASC").
+		Offset(pagination.Offset()).
+		Limit(pagination.Limit).
+		Find(&resp.Appeals).Error; err != nil {
+		utilities.RespondDBError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// ReviewAppeal accepts or rejects pending appeal, accepting lifts the appealed punishment
+// @Summary Review punishment appeal
+// @Description Only user with user:punish permission can access this endpoint
+// @Description status must be 'accepted' or 'rejected', admin_note is shown to the user
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Insert your access token" default(Bearer <your access token>)
+// @Param id path integer true "ID of the appeal"
+// @Param Decision body reviewAppealRequest true "Decision and note to the user"
+// @Success 200 {object} model.PunishmentAppeal "Reviewed appeal"
+// @Failure 400 {object} utilities.ErrorResponse "Invalid request body"
+// @Failure 401 {object} utilities.ErrorResponse "Invalid token"
+// @Failure 403 {object} utilities.ErrorResponse "Do not have permission"
+// @Failure 404 {object} utilities.ErrorResponse "Appeal not found"
+// @Failure 409 {object} utilities.ErrorResponse "Appeal has already been reviewed"
+// @Failure 500 {object} utilities.ErrorResponse "Database error"
+// @Router /admin/appeals/{id} [patch]
+func (ac *AppealController) ReviewAppeal(c *gin.Context) {
+	admin, err := utilities.ExtractUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, utilities.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	req := reviewAppealRequest{}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utilities.ErrorResponse{
+			Error: fmt.Sprintf("Invalid request body: %s", err.Error()),
+		})
+		return
+	}
+	status := strings.ToLower(strings.TrimSpace(req.Status))
+	if status != model.AppealStatusAccepted && status != model.AppealStatusRejected {
+		c.JSON(http.StatusBadRequest, utilities.ErrorResponse{
+			Error: "Invalid request body: status can be only 'accepted' or 'rejected'",
+		})
+		return
+	}
+
+	var appeal model.PunishmentAppeal
+	if err := ac.DB.Where("id = ?", c.Param("id")).First(&appeal).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, utilities.ErrorResponse{Error: "Appeal not found"})
+			return
+		}
+		utilities.RespondDBError(c, err)
+		return
+	}
+	if appeal.Status != model.AppealStatusPending {
+		c.JSON(http.StatusConflict, utilities.ErrorResponse{Error: errAppealReviewed.Error()})
+		return
+	}
+
+	now := time.Now()
+	if err := ac.DB.Transaction(func(tx *gorm.DB) error {
+		// Only update if appeal is still pending so it is not reviewed twice
+		result := tx.Model(&model.PunishmentAppeal{}).
+			Where("id = ? AND status = ?", appeal.ID, model.AppealStatusPending).
+			Updates(map[string]interface{}{
+				"status":         status,
+				"admin_note":     req.AdminNote,
+				"reviewed_by_id": admin.ID,
+				"reviewed_at":    now,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errAppealReviewed
+		}
+
+		if status != model.AppealStatusAccepted {
+			return nil
+		}
+		return database.LiftPunishment(tx, appeal.PunishmentID, appeal.UserID, admin.ID,
+			fmt.Sprintf("Appeal #%d accepted: %s", appeal.ID, req.AdminNote))
+	}); err != nil {
+		if errors.Is(err, errAppealReviewed) {
+			c.JSON(http.StatusConflict, utilities.ErrorResponse{Error: err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, utilities.ErrorResponse{
+			Error: fmt.Sprintf("Failed to review appeal: %s", err.Error()),
+		})
+		return
+	}
+
+	if err := ac.DB.Preload("Punishment").Where("id = ?", appeal.ID).First(&appeal).Error; err != nil {
+		utilities.RespondDBError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, appeal)
+}
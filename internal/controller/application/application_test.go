@@ -2,17 +2,21 @@ package application
 
 import (
 	"HireMeMaybe-backend/internal/auth"
+	"HireMeMaybe-backend/internal/controller/file"
 	"HireMeMaybe-backend/internal/database"
 	"HireMeMaybe-backend/internal/middleware"
 	"HireMeMaybe-backend/internal/model"
 	"HireMeMaybe-backend/internal/testutil"
 	"context"
+	"encoding/csv"
+	"fmt"
 	"net/http"
 	"os"
 	"testing"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/testcontainers/testcontainers-go"
 )
@@ -124,3 +128,469 @@ func TestApplicationHandler_InvalidPostID(t *testing.T) {
 		assert.Contains(t, resp["error"], "job post not found")
 	}
 }
+
+func setupPostApplicationsRouter() *gin.Engine {
+	r := gin.Default()
+	ac := &ApplicationController{DB: testDB}
+	r.GET("/jobpost/:id/applications", middleware.RequireAuth(testDB), middleware.CheckRole(model.RoleCompany), ac.GetPostApplications)
+	return r
+}
+
+func createTestApplication(t *testing.T, cpskID uuid.UUID, postID uint, status string) model.Application {
+	t.Helper()
+
+	f := model.File{Content: []byte("resume"), Extension: ".pdf"}
+	if err := testDB.Create(&f).Error; err != nil {
+		t.Fatalf("failed to create resume file: %v", err)
+	}
+
+	// Ensure only one application per CPSK and post
+	if err := testDB.Where("post_id = ? AND cpsk_id = ?", postID, cpskID).
+		Delete(&model.Application{}).Error; err != nil {
+		t.Fatalf("failed to cleanup existing application: %v", err)
+	}
+
+	application := model.Application{
+		CPSKID:   cpskID,
+		PostID:   postID,
+		ResumeID: &f.ID,
+		Status:   status,
+	}
+	if err := testDB.Create(&application).Error; err != nil {
+		t.Fatalf("failed to create application: %v", err)
+	}
+	return application
+}
+
+func TestGetPostApplications_Owner(t *testing.T) {
+	application := createTestApplication(t, database.TestUserCPSK2.ID, database.TestJobPost1.ID, model.ApplicationStatusPending)
+
+	companyToken, err := auth.GetAccessToken(t, testDB, database.TestUserCompany1.Username, database.TestSeedPassword)
+	assert.NoError(t, err)
+
+	r := setupPostApplicationsRouter()
+	rec, resp := testutil.MakeJSONRequest(nil, companyToken, r, fmt.Sprintf("/jobpost/%d/applications", database.TestJobPost1.ID), http.MethodGet)
+
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.GreaterOrEqual(t, resp["total"], float64(1))
+	assert.Equal(t, float64(1), resp["page"])
+
+	applications, ok := resp["applications"].([]interface{})
+	assert.True(t, ok)
+
+	found := false
+	for _, raw := range applications {
+		item := raw.(map[string]interface{})
+		if item["id"] == float64(application.ID) {
+			found = true
+			assert.Equal(t, fmt.Sprintf("/api/v1/file/%d", *application.ResumeID), item["resume_url"])
+			assert.NotNil(t, item["cpsk_user"])
+		}
+	}
+	assert.True(t, found, "created application should be listed")
+}
+
+func TestGetPostApplications_StatusFilter(t *testing.T) {
+	createTestApplication(t, database.TestUserCPSK2.ID, database.TestJobPost2.ID, model.ApplicationStatusRejected)
+
+	companyToken, err := auth.GetAccessToken(t, testDB, database.TestUserCompany1.Username, database.TestSeedPassword)
+	assert.NoError(t, err)
+
+	r := setupPostApplicationsRouter()
+	rec, resp := testutil.MakeJSONRequest(nil, companyToken, r, fmt.Sprintf("/jobpost/%d/applications?status=pending&limit=5", database.TestJobPost2.ID), http.MethodGet)
+
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, float64(5), resp["limit"])
+	for _, raw := range resp["applications"].([]interface{}) {
+		item := raw.(map[string]interface{})
+		assert.Equal(t, model.ApplicationStatusPending, item["status"])
+	}
+}
+
+func TestGetPostApplications_NotOwner(t *testing.T) {
+	companyToken, err := auth.GetAccessToken(t, testDB, database.TestUserCompany2.Username, database.TestSeedPassword)
+	assert.NoError(t, err)
+
+	r := setupPostApplicationsRouter()
+	rec, resp := testutil.MakeJSONRequest(nil, companyToken, r, fmt.Sprintf("/jobpost/%d/applications", database.TestJobPost1.ID), http.MethodGet)
+
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	assert.Contains(t, resp["error"], "not allowed")
+}
+
+func TestGetPostApplications_NotFound(t *testing.T) {
+	companyToken, err := auth.GetAccessToken(t, testDB, database.TestUserCompany1.Username, database.TestSeedPassword)
+	assert.NoError(t, err)
+
+	r := setupPostApplicationsRouter()
+	rec, _ := testutil.MakeJSONRequest(nil, companyToken, r, "/jobpost/999999/applications", http.MethodGet)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestGetPostApplications_InvalidPage(t *testing.T) {
+	companyToken, err := auth.GetAccessToken(t, testDB, database.TestUserCompany1.Username, database.TestSeedPassword)
+	assert.NoError(t, err)
+
+	r := setupPostApplicationsRouter()
+	rec, _ := testutil.MakeJSONRequest(nil, companyToken, r, fmt.Sprintf("/jobpost/%d/applications?page=0", database.TestJobPost1.ID), http.MethodGet)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func setupStatusRouter() *gin.Engine {
+	r := gin.Default()
+	ac := &ApplicationController{DB: testDB}
+	r.PATCH("/application/:id/status", middleware.RequireAuth(testDB), middleware.CheckRole(model.RoleCompany), ac.UpdateApplicationStatus)
+	return r
+}
+
+func TestUpdateApplicationStatus_Success(t *testing.T) {
+	application := createTestApplication(t, database.TestUserCPSK2.ID, database.TestJobPost1.ID, model.ApplicationStatusPending)
+
+	companyToken, err := auth.GetAccessToken(t, testDB, database.TestUserCompany1.Username, database.TestSeedPassword)
+	assert.NoError(t, err)
+
+	r := setupStatusRouter()
+	body := gin.H{"status": model.ApplicationStatusInterview, "note": "Invite to first round"}
+	rec, resp := testutil.MakeJSONRequest(body, companyToken, r, fmt.Sprintf("/application/%d/status", application.ID), http.MethodPatch)
+
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, model.ApplicationStatusInterview, resp["status"])
+
+	var history []model.ApplicationHistory
+	assert.NoError(t, testDB.Where("application_id = ?", application.ID).Find(&history).Error)
+	assert.Len(t, history, 1)
+	assert.Equal(t, model.ApplicationStatusPending, history[0].FromStatus)
+	assert.Equal(t, model.ApplicationStatusInterview, history[0].ToStatus)
+	assert.Equal(t, database.TestUserCompany1.ID, history[0].ActorID)
+	assert.Equal(t, "Invite to first round", history[0].Note)
+}
+
+func TestUpdateApplicationStatus_IllegalTransition(t *testing.T) {
+	application := createTestApplication(t, database.TestUserCPSK2.ID, database.TestJobPost1.ID, model.ApplicationStatusPending)
+
+	companyToken, err := auth.GetAccessToken(t, testDB, database.TestUserCompany1.Username, database.TestSeedPassword)
+	assert.NoError(t, err)
+
+	r := setupStatusRouter()
+	rec, resp := testutil.MakeJSONRequest(gin.H{"status": model.ApplicationStatusHired}, companyToken, r, fmt.Sprintf("/application/%d/status", application.ID), http.MethodPatch)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, resp["error"], "Cannot change application status")
+}
+
+func TestUpdateApplicationStatus_CompanyCannotWithdraw(t *testing.T) {
+	application := createTestApplication(t, database.TestUserCPSK2.ID, database.TestJobPost1.ID, model.ApplicationStatusPending)
+
+	companyToken, err := auth.GetAccessToken(t, testDB, database.TestUserCompany1.Username, database.TestSeedPassword)
+	assert.NoError(t, err)
+
+	r := setupStatusRouter()
+	rec, _ := testutil.MakeJSONRequest(gin.H{"status": model.ApplicationStatusWithdrawn}, companyToken, r, fmt.Sprintf("/application/%d/status", application.ID), http.MethodPatch)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestUpdateApplicationStatus_NotOwner(t *testing.T) {
+	application := createTestApplication(t, database.TestUserCPSK2.ID, database.TestJobPost1.ID, model.ApplicationStatusPending)
+
+	companyToken, err := auth.GetAccessToken(t, testDB, database.TestUserCompany2.Username, database.TestSeedPassword)
+	assert.NoError(t, err)
+
+	r := setupStatusRouter()
+	rec, _ := testutil.MakeJSONRequest(gin.H{"status": model.ApplicationStatusRejected}, companyToken, r, fmt.Sprintf("/application/%d/status", application.ID), http.MethodPatch)
+
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+}
+
+func TestUpdateApplicationStatus_NotFound(t *testing.T) {
+	companyToken, err := auth.GetAccessToken(t, testDB, database.TestUserCompany1.Username, database.TestSeedPassword)
+	assert.NoError(t, err)
+
+	r := setupStatusRouter()
+	rec, _ := testutil.MakeJSONRequest(gin.H{"status": model.ApplicationStatusRejected}, companyToken, r, "/application/999999/status", http.MethodPatch)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func setupMyApplicationsRouter() *gin.Engine {
+	r := gin.Default()
+	ac := &ApplicationController{DB: testDB}
+	cpskRoute := r.Group("/cpsk", middleware.RequireAuth(testDB), middleware.CheckRole(model.RoleCPSK))
+	cpskRoute.GET("applications", ac.GetMyApplications)
+	cpskRoute.GET("applications/:id", ac.GetMyApplicationByID)
+	cpskRoute.POST("applications/:id/withdraw", ac.WithdrawApplication)
+	return r
+}
+
+func TestGetMyApplications_Success(t *testing.T) {
+	application := createTestApplication(t, database.TestUserCPSK2.ID, database.TestJobPost1.ID, model.ApplicationStatusPending)
+
+	cpskToken, err := auth.GetAccessToken(t, testDB, database.TestUserCPSK2.Username, database.TestSeedPassword)
+	assert.NoError(t, err)
+
+	r := setupMyApplicationsRouter()
+	rec, resp := testutil.MakeJSONRequest(nil, cpskToken, r, "/cpsk/applications", http.MethodGet)
+
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	found := false
+	for _, raw := range resp["applications"].([]interface{}) {
+		item := raw.(map[string]interface{})
+		if item["id"] == float64(application.ID) {
+			found = true
+			jobPost := item["job_post"].(map[string]interface{})
+			assert.Equal(t, database.TestJobPost1.Title, jobPost["title"])
+			assert.Equal(t, database.TestCompany1.Name, jobPost["company_name"])
+		}
+	}
+	assert.True(t, found, "created application should be listed")
+}
+
+func TestGetMyApplicationByID_OtherUser(t *testing.T) {
+	application := createTestApplication(t, database.TestUserCPSK2.ID, database.TestJobPost1.ID, model.ApplicationStatusPending)
+
+	cpskToken, err := auth.GetAccessToken(t, testDB, database.TestUserCPSK1.Username, database.TestSeedPassword)
+	assert.NoError(t, err)
+
+	r := setupMyApplicationsRouter()
+	rec, _ := testutil.MakeJSONRequest(nil, cpskToken, r, fmt.Sprintf("/cpsk/applications/%d", application.ID), http.MethodGet)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestWithdrawApplication_Success(t *testing.T) {
+	application := createTestApplication(t, database.TestUserCPSK2.ID, database.TestJobPost1.ID, model.ApplicationStatusPending)
+
+	cpskToken, err := auth.GetAccessToken(t, testDB, database.TestUserCPSK2.Username, database.TestSeedPassword)
+	assert.NoError(t, err)
+
+	r := setupMyApplicationsRouter()
+	route := fmt.Sprintf("/cpsk/applications/%d/withdraw", application.ID)
+	rec, resp := testutil.MakeJSONRequest(gin.H{"note": "Accepted another offer"}, cpskToken, r, route, http.MethodPost)
+
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, model.ApplicationStatusWithdrawn, resp["status"])
+	history := resp["history"].([]interface{})
+	assert.Len(t, history, 1)
+	assert.Equal(t, model.ApplicationStatusWithdrawn, history[0].(map[string]interface{})["to_status"])
+
+	// Withdrawn is a final status
+	rec, resp = testutil.MakeJSONRequest(nil, cpskToken, r, route, http.MethodPost)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, resp["error"], "can't be withdrawn")
+}
+
+func TestWithdrawApplication_OtherUser(t *testing.T) {
+	application := createTestApplication(t, database.TestUserCPSK2.ID, database.TestJobPost1.ID, model.ApplicationStatusPending)
+
+	cpskToken, err := auth.GetAccessToken(t, testDB, database.TestUserCPSK1.Username, database.TestSeedPassword)
+	assert.NoError(t, err)
+
+	r := setupMyApplicationsRouter()
+	rec, _ := testutil.MakeJSONRequest(nil, cpskToken, r, fmt.Sprintf("/cpsk/applications/%d/withdraw", application.ID), http.MethodPost)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+// createFormJobPost creates job post of TestUserCompany1 with custom form of given questions
+func createFormJobPost(t *testing.T, questions []model.FormQuestion) model.JobPost {
+	t.Helper()
+	for i := range questions {
+		questions[i].Position = i
+	}
+	post := model.JobPost{
+		CompanyUserID:       database.TestUserCompany1.ID,
+		EditableJobPostInfo: model.EditableJobPostInfo{Title: "Custom Form Internship"},
+		Questions:           questions,
+	}
+	if err := testDB.Create(&post).Error; err != nil {
+		t.Fatalf("failed to create job post: %v", err)
+	}
+	return post
+}
+
+func TestApplicationHandler_CustomFormAnswers(t *testing.T) {
+	cpskToken, err := auth.GetAccessToken(t, testDB, database.TestUserCPSK1.Username, database.TestSeedPassword)
+	assert.NoError(t, err)
+
+	post := createFormJobPost(t, []model.FormQuestion{
+		{Label: "Why us?", Type: model.QuestionTypeLongText, Required: true},
+		{Label: "Preferred team", Type: model.QuestionTypeSingleChoice, Options: []string{"Backend", "Frontend"}},
+		{Label: "Portfolio", Type: model.QuestionTypeFile},
+	})
+	whyUs, team, portfolio := post.Questions[0].ID, post.Questions[1].ID, post.Questions[2].ID
+	resume := model.File{Content: []byte("resume"), Extension: ".pdf"}
+	assert.NoError(t, testDB.Create(&resume).Error)
+
+	r := gin.Default()
+	ac := &ApplicationController{DB: testDB}
+	r.POST("/application", middleware.RequireAuth(testDB), middleware.CheckRole(model.RoleCPSK), ac.ApplicationHandler)
+
+	rec, resp := testutil.MakeJSONRequest(gin.H{
+		"post_id":   post.ID,
+		"resume_id": resume.ID,
+		"answers":   []gin.H{{"question_id": team, "choices": []string{"Backend"}}},
+	}, cpskToken, r, "/application", http.MethodPost)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, resp["error"], "'Why us?' is required")
+
+	rec, resp = testutil.MakeJSONRequest(gin.H{
+		"post_id":   post.ID,
+		"resume_id": resume.ID,
+		"answers": []gin.H{
+			{"question_id": whyUs, "text": "I like Go"},
+			{"question_id": team, "choices": []string{"Design"}},
+		},
+	}, cpskToken, r, "/application", http.MethodPost)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, resp["error"], "not an option")
+
+	// File uploaded by someone else can't be attached
+	otherFile := model.File{Content: []byte("portfolio"), Extension: ".pdf", UploaderID: &database.TestUserCPSK2.ID}
+	assert.NoError(t, testDB.Create(&otherFile).Error)
+	rec, _ = testutil.MakeJSONRequest(gin.H{
+		"post_id":   post.ID,
+		"resume_id": resume.ID,
+		"answers": []gin.H{
+			{"question_id": whyUs, "text": "I like Go"},
+			{"question_id": portfolio, "file_id": otherFile.ID},
+		},
+	}, cpskToken, r, "/application", http.MethodPost)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	ownFile := model.File{Content: []byte("portfolio"), Extension: ".pdf", UploaderID: &database.TestUserCPSK1.ID}
+	assert.NoError(t, testDB.Create(&ownFile).Error)
+	rec, _ = testutil.MakeJSONRequest(gin.H{
+		"post_id":   post.ID,
+		"resume_id": resume.ID,
+		"answers": []gin.H{
+			{"question_id": whyUs, "text": "I like Go"},
+			{"question_id": team, "choices": []string{"Backend"}},
+			{"question_id": portfolio, "file_id": ownFile.ID},
+		},
+	}, cpskToken, r, "/application", http.MethodPost)
+	assert.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+
+	var answers []model.FormAnswer
+	testDB.Joins("JOIN applications ON applications.id = form_answers.application_id").
+		Where("applications.post_id = ?", post.ID).Find(&answers)
+	assert.Len(t, answers, 3, "Answer is stored per question")
+}
+
+func TestApplicationHandler_SameFileForSeveralQuestions(t *testing.T) {
+	cpskToken, err := auth.GetAccessToken(t, testDB, database.TestUserCPSK1.Username, database.TestSeedPassword)
+	assert.NoError(t, err)
+
+	post := createFormJobPost(t, []model.FormQuestion{
+		{Label: "Portfolio", Type: model.QuestionTypeFile, Required: true},
+		{Label: "Transcript", Type: model.QuestionTypeFile, Required: true},
+	})
+	resume := model.File{Content: []byte("resume"), Extension: ".pdf"}
+	assert.NoError(t, testDB.Create(&resume).Error)
+	ownFile := model.File{Content: []byte("portfolio and transcript"), Extension: ".pdf", UploaderID: &database.TestUserCPSK1.ID}
+	assert.NoError(t, testDB.Create(&ownFile).Error)
+
+	r := gin.Default()
+	ac := &ApplicationController{DB: testDB}
+	r.POST("/application", middleware.RequireAuth(testDB), middleware.CheckRole(model.RoleCPSK), ac.ApplicationHandler)
+
+	rec, _ := testutil.MakeJSONRequest(gin.H{
+		"post_id":   post.ID,
+		"resume_id": resume.ID,
+		"answers": []gin.H{
+			{"question_id": post.Questions[0].ID, "file_id": ownFile.ID},
+			{"question_id": post.Questions[1].ID, "file_id": ownFile.ID},
+		},
+	}, cpskToken, r, "/application", http.MethodPost)
+	assert.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+}
+
+func TestGetAnswerFile_OnlyUploaderAndCompany(t *testing.T) {
+	post := createFormJobPost(t, []model.FormQuestion{
+		{Label: "Portfolio", Type: model.QuestionTypeFile, Required: true},
+	})
+	application := createTestApplication(t, database.TestUserCPSK1.ID, post.ID, model.ApplicationStatusPending)
+	answerFile := model.File{Content: []byte("portfolio"), Extension: ".pdf", UploaderID: &database.TestUserCPSK1.ID}
+	assert.NoError(t, testDB.Create(&answerFile).Error)
+	assert.NoError(t, testDB.Create(&model.FormAnswer{
+		ApplicationID: application.ID,
+		QuestionID:    post.Questions[0].ID,
+		FileID:        &answerFile.ID,
+	}).Error)
+
+	r := gin.Default()
+	fc := file.NewFileController(testDB, nil)
+	r.GET("/file/:id", middleware.RequireAuth(testDB), fc.GetFile)
+	endpoint := fmt.Sprintf("/file/%d", answerFile.ID)
+
+	for _, tc := range []struct {
+		username string
+		status   int
+	}{
+		{database.TestUserCPSK1.Username, http.StatusOK},
+		{database.TestUserCompany1.Username, http.StatusOK},
+		{database.TestUserCPSK2.Username, http.StatusForbidden},
+		{database.TestUserCompany2.Username, http.StatusForbidden},
+	} {
+		token, err := auth.GetAccessToken(t, testDB, tc.username, database.TestSeedPassword)
+		assert.NoError(t, err)
+		rec, _ := testutil.MakeJSONRequest(nil, token, r, endpoint, http.MethodGet)
+		assert.Equal(t, tc.status, rec.Code, tc.username)
+	}
+}
+
+func TestCSVCell(t *testing.T) {
+	for value, expected := range map[string]string{
+		"":                  "",
+		"Jane":              "Jane",
+		"=HYPERLINK(\"x\")": "'=HYPERLINK(\"x\")",
+		"+1":                "'+1",
+		"-1":                "'-1",
+		"@SUM(A1)":          "'@SUM(A1)",
+		"\tcmd":             "'\tcmd",
+		"\rcmd":             "'\rcmd",
+		"a=b":               "a=b",
+	} {
+		assert.Equal(t, expected, csvCell(value), value)
+	}
+}
+
+func TestExportPostAnswers(t *testing.T) {
+	companyToken, err := auth.GetAccessToken(t, testDB, database.TestUserCompany1.Username, database.TestSeedPassword)
+	assert.NoError(t, err)
+	company2Token, err := auth.GetAccessToken(t, testDB, database.TestUserCompany2.Username, database.TestSeedPassword)
+	assert.NoError(t, err)
+
+	post := createFormJobPost(t, []model.FormQuestion{
+		{Label: "Years of Go", Type: model.QuestionTypeNumber, Required: true},
+	})
+	application := createTestApplication(t, database.TestUserCPSK2.ID, post.ID, model.ApplicationStatusPending)
+	years := 2.0
+	assert.NoError(t, testDB.Create(&model.FormAnswer{
+		ApplicationID: application.ID,
+		QuestionID:    post.Questions[0].ID,
+		Number:        &years,
+	}).Error)
+
+	r := gin.Default()
+	ac := &ApplicationController{DB: testDB}
+	r.GET("/jobpost/:id/applications/export", middleware.RequireAuth(testDB), ac.ExportPostAnswers)
+	endpoint := fmt.Sprintf("/jobpost/%d/applications/export", post.ID)
+
+	rec, _ := testutil.MakeJSONRequest(nil, company2Token, r, endpoint, http.MethodGet)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	rec, _ = testutil.MakeJSONRequest(nil, companyToken, r, endpoint, http.MethodGet)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Contains(t, rec.Header().Get("Content-Type"), "text/csv")
+
+	rows, err := csv.NewReader(rec.Body).ReadAll()
+	assert.NoError(t, err)
+	if assert.Len(t, rows, 2) {
+		assert.Equal(t, "Years of Go", rows[0][len(rows[0])-1])
+		assert.Equal(t, fmt.Sprint(application.ID), rows[1][0])
+		assert.Equal(t, "2", rows[1][len(rows[1])-1])
+	}
+}
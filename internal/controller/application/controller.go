@@ -4,16 +4,28 @@ package application
 import (
 	"HireMeMaybe-backend/internal/database"
 	"HireMeMaybe-backend/internal/model"
+	"HireMeMaybe-backend/internal/policy"
 	"HireMeMaybe-backend/internal/utilities"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgconn"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// fileRoutePrefix is the route that serve uploaded file, used to build resume link
+const fileRoutePrefix = "/api/v1/file/"
+
+// errStatusChanged is returned when application status was changed by another request
+var errStatusChanged = errors.New("application status has been changed by another request")
+
 // ApplicationController handles job application related endpoints
 type ApplicationController struct {
 	DB *database.DBinstanceStruct
@@ -33,9 +45,11 @@ func NewApplicationController(db *database.DBinstanceStruct) *ApplicationControl
 // @Accept json
 // @Produce json
 // @Param Authorization header string true "Insert your access token" default(Bearer <your access token>)
+// @Description Answers to custom form of the post are required for its required questions, file answer is ID of file
+// @Description uploaded at POST /application/files
 // @Param application body model.Application true "Application information"
 // @Success 201 {object} model.CPSKUser "Successfully apply job post"
-// @Failure 400 {object} utilities.ErrorResponse "Invalid authorization header, request body"
+// @Failure 400 {object} utilities.ErrorResponse "Invalid authorization header, request body, or answers not matching the form"
 // @Failure 401 {object} utilities.ErrorResponse "Invalid token"
 // @Failure 403 {object} utilities.ErrorResponse "Not logged in as CPSK, User is banned or suspended"
 // @Failure 500 {object} utilities.ErrorResponse "Database error"
@@ -79,12 +93,24 @@ func (j *ApplicationController) ApplicationHandler(c *gin.Context) {
 	}
 
 	application.Status = model.ApplicationStatusPending
+	// Record initial status, any history from request body is discarded
+	application.History = []model.ApplicationHistory{
+		{
+			ToStatus: model.ApplicationStatusPending,
+			ActorID:  user.ID,
+		},
+	}
 
-	// Fetch referenced job post to determine form type instead of trusting request body
+	// Fetch referenced job post to determine form type instead of trusting request body.
+	// Only published post that has not expired can be applied.
 	var job model.JobPost
-	if err := j.DB.Select("id", "default_form").Where("id = ?", application.PostID).First(&job).Error; err != nil {
+	if err := j.DB.Select("id", "default_form").
+		Preload("Questions").
+		Where("id = ? AND status = ?", application.PostID, model.JobPostStatusPublished).
+		Where("expiring > ? OR expiring IS NULL", time.Now()).
+		First(&job).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			c.JSON(http.StatusBadRequest, utilities.ErrorResponse{Error: "Invalid PostID: job post not found"})
+			c.JSON(http.StatusBadRequest, utilities.ErrorResponse{Error: "Invalid PostID: job post not found or not open for application"})
 			return
 		}
 		c.JSON(http.StatusInternalServerError, utilities.ErrorResponse{Error: fmt.Sprintf("Failed to verify job post: %s", err.Error())})
@@ -97,6 +123,25 @@ func (j *ApplicationController) ApplicationHandler(c *gin.Context) {
 		application.AnswerID = nil
 	}
 
+	// Answers to custom form are validated against questions of the post
+	for i := range application.Answers {
+		application.Answers[i].ID = 0
+		application.Answers[i].ApplicationID = 0
+	}
+	if err := model.ValidateFormAnswers(job.Questions, application.Answers); err != nil {
+		c.JSON(http.StatusBadRequest, utilities.ErrorResponse{Error: fmt.Sprintf("Invalid answers: %s", err.Error())})
+		return
+	}
+	ownsFiles, err := j.ownsAnswerFiles(user.ID, application.Answers)
+	if err != nil {
+		utilities.RespondDBError(c, err)
+		return
+	}
+	if !ownsFiles {
+		c.JSON(http.StatusBadRequest, utilities.ErrorResponse{Error: "Invalid answers: file must be uploaded by the applicant"})
+		return
+	}
+
 	// Save application to database
 	if err := j.DB.Create(&application).Error; err != nil {
 		var pqErr *pgconn.PgError
@@ -118,3 +163,544 @@ func (j *ApplicationController) ApplicationHandler(c *gin.Context) {
 	// Return response
 	c.JSON(http.StatusCreated, application)
 }
+
+// ownsAnswerFiles reports whether every file answered was uploaded by the user as application form answer
+func (j *ApplicationController) ownsAnswerFiles(userID uuid.UUID, answers []model.FormAnswer) (bool, error) {
+	// Same file may answer several questions
+	seen := map[int]bool{}
+	fileIDs := []int{}
+	for _, answer := range answers {
+		if answer.FileID != nil && !seen[*answer.FileID] {
+			seen[*answer.FileID] = true
+			fileIDs = append(fileIDs, *answer.FileID)
+		}
+	}
+	if len(fileIDs) == 0 {
+		return true, nil
+	}
+
+	var count int64
+	if err := j.DB.Model(&model.File{}).Where("id IN ? AND uploader_id = ?", fileIDs, userID).Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count == int64(len(fileIDs)), nil
+}
+
+// applicantResponse is an application with link to download applicant's resume
+type applicantResponse struct {
+	model.Application
+	ResumeURL string `json:"resume_url"`
+}
+
+// applicantListResponse is paginated list of applicants for a job post
+type applicantListResponse struct {
+	Applications []applicantResponse `json:"applications"`
+	utilities.Pagination
+}
+
+// GetPostApplications lists applications of given job post for the company that own the post.
+// @Summary Get applications of a job post
+// @Description Only member of company organization that own the post have access to this endpoint
+// @Description status can be multiple value separated by comma, e.g. "pending,rejected"
+// @Tags Application
+// @Produce json
+// @Param Authorization header string true "Insert your access token" default(Bearer <your access token>)
+// @Param id path integer true "ID of desired job post"
+// @Param status query string false "Filter by application status, comma separated"
+// @Param program query string false "Filter by applicant program (CPE or SKE)"
+// @Param search query string false "Search from applicant first or last name with substring matching and case insensitive"
+// @Param desc query boolean false "Sorting by applied time in descending if true, otherwise ascending"
+// @Param page query integer false "Page number, start from 1" default(1)
+// @Param limit query integer false "Number of applications per page (max 100)" default(20)
+// @Success 200 {object} applicantListResponse "Return applications of the job post"
+// @Failure 400 {object} utilities.ErrorResponse "Invalid authorization header, or invalid query"
+// @Failure 401 {object} utilities.ErrorResponse "Invalid token"
+// @Failure 403 {object} utilities.ErrorResponse "Not the owner of the post, User is banned"
+// @Failure 404 {object} utilities.ErrorResponse "Job post not found"
+// @Failure 500 {object} utilities.ErrorResponse "Database error"
+// @Router /jobpost/{id}/applications [get]
+func (j *ApplicationController) GetPostApplications(c *gin.Context) {
+	if _, err := utilities.ExtractUser(c); err != nil {
+		c.JSON(http.StatusUnauthorized, utilities.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	pagination, err := utilities.ParsePagination(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utilities.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	var job model.JobPost
+	if err := j.DB.Select("id", "company_user_id").Where("id = ?", c.Param("id")).First(&job).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, utilities.ErrorResponse{Error: "Job post not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, utilities.ErrorResponse{
+			Error: fmt.Sprintf("Failed to retrieve job post: %s", err.Error()),
+		})
+		return
+	}
+
+	allowed, err := policy.CanOnCompany(c, j.DB.DB, policy.ApplicationViewOwn, policy.ApplicationViewAny, job.CompanyUserID)
+	if err != nil {
+		utilities.RespondDBError(c, err)
+		return
+	}
+	if !allowed {
+		c.JSON(http.StatusForbidden, utilities.ErrorResponse{
+			Error: "You are not allowed to view applications of this job post",
+		})
+		return
+	}
+
+	rawStatus := c.Query("status")
+	rawProgram := c.Query("program")
+	rawSearch := c.Query("search")
+	rawDesc := c.Query("desc")
+
+	query := j.DB.Model(&model.Application{}).Where("applications.post_id = ?", job.ID)
+
+	if rawStatus != "" {
+		status := strings.Split(rawStatus, ",")
+		for i := range status {
+			status[i] = strings.ToLower(strings.TrimSpace(status[i]))
+		}
+		query = query.Where("applications.status IN ?", status)
+	}
+
+	if rawProgram != "" || rawSearch != "" {
+		query = query.Joins("JOIN cpsk_users ON cpsk_users.user_id = applications.cpsk_id")
+	}
+
+	if rawProgram != "" {
+		query = query.Where("cpsk_users.program = ?", strings.ToUpper(rawProgram))
+	}
+
+	if rawSearch != "" {
+		query = query.Where("(cpsk_users.first_name ILIKE ? OR cpsk_users.last_name ILIKE ?)", "%"+rawSearch+"%", "%"+rawSearch+"%")
+	}
+
+	// Make query reusable for both counting and fetching
+	query = query.Session(&gorm.Session{})
+
+	if err := query.Count(&pagination.Total).Error; err != nil {
+		utilities.RespondDBError(c, err)
+		return
+	}
+
+	var applications []model.Application
+	if err := query.Preload("CPSKUser").
+		Preload("CPSKUser.User").
+		Preload("Answer").
+		Preload("Answers").
+		Order(clause.OrderByColumn{
+			Column: clause.Column{Table: "applications", Name: "applied_at"},
+			Desc:   strings.ToLower(rawDesc) == "true",
+		}).
+		Offset(pagination.Offset()).
+		Limit(pagination.Limit).
+		Find(&applications).Error; err != nil {
+		utilities.RespondDBError(c, err)
+		return
+	}
+
+	resp := applicantListResponse{
+		Applications: []applicantResponse{},
+		Pagination:   pagination,
+	}
+	for _, application := range applications {
+		applicant := applicantResponse{Application: application}
+		if application.ResumeID != nil {
+			applicant.ResumeURL = fmt.Sprintf("%s%d", fileRoutePrefix, *application.ResumeID)
+		}
+		resp.Applications = append(resp.Applications, applicant)
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+type statusUpdateRequest struct {
+	Status string `json:"status" binding:"required"`
+	Note   string `json:"note"`
+}
+
+// changeStatus moves the application to given status and records the change in application history.
+// It should be called within a transaction.
+func changeStatus(tx *gorm.DB, application *model.Application, status string, actorID uuid.UUID, note string) error {
+	// Only update if status is still the one we validated against
+	result := tx.Model(&model.Application{}).
+		Where("id = ? AND status = ?", application.ID, application.Status).
+		Update("status", status)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errStatusChanged
+	}
+
+	history := model.ApplicationHistory{
+		ApplicationID: application.ID,
+		FromStatus:    application.Status,
+		ToStatus:      status,
+		ActorID:       actorID,
+		Note:          note,
+	}
+	if err := tx.Create(&history).Error; err != nil {
+		return err
+	}
+
+	application.Status = status
+	return nil
+}
+
+// UpdateApplicationStatus allows the company that own the job post to move an application to next status.
+// @Summary Update status of an application
+// @Description Only owner or recruiter of company organization that own the job post have access to this endpoint
+// @Description Allowed transitions:
+// @Description pending -> in consideration, interview, rejected
+// @Description in consideration -> interview, offer, rejected
+// @Description interview -> in consideration, offer, rejected
+// @Description offer -> hired, rejected
+// @Description hired and rejected are final, withdrawn can only be set by the applicant
+// @Tags Application
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Insert your access token" default(Bearer <your access token>)
+// @Param id path integer true "ID of desired application"
+// @Param Status body statusUpdateRequest true "New status and optional note"
+// @Success 200 {object} model.Application "Successfully update application status"
+// @Failure 400 {object} utilities.ErrorResponse "Invalid authorization header, request body, or illegal status transition"
+// @Failure 401 {object} utilities.ErrorResponse "Invalid token"
+// @Failure 403 {object} utilities.ErrorResponse "Not the owner of the job post, User is banned or suspended"
+// @Failure 404 {object} utilities.ErrorResponse "Application not found"
+// @Failure 409 {object} utilities.ErrorResponse "Application status was changed by another request"
+// @Failure 500 {object} utilities.ErrorResponse "Database error"
+// @Router /application/{id}/status [patch]
+func (j *ApplicationController) UpdateApplicationStatus(c *gin.Context) {
+	user, err := utilities.ExtractUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, utilities.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	var req statusUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utilities.ErrorResponse{
+			Error: fmt.Sprintf("Invalid request body: %s", err.Error()),
+		})
+		return
+	}
+	status := strings.ToLower(strings.TrimSpace(req.Status))
+
+	if status == model.ApplicationStatusWithdrawn {
+		c.JSON(http.StatusBadRequest, utilities.ErrorResponse{
+			Error: "Only the applicant can withdraw an application",
+		})
+		return
+	}
+
+	var application model.Application
+	if err := j.DB.Preload("JobPost").Where("id = ?", c.Param("id")).First(&application).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, utilities.ErrorResponse{Error: "Application not found"})
+			return
+		}
+		utilities.RespondDBError(c, err)
+		return
+	}
+
+	allowed, err := policy.CanOnCompany(c, j.DB.DB, policy.ApplicationReviewOwn, policy.ApplicationReviewAny, application.JobPost.CompanyUserID)
+	if err != nil {
+		utilities.RespondDBError(c, err)
+		return
+	}
+	if !allowed {
+		c.JSON(http.StatusForbidden, utilities.ErrorResponse{
+			Error: "You are not allowed to update this application",
+		})
+		return
+	}
+
+	if !application.CanTransitionTo(status) {
+		c.JSON(http.StatusBadRequest, utilities.ErrorResponse{
+			Error: fmt.Sprintf("Cannot change application status from '%s' to '%s'", application.Status, status),
+		})
+		return
+	}
+
+	if err := j.DB.Transaction(func(tx *gorm.DB) error {
+		return changeStatus(tx, &application, status, user.ID, req.Note)
+	}); err != nil {
+		if errors.Is(err, errStatusChanged) {
+			c.JSON(http.StatusConflict, utilities.ErrorResponse{Error: err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, utilities.ErrorResponse{
+			Error: fmt.Sprintf("Failed to update application status: %s", err.Error()),
+		})
+		return
+	}
+
+	// Reload application with its history to return the latest data
+	if err := j.DB.Preload("Answer").
+		Preload("History", func(db *gorm.DB) *gorm.DB {
+			return db.Order("changed_at ASC, id ASC")
+		}).
+		Where("id = ?", application.ID).
+		First(&application).Error; err != nil {
+		utilities.RespondDBError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, application)
+}
+
+// jobPostSummary is brief information of job post shown along with applicant's own application
+type jobPostSummary struct {
+	ID          uint       `json:"id"`
+	Title       string     `json:"title"`
+	Location    string     `json:"location"`
+	Type        string     `json:"type"`
+	ExpLvl      string     `json:"exp_lvl"`
+	Expiring    *time.Time `json:"expiring,omitempty"`
+	CompanyID   uuid.UUID  `json:"company_id"`
+	CompanyName string     `json:"company_name"`
+}
+
+// myApplicationResponse is an application as seen by the applicant
+type myApplicationResponse struct {
+	ID        uint                       `json:"id"`
+	AppliedAt time.Time                  `json:"applied_at"`
+	Status    string                     `json:"status"`
+	ResumeURL string                     `json:"resume_url"`
+	Answer    *model.ApplicationAnswer   `json:"answer"`
+	Answers   []model.FormAnswer         `json:"answers,omitempty"`
+	JobPost   jobPostSummary             `json:"job_post"`
+	History   []model.ApplicationHistory `json:"history"`
+}
+
+// myApplicationListResponse is paginated list of applicant's own applications
+type myApplicationListResponse struct {
+	Applications []myApplicationResponse `json:"applications"`
+	utilities.Pagination
+}
+
+type withdrawRequest struct {
+	Note string `json:"note"`
+}
+
+func toMyApplicationResponse(application model.Application) myApplicationResponse {
+	resp := myApplicationResponse{
+		ID:        application.ID,
+		AppliedAt: application.AppliedAt,
+		Status:    application.Status,
+		Answer:    application.Answer,
+		Answers:   application.Answers,
+		JobPost: jobPostSummary{
+			ID:          application.JobPost.ID,
+			Title:       application.JobPost.Title,
+			Location:    application.JobPost.Location,
+			Type:        application.JobPost.Type,
+			ExpLvl:      application.JobPost.ExpLvl,
+			Expiring:    application.JobPost.Expiring,
+			CompanyID:   application.JobPost.CompanyUserID,
+			CompanyName: application.JobPost.CompanyUser.Name,
+		},
+		History: application.History,
+	}
+	if application.ResumeID != nil {
+		resp.ResumeURL = fmt.Sprintf("%s%d", fileRoutePrefix, *application.ResumeID)
+	}
+	if resp.History == nil {
+		resp.History = []model.ApplicationHistory{}
+	}
+	return resp
+}
+
+// preloadMyApplication preloads associations needed to build myApplicationResponse
+func preloadMyApplication(db *gorm.DB) *gorm.DB {
+	return db.Preload("Answer").
+		Preload("Answers").
+		Preload("JobPost").
+		Preload("JobPost.CompanyUser").
+		Preload("History", func(db *gorm.DB) *gorm.DB {
+			return db.Order("changed_at ASC, id ASC")
+		})
+}
+
+// GetMyApplications lists applications made by the logged in CPSK user.
+// @Summary Get my applications
+// @Description Only CPSK user can access this endpoint
+// @Description status can be multiple value separated by comma, e.g. "pending,interview"
+// @Tags Application
+// @Produce json
+// @Param Authorization header string true "Insert your access token" default(Bearer <your access token>)
+// @Param status query string false "Filter by application status, comma separated"
+// @Param page query integer false "Page number, start from 1" default(1)
+// @Param limit query integer false "Number of applications per page (max 100)" default(20)
+// @Success 200 {object} myApplicationListResponse "Return applications of the user, newest first"
+// @Failure 400 {object} utilities.ErrorResponse "Invalid authorization header, or invalid query"
+// @Failure 401 {object} utilities.ErrorResponse "Invalid token"
+// @Failure 403 {object} utilities.ErrorResponse "Not logged in as CPSK, User is banned"
+// @Failure 500 {object} utilities.ErrorResponse "Database error"
+// @Router /cpsk/applications [get]
+func (j *ApplicationController) GetMyApplications(c *gin.Context) {
+	user, err := utilities.ExtractUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, utilities.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	pagination, err := utilities.ParsePagination(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utilities.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	query := j.DB.Model(&model.Application{}).Where("cpsk_id = ?", user.ID)
+
+	if rawStatus := c.Query("status"); rawStatus != "" {
+		status := strings.Split(rawStatus, ",")
+		for i := range status {
+			status[i] = strings.ToLower(strings.TrimSpace(status[i]))
+		}
+		query = query.Where("status IN ?", status)
+	}
+
+	// Make query reusable for both counting and fetching
+	query = query.Session(&gorm.Session{})
+
+	if err := query.Count(&pagination.Total).Error; err != nil {
+		utilities.RespondDBError(c, err)
+		return
+	}
+
+	var applications []model.Application
+	if err := preloadMyApplication(query).
+		Order("applied_at DESC, id DESC").
+		Offset(pagination.Offset()).
+		Limit(pagination.Limit).
+		Find(&applications).Error; err != nil {
+		utilities.RespondDBError(c, err)
+		return
+	}
+
+	resp := myApplicationListResponse{
+		Applications: []myApplicationResponse{},
+		Pagination:   pagination,
+	}
+	for _, application := range applications {
+		resp.Applications = append(resp.Applications, toMyApplicationResponse(application))
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetMyApplicationByID retrieves one of the logged in CPSK user's applications.
+// @Summary Get my application by ID
+// @Description Only CPSK user who made the application can access this endpoint
+// @Tags Application
+// @Produce json
+// @Param Authorization header string true "Insert your access token" default(Bearer <your access token>)
+// @Param id path integer true "ID of desired application"
+// @Success 200 {object} myApplicationResponse "Return the application with its status history"
+// @Failure 400 {object} utilities.ErrorResponse "Invalid authorization header"
+// @Failure 401 {object} utilities.ErrorResponse "Invalid token"
+// @Failure 403 {object} utilities.ErrorResponse "Not logged in as CPSK, User is banned"
+// @Failure 404 {object} utilities.ErrorResponse "Application not found"
+// @Failure 500 {object} utilities.ErrorResponse "Database error"
+// @Router /cpsk/applications/{id} [get]
+func (j *ApplicationController) GetMyApplicationByID(c *gin.Context) {
+	user, err := utilities.ExtractUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, utilities.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	var application model.Application
+	if err := preloadMyApplication(j.DB.DB).
+		Where("id = ? AND cpsk_id = ?", c.Param("id"), user.ID).
+		First(&application).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, utilities.ErrorResponse{Error: "Application not found"})
+			return
+		}
+		utilities.RespondDBError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toMyApplicationResponse(application))
+}
+
+// WithdrawApplication marks one of the logged in CPSK user's applications as withdrawn.
+// @Summary Withdraw my application
+// @Description Only CPSK user who made the application can access this endpoint
+// @Description Application that is already hired, rejected, or withdrawn can't be withdrawn
+// @Tags Application
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Insert your access token" default(Bearer <your access token>)
+// @Param id path integer true "ID of desired application"
+// @Param Note body withdrawRequest false "Optional reason of withdrawal"
+// @Success 200 {object} myApplicationResponse "Successfully withdraw application"
+// @Failure 400 {object} utilities.ErrorResponse "Invalid authorization header, request body, or application can't be withdrawn"
+// @Failure 401 {object} utilities.ErrorResponse "Invalid token"
+// @Failure 403 {object} utilities.ErrorResponse "Not logged in as CPSK, User is banned"
+// @Failure 404 {object} utilities.ErrorResponse "Application not found"
+// @Failure 409 {object} utilities.ErrorResponse "Application status was changed by another request"
+// @Failure 500 {object} utilities.ErrorResponse "Database error"
+// @Router /cpsk/applications/{id}/withdraw [post]
+func (j *ApplicationController) WithdrawApplication(c *gin.Context) {
+	user, err := utilities.ExtractUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, utilities.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	var req withdrawRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, utilities.ErrorResponse{
+			Error: fmt.Sprintf("Invalid request body: %s", err.Error()),
+		})
+		return
+	}
+
+	var application model.Application
+	if err := j.DB.Where("id = ? AND cpsk_id = ?", c.Param("id"), user.ID).First(&application).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, utilities.ErrorResponse{Error: "Application not found"})
+			return
+		}
+		utilities.RespondDBError(c, err)
+		return
+	}
+
+	if !application.CanTransitionTo(model.ApplicationStatusWithdrawn) {
+		c.JSON(http.StatusBadRequest, utilities.ErrorResponse{
+			Error: fmt.Sprintf("Application with status '%s' can't be withdrawn", application.Status),
+		})
+		return
+	}
+
+	if err := j.DB.Transaction(func(tx *gorm.DB) error {
+		return changeStatus(tx, &application, model.ApplicationStatusWithdrawn, user.ID, req.Note)
+	}); err != nil {
+		if errors.Is(err, errStatusChanged) {
+			c.JSON(http.StatusConflict, utilities.ErrorResponse{Error: err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, utilities.ErrorResponse{
+			Error: fmt.Sprintf("Failed to withdraw application: %s", err.Error()),
+		})
+		return
+	}
+
+	if err := preloadMyApplication(j.DB.DB).Where("id = ?", application.ID).First(&application).Error; err != nil {
+		utilities.RespondDBError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toMyApplicationResponse(application))
+}
@@ -0,0 +1,153 @@
+package application
+
+import (
+	"HireMeMaybe-backend/internal/model"
+	"HireMeMaybe-backend/internal/policy"
+	"HireMeMaybe-backend/internal/utilities"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// csvFormulaPrefixes are first characters making spreadsheet treat cell as formula
+const csvFormulaPrefixes = "=+-@\t\r"
+
+// csvCell escapes value so spreadsheet opening the export shows it as text instead of running it as formula
+func csvCell(value string) string {
+	if value != "" && strings.ContainsRune(csvFormulaPrefixes, rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+// ExportPostAnswers exports answers of every application of a job post as CSV.
+// @Summary Export application answers of a job post
+// @Description Only member of company organization that own the post have access to this endpoint
+// @Description Each row is an application, with applicant information followed by answer to each question of the form.
+// @Description Default form answers are included if the post uses default form, file answer is given as download link.
+// @Tags Application
+// @Produce text/csv
+// @Param Authorization header string true "Insert your access token" default(Bearer <your access token>)
+// @Param id path integer true "ID of desired job post"
+// @Success 200 {string} string "CSV of application answers"
+// @Failure 400 {object} utilities.ErrorResponse "Invalid authorization header"
+// @Failure 401 {object} utilities.ErrorResponse "Invalid token"
+// @Failure 403 {object} utilities.ErrorResponse "Not the owner of the post, User is banned"
+// @Failure 404 {object} utilities.ErrorResponse "Job post not found"
+// @Failure 500 {object} utilities.ErrorResponse "Database error"
+// @Router /jobpost/{id}/applications/export [get]
+func (j *ApplicationController) ExportPostAnswers(c *gin.Context) {
+	if _, err := utilities.ExtractUser(c); err != nil {
+		c.JSON(http.StatusUnauthorized, utilities.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	var job model.JobPost
+	if err := j.DB.Select("id", "company_user_id", "default_form").
+		Preload("Questions", func(db *gorm.DB) *gorm.DB {
+			return db.Order("position ASC")
+		}).
+		Where("id = ?", c.Param("id")).
+		First(&job).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, utilities.ErrorResponse{Error: "Job post not found"})
+			return
+		}
+		utilities.RespondDBError(c, err)
+		return
+	}
+
+	allowed, err := policy.CanOnCompany(c, j.DB.DB, policy.ApplicationViewOwn, policy.ApplicationViewAny, job.CompanyUserID)
+	if err != nil {
+		utilities.RespondDBError(c, err)
+		return
+	}
+	if !allowed {
+		c.JSON(http.StatusForbidden, utilities.ErrorResponse{
+			Error: "You are not allowed to view applications of this job post",
+		})
+		return
+	}
+
+	var applications []model.Application
+	if err := j.DB.Preload("CPSKUser").
+		Preload("CPSKUser.User").
+		Preload("Answer").
+		Preload("Answers").
+		Where("post_id = ?", job.ID).
+		Order("applied_at ASC, id ASC").
+		Find(&applications).Error; err != nil {
+		utilities.RespondDBError(c, err)
+		return
+	}
+
+	header := []string{"application_id", "applied_at", "status", "first_name", "last_name", "email", "program", "resume_url"}
+	if job.DefaultForm {
+		header = append(header, "right_to_work", "expected_salary", "year_of_experience", "programming_languages")
+	}
+	for _, q := range job.Questions {
+		header = append(header, q.Label)
+	}
+
+	rows := [][]string{header}
+	for _, application := range applications {
+		row := []string{
+			fmt.Sprint(application.ID),
+			application.AppliedAt.Format(time.RFC3339),
+			application.Status,
+			application.CPSKUser.FirstName,
+			application.CPSKUser.LastName,
+			"",
+			"",
+			"",
+		}
+		if application.CPSKUser.User.Email != nil {
+			row[5] = *application.CPSKUser.User.Email
+		}
+		if application.CPSKUser.Program != nil {
+			row[6] = *application.CPSKUser.Program
+		}
+		if application.ResumeID != nil {
+			row[7] = fmt.Sprintf("%s%d", fileRoutePrefix, *application.ResumeID)
+		}
+
+		if job.DefaultForm {
+			if answer := application.Answer; answer != nil {
+				row = append(row, answer.RightToWork, answer.ExpectedSalary,
+					fmt.Sprint(answer.YearOfExperience), strings.Join(answer.ProgrammingLanguages, "; "))
+			} else {
+				row = append(row, "", "", "", "")
+			}
+		}
+
+		answers := map[uint]model.FormAnswer{}
+		for _, answer := range application.Answers {
+			answers[answer.QuestionID] = answer
+		}
+		for _, q := range job.Questions {
+			answer := answers[q.ID]
+			row = append(row, answer.AnswerText(fileRoutePrefix))
+		}
+		rows = append(rows, row)
+	}
+
+	// Names, labels and answers are written by applicants and companies
+	for _, row := range rows {
+		for i := range row {
+			row[i] = csvCell(row[i])
+		}
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=jobpost-%d-applications.csv", job.ID))
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Status(http.StatusOK)
+	if err := csv.NewWriter(c.Writer).WriteAll(rows); err != nil {
+		c.Abort()
+	}
+}
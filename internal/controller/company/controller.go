@@ -4,6 +4,7 @@ package company
 import (
 	"HireMeMaybe-backend/internal/database"
 	"HireMeMaybe-backend/internal/model"
+	"HireMeMaybe-backend/internal/policy"
 	"HireMeMaybe-backend/internal/utilities"
 	"encoding/json"
 	"errors"
@@ -44,24 +45,34 @@ type editCompanyUser struct {
 // @Failure 500 {object} utilities.ErrorResponse "Database error"
 // @Router /company/myprofile [get]
 func (jc *CompanyController) GetMyCompanyProfile(c *gin.Context) {
-	user, err := utilities.ExtractUser(c)
-	if err != nil {
+	if _, err := utilities.ExtractUser(c); err != nil {
 		c.JSON(http.StatusUnauthorized, utilities.ErrorResponse{Error: err.Error()})
 		return
 	}
 
+	// Profile belong to organization of the user
+	org, err := policy.MembershipFromContext(c, jc.DB.DB)
+	if err != nil {
+		utilities.RespondDBError(c, err)
+		return
+	}
+	if !org.IsMember() {
+		c.JSON(http.StatusForbidden, utilities.ErrorResponse{Error: "User is not a member of any company organization"})
+		return
+	}
+
 	company := model.CompanyUser{}
 
 	// Retrieve company profile from database.
 	if err := jc.DB.Preload("User").
 		Preload("Logo").
 		Preload("Banner").
-		Preload("JobPost").
+		Preload("JobPost", "status <> ?", model.JobPostStatusArchived).
 		Preload("JobPost.Applications").
 		Preload("JobPost.Applications.Answer").
 		Preload("JobPost.Applications.CPSKUser").
 		Preload("JobPost.Applications.CPSKUser.User").
-		Where("user_id = ?", user.ID.String()).
+		Where("user_id = ?", org.CompanyID).
 		First(&company).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, utilities.ErrorResponse{
 			Error: fmt.Sprintf("Failed to retrieve user information from database: %s", err.Error()),
@@ -88,18 +99,28 @@ func (jc *CompanyController) GetMyCompanyProfile(c *gin.Context) {
 // @Failure 500 {object} utilities.ErrorResponse "Database error"
 // @Router /company/profile [patch]
 func (jc *CompanyController) EditCompanyProfile(c *gin.Context) {
-	user, err := utilities.ExtractUser(c)
-	if err != nil {
+	if _, err := utilities.ExtractUser(c); err != nil {
 		c.JSON(http.StatusUnauthorized, utilities.ErrorResponse{Error: err.Error()})
 		return
 	}
 
+	// Profile belong to organization of the user
+	org, err := policy.MembershipFromContext(c, jc.DB.DB)
+	if err != nil {
+		utilities.RespondDBError(c, err)
+		return
+	}
+	if !org.IsMember() {
+		c.JSON(http.StatusForbidden, utilities.ErrorResponse{Error: "User is not a member of any company organization"})
+		return
+	}
+
 	company := model.CompanyUser{}
 
 	// Retrieve company profile from database
 	if err := jc.DB.
 		Preload("User").
-		Where("user_id = ?", user.ID.String()).
+		Where("user_id = ?", org.CompanyID).
 		First(&company).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, utilities.ErrorResponse{
 			Error: fmt.Sprintf("Fail to retrieve user information from database: %s", err.Error()),
@@ -155,7 +176,8 @@ func (jc *CompanyController) GetCompanyByID(c *gin.Context) {
 	if err := jc.DB.Preload("User").
 		Preload("Logo").
 		Preload("Banner").
-		Preload("JobPost").
+		// Only posts visible to everyone are shown on company profile
+		Preload("JobPost", "status IN ?", []string{model.JobPostStatusPublished, model.JobPostStatusClosed}).
 		Where("user_id = ?", companyID).
 		First(&company).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -4,6 +4,7 @@ package file
 import (
 	"HireMeMaybe-backend/internal/database"
 	"HireMeMaybe-backend/internal/model"
+	"HireMeMaybe-backend/internal/policy"
 	"HireMeMaybe-backend/internal/utilities"
 	"bytes"
 	"errors"
@@ -29,8 +30,15 @@ const (
 	resumeObjectPrefix = "resumes"
 	logoObjectPrefix   = "logos"
 	bannerObjectPrefix = "banners"
+	answerObjectPrefix = "answers"
 )
 
+// answerFileResponse is file uploaded to answer application form
+type answerFileResponse struct {
+	FileID int    `json:"file_id"`
+	URL    string `json:"url"`
+}
+
 // NewFileController creates a new instance of FileController
 func NewFileController(db *database.DBinstanceStruct, storage StorageClient) *FileController {
 	return &FileController{
@@ -136,14 +144,24 @@ func (jc *FileController) UploadResume(c *gin.Context) {
 func (jc *FileController) companyUpload(c *gin.Context, fName string) (model.CompanyUser, []byte, string) {
 	var company = model.CompanyUser{}
 
-	user, err := utilities.ExtractUser(c)
-	if err != nil {
+	if _, err := utilities.ExtractUser(c); err != nil {
 		c.JSON(http.StatusUnauthorized, utilities.ErrorResponse{Error: err.Error()})
 		return company, nil, ""
 	}
 
+	// Logo and banner belong to organization of the user
+	org, err := policy.MembershipFromContext(c, jc.DB.DB)
+	if err != nil {
+		utilities.RespondDBError(c, err)
+		return company, nil, ""
+	}
+	if !org.IsMember() {
+		c.JSON(http.StatusForbidden, utilities.ErrorResponse{Error: "User is not a member of any company organization"})
+		return company, nil, ""
+	}
+
 	// Retrieve original profile from DB
-	if err := jc.DB.Preload("User").Where("user_id = ?", user.ID.String()).First(&company).Error; err != nil {
+	if err := jc.DB.Preload("User").Where("user_id = ?", org.CompanyID).First(&company).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, utilities.ErrorResponse{
 			Error: fmt.Sprintf("Failed to retrieve user information from database: %s", err.Error()),
 		})
@@ -280,6 +298,97 @@ func (jc *FileController) UploadBanner(c *gin.Context) {
 	c.JSON(http.StatusOK, company)
 }
 
+// UploadAnswerFile handles uploading file to answer file question of job post application form.
+// @Summary Upload file to answer application form
+// @Description Only file that smaller than 10 MB with .pdf, .doc, .docx, .jpg, .jpeg, or .png extension is permitted
+// @Description Returned file ID is given as answer to file question when applying, only the uploader can use it.
+// @Description The file can be downloaded only by the uploader and the company the application is submitted to
+// @Tags Application
+// @Accept mpfd
+// @Produce json
+// @Param Authorization header string true "Insert your access token" default(Bearer <your access token>)
+// @Param file formData file true "Upload your answer file"
+// @Success 201 {object} answerFileResponse "Successfully upload file"
+// @Failure 400 {object} utilities.ErrorResponse "Invalid authorization header"
+// @Failure 401 {object} utilities.ErrorResponse "Invalid token"
+// @Failure 403 {object} utilities.ErrorResponse "Not logged in as CPSK, User is banned or suspended"
+// @Failure 413 {object} utilities.ErrorResponse "File size is larger than 10 MB"
+// @Failure 415 {object} utilities.ErrorResponse "File extension is not allowed"
+// @Failure 500 {object} utilities.ErrorResponse "Database error"
+// @Router /application/files [post]
+func (jc *FileController) UploadAnswerFile(c *gin.Context) {
+	user, err := utilities.ExtractUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, utilities.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	rawFile, err := c.FormFile("file")
+	var maxBytesError *http.MaxBytesError
+	if errors.As(err, &maxBytesError) {
+		c.JSON(http.StatusRequestEntityTooLarge, utilities.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utilities.ErrorResponse{
+			Error: fmt.Sprintf("Failed to retrieve file: %s", err.Error()),
+		})
+		return
+	}
+
+	allowedExtensions := map[string]bool{
+		".pdf":  true,
+		".doc":  true,
+		".docx": true,
+		".jpg":  true,
+		".jpeg": true,
+		".png":  true,
+	}
+	extension := strings.ToLower(filepath.Ext(rawFile.Filename))
+	if !allowedExtensions[extension] {
+		c.JSON(http.StatusUnsupportedMediaType, utilities.ErrorResponse{
+			Error: fmt.Sprintf("Unsupported file extension: %s", extension),
+		})
+		return
+	}
+
+	f, err := rawFile.Open()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utilities.ErrorResponse{Error: "Cannot open file"})
+		return
+	}
+	defer func() {
+		if err := f.Close(); err != nil {
+			log.Printf("failed to close uploaded file: %v", err)
+		}
+	}()
+
+	fileBytes, err := io.ReadAll(f)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utilities.ErrorResponse{Error: "Cannot read file"})
+		return
+	}
+
+	file := model.File{UploaderID: &user.ID}
+	if err := jc.persistFileData(&file, fileBytes, extension, answerObjectPrefix); err != nil {
+		c.JSON(http.StatusInternalServerError, utilities.ErrorResponse{
+			Error: fmt.Sprintf("Failed to store file: %s", err.Error()),
+		})
+		return
+	}
+	if err := jc.DB.Create(&file).Error; err != nil {
+		utilities.RespondDBError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, answerFileResponse{
+		FileID: file.ID,
+		URL:    fmt.Sprintf("/api/v1/file/%d", file.ID),
+	})
+}
+
 // GetFile function retrieves a file from the database and sends it as a downloadable attachment in
 // the response.
 // @Summary Retrieve dowloadable attachment
@@ -290,7 +399,7 @@ func (jc *FileController) UploadBanner(c *gin.Context) {
 // @Success 200 {string} binary "Successfully retrieve file"
 // @Failure 400 {object} utilities.ErrorResponse "Invalid authorization header"
 // @Failure 401 {object} utilities.ErrorResponse "Invalid token"
-// @Failure 403 {object} utilities.ErrorResponse "User is banned"
+// @Failure 403 {object} utilities.ErrorResponse "User is banned, not allowed to access answer file"
 // @Failure 404 {object} utilities.ErrorResponse "Given file id not found"
 // @Failure 500 {object} utilities.ErrorResponse "Fail to send file content"
 // @Router /file/{id} [get]
@@ -303,9 +412,51 @@ func (jc *FileController) GetFile(c *gin.Context) {
 		return
 	}
 
+	if file.UploaderID != nil {
+		allowed, err := jc.canReadAnswerFile(c, &file)
+		if err != nil {
+			utilities.RespondDBError(c, err)
+			return
+		}
+		if !allowed {
+			c.JSON(http.StatusForbidden, utilities.ErrorResponse{
+				Error: "You are not allowed to access this file",
+			})
+			return
+		}
+	}
+
 	jc.writeFileResponse(c, &file)
 }
 
+// canReadAnswerFile reports whether current user can read file uploaded as answer to application form,
+// which is the uploader and whoever can view applications of the job post the file was submitted to.
+func (jc *FileController) canReadAnswerFile(c *gin.Context, file *model.File) (bool, error) {
+	user, err := utilities.ExtractUser(c)
+	if err != nil {
+		return false, nil
+	}
+	if *file.UploaderID == user.ID {
+		return true, nil
+	}
+
+	var companyIDs []uuid.UUID
+	if err := jc.DB.Model(&model.FormAnswer{}).
+		Joins("JOIN applications ON applications.id = form_answers.application_id").
+		Joins("JOIN job_posts ON job_posts.id = applications.post_id").
+		Where("form_answers.file_id = ?", file.ID).
+		Distinct().Pluck("job_posts.company_user_id", &companyIDs).Error; err != nil {
+		return false, err
+	}
+	for _, companyID := range companyIDs {
+		allowed, err := policy.CanOnCompany(c, jc.DB.DB, policy.ApplicationViewOwn, policy.ApplicationViewAny, companyID)
+		if err != nil || allowed {
+			return allowed, err
+		}
+	}
+	return false, nil
+}
+
 func (jc *FileController) writeFileResponse(c *gin.Context, file *model.File) {
 	c.Writer.Header().Set("Content-Disposition", "attachment; filename="+fmt.Sprint(file.ID)+file.Extension)
 	c.Writer.Header().Set("Content-Type", "application/octet-stream")
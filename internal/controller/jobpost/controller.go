@@ -4,6 +4,7 @@ package jobpost
 import (
 	"HireMeMaybe-backend/internal/database"
 	"HireMeMaybe-backend/internal/model"
+	"HireMeMaybe-backend/internal/policy"
 	"HireMeMaybe-backend/internal/utilities"
 	"encoding/json"
 	"errors"
@@ -13,8 +14,8 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"gorm.io/gorm"
-	"gorm.io/gorm/clause"
 )
 
 // JobPostController handles job post related endpoints
@@ -25,6 +26,14 @@ type JobPostController struct {
 type jobPostCreateRequest struct {
 	model.EditableJobPostInfo
 	DefaultForm bool `json:"default_form"`
+	// Status is either draft or published, post is published if not given
+	Status string `json:"status"`
+	// Questions is custom application form of the post
+	Questions []model.FormQuestion `json:"questions"`
+}
+
+type jobPostStatusRequest struct {
+	Status string `json:"status" binding:"required"`
 }
 
 // NewJobPostController creates a new instance of JobPostController
@@ -36,7 +45,9 @@ func NewJobPostController(db *database.DBinstanceStruct) *JobPostController {
 
 // CreateJobPostHandler handles the creation of a new job post by a company user.
 // @Summary Create job post based on given json structure
-// @Description Only verified company have access to this endpoint
+// @Description Only owner or recruiter of verified company organization have access to this endpoint
+// @Description Post is created as draft if status is draft, otherwise it is published
+// @Description Questions define custom application form, see PUT /jobpost/{id}/form for question types
 // @Tags Jobpost
 // @Accept json
 // @Produce json
@@ -51,15 +62,23 @@ func NewJobPostController(db *database.DBinstanceStruct) *JobPostController {
 func (jc *JobPostController) CreateJobPostHandler(c *gin.Context) {
 
 	// Get user
-	user, err := utilities.ExtractUser(c)
-	if err != nil {
+	if _, err := utilities.ExtractUser(c); err != nil {
 		c.JSON(http.StatusUnauthorized, utilities.ErrorResponse{Error: err.Error()})
 		return
 	}
 
-	// Ensure that user is a verified company
+	// Ensure that user acts for a verified company
+	org, err := policy.MembershipFromContext(c, jc.DB.DB)
+	if err != nil {
+		utilities.RespondDBError(c, err)
+		return
+	}
+	if !org.IsMember() {
+		c.JSON(http.StatusForbidden, utilities.ErrorResponse{Error: "Only company users can create job posts"})
+		return
+	}
 	var companyUser model.CompanyUser
-	if err := jc.DB.Where("user_id = ?", user.ID.String()).First(&companyUser).Error; err != nil {
+	if err := jc.DB.Where("user_id = ?", org.CompanyID).First(&companyUser).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			c.JSON(http.StatusForbidden, utilities.ErrorResponse{Error: "Only company users can create job posts"})
 			return
@@ -87,10 +106,28 @@ func (jc *JobPostController) CreateJobPostHandler(c *gin.Context) {
 		return
 	}
 
+	status := strings.ToLower(strings.TrimSpace(rawInput.Status))
+	if status == "" {
+		status = model.JobPostStatusPublished
+	}
+	if status != model.JobPostStatusDraft && status != model.JobPostStatusPublished {
+		c.JSON(http.StatusBadRequest, utilities.ErrorResponse{
+			Error: "Job post can only be created as draft or published",
+		})
+		return
+	}
+
+	if err := prepareQuestions(rawInput.Questions); err != nil {
+		c.JSON(http.StatusBadRequest, utilities.ErrorResponse{Error: err.Error()})
+		return
+	}
+
 	jobPost := model.JobPost{
 		EditableJobPostInfo: rawInput.EditableJobPostInfo,
 		DefaultForm:         rawInput.DefaultForm,
-		CompanyUserID:       user.ID,
+		CompanyUserID:       companyUser.UserID,
+		Status:              status,
+		Questions:           rawInput.Questions,
 	}
 
 	// save job post
@@ -105,14 +142,16 @@ func (jc *JobPostController) CreateJobPostHandler(c *gin.Context) {
 	c.JSON(http.StatusCreated, jobPost)
 }
 
-// GetPosts fetches all non-expired job posts that match query from the database
+// GetPosts fetches all job posts that match query from the database
 // and returns them as a JSON response.
-// @Summary Get non-expired job posts based on query
+// @Summary Get published job posts based on query
 // @Description Every query are not required, but they have specific use defined in their description
+// @Description Only published post that has not expired is listed unless status is given.
+// @Description Draft and hidden posts are only listed for company organization that own them or admin, archived posts only for admin.
 // @Tags Jobpost
 // @Produce json
 // @Param Authorization header string true "Insert your access token" default(Bearer <your access token>)
-// @Param search query string false "Search from job post title with substring matching and case insensitive"
+// @Param search query string false "Full-text search over title, description, requirement and tags, tolerating typo in title"
 // @Param type query string false "Job type field with substring matching and case insensitive"
 // @Param tag query string false "Search if tags field contain tag param, no substring matching and case insensitive"
 // @Param salary query string false "Salary field, must exactly match to get result"
@@ -120,11 +159,13 @@ func (jc *JobPostController) CreateJobPostHandler(c *gin.Context) {
 // @Param company query string false "Search from company name with substring matching and case insensitive"
 // @Param industry query string false "Search from industry of company with substring matching and case insensitive"
 // @Param location query string false "Search from location with substring matching and case insensitive"
+// @Param sort query string false "Sort by relevance or post_time, relevance is default when search is given"
 // @Param desc query boolean false "Sorting by post time in descending if true, otherwise ascendind"
-// @Success 200 {array} model.JobPostResponse "Return non-expired job post(s)"
-// @Failure 400 {object} utilities.ErrorResponse "Invalid authorization header, or invalid job post struct"
+// @Param status query string false "Status of job post, one of draft, published, closed, hidden or archived" default(published)
+// @Success 200 {array} model.JobPostResponse "Return job post(s) with given status"
+// @Failure 400 {object} utilities.ErrorResponse "Invalid authorization header, invalid job post struct or status"
 // @Failure 401 {object} utilities.ErrorResponse "Invalid token"
-// @Failure 403 {object} utilities.ErrorResponse "User is banned"
+// @Failure 403 {object} utilities.ErrorResponse "User is banned, or not allowed to list posts with given status"
 // @Failure 500 {object} utilities.ErrorResponse "Database error"
 // @Router /jobpost [get]
 func (jc *JobPostController) GetPosts(c *gin.Context) {
@@ -135,67 +176,18 @@ func (jc *JobPostController) GetPosts(c *gin.Context) {
 		return
 	}
 
-	rawSearch := c.Query("search")
-	rawJobType := c.Query("type")
-	rawTag := c.Query("tag")
-	rawSalary := c.Query("salary")
-	rawExp := c.Query("exp")
-	rawCompany := c.Query("company")
-	rawIndustry := c.Query("industry")
-	rawLocation := c.Query("location")
-	rawDesc := c.Query("desc")
+	query, ok := jc.filterPosts(c)
+	if !ok {
+		return
+	}
 
 	var rawPosts []model.JobPost
-
-	result := jc.DB.Preload("CompanyUser").
+	if err := orderPosts(c, query).
+		Preload("CompanyUser").
 		Preload("CompanyUser.User").
 		Preload("CompanyUser.User.Punishment").
 		Preload("Applications").
-		Where("expiring > ? OR expiring IS NULL", time.Now())
-
-	if rawSearch != "" {
-		result = result.Where("title ILIKE ?", "%"+rawSearch+"%")
-	}
-
-	if rawJobType != "" {
-		result = result.Where("type ILIKE ?", "%"+rawJobType+"%")
-	}
-
-	if rawTag != "" {
-		result = result.Where("? ILIKE ANY(tags)", rawTag)
-	}
-
-	if rawSalary != "" {
-		result = result.Where("salary = ?", rawSalary)
-	}
-
-	if rawExp != "" {
-		result = result.Where("exp_lvl = ?", rawExp)
-	}
-
-	// Join company_users table only once if needed for company or industry filters
-	if rawCompany != "" || rawIndustry != "" {
-		result = result.Joins("JOIN company_users ON company_users.user_id = job_posts.company_user_id")
-	}
-
-	if rawCompany != "" {
-		result = result.Where("company_users.name ILIKE ?", "%"+rawCompany+"%")
-	}
-
-	if rawIndustry != "" {
-		result = result.Where("company_users.industry ILIKE ?", "%"+rawIndustry+"%")
-	}
-
-	if rawLocation != "" {
-		result = result.Where("job_posts.location ILIKE ?", "%"+rawLocation+"%")
-	}
-
-	result = result.Order(clause.OrderByColumn{
-		Column: clause.Column{Name: "post_time"},
-		Desc:   strings.ToLower(rawDesc) == "true",
-	}).Find(&rawPosts)
-
-	if err := result.Error; err != nil {
+		Find(&rawPosts).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, utilities.ErrorResponse{
 			Error: fmt.Sprint("Failed to fetch job post: ", err.Error()),
 		})
@@ -204,13 +196,6 @@ func (jc *JobPostController) GetPosts(c *gin.Context) {
 
 	posts := []model.JobPostResponse{}
 	for _, rawPost := range rawPosts {
-		if rawPost.CompanyUser.User.Punishment != nil {
-			if rawPost.CompanyUser.User.Punishment.PunishmentType == "ban" &&
-				(rawPost.CompanyUser.User.Punishment.PunishEnd == nil ||
-					rawPost.CompanyUser.User.Punishment.PunishEnd.After(time.Now())) {
-				continue
-			}
-		}
 		rawPostResp, err := rawPost.ToJobPostResponse(user)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, utilities.ErrorResponse{
@@ -224,10 +209,35 @@ func (jc *JobPostController) GetPosts(c *gin.Context) {
 	c.JSON(http.StatusOK, posts)
 }
 
+// privateListScope reports whether current user can list job posts with draft, hidden or archived status.
+// Returned company ID limits the list to posts of user's organization, nil if user can list posts of every company.
+func (jc *JobPostController) privateListScope(c *gin.Context, status string) (*uuid.UUID, bool, error) {
+	perms, err := policy.FromContext(c, jc.DB.DB)
+	if err != nil {
+		return nil, false, err
+	}
+	if perms.Has(policy.JobPostEditAny) {
+		return nil, true, nil
+	}
+	if status == model.JobPostStatusArchived || !perms.Has(policy.JobPostEditOwn) {
+		return nil, false, nil
+	}
+
+	m, err := policy.MembershipFromContext(c, jc.DB.DB)
+	if err != nil {
+		return nil, false, err
+	}
+	if !m.IsMember() {
+		return nil, false, nil
+	}
+	return &m.CompanyID, true, nil
+}
+
 // GetPostByID fetches a job post by its ID from the database
 // and returns it as a JSON response.
 // @Summary Get job post by ID
 // @Description Retrieve a specific job post using its unique ID
+// @Description Draft and hidden posts are only visible to company organization that own them or admin, archived posts only to admin
 // @Tags Jobpost
 // @Produce json
 // @Param Authorization header string true "Insert your access token" default(Bearer <your access token>)
@@ -236,7 +246,7 @@ func (jc *JobPostController) GetPosts(c *gin.Context) {
 // @Failure 400 {object} utilities.ErrorResponse "Invalid authorization header"
 // @Failure 401 {object} utilities.ErrorResponse "Invalid token"
 // @Failure 403 {object} utilities.ErrorResponse "User is banned"
-// @Failure 404 {object} utilities.ErrorResponse "Job post not found"
+// @Failure 404 {object} utilities.ErrorResponse "Job post not found or not visible to user"
 // @Failure 500 {object} utilities.ErrorResponse "Database error"
 // @Router /jobpost/{id} [get]
 func (jc *JobPostController) GetPostByID(c *gin.Context) {
@@ -254,6 +264,7 @@ func (jc *JobPostController) GetPostByID(c *gin.Context) {
 		Preload("CompanyUser.User").
 		Preload("CompanyUser.User.Punishment").
 		Preload("Applications").
+		Preload("Questions", preloadQuestions).
 		Where("id = ?", id).
 		First(&job).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -275,6 +286,16 @@ func (jc *JobPostController) GetPostByID(c *gin.Context) {
 		}
 	}
 
+	visible, err := jc.canSee(c, job)
+	if err != nil {
+		utilities.RespondDBError(c, err)
+		return
+	}
+	if !visible {
+		c.JSON(http.StatusNotFound, utilities.ErrorResponse{Error: "Job post not found"})
+		return
+	}
+
 	rawPostResp, err := job.ToJobPostResponse(user)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, utilities.ErrorResponse{
@@ -286,9 +307,26 @@ func (jc *JobPostController) GetPostByID(c *gin.Context) {
 	c.JSON(http.StatusOK, rawPostResp)
 }
 
+// canSee reports whether current user can see the job post.
+// Published and closed posts are visible to everyone, draft and hidden posts to organization owning them,
+// and archived post only to user who can edit any post.
+func (jc *JobPostController) canSee(c *gin.Context, job model.JobPost) (bool, error) {
+	switch job.Status {
+	case model.JobPostStatusPublished, model.JobPostStatusClosed:
+		return true, nil
+	case model.JobPostStatusDraft, model.JobPostStatusHidden:
+		return policy.CanOnCompany(c, jc.DB.DB, policy.JobPostEditOwn, policy.JobPostEditAny, job.CompanyUserID)
+	}
+	perms, err := policy.FromContext(c, jc.DB.DB)
+	if err != nil {
+		return false, err
+	}
+	return perms.Has(policy.JobPostEditAny), nil
+}
+
 // EditJobPost allows a company user to update a job post they own.
 // @Summary Edit job post based on given json structure
-// @Description Only company that own the post or admin have access to this endpoint
+// @Description Only owner or recruiter of company organization that own the post, or admin have access to this endpoint
 // @Tags Jobpost
 // @Accept json
 // @Produce json
@@ -304,9 +342,8 @@ func (jc *JobPostController) GetPostByID(c *gin.Context) {
 // @Router /jobpost/{id} [patch]
 func (jc *JobPostController) EditJobPost(c *gin.Context) {
 
-	// Use ExtractUser utility to get authenticated user
-	user, err := utilities.ExtractUser(c)
-	if err != nil {
+	// Use ExtractUser utility to ensure user is authenticated
+	if _, err := utilities.ExtractUser(c); err != nil {
 		c.JSON(http.StatusUnauthorized, utilities.ErrorResponse{Error: err.Error()})
 		return
 	}
@@ -328,9 +365,19 @@ func (jc *JobPostController) EditJobPost(c *gin.Context) {
 		return
 	}
 
-	// Verify ownership: the job post must belong to the requesting company user
-	// Compare as strings to avoid type mismatches
-	if job.CompanyUserID.String() != user.ID.String() {
+	// Archived post can't be edited
+	if job.Status == model.JobPostStatusArchived {
+		c.JSON(http.StatusNotFound, utilities.ErrorResponse{Error: "Job post not found"})
+		return
+	}
+
+	// Verify ownership: the job post must belong to organization of the user unless user can edit any post
+	allowed, err := policy.CanOnCompany(c, jc.DB.DB, policy.JobPostEditOwn, policy.JobPostEditAny, job.CompanyUserID)
+	if err != nil {
+		utilities.RespondDBError(c, err)
+		return
+	}
+	if !allowed {
 		c.JSON(http.StatusForbidden, utilities.ErrorResponse{
 			Error: "You are not allowed to edit this job post",
 		})
@@ -369,7 +416,8 @@ func (jc *JobPostController) EditJobPost(c *gin.Context) {
 
 // DeleteJobPost allows a company user to delete a job post they own.
 // @Summary Delete given job post ID
-// @Description Only company that own the post or admin have access to this endpoint
+// @Description Only owner or recruiter of company organization that own the post, or admin have access to this endpoint
+// @Description Post is archived instead of removed, so its applications and reports are kept
 // @Tags Jobpost
 // @Produce json
 // @Param Authorization header string true "Insert your access token" default(Bearer <your access token>)
@@ -382,15 +430,14 @@ func (jc *JobPostController) EditJobPost(c *gin.Context) {
 // @Failure 500 {object} utilities.ErrorResponse "Database error"
 // @Router /jobpost/{id} [delete]
 func (jc *JobPostController) DeleteJobPost(c *gin.Context) {
-	user, err := utilities.ExtractUser(c)
-	if err != nil {
+	if _, err := utilities.ExtractUser(c); err != nil {
 		c.JSON(http.StatusUnauthorized, utilities.ErrorResponse{Error: err.Error()})
 		return
 	}
 	id := c.Param("id")
 
 	job := model.JobPost{}
-	if err := jc.DB.Where("id = ?", id).First(&job).Error; err != nil {
+	if err := jc.DB.Where("id = ? AND status <> ?", id, model.JobPostStatusArchived).First(&job).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			c.JSON(http.StatusNotFound, utilities.ErrorResponse{Error: "Job post not found"})
 			return
@@ -401,22 +448,118 @@ func (jc *JobPostController) DeleteJobPost(c *gin.Context) {
 		return
 	}
 
-	if job.CompanyUserID.String() != user.ID.String() {
-		// Allow admins to bypass ownership check
-		if user.Role != model.RoleAdmin {
+	allowed, err := policy.CanOnCompany(c, jc.DB.DB, policy.JobPostDeleteOwn, policy.JobPostDeleteAny, job.CompanyUserID)
+	if err != nil {
+		utilities.RespondDBError(c, err)
+		return
+	}
+	if !allowed {
+		c.JSON(http.StatusForbidden, utilities.ErrorResponse{
+			Error: "You are not allowed to delete this job post",
+		})
+		return
+	}
+
+	// Archive instead of delete so applicants keep their history and reports keep the post
+	if err := jc.DB.Model(&job).Update("status", model.JobPostStatusArchived).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, utilities.ErrorResponse{
+			Error: fmt.Sprintf("Failed to delete job post: %s", err.Error()),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, utilities.MessageResponse{Message: "Job post deleted"})
+}
+
+// UpdateJobPostStatus allows a company user to publish, close or reopen a job post they own.
+// @Summary Update status of job post
+// @Description Only owner or recruiter of company organization that own the post, or admin have access to this endpoint
+// @Description Allowed transitions:
+// @Description draft -> published
+// @Description published -> closed, hidden
+// @Description closed -> published, hidden
+// @Description hidden -> published
+// @Description Only admin can hide post or publish hidden post, expired post can't be published until its expiring is extended.
+// @Description Post is archived by deleting it.
+// @Tags Jobpost
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Insert your access token" default(Bearer <your access token>)
+// @Param id path integer true "ID of desired job post"
+// @Param Status body jobPostStatusRequest true "New status of job post"
+// @Success 200 {object} model.JobPost "Successfully update job post status"
+// @Failure 400 {object} utilities.ErrorResponse "Invalid authorization header, request body, or illegal status transition"
+// @Failure 401 {object} utilities.ErrorResponse "Invalid token"
+// @Failure 403 {object} utilities.ErrorResponse "Do not have permission to update status, User is banned"
+// @Failure 404 {object} utilities.ErrorResponse "Post not found"
+// @Failure 500 {object} utilities.ErrorResponse "Database error"
+// @Router /jobpost/{id}/status [patch]
+func (jc *JobPostController) UpdateJobPostStatus(c *gin.Context) {
+	if _, err := utilities.ExtractUser(c); err != nil {
+		c.JSON(http.StatusUnauthorized, utilities.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	var req jobPostStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utilities.ErrorResponse{
+			Error: fmt.Sprintf("Invalid request body: %s", err.Error()),
+		})
+		return
+	}
+	status := strings.ToLower(strings.TrimSpace(req.Status))
+
+	job := model.JobPost{}
+	if err := jc.DB.Where("id = ? AND status <> ?", c.Param("id"), model.JobPostStatusArchived).First(&job).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, utilities.ErrorResponse{Error: "Job post not found"})
+			return
+		}
+		utilities.RespondDBError(c, err)
+		return
+	}
+
+	allowed, err := policy.CanOnCompany(c, jc.DB.DB, policy.JobPostEditOwn, policy.JobPostEditAny, job.CompanyUserID)
+	if err != nil {
+		utilities.RespondDBError(c, err)
+		return
+	}
+	if !allowed {
+		c.JSON(http.StatusForbidden, utilities.ErrorResponse{
+			Error: "You are not allowed to update status of this job post",
+		})
+		return
+	}
+
+	// Hiding is moderation, company can't hide its own post or bring back post hidden by admin
+	if status == model.JobPostStatusHidden || job.Status == model.JobPostStatusHidden {
+		perms, err := policy.FromContext(c, jc.DB.DB)
+		if err != nil {
+			utilities.RespondDBError(c, err)
+			return
+		}
+		if !perms.Has(policy.JobPostEditAny) {
 			c.JSON(http.StatusForbidden, utilities.ErrorResponse{
-				Error: "You are not allowed to delete this job post",
+				Error: "Only admin can hide job post or publish hidden job post",
 			})
 			return
 		}
 	}
 
-	if err := jc.DB.Delete(&job).Error; err != nil {
+	now := time.Now()
+	if !job.CanTransitionTo(status, now) {
+		c.JSON(http.StatusBadRequest, utilities.ErrorResponse{
+			Error: fmt.Sprintf("Cannot change job post status from '%s' to '%s'", job.CurrentStatus(now), status),
+		})
+		return
+	}
+
+	if err := jc.DB.Model(&job).Update("status", status).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, utilities.ErrorResponse{
-			Error: fmt.Sprintf("Failed to delete job post: %s", err.Error()),
+			Error: fmt.Sprintf("Failed to update job post status: %s", err.Error()),
 		})
 		return
 	}
 
-	c.JSON(http.StatusOK, utilities.MessageResponse{Message: "Job post deleted"})
+	c.JSON(http.StatusOK, job)
 }
@@ -0,0 +1,146 @@
+package jobpost
+
+import (
+	"HireMeMaybe-backend/internal/model"
+	"HireMeMaybe-backend/internal/policy"
+	"HireMeMaybe-backend/internal/utilities"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type formRequest struct {
+	// DefaultForm keeps current value if not given
+	DefaultForm *bool                `json:"default_form"`
+	Questions   []model.FormQuestion `json:"questions"`
+}
+
+// prepareQuestions validates questions of a form and orders them as given.
+// ID and post of the questions are reset so they are created as new questions.
+func prepareQuestions(questions []model.FormQuestion) error {
+	for i := range questions {
+		q := &questions[i]
+		q.ID = 0
+		q.PostID = 0
+		q.Position = i
+		q.Label = strings.TrimSpace(q.Label)
+		q.Type = strings.ToLower(strings.TrimSpace(q.Type))
+		if err := q.Validate(); err != nil {
+			return fmt.Errorf("question %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+// preloadQuestions preloads questions of the post form in their order
+func preloadQuestions(db *gorm.DB) *gorm.DB {
+	return db.Order("position ASC")
+}
+
+// PutJobPostForm replaces custom application form of a job post.
+// @Summary Replace application form of job post
+// @Description Only owner or recruiter of company organization that own the post, or admin have access to this endpoint
+// @Description Question type is one of short_text, long_text, single_choice, multi_choice, number or file.
+// @Description Options are required for single_choice and multi_choice questions and not allowed otherwise.
+// @Description Form can't be changed once the post has received an application.
+// @Tags Jobpost
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Insert your access token" default(Bearer <your access token>)
+// @Param id path integer true "ID of desired job post"
+// @Param Form body formRequest true "Questions of the form in order they are asked"
+// @Success 200 {object} model.JobPost "Successfully replace application form"
+// @Failure 400 {object} utilities.ErrorResponse "Invalid authorization header, or invalid question"
+// @Failure 401 {object} utilities.ErrorResponse "Invalid token"
+// @Failure 403 {object} utilities.ErrorResponse "Do not have permission to edit, User is banned"
+// @Failure 404 {object} utilities.ErrorResponse "Post not found"
+// @Failure 409 {object} utilities.ErrorResponse "Post has already received applications"
+// @Failure 500 {object} utilities.ErrorResponse "Database error"
+// @Router /jobpost/{id}/form [put]
+func (jc *JobPostController) PutJobPostForm(c *gin.Context) {
+	if _, err := utilities.ExtractUser(c); err != nil {
+		c.JSON(http.StatusUnauthorized, utilities.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	var req formRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utilities.ErrorResponse{
+			Error: fmt.Sprintf("Invalid request body: %s", err.Error()),
+		})
+		return
+	}
+	if err := prepareQuestions(req.Questions); err != nil {
+		c.JSON(http.StatusBadRequest, utilities.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	job := model.JobPost{}
+	if err := jc.DB.Where("id = ? AND status <> ?", c.Param("id"), model.JobPostStatusArchived).First(&job).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, utilities.ErrorResponse{Error: "Job post not found"})
+			return
+		}
+		utilities.RespondDBError(c, err)
+		return
+	}
+
+	allowed, err := policy.CanOnCompany(c, jc.DB.DB, policy.JobPostEditOwn, policy.JobPostEditAny, job.CompanyUserID)
+	if err != nil {
+		utilities.RespondDBError(c, err)
+		return
+	}
+	if !allowed {
+		c.JSON(http.StatusForbidden, utilities.ErrorResponse{
+			Error: "You are not allowed to edit this job post",
+		})
+		return
+	}
+
+	// Answers already given would no longer match the form
+	var applications int64
+	if err := jc.DB.Model(&model.Application{}).Where("post_id = ?", job.ID).Count(&applications).Error; err != nil {
+		utilities.RespondDBError(c, err)
+		return
+	}
+	if applications > 0 {
+		c.JSON(http.StatusConflict, utilities.ErrorResponse{
+			Error: "Form can't be changed after the job post has received applications",
+		})
+		return
+	}
+
+	if err := jc.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("post_id = ?", job.ID).Delete(&model.FormQuestion{}).Error; err != nil {
+			return err
+		}
+		for i := range req.Questions {
+			req.Questions[i].PostID = job.ID
+		}
+		if len(req.Questions) > 0 {
+			if err := tx.Create(&req.Questions).Error; err != nil {
+				return err
+			}
+		}
+		if req.DefaultForm != nil {
+			return tx.Model(&job).Update("default_form", *req.DefaultForm).Error
+		}
+		return nil
+	}); err != nil {
+		c.JSON(http.StatusInternalServerError, utilities.ErrorResponse{
+			Error: fmt.Sprintf("Failed to update application form: %s", err.Error()),
+		})
+		return
+	}
+
+	if err := jc.DB.Preload("Questions", preloadQuestions).Where("id = ?", job.ID).First(&job).Error; err != nil {
+		utilities.RespondDBError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, job)
+}
@@ -5,6 +5,7 @@ import (
 	"HireMeMaybe-backend/internal/database"
 	"HireMeMaybe-backend/internal/middleware"
 	"HireMeMaybe-backend/internal/model"
+	"HireMeMaybe-backend/internal/policy"
 	"HireMeMaybe-backend/internal/testutil"
 	"context"
 	"encoding/json"
@@ -14,6 +15,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/lib/pq"
 	"gorm.io/gorm"
 
 	"github.com/gin-gonic/gin"
@@ -185,11 +187,10 @@ func TestDeleteJobPost_Success(t *testing.T) {
 	assert.Equal(t, http.StatusOK, rec.Code)
 	assert.Equal(t, "Job post deleted", resp["message"])
 
-	// Verify the job post was actually deleted
+	// Verify the job post was archived instead of removed
 	var deletedJob model.JobPost
-	err = testDB.Where("id = ?", jobPost.ID).First(&deletedJob).Error
-	assert.Error(t, err)
-	assert.Equal(t, gorm.ErrRecordNotFound, err)
+	assert.NoError(t, testDB.Where("id = ?", jobPost.ID).First(&deletedJob).Error)
+	assert.Equal(t, model.JobPostStatusArchived, deletedJob.Status)
 }
 
 func TestDeleteJobPost_NotFound(t *testing.T) {
@@ -255,11 +256,10 @@ func TestDeleteJobPost_AdminCanDelete(t *testing.T) {
 	assert.Equal(t, http.StatusOK, rec.Code)
 	assert.Equal(t, "Job post deleted", resp["message"])
 
-	// Verify the job post was actually deleted
+	// Verify the job post was archived instead of removed
 	var deletedJob model.JobPost
-	err = testDB.Where("id = ?", jobPost.ID).First(&deletedJob).Error
-	assert.Error(t, err)
-	assert.Equal(t, gorm.ErrRecordNotFound, err)
+	assert.NoError(t, testDB.Where("id = ?", jobPost.ID).First(&deletedJob).Error)
+	assert.Equal(t, model.JobPostStatusArchived, deletedJob.Status)
 }
 
 func TestDeleteJobPost_CPSKUserCannotDelete(t *testing.T) {
@@ -276,3 +276,305 @@ func TestDeleteJobPost_CPSKUserCannotDelete(t *testing.T) {
 	assert.Equal(t, http.StatusForbidden, rec.Code)
 	assert.Equal(t, "You are not allowed to delete this job post", resp["error"])
 }
+
+// createCompany1JobPost creates job post owned by TestUserCompany1
+func createCompany1JobPost(t *testing.T, title string) model.JobPost {
+	t.Helper()
+	jobPost := model.JobPost{
+		EditableJobPostInfo: model.EditableJobPostInfo{
+			Title:    title,
+			Desc:     "None",
+			Req:      "None",
+			ExpLvl:   "Entry",
+			Location: "Test Location",
+			Type:     "Full-time",
+			Salary:   "0",
+		},
+		CompanyUserID: database.TestUserCompany1.ID,
+		DefaultForm:   true,
+	}
+	if err := testDB.Create(&jobPost).Error; err != nil {
+		t.Fatalf("failed to create test job post: %v", err)
+	}
+	return jobPost
+}
+
+func TestEditJobPost_CustomRoleCanEditAny(t *testing.T) {
+	jobPost := createCompany1JobPost(t, "Test Job Moderator Edit")
+
+	cpskToken, err := auth.GetAccessToken(t, testDB, database.TestUserCPSK2.Username, database.TestSeedPassword)
+	assert.NoError(t, err)
+
+	r := gin.Default()
+	jc := &JobPostController{DB: testDB}
+	r.PATCH("/jobpost/:id", middleware.RequireAuth(testDB),
+		middleware.RequirePermission(testDB, policy.JobPostEditOwn, policy.JobPostEditAny), jc.EditJobPost)
+	endpoint := fmt.Sprintf("/jobpost/%d", jobPost.ID)
+
+	rec, _ := testutil.MakeJSONRequest(gin.H{"title": "Edited by moderator"}, cpskToken, r, endpoint, http.MethodPatch)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	role := model.CustomRole{Name: "jobpost_moderator", Permissions: []string{string(policy.JobPostEditAny)}}
+	assert.NoError(t, testDB.Create(&role).Error)
+	assert.NoError(t, testDB.Create(&model.UserCustomRole{UserID: database.TestUserCPSK2.ID, RoleName: role.Name}).Error)
+	t.Cleanup(func() { testDB.Delete(&role) })
+
+	rec, resp := testutil.MakeJSONRequest(gin.H{"title": "Edited by moderator"}, cpskToken, r, endpoint, http.MethodPatch)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, "Edited by moderator", resp["title"])
+}
+
+func TestEditJobPost_AdminCanEdit(t *testing.T) {
+	adminToken, err := auth.GetAccessToken(t, testDB, database.TestAdminUser.Username, database.TestSeedPassword)
+	assert.NoError(t, err)
+
+	r := gin.Default()
+	jc := &JobPostController{DB: testDB}
+	r.PATCH("/jobpost/:id", middleware.RequireAuth(testDB), jc.EditJobPost)
+
+	jobPost := createCompany1JobPost(t, "Test Job Admin Edit")
+	rec, _ := testutil.MakeJSONRequest(gin.H{"location": "Edited by admin"}, adminToken, r,
+		fmt.Sprintf("/jobpost/%d", jobPost.ID), http.MethodPatch)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+}
+
+func TestDeleteJobPost_KeepsApplications(t *testing.T) {
+	companyToken, err := auth.GetAccessToken(t, testDB, database.TestUserCompany1.Username, database.TestSeedPassword)
+	assert.NoError(t, err)
+
+	jobPost := createCompany1JobPost(t, "Test Job Archive Applications")
+	application := model.Application{
+		CPSKID: database.TestUserCPSK1.ID,
+		PostID: jobPost.ID,
+		Status: model.ApplicationStatusPending,
+	}
+	assert.NoError(t, testDB.Create(&application).Error)
+
+	r := gin.Default()
+	jc := &JobPostController{DB: testDB}
+	r.DELETE("/jobpost/:id", middleware.RequireAuth(testDB), jc.DeleteJobPost)
+	r.GET("/jobpost/:id", middleware.RequireAuth(testDB), jc.GetPostByID)
+	endpoint := fmt.Sprintf("/jobpost/%d", jobPost.ID)
+
+	rec, _ := testutil.MakeJSONRequest(nil, companyToken, r, endpoint, http.MethodDelete)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	var count int64
+	testDB.Model(&model.Application{}).Where("post_id = ?", jobPost.ID).Count(&count)
+	assert.EqualValues(t, 1, count, "Applications are kept when post is archived")
+
+	rec, _ = testutil.MakeJSONRequest(nil, companyToken, r, endpoint, http.MethodGet)
+	assert.Equal(t, http.StatusNotFound, rec.Code, "Archived post is not visible to its company")
+
+	rec, _ = testutil.MakeJSONRequest(nil, companyToken, r, endpoint, http.MethodDelete)
+	assert.Equal(t, http.StatusNotFound, rec.Code, "Archived post can't be deleted again")
+}
+
+func TestCreateJobPost_DraftOnlyVisibleToCompany(t *testing.T) {
+	companyToken, err := auth.GetAccessToken(t, testDB, database.TestUserCompany1.Username, database.TestSeedPassword)
+	assert.NoError(t, err)
+	cpskToken, err := auth.GetAccessToken(t, testDB, database.TestUserCPSK1.Username, database.TestSeedPassword)
+	assert.NoError(t, err)
+
+	r := gin.Default()
+	jc := &JobPostController{DB: testDB}
+	r.POST("/jobpost", middleware.RequireAuth(testDB), jc.CreateJobPostHandler)
+	r.GET("/jobpost", middleware.RequireAuth(testDB), jc.GetPosts)
+	r.GET("/jobpost/:id", middleware.RequireAuth(testDB), jc.GetPostByID)
+
+	rec, resp := testutil.MakeJSONRequest(gin.H{"title": "Draft Internship", "status": "draft"}, companyToken, r, "/jobpost", http.MethodPost)
+	assert.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	assert.Equal(t, model.JobPostStatusDraft, resp["status"])
+	endpoint := fmt.Sprintf("/jobpost/%v", resp["id"])
+
+	rec, _ = testutil.MakeJSONRequest(nil, cpskToken, r, endpoint, http.MethodGet)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	rec, _ = testutil.MakeJSONRequest(nil, companyToken, r, endpoint, http.MethodGet)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec, _ = testutil.MakeJSONRequest(nil, cpskToken, r, "/jobpost?status=draft", http.MethodGet)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	rec, _ = testutil.MakeJSONRequest(nil, companyToken, r, "/jobpost?status=draft", http.MethodGet)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var posts []map[string]interface{}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &posts))
+	if assert.NotEmpty(t, posts) {
+		for _, post := range posts {
+			assert.Equal(t, model.JobPostStatusDraft, post["status"])
+			assert.Equal(t, database.TestUserCompany1.ID.String(), post["company_id"])
+		}
+	}
+
+	rec, _ = testutil.MakeJSONRequest(gin.H{"title": "Closed Internship", "status": "closed"}, companyToken, r, "/jobpost", http.MethodPost)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestUpdateJobPostStatus_Transitions(t *testing.T) {
+	companyToken, err := auth.GetAccessToken(t, testDB, database.TestUserCompany1.Username, database.TestSeedPassword)
+	assert.NoError(t, err)
+	adminToken, err := auth.GetAccessToken(t, testDB, database.TestAdminUser.Username, database.TestSeedPassword)
+	assert.NoError(t, err)
+
+	jobPost := createCompany1JobPost(t, "Test Job Status")
+	assert.NoError(t, testDB.Model(&jobPost).Update("status", model.JobPostStatusDraft).Error)
+
+	r := gin.Default()
+	jc := &JobPostController{DB: testDB}
+	r.PATCH("/jobpost/:id/status", middleware.RequireAuth(testDB), jc.UpdateJobPostStatus)
+	endpoint := fmt.Sprintf("/jobpost/%d/status", jobPost.ID)
+
+	rec, _ := testutil.MakeJSONRequest(gin.H{"status": "closed"}, companyToken, r, endpoint, http.MethodPatch)
+	assert.Equal(t, http.StatusBadRequest, rec.Code, "Draft can't be closed")
+
+	rec, resp := testutil.MakeJSONRequest(gin.H{"status": "published"}, companyToken, r, endpoint, http.MethodPatch)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, model.JobPostStatusPublished, resp["status"])
+
+	rec, _ = testutil.MakeJSONRequest(gin.H{"status": "hidden"}, companyToken, r, endpoint, http.MethodPatch)
+	assert.Equal(t, http.StatusForbidden, rec.Code, "Company can't hide its post")
+
+	rec, _ = testutil.MakeJSONRequest(gin.H{"status": "hidden"}, adminToken, r, endpoint, http.MethodPatch)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	rec, _ = testutil.MakeJSONRequest(gin.H{"status": "published"}, companyToken, r, endpoint, http.MethodPatch)
+	assert.Equal(t, http.StatusForbidden, rec.Code, "Company can't publish post hidden by admin")
+
+	rec, _ = testutil.MakeJSONRequest(gin.H{"status": "published"}, adminToken, r, endpoint, http.MethodPatch)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	rec, _ = testutil.MakeJSONRequest(gin.H{"status": "closed"}, companyToken, r, endpoint, http.MethodPatch)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+}
+
+func TestCloseExpiredPosts(t *testing.T) {
+	cpskToken, err := auth.GetAccessToken(t, testDB, database.TestUserCPSK1.Username, database.TestSeedPassword)
+	assert.NoError(t, err)
+
+	jobPost := createCompany1JobPost(t, "Test Job Expired")
+	expired := time.Now().Add(-time.Hour)
+	assert.NoError(t, testDB.Model(&jobPost).Update("expiring", expired).Error)
+
+	r := gin.Default()
+	jc := &JobPostController{DB: testDB}
+	r.GET("/jobpost/:id", middleware.RequireAuth(testDB), jc.GetPostByID)
+	r.PATCH("/jobpost/:id/status", middleware.RequireAuth(testDB), jc.UpdateJobPostStatus)
+
+	rec, resp := testutil.MakeJSONRequest(nil, cpskToken, r, fmt.Sprintf("/jobpost/%d", jobPost.ID), http.MethodGet)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, model.JobPostStatusClosed, resp["status"], "Expired post is shown as closed before it is closed")
+
+	closed, err := database.CloseExpiredPosts(testDB.DB)
+	assert.NoError(t, err)
+	assert.GreaterOrEqual(t, closed, int64(1))
+
+	var post model.JobPost
+	assert.NoError(t, testDB.Where("id = ?", jobPost.ID).First(&post).Error)
+	assert.Equal(t, model.JobPostStatusClosed, post.Status)
+
+	companyToken, err := auth.GetAccessToken(t, testDB, database.TestUserCompany1.Username, database.TestSeedPassword)
+	assert.NoError(t, err)
+	rec, _ = testutil.MakeJSONRequest(gin.H{"status": "published"}, companyToken, r,
+		fmt.Sprintf("/jobpost/%d/status", jobPost.ID), http.MethodPatch)
+	assert.Equal(t, http.StatusBadRequest, rec.Code, "Expired post can't be published again")
+}
+
+func TestPutJobPostForm(t *testing.T) {
+	companyToken, err := auth.GetAccessToken(t, testDB, database.TestUserCompany1.Username, database.TestSeedPassword)
+	assert.NoError(t, err)
+	company2Token, err := auth.GetAccessToken(t, testDB, database.TestUserCompany2.Username, database.TestSeedPassword)
+	assert.NoError(t, err)
+
+	jobPost := createCompany1JobPost(t, "Test Job Form")
+
+	r := gin.Default()
+	jc := &JobPostController{DB: testDB}
+	r.PUT("/jobpost/:id/form", middleware.RequireAuth(testDB), jc.PutJobPostForm)
+	r.GET("/jobpost/:id", middleware.RequireAuth(testDB), jc.GetPostByID)
+	endpoint := fmt.Sprintf("/jobpost/%d/form", jobPost.ID)
+
+	form := gin.H{
+		"default_form": false,
+		"questions": []gin.H{
+			{"label": "Why us?", "type": "long_text", "required": true},
+			{"label": "Stack", "type": "multi_choice", "options": []string{"Go", "React"}},
+		},
+	}
+
+	rec, _ := testutil.MakeJSONRequest(form, company2Token, r, endpoint, http.MethodPut)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	invalid := gin.H{"questions": []gin.H{{"label": "Stack", "type": "single_choice"}}}
+	rec, _ = testutil.MakeJSONRequest(invalid, companyToken, r, endpoint, http.MethodPut)
+	assert.Equal(t, http.StatusBadRequest, rec.Code, "Choice question requires options")
+
+	rec, resp := testutil.MakeJSONRequest(form, companyToken, r, endpoint, http.MethodPut)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, false, resp["default_form"])
+
+	rec, resp = testutil.MakeJSONRequest(nil, companyToken, r, fmt.Sprintf("/jobpost/%d", jobPost.ID), http.MethodGet)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	if questions, ok := resp["questions"].([]interface{}); assert.True(t, ok) && assert.Len(t, questions, 2) {
+		assert.Equal(t, "Why us?", questions[0].(map[string]interface{})["label"])
+	}
+
+	application := model.Application{CPSKID: database.TestUserCPSK1.ID, PostID: jobPost.ID, Status: model.ApplicationStatusPending}
+	assert.NoError(t, testDB.Create(&application).Error)
+	rec, _ = testutil.MakeJSONRequest(form, companyToken, r, endpoint, http.MethodPut)
+	assert.Equal(t, http.StatusConflict, rec.Code, "Form can't be changed after receiving applications")
+}
+
+func TestSearchPosts_RankingFacetsAndTypo(t *testing.T) {
+	userToken, err := auth.GetAccessToken(t, testDB, database.TestUserCPSK1.Username, database.TestSeedPassword)
+	assert.NoError(t, err)
+
+	titleMatch := createCompany1JobPost(t, "Quasarbyte Backend Engineer")
+	assert.NoError(t, testDB.Model(&titleMatch).Update("tags", pq.StringArray{"golang", "remote"}).Error)
+	descMatch := createCompany1JobPost(t, "Frontend Developer")
+	assert.NoError(t, testDB.Model(&descMatch).Updates(map[string]interface{}{
+		"desc": "Work closely with Quasarbyte backend team",
+		"type": "Internship",
+		"tags": pq.StringArray{"golang"},
+	}).Error)
+
+	r := gin.Default()
+	jc := &JobPostController{DB: testDB}
+	r.GET("/jobpost/search", middleware.RequireAuth(testDB), jc.SearchPosts)
+	r.GET("/jobpost", middleware.RequireAuth(testDB), jc.GetPosts)
+
+	var resp struct {
+		Posts  []model.JobPostResponse `json:"posts"`
+		Facets searchFacets            `json:"facets"`
+		Total  int64                   `json:"total"`
+	}
+
+	rec, _ := testutil.MakeJSONRequest(nil, userToken, r, "/jobpost/search?search=quasarbyte", http.MethodGet)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, int64(2), resp.Total)
+	if assert.Len(t, resp.Posts, 2) {
+		assert.Equal(t, titleMatch.ID, resp.Posts[0].ID, "Title match ranks above description match")
+		assert.Equal(t, descMatch.ID, resp.Posts[1].ID)
+	}
+	assert.ElementsMatch(t, []facetCount{{Value: "Full-time", Count: 1}, {Value: "Internship", Count: 1}}, resp.Facets.Type)
+	assert.Equal(t, []facetCount{{Value: "Entry", Count: 2}}, resp.Facets.ExpLvl)
+	assert.Equal(t, []facetCount{{Value: "golang", Count: 2}, {Value: "remote", Count: 1}}, resp.Facets.Tag)
+
+	rec, _ = testutil.MakeJSONRequest(nil, userToken, r, "/jobpost/search?search=quasarbite+backend", http.MethodGet)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	if assert.Len(t, resp.Posts, 1, "Misspelled title still matches") {
+		assert.Equal(t, titleMatch.ID, resp.Posts[0].ID)
+	}
+
+	rec, _ = testutil.MakeJSONRequest(nil, userToken, r, "/jobpost?search=quasarbyte&type=internship", http.MethodGet)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var posts []model.JobPostResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &posts))
+	if assert.Len(t, posts, 1) {
+		assert.Equal(t, descMatch.ID, posts[0].ID)
+	}
+
+	rec, _ = testutil.MakeJSONRequest(nil, userToken, r, "/jobpost/search?sort=salary", http.MethodGet)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
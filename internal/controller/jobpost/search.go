@@ -0,0 +1,298 @@
+package jobpost
+
+import (
+	"HireMeMaybe-backend/internal/database"
+	"HireMeMaybe-backend/internal/model"
+	"HireMeMaybe-backend/internal/utilities"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// facetLimit is maximum number of values listed in each facet
+const facetLimit = 20
+
+// Sort order of job post list
+const (
+	sortRelevance = "relevance"
+	sortPostTime  = "post_time"
+)
+
+// tsQuery matches search terms with both English stemming and Thai words as written
+var tsQuery = fmt.Sprintf("(websearch_to_tsquery('%s', ?) || websearch_to_tsquery('%s', ?))",
+	database.SearchConfigEnglish, database.SearchConfigThai)
+
+// facetCount is number of matched job posts having the value
+type facetCount struct {
+	Value string `json:"value"`
+	Count int64  `json:"count"`
+}
+
+// searchFacets holds counts of matched job posts by each facet, most common value first
+type searchFacets struct {
+	Type     []facetCount `json:"type"`
+	ExpLvl   []facetCount `json:"exp_lvl"`
+	Location []facetCount `json:"location"`
+	Industry []facetCount `json:"industry"`
+	Tag      []facetCount `json:"tag"`
+}
+
+// searchResponse is a page of matched job posts with facet counts of every matched post
+type searchResponse struct {
+	Posts  []model.JobPostResponse `json:"posts"`
+	Facets searchFacets            `json:"facets"`
+	utilities.Pagination
+}
+
+// filterPosts builds query of job posts matching query string of the request and visible to the user.
+// It writes error response and returns false if the query string is invalid or user can't list posts with given status.
+func (jc *JobPostController) filterPosts(c *gin.Context) (*gorm.DB, bool) {
+	rawSearch := strings.TrimSpace(c.Query("search"))
+	rawJobType := c.Query("type")
+	rawTag := c.Query("tag")
+	rawSalary := c.Query("salary")
+	rawExp := c.Query("exp")
+	rawCompany := c.Query("company")
+	rawIndustry := c.Query("industry")
+	rawLocation := c.Query("location")
+	rawStatus := strings.ToLower(c.DefaultQuery("status", model.JobPostStatusPublished))
+
+	switch sort := strings.ToLower(c.Query("sort")); sort {
+	case "", sortRelevance, sortPostTime:
+	default:
+		c.JSON(http.StatusBadRequest, utilities.ErrorResponse{
+			Error: fmt.Sprintf("Invalid sort '%s', must be %s or %s", sort, sortRelevance, sortPostTime),
+		})
+		return nil, false
+	}
+
+	now := time.Now()
+	result := jc.DB.Model(&model.JobPost{}).
+		Joins("JOIN company_users ON company_users.user_id = job_posts.company_user_id").
+		// Posts of banned company are not listed
+		Where(`NOT EXISTS (SELECT 1 FROM users JOIN punishment_structs ON punishment_structs.id = users.punishment_id
+			WHERE users.id = job_posts.company_user_id AND punishment_structs.punishment_type = ?
+			AND (punishment_structs.punish_end IS NULL OR punishment_structs.punish_end > ?))`, model.BanPunishment, now)
+
+	switch rawStatus {
+	case model.JobPostStatusPublished:
+		result = result.Where("job_posts.status = ?", model.JobPostStatusPublished).
+			Where("job_posts.expiring > ? OR job_posts.expiring IS NULL", now)
+
+	case model.JobPostStatusClosed:
+		// Expired post is closed even if it has not been closed yet
+		result = result.Where("job_posts.status = ? OR (job_posts.status = ? AND job_posts.expiring <= ?)",
+			model.JobPostStatusClosed, model.JobPostStatusPublished, now)
+
+	case model.JobPostStatusDraft, model.JobPostStatusHidden, model.JobPostStatusArchived:
+		companyID, allowed, err := jc.privateListScope(c, rawStatus)
+		if err != nil {
+			utilities.RespondDBError(c, err)
+			return nil, false
+		}
+		if !allowed {
+			c.JSON(http.StatusForbidden, utilities.ErrorResponse{
+				Error: fmt.Sprintf("You are not allowed to list %s job posts", rawStatus),
+			})
+			return nil, false
+		}
+		result = result.Where("job_posts.status = ?", rawStatus)
+		if companyID != nil {
+			result = result.Where("job_posts.company_user_id = ?", *companyID)
+		}
+
+	default:
+		c.JSON(http.StatusBadRequest, utilities.ErrorResponse{
+			Error: fmt.Sprintf("Invalid status '%s'", rawStatus),
+		})
+		return nil, false
+	}
+
+	if rawSearch != "" {
+		// Title close to search term matches even if it is misspelled
+		result = result.Where("job_posts.search_vector @@ "+tsQuery+" OR ? <% job_posts.title OR job_posts.title ILIKE ?",
+			rawSearch, rawSearch, rawSearch, "%"+rawSearch+"%")
+	}
+
+	if rawJobType != "" {
+		result = result.Where("job_posts.type ILIKE ?", "%"+rawJobType+"%")
+	}
+
+	if rawTag != "" {
+		result = result.Where("? ILIKE ANY(job_posts.tags)", rawTag)
+	}
+
+	if rawSalary != "" {
+		result = result.Where("job_posts.salary = ?", rawSalary)
+	}
+
+	if rawExp != "" {
+		result = result.Where("job_posts.exp_lvl = ?", rawExp)
+	}
+
+	if rawCompany != "" {
+		result = result.Where("company_users.name ILIKE ?", "%"+rawCompany+"%")
+	}
+
+	if rawIndustry != "" {
+		result = result.Where("company_users.industry ILIKE ?", "%"+rawIndustry+"%")
+	}
+
+	if rawLocation != "" {
+		result = result.Where("job_posts.location ILIKE ?", "%"+rawLocation+"%")
+	}
+
+	// Make query reusable for counting, faceting and fetching
+	return result.Session(&gorm.Session{}), true
+}
+
+// orderPosts sorts query by relevance to search term or by post time as requested by "sort" and "desc" query.
+// Relevance is full-text rank plus title similarity, it is the default when search term is given.
+func orderPosts(c *gin.Context, query *gorm.DB) *gorm.DB {
+	search := strings.TrimSpace(c.Query("search"))
+	sort := strings.ToLower(c.Query("sort"))
+	if sort == "" && search != "" {
+		sort = sortRelevance
+	}
+
+	byPostTime := clause.OrderByColumn{
+		Column: clause.Column{Table: "job_posts", Name: "post_time"},
+		Desc:   strings.ToLower(c.Query("desc")) == "true",
+	}
+	if sort != sortRelevance || search == "" {
+		return query.Order(byPostTime)
+	}
+
+	return query.Order(clause.OrderBy{Expression: clause.Expr{
+		SQL:                "ts_rank_cd(job_posts.search_vector, " + tsQuery + ") + word_similarity(?, job_posts.title) DESC",
+		Vars:               []interface{}{search, search, search},
+		WithoutParentheses: true,
+	}}).Order(byPostTime)
+}
+
+// countFacet counts matched posts by value of column, empty values are skipped
+func countFacet(query *gorm.DB, column string) ([]facetCount, error) {
+	counts := []facetCount{}
+	err := query.Select(column + " AS value, COUNT(*) AS count").
+		Where(column + " <> ''").
+		Group(column).
+		Order("count DESC, value ASC").
+		Limit(facetLimit).
+		Scan(&counts).Error
+	return counts, err
+}
+
+// countFacets counts matched posts by every facet
+func countFacets(query *gorm.DB) (searchFacets, error) {
+	var facets searchFacets
+	var err error
+	if facets.Type, err = countFacet(query, "job_posts.type"); err != nil {
+		return facets, err
+	}
+	if facets.ExpLvl, err = countFacet(query, "job_posts.exp_lvl"); err != nil {
+		return facets, err
+	}
+	if facets.Location, err = countFacet(query, "job_posts.location"); err != nil {
+		return facets, err
+	}
+	if facets.Industry, err = countFacet(query, "company_users.industry"); err != nil {
+		return facets, err
+	}
+	// Each tag of a post is counted once
+	facets.Tag, err = countFacet(query.Joins("CROSS JOIN LATERAL unnest(job_posts.tags) AS tag"), "tag")
+	return facets, err
+}
+
+// SearchPosts searches job posts ranked by relevance and returns them with facet counts.
+// @Summary Search job posts with ranking and facets
+// @Description Accept the same query as GET /jobpost, results are ranked by relevance when search is given.
+// @Description search uses full-text search over title, description, requirement and tags in English and Thai,
+// @Description and also matches title with typo or containing the search term.
+// @Description Facets count every matched post by type, exp_lvl, location, industry and tag, up to 20 values each.
+// @Tags Jobpost
+// @Produce json
+// @Param Authorization header string true "Insert your access token" default(Bearer <your access token>)
+// @Param search query string false "Full-text search term"
+// @Param type query string false "Job type field with substring matching and case insensitive"
+// @Param tag query string false "Search if tags field contain tag param, no substring matching and case insensitive"
+// @Param salary query string false "Salary field, must exactly match to get result"
+// @Param exp query string false "Exp_lvl field, must exactly match to get result"
+// @Param company query string false "Search from company name with substring matching and case insensitive"
+// @Param industry query string false "Search from industry of company with substring matching and case insensitive"
+// @Param location query string false "Search from location with substring matching and case insensitive"
+// @Param status query string false "Status of job post, one of draft, published, closed, hidden or archived" default(published)
+// @Param sort query string false "Sort by relevance or post_time, relevance is default when search is given"
+// @Param desc query boolean false "Sorting by post time in descending if true, otherwise ascendind"
+// @Param page query integer false "Page number, start from 1" default(1)
+// @Param limit query integer false "Number of posts per page (max 100)" default(20)
+// @Success 200 {object} searchResponse "Return matched job posts and facet counts"
+// @Failure 400 {object} utilities.ErrorResponse "Invalid authorization header, or invalid query"
+// @Failure 401 {object} utilities.ErrorResponse "Invalid token"
+// @Failure 403 {object} utilities.ErrorResponse "User is banned, or not allowed to list posts with given status"
+// @Failure 500 {object} utilities.ErrorResponse "Database error"
+// @Router /jobpost/search [get]
+func (jc *JobPostController) SearchPosts(c *gin.Context) {
+	user, err := utilities.ExtractUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, utilities.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	pagination, err := utilities.ParsePagination(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utilities.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	query, ok := jc.filterPosts(c)
+	if !ok {
+		return
+	}
+
+	if err := query.Count(&pagination.Total).Error; err != nil {
+		utilities.RespondDBError(c, err)
+		return
+	}
+
+	facets, err := countFacets(query)
+	if err != nil {
+		utilities.RespondDBError(c, err)
+		return
+	}
+
+	var rawPosts []model.JobPost
+	if err := orderPosts(c, query).
+		Preload("CompanyUser").
+		Preload("CompanyUser.User").
+		Preload("CompanyUser.User.Punishment").
+		Preload("Applications").
+		Offset(pagination.Offset()).
+		Limit(pagination.Limit).
+		Find(&rawPosts).Error; err != nil {
+		utilities.RespondDBError(c, err)
+		return
+	}
+
+	resp := searchResponse{
+		Posts:      []model.JobPostResponse{},
+		Facets:     facets,
+		Pagination: pagination,
+	}
+	for _, rawPost := range rawPosts {
+		post, err := rawPost.ToJobPostResponse(user)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, utilities.ErrorResponse{
+				Error: fmt.Sprint("Failed to process job post: ", err.Error()),
+			})
+			return
+		}
+		resp.Posts = append(resp.Posts, post)
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,514 @@
+// Package organization provides HTTP handlers for managing members of company organization.
+package organization
+
+import (
+	"HireMeMaybe-backend/internal/auth"
+	"HireMeMaybe-backend/internal/database"
+	"HireMeMaybe-backend/internal/mail"
+	"HireMeMaybe-backend/internal/model"
+	"HireMeMaybe-backend/internal/policy"
+	"HireMeMaybe-backend/internal/utilities"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// InvitationDuration is how long invitation link stay valid
+const InvitationDuration = 7 * 24 * time.Hour
+
+var (
+	errInvalidInvitation = errors.New("invitation is invalid or expired")
+	errInvitationEmail   = errors.New("invitation was sent to another email")
+	errCompanyAccount    = errors.New("company account can't join another organization")
+	errAlreadyMember     = errors.New("user is already a member of an organization")
+)
+
+// OrganizationController handles company organization members and invitations
+type OrganizationController struct {
+	DB     *database.DBinstanceStruct
+	Mailer mail.Sender
+	// InviteURL is frontend page receiving invitation token as "token" query parameter
+	InviteURL string
+}
+
+// NewOrganizationController creates a new instance of OrganizationController.
+// Invitation link point to ORGANIZATION_INVITE_URL environment.
+func NewOrganizationController(db *database.DBinstanceStruct, mailer mail.Sender) *OrganizationController {
+	inviteURL := os.Getenv("ORGANIZATION_INVITE_URL")
+	if inviteURL == "" {
+		inviteURL = "http://localhost:3000/accept-invitation"
+	}
+	return &OrganizationController{
+		DB:        db,
+		Mailer:    mailer,
+		InviteURL: inviteURL,
+	}
+}
+
+type memberInfo struct {
+	UserID   uuid.UUID `json:"user_id"`
+	Username string    `json:"username"`
+	Email    *string   `json:"email"`
+	Role     string    `json:"role"`
+}
+
+type organizationResponse struct {
+	Company model.CompanyUser `json:"company"`
+	MyRole  string            `json:"my_role"`
+	Members []memberInfo      `json:"members"`
+}
+
+type inviteRequest struct {
+	Email string `json:"email" binding:"required,email" example:"recruiter@example.com"`
+	Role  string `json:"role" binding:"required" example:"recruiter"`
+}
+
+type acceptInvitationRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+type memberRoleRequest struct {
+	Role string `json:"role" binding:"required" example:"viewer"`
+}
+
+// membership returns organization of the current user, or respond and return false if user is not in any
+func (oc *OrganizationController) membership(c *gin.Context) (policy.Membership, bool) {
+	org, err := policy.MembershipFromContext(c, oc.DB.DB)
+	if err != nil {
+		utilities.RespondDBError(c, err)
+		return org, false
+	}
+	if !org.IsMember() {
+		c.JSON(http.StatusForbidden, utilities.ErrorResponse{Error: "User is not a member of any company organization"})
+		return org, false
+	}
+	return org, true
+}
+
+// GetMyOrganization function returns company and members of organization of the current user
+// @Summary Get organization of current user
+// @Description Company account is always owner of its organization
+// @Tags Organization
+// @Produce json
+// @Param Authorization header string true "Insert your access token" default(Bearer <your access token>)
+// @Success 200 {object} organizationResponse
+// @Failure 401 {object} utilities.ErrorResponse "Invalid token"
+// @Failure 403 {object} utilities.ErrorResponse "Not a member of any organization, User is banned"
+// @Failure 500 {object} utilities.ErrorResponse "Database error"
+// @Router /organization [get]
+func (oc *OrganizationController) GetMyOrganization(c *gin.Context) {
+	org, ok := oc.membership(c)
+	if !ok {
+		return
+	}
+
+	resp := organizationResponse{MyRole: org.Role}
+	if err := oc.DB.Preload("User").Where("user_id = ?", org.CompanyID).First(&resp.Company).Error; err != nil {
+		utilities.RespondDBError(c, err)
+		return
+	}
+
+	var members []model.OrganizationMember
+	if err := oc.DB.Preload("User").Where("company_id = ?", org.CompanyID).Order("created_at").Find(&members).Error; err != nil {
+		utilities.RespondDBError(c, err)
+		return
+	}
+
+	resp.Members = append(resp.Members, memberInfo{
+		UserID:   resp.Company.User.ID,
+		Username: resp.Company.User.Username,
+		Email:    resp.Company.User.Email,
+		Role:     model.OrgRoleOwner,
+	})
+	for _, m := range members {
+		resp.Members = append(resp.Members, memberInfo{
+			UserID:   m.UserID,
+			Username: m.User.Username,
+			Email:    m.User.Email,
+			Role:     m.Role,
+		})
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// InviteMember function sends invitation link to join organization of the current user
+// @Summary Invite user to organization
+// @Description Only owner of the organization can access this endpoint
+// @Description Previous invitation to the same email is replaced. Role must be owner, recruiter or viewer
+// @Tags Organization
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Insert your access token" default(Bearer <your access token>)
+// @Param Invitation body inviteRequest true "Email and role of the invited user"
+// @Success 201 {object} model.OrganizationInvitation
+// @Failure 400 {object} utilities.ErrorResponse "Invalid email or role"
+// @Failure 401 {object} utilities.ErrorResponse "Invalid token"
+// @Failure 403 {object} utilities.ErrorResponse "Do not have permission"
+// @Failure 409 {object} utilities.ErrorResponse "User is already a member"
+// @Failure 500 {object} utilities.ErrorResponse "Database or mail error"
+// @Router /organization/invitations [post]
+func (oc *OrganizationController) InviteMember(c *gin.Context) {
+	user, err := utilities.ExtractUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, utilities.ErrorResponse{Error: err.Error()})
+		return
+	}
+	org, ok := oc.membership(c)
+	if !ok {
+		return
+	}
+
+	var req inviteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utilities.ErrorResponse{
+			Error: fmt.Sprintf("Invalid request body: %s", err.Error()),
+		})
+		return
+	}
+	if !policy.IsOrganizationRole(req.Role) {
+		c.JSON(http.StatusBadRequest, utilities.ErrorResponse{
+			Error: fmt.Sprintf("Invalid organization role '%s'", req.Role),
+		})
+		return
+	}
+	email := strings.ToLower(strings.TrimSpace(req.Email))
+
+	// Reject invitation to someone already in the organization
+	var existing model.User
+	err = oc.DB.Where("LOWER(email) = ?", email).First(&existing).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		utilities.RespondDBError(c, err)
+		return
+	}
+	if err == nil {
+		m, err := policy.LoadMembership(oc.DB.DB, existing)
+		if err != nil {
+			utilities.RespondDBError(c, err)
+			return
+		}
+		if m.CompanyID == org.CompanyID {
+			c.JSON(http.StatusConflict, utilities.ErrorResponse{Error: "User is already a member of the organization"})
+			return
+		}
+	}
+
+	var company model.CompanyUser
+	if err := oc.DB.Where("user_id = ?", org.CompanyID).First(&company).Error; err != nil {
+		utilities.RespondDBError(c, err)
+		return
+	}
+
+	raw, err := auth.NewOpaqueToken()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utilities.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	invitation := model.OrganizationInvitation{
+		CompanyID:   org.CompanyID,
+		Email:       email,
+		Role:        req.Role,
+		TokenHash:   auth.HashToken(raw),
+		InvitedByID: user.ID,
+		ExpiresAt:   time.Now().Add(InvitationDuration),
+	}
+	err = oc.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("company_id = ? AND email = ?", org.CompanyID, email).
+			Delete(&model.OrganizationInvitation{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(&invitation).Error
+	})
+	if err != nil {
+		utilities.RespondDBError(c, err)
+		return
+	}
+
+	link := oc.InviteURL + "?token=" + url.QueryEscape(raw)
+	err = oc.Mailer.Send(mail.Message{
+		To:      email,
+		Subject: fmt.Sprintf("You are invited to join %s on HireMeMaybe", company.Name),
+		Body: fmt.Sprintf("Hi,\n\n%s invited you to join the hiring team of %s as %s. Sign in with this email and use the link below to accept. The link expires in %d days.\n\n%s\n\nIf you did not expect this, you can ignore this email.",
+			user.Username, company.Name, req.Role, int(InvitationDuration.Hours()/24), link),
+	})
+	if err != nil {
+		log.Printf("Failed to send organization invitation to %s: %s", email, err.Error())
+		c.JSON(http.StatusInternalServerError, utilities.ErrorResponse{Error: "Failed to send invitation email"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, invitation)
+}
+
+// GetInvitations function returns pending invitations of organization of the current user
+// @Summary Get pending invitations of organization
+// @Description Only owner of the organization can access this endpoint
+// @Tags Organization
+// @Produce json
+// @Param Authorization header string true "Insert your access token" default(Bearer <your access token>)
+// @Success 200 {array} model.OrganizationInvitation
+// @Failure 401 {object} utilities.ErrorResponse "Invalid token"
+// @Failure 403 {object} utilities.ErrorResponse "Do not have permission"
+// @Failure 500 {object} utilities.ErrorResponse "Database error"
+// @Router /organization/invitations [get]
+func (oc *OrganizationController) GetInvitations(c *gin.Context) {
+	org, ok := oc.membership(c)
+	if !ok {
+		return
+	}
+
+	invitations := []model.OrganizationInvitation{}
+	if err := oc.DB.Where("company_id = ? AND expires_at > ?", org.CompanyID, time.Now()).
+		Order("created_at DESC").
+		Find(&invitations).Error; err != nil {
+		utilities.RespondDBError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, invitations)
+}
+
+// RevokeInvitation function deletes pending invitation of organization of the current user
+// @Summary Revoke invitation
+// @Description Only owner of the organization can access this endpoint
+// @Tags Organization
+// @Produce json
+// @Param Authorization header string true "Insert your access token" default(Bearer <your access token>)
+// @Param id path integer true "ID of the invitation"
+// @Success 200 {object} utilities.MessageResponse "Invitation revoked"
+// @Failure 401 {object} utilities.ErrorResponse "Invalid token"
+// @Failure 403 {object} utilities.ErrorResponse "Do not have permission"
+// @Failure 404 {object} utilities.ErrorResponse "Invitation not found"
+// @Failure 500 {object} utilities.ErrorResponse "Database error"
+// @Router /organization/invitations/{id} [delete]
+func (oc *OrganizationController) RevokeInvitation(c *gin.Context) {
+	org, ok := oc.membership(c)
+	if !ok {
+		return
+	}
+
+	result := oc.DB.Where("id = ? AND company_id = ?", c.Param("id"), org.CompanyID).
+		Delete(&model.OrganizationInvitation{})
+	if result.Error != nil {
+		utilities.RespondDBError(c, result.Error)
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, utilities.ErrorResponse{Error: "Invitation not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, utilities.MessageResponse{Message: "Invitation revoked"})
+}
+
+// AcceptInvitation function adds the current user to organization of the invitation
+// @Summary Accept invitation to organization
+// @Description Verified email of the current user must match the invited email. Invitation can be used once
+// @Description Company account and member of another organization can't accept
+// @Tags Organization
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Insert your access token" default(Bearer <your access token>)
+// @Param Token body acceptInvitationRequest true "Token from invitation link"
+// @Success 200 {object} policy.Membership
+// @Failure 400 {object} utilities.ErrorResponse "Token is not provided, invalid or expired"
+// @Failure 401 {object} utilities.ErrorResponse "Invalid token"
+// @Failure 403 {object} utilities.ErrorResponse "Invitation was sent to another email, User is banned"
+// @Failure 409 {object} utilities.ErrorResponse "User is company account or already a member of an organization"
+// @Failure 500 {object} utilities.ErrorResponse "Database error"
+// @Router /organization/invitations/accept [post]
+func (oc *OrganizationController) AcceptInvitation(c *gin.Context) {
+	user, err := utilities.ExtractUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, utilities.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	var req acceptInvitationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utilities.ErrorResponse{Error: "Token must be provided"})
+		return
+	}
+
+	var invitation model.OrganizationInvitation
+	err = oc.DB.Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("token_hash = ? AND expires_at > ?", auth.HashToken(req.Token), time.Now()).
+			First(&invitation).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errInvalidInvitation
+		}
+		if err != nil {
+			return err
+		}
+
+		if user.Email == nil || !user.EmailVerified || !strings.EqualFold(*user.Email, invitation.Email) {
+			return errInvitationEmail
+		}
+		if user.Role == model.RoleCompany {
+			return errCompanyAccount
+		}
+		var count int64
+		if err := tx.Model(&model.OrganizationMember{}).Where("user_id = ?", user.ID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return errAlreadyMember
+		}
+
+		result := tx.Delete(&model.OrganizationInvitation{}, invitation.ID)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			// Used by another request
+			return errInvalidInvitation
+		}
+
+		return tx.Create(&model.OrganizationMember{
+			UserID:    user.ID,
+			CompanyID: invitation.CompanyID,
+			Role:      invitation.Role,
+		}).Error
+	})
+
+	switch {
+	case errors.Is(err, errInvalidInvitation):
+		c.JSON(http.StatusBadRequest, utilities.ErrorResponse{Error: "Invitation is invalid or expired"})
+		return
+
+	case errors.Is(err, errInvitationEmail):
+		c.JSON(http.StatusForbidden, utilities.ErrorResponse{Error: "Invitation was sent to another email, verify the invited email first"})
+		return
+
+	case errors.Is(err, errCompanyAccount), errors.Is(err, errAlreadyMember):
+		c.JSON(http.StatusConflict, utilities.ErrorResponse{Error: err.Error()})
+		return
+
+	case err != nil:
+		utilities.RespondDBError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, policy.Membership{CompanyID: invitation.CompanyID, Role: invitation.Role})
+}
+
+// UpdateMemberRole function changes role of member of organization of the current user
+// @Summary Change role of organization member
+// @Description Only owner of the organization can access this endpoint. Role of company account can't be changed
+// @Tags Organization
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Insert your access token" default(Bearer <your access token>)
+// @Param user_id path string true "ID of the member"
+// @Param Role body memberRoleRequest true "New role of the member"
+// @Success 200 {object} utilities.MessageResponse "Role updated"
+// @Failure 400 {object} utilities.ErrorResponse "Invalid user ID or role"
+// @Failure 401 {object} utilities.ErrorResponse "Invalid token"
+// @Failure 403 {object} utilities.ErrorResponse "Do not have permission"
+// @Failure 404 {object} utilities.ErrorResponse "Member not found"
+// @Failure 500 {object} utilities.ErrorResponse "Database error"
+// @Router /organization/members/{user_id} [patch]
+func (oc *OrganizationController) UpdateMemberRole(c *gin.Context) {
+	org, ok := oc.membership(c)
+	if !ok {
+		return
+	}
+
+	memberID, err := uuid.Parse(c.Param("user_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utilities.ErrorResponse{Error: "Invalid user ID"})
+		return
+	}
+
+	var req memberRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil || !policy.IsOrganizationRole(req.Role) {
+		c.JSON(http.StatusBadRequest, utilities.ErrorResponse{
+			Error: "Role must be one of owner, recruiter or viewer",
+		})
+		return
+	}
+
+	result := oc.DB.Model(&model.OrganizationMember{}).
+		Where("company_id = ? AND user_id = ?", org.CompanyID, memberID).
+		Update("role", req.Role)
+	if result.Error != nil {
+		utilities.RespondDBError(c, result.Error)
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, utilities.ErrorResponse{Error: "Member not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, utilities.MessageResponse{Message: "Role updated"})
+}
+
+// RemoveMember function removes member from organization of the current user
+// @Summary Remove organization member
+// @Description Owner of the organization can remove any member, other member can only remove themselves to leave
+// @Description Company account can't be removed
+// @Tags Organization
+// @Produce json
+// @Param Authorization header string true "Insert your access token" default(Bearer <your access token>)
+// @Param user_id path string true "ID of the member"
+// @Success 200 {object} utilities.MessageResponse "Member removed"
+// @Failure 400 {object} utilities.ErrorResponse "Invalid user ID"
+// @Failure 401 {object} utilities.ErrorResponse "Invalid token"
+// @Failure 403 {object} utilities.ErrorResponse "Do not have permission"
+// @Failure 404 {object} utilities.ErrorResponse "Member not found"
+// @Failure 500 {object} utilities.ErrorResponse "Database error"
+// @Router /organization/members/{user_id} [delete]
+func (oc *OrganizationController) RemoveMember(c *gin.Context) {
+	user, err := utilities.ExtractUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, utilities.ErrorResponse{Error: err.Error()})
+		return
+	}
+	org, ok := oc.membership(c)
+	if !ok {
+		return
+	}
+
+	memberID, err := uuid.Parse(c.Param("user_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utilities.ErrorResponse{Error: "Invalid user ID"})
+		return
+	}
+
+	if memberID != user.ID {
+		perms, err := policy.FromContext(c, oc.DB.DB)
+		if err != nil {
+			utilities.RespondDBError(c, err)
+			return
+		}
+		if !perms.Has(policy.OrganizationManage) {
+			c.JSON(http.StatusForbidden, utilities.ErrorResponse{
+				Error: "You are not allowed to remove other members",
+			})
+			return
+		}
+	}
+
+	result := oc.DB.Where("company_id = ? AND user_id = ?", org.CompanyID, memberID).
+		Delete(&model.OrganizationMember{})
+	if result.Error != nil {
+		utilities.RespondDBError(c, result.Error)
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, utilities.ErrorResponse{Error: "Member not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, utilities.MessageResponse{Message: "Member removed"})
+}
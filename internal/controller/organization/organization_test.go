@@ -0,0 +1,256 @@
+package organization
+
+import (
+	"HireMeMaybe-backend/internal/auth"
+	"HireMeMaybe-backend/internal/controller/application"
+	"HireMeMaybe-backend/internal/controller/jobpost"
+	"HireMeMaybe-backend/internal/database"
+	"HireMeMaybe-backend/internal/mail"
+	"HireMeMaybe-backend/internal/middleware"
+	"HireMeMaybe-backend/internal/model"
+	"HireMeMaybe-backend/internal/policy"
+	"HireMeMaybe-backend/internal/testutil"
+	"HireMeMaybe-backend/internal/utilities"
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/testcontainers/testcontainers-go"
+)
+
+var testDB *database.DBinstanceStruct
+
+func TestMain(m *testing.M) {
+	gin.SetMode(gin.TestMode)
+	var err error
+	var midTeardown func(context.Context, ...testcontainers.TerminateOption) error
+	midTeardown, testDB, err = database.GetTestDB()
+	if err != nil {
+		os.Exit(1)
+	}
+	m.Run()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if midTeardown != nil {
+		_ = midTeardown(ctx)
+	}
+}
+
+type recordingSender struct {
+	sent []mail.Message
+}
+
+func (s *recordingSender) Send(msg mail.Message) error {
+	s.sent = append(s.sent, msg)
+	return nil
+}
+
+func organizationRouter(sender mail.Sender) *gin.Engine {
+	r := gin.Default()
+	oc := NewOrganizationController(testDB, sender)
+	jc := jobpost.NewJobPostController(testDB)
+	ac := application.NewApplicationController(testDB)
+
+	orgRoute := r.Group("/organization", middleware.RequireAuth(testDB))
+	orgRoute.GET("", oc.GetMyOrganization)
+	orgRoute.POST("/invitations/accept", oc.AcceptInvitation)
+	orgRoute.DELETE("/members/:user_id", oc.RemoveMember)
+	orgRoute.Use(middleware.RequirePermission(testDB, policy.OrganizationManage))
+	orgRoute.GET("/invitations", oc.GetInvitations)
+	orgRoute.POST("/invitations", oc.InviteMember)
+	orgRoute.DELETE("/invitations/:id", oc.RevokeInvitation)
+	orgRoute.PATCH("/members/:user_id", oc.UpdateMemberRole)
+
+	r.POST("/jobpost", middleware.RequireAuth(testDB), middleware.RequirePermission(testDB, policy.JobPostCreate), middleware.CheckOrganizationPunishment(testDB), jc.CreateJobPostHandler)
+	r.PATCH("/jobpost/:id", middleware.RequireAuth(testDB), middleware.RequirePermission(testDB, policy.JobPostEditOwn, policy.JobPostEditAny), middleware.CheckOrganizationPunishment(testDB), jc.EditJobPost)
+	r.GET("/jobpost/:id/applications", middleware.RequireAuth(testDB), middleware.RequirePermission(testDB, policy.ApplicationViewOwn, policy.ApplicationViewAny), ac.GetPostApplications)
+	return r
+}
+
+// Helper: create user with verified email and seed password, removed when test end.
+func createInvitee(t *testing.T, username string) (model.User, string) {
+	t.Helper()
+	hashed, err := utilities.HashPassword(database.TestSeedPassword)
+	assert.NoError(t, err)
+	email := username + "@example.com"
+	user := model.User{
+		ID:            uuid.New(),
+		Username:      username,
+		Email:         &email,
+		EmailVerified: true,
+		Password:      hashed,
+		Role:          model.RoleVisitor,
+	}
+	assert.NoError(t, testDB.Create(&user).Error)
+	t.Cleanup(func() { testDB.Delete(&model.User{}, "id = ?", user.ID) })
+
+	token, err := auth.GetAccessToken(t, testDB, username, database.TestSeedPassword)
+	assert.NoError(t, err)
+	return user, token
+}
+
+// Helper: invite email as company 1 and return token from invitation link.
+func inviteAsCompany1(t *testing.T, r *gin.Engine, sender *recordingSender, email string, role string) string {
+	t.Helper()
+	companyToken, err := auth.GetAccessToken(t, testDB, database.TestUserCompany1.Username, database.TestSeedPassword)
+	assert.NoError(t, err)
+
+	rec, _ := testutil.MakeJSONRequest(gin.H{"email": email, "role": role}, companyToken, r, "/organization/invitations", http.MethodPost)
+	if !assert.Equal(t, http.StatusCreated, rec.Code, rec.Body.String()) || !assert.NotEmpty(t, sender.sent) {
+		return ""
+	}
+	msg := sender.sent[len(sender.sent)-1]
+	assert.Equal(t, email, msg.To)
+
+	start := strings.Index(msg.Body, "?token=")
+	if !assert.GreaterOrEqual(t, start, 0, "link not found in mail body") {
+		return ""
+	}
+	token, err := url.QueryUnescape(strings.Fields(msg.Body[start+len("?token="):])[0])
+	assert.NoError(t, err)
+	return token
+}
+
+func TestRecruiterManagesCompanyJobPost(t *testing.T) {
+	sender := &recordingSender{}
+	r := organizationRouter(sender)
+	recruiter, recruiterToken := createInvitee(t, "org_recruiter")
+
+	// Permissions of organization role are not granted before accepting
+	rec, _ := testutil.MakeJSONRequest(gin.H{"title": "Before joining"}, recruiterToken, r, "/jobpost", http.MethodPost)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	token := inviteAsCompany1(t, r, sender, *recruiter.Email, model.OrgRoleRecruiter)
+	rec, resp := testutil.MakeJSONRequest(gin.H{"token": token}, recruiterToken, r, "/organization/invitations/accept", http.MethodPost)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, database.TestUserCompany1.ID.String(), resp["company_id"])
+
+	// Invitation can be used once
+	rec, _ = testutil.MakeJSONRequest(gin.H{"token": token}, recruiterToken, r, "/organization/invitations/accept", http.MethodPost)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec, resp = testutil.MakeJSONRequest(gin.H{"title": "Posted by recruiter"}, recruiterToken, r, "/jobpost", http.MethodPost)
+	assert.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	assert.Equal(t, database.TestUserCompany1.ID.String(), resp["company_id"], "Post belong to the company, not the recruiter")
+	t.Cleanup(func() { testDB.Delete(&model.JobPost{}, "id = ?", resp["id"]) })
+
+	rec, _ = testutil.MakeJSONRequest(gin.H{"title": "Edited by recruiter"}, recruiterToken, r,
+		fmt.Sprintf("/jobpost/%v", resp["id"]), http.MethodPatch)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	rec, _ = testutil.MakeJSONRequest(gin.H{"title": "Not our post"}, recruiterToken, r,
+		fmt.Sprintf("/jobpost/%d", database.TestJobPost3.ID), http.MethodPatch)
+	assert.Equal(t, http.StatusForbidden, rec.Code, "Post of other company can't be edited")
+
+	// Only owner can manage members
+	rec, _ = testutil.MakeJSONRequest(gin.H{"email": "someone@example.com", "role": model.OrgRoleViewer}, recruiterToken, r, "/organization/invitations", http.MethodPost)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	rec, resp = testutil.MakeJSONRequest(nil, recruiterToken, r, "/organization", http.MethodGet)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, model.OrgRoleRecruiter, resp["my_role"])
+	assert.Len(t, resp["members"], 2)
+
+	// Leave the organization
+	rec, _ = testutil.MakeJSONRequest(nil, recruiterToken, r, "/organization/members/"+recruiter.ID.String(), http.MethodDelete)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	rec, _ = testutil.MakeJSONRequest(gin.H{"title": "After leaving"}, recruiterToken, r, "/jobpost", http.MethodPost)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+}
+
+func TestMemberOfBannedCompanyCannotPost(t *testing.T) {
+	r := organizationRouter(&recordingSender{})
+	recruiter, recruiterToken := createInvitee(t, "banned_org_recruiter")
+	assert.NoError(t, testDB.Create(&model.OrganizationMember{
+		UserID:    recruiter.ID,
+		CompanyID: database.TestUserCompany2.ID,
+		Role:      model.OrgRoleRecruiter,
+	}).Error)
+
+	companyID := database.TestUserCompany2.ID
+	assert.NoError(t, database.IssuePunishment(testDB.DB, &model.PunishmentStruct{
+		UserID:         &companyID,
+		PunishmentType: model.BanPunishment,
+		Reason:         "Scam posts",
+	}))
+	t.Cleanup(func() {
+		testDB.Model(&model.User{}).Where("id = ?", companyID).Update("punishment_id", nil)
+		testDB.Where("user_id = ?", companyID).Delete(&model.PunishmentStruct{})
+	})
+
+	rec, _ := testutil.MakeJSONRequest(gin.H{"title": "Posted for banned company"}, recruiterToken, r, "/jobpost", http.MethodPost)
+	assert.Equal(t, http.StatusForbidden, rec.Code, rec.Body.String())
+
+	rec, _ = testutil.MakeJSONRequest(gin.H{"title": "Edited for banned company"}, recruiterToken, r,
+		fmt.Sprintf("/jobpost/%d", database.TestJobPost3.ID), http.MethodPatch)
+	assert.Equal(t, http.StatusForbidden, rec.Code, rec.Body.String())
+}
+
+func TestViewerCanOnlyViewApplications(t *testing.T) {
+	sender := &recordingSender{}
+	r := organizationRouter(sender)
+	viewer, viewerToken := createInvitee(t, "org_viewer")
+
+	token := inviteAsCompany1(t, r, sender, *viewer.Email, model.OrgRoleViewer)
+	rec, _ := testutil.MakeJSONRequest(gin.H{"token": token}, viewerToken, r, "/organization/invitations/accept", http.MethodPost)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	rec, _ = testutil.MakeJSONRequest(nil, viewerToken, r, fmt.Sprintf("/jobpost/%d/applications", database.TestJobPost1.ID), http.MethodGet)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	rec, _ = testutil.MakeJSONRequest(gin.H{"title": "Edited by viewer"}, viewerToken, r,
+		fmt.Sprintf("/jobpost/%d", database.TestJobPost1.ID), http.MethodPatch)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	// Owner promote viewer to recruiter
+	companyToken, err := auth.GetAccessToken(t, testDB, database.TestUserCompany1.Username, database.TestSeedPassword)
+	assert.NoError(t, err)
+	rec, _ = testutil.MakeJSONRequest(gin.H{"role": model.OrgRoleRecruiter}, companyToken, r, "/organization/members/"+viewer.ID.String(), http.MethodPatch)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	m, err := policy.LoadMembership(testDB.DB, viewer)
+	assert.NoError(t, err)
+	assert.Equal(t, policy.Membership{CompanyID: database.TestUserCompany1.ID, Role: model.OrgRoleRecruiter}, m)
+
+	// Company account can't be changed or removed
+	rec, _ = testutil.MakeJSONRequest(gin.H{"role": model.OrgRoleViewer}, companyToken, r, "/organization/members/"+database.TestUserCompany1.ID.String(), http.MethodPatch)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	rec, _ = testutil.MakeJSONRequest(nil, companyToken, r, "/organization/members/"+viewer.ID.String(), http.MethodDelete)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+}
+
+func TestAcceptInvitationRequireInvitedEmail(t *testing.T) {
+	sender := &recordingSender{}
+	r := organizationRouter(sender)
+	_, otherToken := createInvitee(t, "org_not_invited")
+
+	token := inviteAsCompany1(t, r, sender, "org_invited@example.com", model.OrgRoleRecruiter)
+	rec, _ := testutil.MakeJSONRequest(gin.H{"token": token}, otherToken, r, "/organization/invitations/accept", http.MethodPost)
+	assert.Equal(t, http.StatusForbidden, rec.Code, rec.Body.String())
+
+	companyToken, err := auth.GetAccessToken(t, testDB, database.TestUserCompany2.Username, database.TestSeedPassword)
+	assert.NoError(t, err)
+	rec, _ = testutil.MakeJSONRequest(gin.H{"token": token}, companyToken, r, "/organization/invitations/accept", http.MethodPost)
+	assert.Equal(t, http.StatusForbidden, rec.Code, "Email of company 2 is not verified nor invited")
+
+	// Invitation is still pending and can be revoked
+	companyToken, err = auth.GetAccessToken(t, testDB, database.TestUserCompany1.Username, database.TestSeedPassword)
+	assert.NoError(t, err)
+	var invitation model.OrganizationInvitation
+	assert.NoError(t, testDB.Where("token_hash = ?", auth.HashToken(token)).First(&invitation).Error)
+	rec, _ = testutil.MakeJSONRequest(nil, companyToken, r, fmt.Sprintf("/organization/invitations/%d", invitation.ID), http.MethodDelete)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	rec, _ = testutil.MakeJSONRequest(gin.H{"email": "bad", "role": model.OrgRoleViewer}, companyToken, r, "/organization/invitations", http.MethodPost)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec, _ = testutil.MakeJSONRequest(gin.H{"email": "x@example.com", "role": "admin"}, companyToken, r, "/organization/invitations", http.MethodPost)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
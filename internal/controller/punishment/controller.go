@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
@@ -27,6 +28,23 @@ func NewPunishmentController(db *database.DBinstanceStruct) *PunishmentControlle
 	}
 }
 
+// punishRequest is detail of punishment issued by admin
+type punishRequest struct {
+	Type       string     `json:"type"`
+	At         *time.Time `json:"at"`
+	End        *time.Time `json:"end"`
+	Reason     string     `json:"reason"`
+	ReportType *string    `json:"report_type"`
+	ReportID   *uint      `json:"report_id"`
+}
+
+// punishmentHistoryResponse is every punishment issued to user, newest first
+type punishmentHistoryResponse struct {
+	UserID             uuid.UUID                `json:"user_id"`
+	ActivePunishmentID *int                     `json:"active_punishment_id"`
+	History            []model.PunishmentStruct `json:"history"`
+}
+
 // PunishUser handles ban and suspend process for admin
 // @Summary Ban or suspend user
 // @Description Type of punishment (Only 'ban' or 'suspend' with case insensitive),
@@ -34,24 +52,31 @@ func NewPunishmentController(db *database.DBinstanceStruct) *PunishmentControlle
 // @Description Only 'type' is required 'at' and 'end' are optional
 // @Description 'at' will be current time by default
 // @Description 'end' leave empty mean permanent punishment
+// @Description 'report_type' ('user' or 'post') and 'report_id' optionally link report on this user or their post.
+// @Description New punishment is added to punishment history, previous punishments are kept.
 // @Tags Admin
 // @Accept json
 // @Produce json
 // @Param Authorization header string true "Insert your access token" default(Bearer <your access token>)
 // @Param user_id path string true "ID of user to be punished"
-// @Param Detail body model.PunishmentStruct true "Detail of punishment"
+// @Param Detail body punishRequest true "Detail of punishment"
 // @Success 200 {object} utilities.MessageResponse "Successfully punish a user"
-// @Failure 400 {object} utilities.ErrorResponse "Invalid authorization header, request body"
+// @Failure 400 {object} utilities.ErrorResponse "Invalid authorization header, request body, report not found"
 // @Failure 401 {object} utilities.ErrorResponse "Invalid token"
 // @Failure 403 {object} utilities.ErrorResponse "Not logged in as Admin, trying to punish other Admin"
 // @Failure 404 {object} utilities.ErrorResponse "User not found"
 // @Failure 500 {object} utilities.ErrorResponse "Database error"
 // @Router /punish/{user_id} [put]
 func (jc *PunishmentController) PunishUser(c *gin.Context) {
+	admin, err := utilities.ExtractUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, utilities.ErrorResponse{Error: err.Error()})
+		return
+	}
+
 	userID := c.Param("user_id")
 
 	user := model.User{}
-	punishment := model.PunishmentStruct{}
 	if err := jc.DB.Where("id = ?", userID).First(&user).Error; err != nil {
 		c.JSON(http.StatusNotFound, utilities.ErrorResponse{Error: "User not found"})
 		return
@@ -64,19 +89,29 @@ func (jc *PunishmentController) PunishUser(c *gin.Context) {
 		return
 	}
 
-	if err := c.ShouldBindJSON(&punishment); err != nil {
+	req := punishRequest{}
+	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, utilities.ErrorResponse{
 			Error: fmt.Sprintf("Invalid request body: %s", err.Error()),
 		})
 		return
 	}
+	punishment := model.PunishmentStruct{
+		UserID:         &user.ID,
+		PunishmentType: strings.ToLower(req.Type),
+		PunishAt:       req.At,
+		PunishEnd:      req.End,
+		Reason:         req.Reason,
+		IssuedByID:     &admin.ID,
+		ReportType:     req.ReportType,
+		ReportID:       req.ReportID,
+	}
 	if punishment.PunishAt == nil {
 		now := time.Now()
 		punishment.PunishAt = &now
 	}
 
 	allowedType := []string{model.BanPunishment, model.SuspendPunishment}
-	punishment.PunishmentType = strings.ToLower(punishment.PunishmentType)
 	if !slices.Contains(allowedType, punishment.PunishmentType) {
 		c.JSON(http.StatusBadRequest, utilities.ErrorResponse{
 			Error: "Invalid request body: type can be only 'ban' or 'suspend'",
@@ -92,10 +127,29 @@ func (jc *PunishmentController) PunishUser(c *gin.Context) {
 		}
 	}
 
-	user.Punishment = &punishment
+	if (req.ReportType == nil) != (req.ReportID == nil) {
+		c.JSON(http.StatusBadRequest, utilities.ErrorResponse{
+			Error: "Invalid request body: 'report_type' and 'report_id' must be given together",
+		})
+		return
+	}
+	if req.ReportType != nil {
+		found, err := jc.reportOnUserExists(*req.ReportType, *req.ReportID, user.ID)
+		if err != nil {
+			utilities.RespondDBError(c, err)
+			return
+		}
+		if !found {
+			c.JSON(http.StatusBadRequest, utilities.ErrorResponse{
+				Error: "Report on this user not found",
+			})
+			return
+		}
+	}
 
-	if err := jc.DB.Session(&gorm.Session{FullSaveAssociations: true}).
-		Save(&user).Error; err != nil {
+	if err := jc.DB.Transaction(func(tx *gorm.DB) error {
+		return database.IssuePunishment(tx, &punishment)
+	}); err != nil {
 		c.JSON(http.StatusInternalServerError, utilities.ErrorResponse{
 			Error: fmt.Sprintf("Failed to update user information: %s", err.Error()),
 		})
@@ -107,13 +161,37 @@ func (jc *PunishmentController) PunishUser(c *gin.Context) {
 	})
 }
 
-// DeletePunishmentRecord removes punishment record from user
-// @Summary Remove punishment record from user
+// reportOnUserExists reports whether report of given type exists and is about the user or their job post
+func (jc *PunishmentController) reportOnUserExists(reportType string, reportID uint, userID uuid.UUID) (bool, error) {
+	var query *gorm.DB
+	switch reportType {
+	case model.ReportTypeUser:
+		query = jc.DB.Model(&model.ReportOnUser{}).
+			Where("id = ? AND reported_user_id = ?", reportID, userID)
+	case model.ReportTypePost:
+		query = jc.DB.Model(&model.ReportOnPost{}).
+			Joins("JOIN job_posts ON job_posts.id = report_on_posts.reported_post_id").
+			Where("report_on_posts.id = ? AND job_posts.company_user_id = ?", reportID, userID)
+	default:
+		return false, nil
+	}
+
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// DeletePunishmentRecord lifts active punishments of user, they are kept in punishment history
+// @Summary Lift punishment of user
+// @Description Every active punishment of the user is marked as lifted by current admin.
 // @Tags Admin
 // @Produce json
 // @Param Authorization header string true "Insert your access token" default(Bearer <your access token>)
 // @Param user_id path string true "ID of user to be unpunished"
-// @Success 200 {object} utilities.MessageResponse "Successfully remove punishment record from user"
+// @Param reason query string false "Reason of lifting the punishment early"
+// @Success 200 {object} utilities.MessageResponse "Successfully lift punishment of user"
 // @Failure 400 {object} utilities.ErrorResponse "Invalid authorization header, request body"
 // @Failure 401 {object} utilities.ErrorResponse "Invalid token"
 // @Failure 403 {object} utilities.ErrorResponse "Not logged in as Admin"
@@ -121,6 +199,12 @@ func (jc *PunishmentController) PunishUser(c *gin.Context) {
 // @Failure 500 {object} utilities.ErrorResponse "Database error"
 // @Router /punish/{user_id} [delete]
 func (jc *PunishmentController) DeletePunishmentRecord(c *gin.Context) {
+	admin, err := utilities.ExtractUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, utilities.ErrorResponse{Error: err.Error()})
+		return
+	}
+
 	userID := c.Param("user_id")
 
 	user := model.User{}
@@ -129,28 +213,60 @@ func (jc *PunishmentController) DeletePunishmentRecord(c *gin.Context) {
 		return
 	}
 
-	punishmentID := user.PunishmentID
-	var punishment model.PunishmentStruct
-	user.Punishment = nil
-	user.PunishmentID = nil
-
-	if err := jc.DB.Save(&user).Error; err != nil {
+	if err := jc.DB.Transaction(func(tx *gorm.DB) error {
+		return database.LiftPunishments(tx, user, admin.ID, c.Query("reason"))
+	}); err != nil {
 		c.JSON(http.StatusInternalServerError, utilities.ErrorResponse{
-			Error: fmt.Sprintf("Failed to update user information: %s", err.Error()),
+			Error: fmt.Sprintf("Failed to lift punishment: %s", err.Error()),
 		})
 		return
 	}
 
-	if punishmentID != nil {
-		if err := jc.DB.Where("id = ?", punishmentID).Delete(&punishment).Error; err != nil {
-			c.JSON(http.StatusInternalServerError, utilities.ErrorResponse{
-				Error: fmt.Sprintf("Failed to delete punishment record: %s", err.Error()),
-			})
-			return
-		}
-	}
-
 	c.JSON(http.StatusOK, utilities.MessageResponse{
-		Message: fmt.Sprintf("Successfully removed punishment record of %s", user.Username),
+		Message: fmt.Sprintf("Successfully lifted punishment of %s", user.Username),
 	})
 }
+
+// GetPunishmentHistory lists every punishment issued to user
+// @Summary Get punishment history of user
+// @Description Lifted and expired punishments are included, newest first.
+// @Tags Admin
+// @Produce json
+// @Param Authorization header string true "Insert your access token" default(Bearer <your access token>)
+// @Param user_id path string true "ID of user"
+// @Success 200 {object} punishmentHistoryResponse "Punishment history of user"
+// @Failure 401 {object} utilities.ErrorResponse "Invalid token"
+// @Failure 403 {object} utilities.ErrorResponse "Not logged in as Admin"
+// @Failure 404 {object} utilities.ErrorResponse "User not found"
+// @Failure 500 {object} utilities.ErrorResponse "Database error"
+// @Router /punish/{user_id} [get]
+func (jc *PunishmentController) GetPunishmentHistory(c *gin.Context) {
+	userID := c.Param("user_id")
+
+	user := model.User{}
+	if err := jc.DB.Where("id = ?", userID).First(&user).Error; err != nil {
+		c.JSON(http.StatusNotFound, utilities.ErrorResponse{Error: "User not found"})
+		return
+	}
+
+	active, err := database.ActivePunishment(jc.DB.DB, user.ID)
+	if err != nil {
+		utilities.RespondDBError(c, err)
+		return
+	}
+
+	resp := punishmentHistoryResponse{UserID: user.ID, History: []model.PunishmentStruct{}}
+	if active != nil {
+		resp.ActivePunishmentID = &active.ID
+	}
+	query := jc.DB.Where("user_id = ?", user.ID)
+	if user.PunishmentID != nil {
+		query = query.Or("id = ?", *user.PunishmentID)
+	}
+	if err := query.Order("created_at DESC, id DESC").Find(&resp.History).Error; err != nil {
+		utilities.RespondDBError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
@@ -4,6 +4,7 @@ import (
 	"HireMeMaybe-backend/internal/auth"
 	"HireMeMaybe-backend/internal/database"
 	"HireMeMaybe-backend/internal/middleware"
+	"HireMeMaybe-backend/internal/model"
 	"HireMeMaybe-backend/internal/testutil"
 	"context"
 	"net/http"
@@ -100,3 +101,56 @@ func TestDeletePunishmentRecord_Success(t *testing.T) {
 
 	assert.Equal(t, http.StatusOK, rec.Code)
 }
+
+func TestPunishmentHistory(t *testing.T) {
+	adminToken, err := auth.GetAccessToken(t, testDB, database.TestAdminUser.Username, database.TestSeedPassword)
+	assert.NoError(t, err)
+
+	r := gin.Default()
+	pc := &PunishmentController{DB: testDB}
+	r.PUT("/punish/:user_id", middleware.RequireAuth(testDB), middleware.CheckRole("admin"), pc.PunishUser)
+	r.DELETE("/punish/:user_id", middleware.RequireAuth(testDB), middleware.CheckRole("admin"), pc.DeletePunishmentRecord)
+	r.GET("/punish/:user_id", middleware.RequireAuth(testDB), middleware.CheckRole("admin"), pc.GetPunishmentHistory)
+
+	endpoint := "/punish/" + database.TestUserCPSK2.ID.String()
+	t.Cleanup(func() {
+		testDB.Model(&model.User{}).Where("id = ?", database.TestUserCPSK2.ID).Update("punishment_id", nil)
+		testDB.Where("user_id = ?", database.TestUserCPSK2.ID).Delete(&model.PunishmentStruct{})
+	})
+
+	end := time.Now().Add(24 * time.Hour).UTC().Format(time.RFC3339)
+	rec, _ := testutil.MakeJSONRequest(gin.H{"type": "suspend", "end": end, "reason": "Spam"}, adminToken, r, endpoint, http.MethodPut)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	rec, _ = testutil.MakeJSONRequest(gin.H{"type": "ban", "reason": "Spam again"}, adminToken, r, endpoint, http.MethodPut)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	// Report must be about the punished user
+	rec, _ = testutil.MakeJSONRequest(gin.H{"type": "ban", "report_type": "user", "report_id": 999999}, adminToken, r, endpoint, http.MethodPut)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	var user model.User
+	assert.NoError(t, testDB.Preload("Punishment").Where("id = ?", database.TestUserCPSK2.ID).First(&user).Error)
+	if assert.NotNil(t, user.Punishment) {
+		assert.Equal(t, model.BanPunishment, user.Punishment.PunishmentType, "Ban takes precedence over suspend")
+		assert.Equal(t, database.TestAdminUser.ID, *user.Punishment.IssuedByID)
+	}
+
+	rec, _ = testutil.MakeJSONRequest(nil, adminToken, r, endpoint+"?reason=Appeal%20accepted", http.MethodDelete)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	assert.NoError(t, testDB.Where("id = ?", database.TestUserCPSK2.ID).First(&user).Error)
+	assert.Nil(t, user.PunishmentID, "Every active punishment is lifted")
+
+	rec, resp := testutil.MakeJSONRequest(nil, adminToken, r, endpoint, http.MethodGet)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Nil(t, resp["active_punishment_id"])
+	history, _ := resp["history"].([]interface{})
+	if assert.Len(t, history, 2, "Lifted punishments are kept") {
+		latest := history[0].(map[string]interface{})
+		assert.Equal(t, model.BanPunishment, latest["type"])
+		assert.Equal(t, "Spam again", latest["reason"])
+		assert.Equal(t, database.TestAdminUser.ID.String(), latest["lifted_by"])
+		assert.Equal(t, "Appeal accepted", latest["lift_reason"])
+		assert.NotNil(t, latest["lifted_at"])
+	}
+}
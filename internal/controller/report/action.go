@@ -0,0 +1,183 @@
+package report
+
+import (
+	"HireMeMaybe-backend/internal/database"
+	"HireMeMaybe-backend/internal/mail"
+	"HireMeMaybe-backend/internal/model"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	errInvalidAction = errors.New("invalid action")
+	errActionTaken   = errors.New("report is already resolved or action was already taken")
+)
+
+// ActionRequest is moderation action taken on author of reported user or post when resolving report.
+// Days is length of suspend or ban, ban without days is permanent.
+type ActionRequest struct {
+	Type string `json:"type" binding:"required,oneof=warn hide_post delete_post suspend ban"`
+	Days int    `json:"days" binding:"min=0"`
+}
+
+// actionResult is outcome of moderation action, used to record it on reports and notify users
+type actionResult struct {
+	Author     model.User
+	Punishment *model.PunishmentStruct
+	At         time.Time
+}
+
+// record returns fields of report that record the action
+func (r actionResult) record(action ActionRequest, adminID uuid.UUID) map[string]interface{} {
+	fields := map[string]interface{}{
+		"action":       action.Type,
+		"action_by_id": adminID,
+		"action_at":    r.At,
+	}
+	if action.Days > 0 {
+		fields["action_days"] = action.Days
+	}
+	if r.Punishment != nil {
+		fields["punishment_id"] = r.Punishment.ID
+	}
+	return fields
+}
+
+// applyAction takes moderation action on author of reported target.
+// Punishment issued by suspend or ban is linked to the report reportID.
+func applyAction(tx *gorm.DB, admin model.User, targetType string, targetID interface{}, reportID uint, action ActionRequest, note string) (actionResult, error) {
+	result := actionResult{At: time.Now()}
+
+	var authorID uuid.UUID
+	switch targetType {
+	case model.ReportTypeUser:
+		authorID, _ = targetID.(uuid.UUID)
+	case model.ReportTypePost:
+		var post model.JobPost
+		if err := tx.Where("id = ?", targetID).First(&post).Error; err != nil {
+			return result, err
+		}
+		authorID = post.CompanyUserID
+	default:
+		return result, fmt.Errorf("%w: unknown report type", errInvalidAction)
+	}
+	if err := tx.Where("id = ?", authorID).First(&result.Author).Error; err != nil {
+		return result, err
+	}
+
+	switch action.Type {
+	case model.ReportActionWarn:
+		// Warning is only sent to the author
+
+	case model.ReportActionHidePost, model.ReportActionDeletePost:
+		if targetType != model.ReportTypePost {
+			return result, fmt.Errorf("%w: %s can only be taken on post report", errInvalidAction, action.Type)
+		}
+		// Removed post is archived so reports on it and the recorded action remain
+		status := model.JobPostStatusHidden
+		if action.Type == model.ReportActionDeletePost {
+			status = model.JobPostStatusArchived
+		}
+		if err := tx.Model(&model.JobPost{}).Where("id = ?", targetID).Update("status", status).Error; err != nil {
+			return result, err
+		}
+
+	case model.ReportActionSuspend, model.ReportActionBan:
+		if result.Author.Role == model.RoleAdmin {
+			return result, fmt.Errorf("%w: unable to punish admin", errInvalidAction)
+		}
+		if action.Type == model.ReportActionSuspend && action.Days == 0 {
+			return result, fmt.Errorf("%w: days is required for suspend", errInvalidAction)
+		}
+
+		reason := note
+		if reason == "" {
+			reason = fmt.Sprintf("Reported %s #%d", targetType, reportID)
+		}
+		punishment := model.PunishmentStruct{
+			UserID:         &result.Author.ID,
+			PunishmentType: action.Type,
+			PunishAt:       &result.At,
+			Reason:         reason,
+			IssuedByID:     &admin.ID,
+			ReportType:     &targetType,
+			ReportID:       &reportID,
+		}
+		if action.Days > 0 {
+			end := result.At.AddDate(0, 0, action.Days)
+			punishment.PunishEnd = &end
+		}
+		if err := database.IssuePunishment(tx, &punishment); err != nil {
+			return result, err
+		}
+		result.Punishment = &punishment
+	}
+
+	return result, nil
+}
+
+// describeAction returns human readable action for notification
+func describeAction(action ActionRequest) string {
+	switch action.Type {
+	case model.ReportActionWarn:
+		return "the author has been warned"
+	case model.ReportActionHidePost:
+		return "the job post has been hidden"
+	case model.ReportActionDeletePost:
+		return "the job post has been removed"
+	case model.ReportActionSuspend:
+		return fmt.Sprintf("the author has been suspended for %d days", action.Days)
+	case model.ReportActionBan:
+		if action.Days > 0 {
+			return fmt.Sprintf("the author has been banned for %d days", action.Days)
+		}
+		return "the author has been banned"
+	}
+	return action.Type
+}
+
+// notifyAction emails reporters that their report was acted on and warns the author if asked.
+// Mail is sent after the action has been committed, failures are only logged.
+func (jc *ReportController) notifyAction(reporterIDs []uuid.UUID, result actionResult, action ActionRequest, note string) {
+	if jc.Mailer == nil {
+		return
+	}
+
+	var reporters []model.User
+	if err := jc.DB.Where("id IN ? AND email IS NOT NULL", reporterIDs).Find(&reporters).Error; err != nil {
+		log.Printf("Failed to load reporters to notify: %s", err.Error())
+		return
+	}
+	for _, reporter := range reporters {
+		err := jc.Mailer.Send(mail.Message{
+			To:      *reporter.Email,
+			Subject: "Your report on HireMeMaybe has been acted on",
+			Body: fmt.Sprintf("Hi %s,\n\nThank you for your report. An admin has reviewed it and %s.\n\nThe HireMeMaybe team",
+				reporter.Username, describeAction(action)),
+		})
+		if err != nil {
+			log.Printf("Failed to notify reporter %s: %s", reporter.ID, err.Error())
+		}
+	}
+
+	if action.Type != model.ReportActionWarn || result.Author.Email == nil {
+		return
+	}
+	body := fmt.Sprintf("Hi %s,\n\nAn admin has reviewed reports on your account or job post and issued a warning.", result.Author.Username)
+	if note != "" {
+		body += "\n\nNote from admin: " + note
+	}
+	body += "\n\nFurther violations may lead to suspension or ban.\n\nThe HireMeMaybe team"
+	if err := jc.Mailer.Send(mail.Message{
+		To:      *result.Author.Email,
+		Subject: "Warning from HireMeMaybe moderation",
+		Body:    body,
+	}); err != nil {
+		log.Printf("Failed to send warning to %s: %s", result.Author.ID, err.Error())
+	}
+}
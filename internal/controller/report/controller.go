@@ -2,9 +2,11 @@
 package report
 
 import (
+	"HireMeMaybe-backend/internal/mail"
 	"HireMeMaybe-backend/internal/model"
 	"HireMeMaybe-backend/internal/utilities"
 	"errors"
+	"fmt"
 	"net/http"
 
 	"HireMeMaybe-backend/internal/database"
@@ -17,12 +19,15 @@ import (
 // ReportController handles report related endpoints
 type ReportController struct {
 	DB *database.DBinstanceStruct
+	// Mailer notifies users about moderation action, no one is notified if it is nil
+	Mailer mail.Sender
 }
 
 // NewReportController creates a new instance of ReportController
-func NewReportController(db *database.DBinstanceStruct) *ReportController {
+func NewReportController(db *database.DBinstanceStruct, mailer mail.Sender) *ReportController {
 	return &ReportController{
-		DB: db,
+		DB:     db,
+		Mailer: mailer,
 	}
 }
 
@@ -222,28 +227,41 @@ func (jc *ReportController) GetReport(c *gin.Context) {
 }
 
 // UpdateReportStatus updates the status of a report (either on a user or a post).
+// Resolved report can have moderation action taken on the author in the same transaction.
 // @Summary Update the status of a report
 // @Description Update the status of a report (either on a user or a post).
+// @Description Optional action (warn, hide_post, delete_post, suspend or ban) is taken on the author
+// @Description of reported user or post when status is resolved, days is length of suspend or ban.
+// @Description The action is recorded on the report and the reporter is notified. Action is taken once per pending report.
 // @Tags Report
 // @Accept json
 // @Produce json
 // @Param Authorization header string true "Insert your access token" default(Bearer <your access token>)
 // @Param id path string true "ID of the report to update"
 // @Param type path string true "Type of report to update (user or post)"
-// @Param report body object{status=string,admin_note=string} true "Report status update information"
+// @Param report body object{status=string,admin_note=string,action=ActionRequest} true "Report status update information"
 // @Success 200 {object} utilities.MessageResponse "Report status updated successfully"
-// @Failure 400 {object} utilities.ErrorResponse "Invalid request body, report not found"
+// @Failure 400 {object} utilities.ErrorResponse "Invalid request body, report not found, invalid action"
 // @Failure 401 {object} utilities.ErrorResponse "Invalid token"
 // @Failure 403 {object} utilities.ErrorResponse "User doesn't have permission to access"
+// @Failure 404 {object} utilities.ErrorResponse "Reported user or post not found"
+// @Failure 409 {object} utilities.ErrorResponse "Report is already resolved or action was already taken"
 // @Failure 500 {object} utilities.ErrorResponse "Database error"
 // @Router /report/{type}/{id} [put]
 func (jc *ReportController) UpdateReportStatus(c *gin.Context) {
+	user, err := utilities.ExtractUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, utilities.ErrorResponse{Error: err.Error()})
+		return
+	}
+
 	reportID := c.Param("id")
 	rType := c.Param("type")
 
 	var req struct {
-		Status    string `json:"status" binding:"required,oneof=pending resolved rejected"`
-		AdminNote string `json:"admin_note"`
+		Status    string         `json:"status" binding:"required,oneof=pending resolved rejected"`
+		AdminNote string         `json:"admin_note"`
+		Action    *ActionRequest `json:"action"`
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -252,8 +270,17 @@ func (jc *ReportController) UpdateReportStatus(c *gin.Context) {
 		})
 		return
 	}
+	if req.Action != nil && req.Status != model.ReportStatusResolved {
+		c.JSON(http.StatusBadRequest, utilities.ErrorResponse{
+			Error: "Action can only be taken when report is resolved",
+		})
+		return
+	}
 
 	var report model.UpdateableReport
+	var common *model.ReportCommon
+	var id uint
+	var targetID interface{}
 
 	switch rType {
 	case "user":
@@ -268,7 +295,7 @@ func (jc *ReportController) UpdateReportStatus(c *gin.Context) {
 			utilities.RespondDBError(c, err)
 			return
 		}
-		report = &userReport
+		report, common, id, targetID = &userReport, &userReport.ReportCommon, userReport.ID, userReport.ReportedUserID
 	case "post":
 		var postReport model.ReportOnPost
 		if err := jc.DB.Where("id = ?", reportID).First(&postReport).Error; err != nil {
@@ -281,7 +308,7 @@ func (jc *ReportController) UpdateReportStatus(c *gin.Context) {
 			utilities.RespondDBError(c, err)
 			return
 		}
-		report = &postReport
+		report, common, id, targetID = &postReport, &postReport.ReportCommon, postReport.ID, postReport.ReportedPostID
 	default:
 		c.JSON(http.StatusBadRequest, utilities.ErrorResponse{
 			Error: "Invalid report type",
@@ -289,19 +316,69 @@ func (jc *ReportController) UpdateReportStatus(c *gin.Context) {
 		return
 	}
 
-	err := report.UpdateStatus(req.Status, req.AdminNote)
-	if err != nil {
+	if req.Action != nil && (common.Action != nil || common.Status != model.ReportStatusPending) {
+		c.JSON(http.StatusConflict, utilities.ErrorResponse{Error: errActionTaken.Error()})
+		return
+	}
+
+	if err := report.UpdateStatus(req.Status, req.AdminNote); err != nil {
 		c.JSON(http.StatusBadRequest, utilities.ErrorResponse{
 			Error: "Invalid status value",
 		})
 		return
 	}
-	if err := jc.DB.Save(report).Error; err != nil {
-		utilities.RespondDBError(c, err)
+
+	var result actionResult
+	if err := jc.DB.Transaction(func(tx *gorm.DB) error {
+		if req.Action != nil {
+			// Admin acting on the same report concurrently waits on the row, then finds it no longer pending
+			taken := tx.Model(report).Where("status = ? AND action IS NULL", model.ReportStatusPending).
+				Update("status", req.Status)
+			if taken.Error != nil {
+				return taken.Error
+			}
+			if taken.RowsAffected == 0 {
+				return errActionTaken
+			}
+		}
+		if err := tx.Save(report).Error; err != nil {
+			return err
+		}
+		if req.Action == nil {
+			return nil
+		}
+
+		result, err = applyAction(tx, user, rType, targetID, id, *req.Action, req.AdminNote)
+		if err != nil {
+			return err
+		}
+		return tx.Model(report).Updates(result.record(*req.Action, user.ID)).Error
+	}); err != nil {
+		respondActionError(c, err)
 		return
 	}
 
+	if req.Action != nil {
+		jc.notifyAction([]uuid.UUID{common.Reporter}, result, *req.Action, req.AdminNote)
+	}
+
 	c.JSON(http.StatusOK, utilities.MessageResponse{
 		Message: "Report status updated successfully",
 	})
 }
+
+// respondActionError responds to error from resolving report with moderation action
+func respondActionError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, errInvalidAction):
+		c.JSON(http.StatusBadRequest, utilities.ErrorResponse{Error: err.Error()})
+	case errors.Is(err, errActionTaken):
+		c.JSON(http.StatusConflict, utilities.ErrorResponse{Error: err.Error()})
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, utilities.ErrorResponse{Error: "Reported user or post not found"})
+	default:
+		c.JSON(http.StatusInternalServerError, utilities.ErrorResponse{
+			Error: fmt.Sprintf("Failed to update report: %s", err.Error()),
+		})
+	}
+}
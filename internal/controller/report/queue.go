@@ -0,0 +1,481 @@
+package report
+
+import (
+	"HireMeMaybe-backend/internal/model"
+	"HireMeMaybe-backend/internal/utilities"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Weights of case priority. Distinct reporters weigh most so one user reporting repeatedly can't flood the queue,
+// and waiting cases slowly rise so they are not starved.
+const (
+	reporterWeight  = 10.0
+	repeatWeight    = 2.0
+	ageWeightPerDay = 1.0
+	maxAgeDays      = 30.0
+)
+
+var (
+	errCaseClaimed  = errors.New("case is claimed by another admin")
+	errCaseNotFound = errors.New("case not found")
+)
+
+// caseSorts maps sort query to order of the queue
+var caseSorts = map[string]string{
+	"priority": "priority DESC",
+	"oldest":   "first_report_time ASC",
+	"newest":   "last_report_time DESC",
+	"count":    "report_count DESC",
+}
+
+// ReportCase is every pending report on the same target grouped as one moderation case
+type ReportCase struct {
+	TargetType      string     `json:"target_type"`
+	TargetID        string     `json:"target_id"`
+	ReportCount     int64      `json:"report_count"`
+	ReporterCount   int64      `json:"reporter_count"`
+	FirstReportTime int64      `json:"first_report_time"`
+	LastReportTime  int64      `json:"last_report_time"`
+	Priority        float64    `json:"priority"`
+	AssigneeID      *uuid.UUID `json:"assignee_id"`
+	ClaimedAt       *time.Time `json:"claimed_at"`
+}
+
+// reportCaseListResponse is paginated moderation queue
+type reportCaseListResponse struct {
+	Cases []ReportCase `json:"cases"`
+	utilities.Pagination
+}
+
+// reportCaseResponse is moderation case with its pending reports
+type reportCaseResponse struct {
+	ReportCase
+	Reports interface{} `json:"reports"`
+}
+
+// resolveCaseRequest is decision of admin on every pending report of the case
+type resolveCaseRequest struct {
+	Status    string         `json:"status" binding:"required,oneof=resolved rejected"`
+	AdminNote string         `json:"admin_note"`
+	Action    *ActionRequest `json:"action"`
+}
+
+// caseQuery returns query of moderation cases, one row per reported target
+func (jc *ReportController) caseQuery() *gorm.DB {
+	pending := jc.DB.DB.Raw(`SELECT CAST(? AS text) AS target_type, CAST(reported_post_id AS text) AS target_id, reporter, report_time
+		FROM report_on_posts WHERE status = ?
+		UNION ALL
+		SELECT CAST(? AS text) AS target_type, CAST(reported_user_id AS text) AS target_id, reporter, report_time
+		FROM report_on_users WHERE status = ?`,
+		model.ReportTypePost, model.ReportStatusPending, model.ReportTypeUser, model.ReportStatusPending)
+
+	priority := fmt.Sprintf(`CAST(COUNT(DISTINCT r.reporter) * %g + (COUNT(*) - COUNT(DISTINCT r.reporter)) * %g
+		+ LEAST((EXTRACT(EPOCH FROM NOW()) - MIN(r.report_time)) / 86400, %g) * %g AS double precision)`,
+		reporterWeight, repeatWeight, maxAgeDays, ageWeightPerDay)
+
+	return jc.DB.Table("(?) AS r", pending).
+		Select(`r.target_type, r.target_id,
+			COUNT(*) AS report_count,
+			COUNT(DISTINCT r.reporter) AS reporter_count,
+			MIN(r.report_time) AS first_report_time,
+			MAX(r.report_time) AS last_report_time,
+			` + priority + ` AS priority,
+			report_claims.admin_id AS assignee_id,
+			report_claims.claimed_at`).
+		Joins("LEFT JOIN report_claims ON report_claims.target_type = r.target_type AND report_claims.target_id = r.target_id").
+		Group("r.target_type, r.target_id, report_claims.admin_id, report_claims.claimed_at")
+}
+
+// parseTarget reads report type and target ID of moderation case from path parameters
+func parseTarget(c *gin.Context) (string, interface{}, error) {
+	targetType := c.Param("type")
+	switch targetType {
+	case model.ReportTypeUser:
+		id, err := uuid.Parse(c.Param("target_id"))
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid user ID")
+		}
+		return targetType, id, nil
+	case model.ReportTypePost:
+		id, err := strconv.ParseUint(c.Param("target_id"), 10, 64)
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid post ID")
+		}
+		return targetType, uint(id), nil
+	}
+	return "", nil, fmt.Errorf("invalid report type")
+}
+
+// pendingReports returns query of pending reports on target of given type, with model to scan them into
+func pendingReports(tx *gorm.DB, targetType string, targetID interface{}) (*gorm.DB, interface{}) {
+	if targetType == model.ReportTypeUser {
+		return tx.Model(&model.ReportOnUser{}).
+			Where("reported_user_id = ? AND status = ?", targetID, model.ReportStatusPending), &[]model.ReportOnUser{}
+	}
+	return tx.Model(&model.ReportOnPost{}).
+		Where("reported_post_id = ? AND status = ?", targetID, model.ReportStatusPending), &[]model.ReportOnPost{}
+}
+
+// findCase returns moderation case of target, nil if the target has no pending report
+func (jc *ReportController) findCase(targetType string, targetID interface{}) (*ReportCase, error) {
+	var cases []ReportCase
+	if err := jc.DB.Table("(?) AS cases", jc.caseQuery()).
+		Where("target_type = ? AND target_id = ?", targetType, fmt.Sprint(targetID)).
+		Scan(&cases).Error; err != nil {
+		return nil, err
+	}
+	if len(cases) == 0 {
+		return nil, nil
+	}
+	return &cases[0], nil
+}
+
+// GetReportCases returns moderation queue of pending reports grouped by reported target
+// @Summary Get moderation queue
+// @Description Pending reports on the same user or post are grouped as one case.
+// @Description Priority rises with number of distinct reporters, repeated reports and waiting time.
+// @Tags Report
+// @Produce json
+// @Param Authorization header string true "Insert your access token" default(Bearer <your access token>)
+// @Param type query string false "Only 'user' or 'post' cases"
+// @Param assignee query string false "'me', 'none' or ID of admin who claimed the case"
+// @Param sort query string false "priority, oldest, newest or count" default(priority)
+// @Param page query integer false "Page number, start from 1" default(1)
+// @Param limit query integer false "Cases per page, max 100" default(20)
+// @Success 200 {object} reportCaseListResponse
+// @Failure 400 {object} utilities.ErrorResponse "Invalid query"
+// @Failure 401 {object} utilities.ErrorResponse "Invalid token"
+// @Failure 403 {object} utilities.ErrorResponse "User doesn't have permission to access"
+// @Failure 500 {object} utilities.ErrorResponse "Database error"
+// @Router /report/cases [get]
+func (jc *ReportController) GetReportCases(c *gin.Context) {
+	user, err := utilities.ExtractUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, utilities.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	pagination, err := utilities.ParsePagination(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utilities.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	order, ok := caseSorts[c.DefaultQuery("sort", "priority")]
+	if !ok {
+		c.JSON(http.StatusBadRequest, utilities.ErrorResponse{Error: "sort can be only 'priority', 'oldest', 'newest' or 'count'"})
+		return
+	}
+
+	query := jc.DB.Table("(?) AS cases", jc.caseQuery())
+	switch targetType := c.Query("type"); targetType {
+	case "":
+	case model.ReportTypeUser, model.ReportTypePost:
+		query = query.Where("target_type = ?", targetType)
+	default:
+		c.JSON(http.StatusBadRequest, utilities.ErrorResponse{Error: "type can be only 'user' or 'post'"})
+		return
+	}
+	switch assignee := c.Query("assignee"); assignee {
+	case "":
+	case "me":
+		query = query.Where("assignee_id = ?", user.ID)
+	case "none":
+		query = query.Where("assignee_id IS NULL")
+	default:
+		adminID, err := uuid.Parse(assignee)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, utilities.ErrorResponse{Error: "Invalid assignee"})
+			return
+		}
+		query = query.Where("assignee_id = ?", adminID)
+	}
+
+	// Make query reusable for both counting and fetching
+	query = query.Session(&gorm.Session{})
+
+	if err := query.Count(&pagination.Total).Error; err != nil {
+		utilities.RespondDBError(c, err)
+		return
+	}
+
+	resp := reportCaseListResponse{Cases: []ReportCase{}, Pagination: pagination}
+	if err := query.Order(order + ", target_type, target_id").
+		Offset(pagination.Offset()).
+		Limit(pagination.Limit).
+		Scan(&resp.Cases).Error; err != nil {
+		utilities.RespondDBError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetReportCase returns moderation case with its pending reports, oldest first
+// @Summary Get moderation case
+// @Tags Report
+// @Produce json
+// @Param Authorization header string true "Insert your access token" default(Bearer <your access token>)
+// @Param type path string true "Type of reported target (user or post)"
+// @Param target_id path string true "ID of reported user or post"
+// @Success 200 {object} reportCaseResponse
+// @Failure 400 {object} utilities.ErrorResponse "Invalid report type or target ID"
+// @Failure 401 {object} utilities.ErrorResponse "Invalid token"
+// @Failure 403 {object} utilities.ErrorResponse "User doesn't have permission to access"
+// @Failure 404 {object} utilities.ErrorResponse "Case not found"
+// @Failure 500 {object} utilities.ErrorResponse "Database error"
+// @Router /report/cases/{type}/{target_id} [get]
+func (jc *ReportController) GetReportCase(c *gin.Context) {
+	targetType, targetID, err := parseTarget(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utilities.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	reportCase, err := jc.findCase(targetType, targetID)
+	if err != nil {
+		utilities.RespondDBError(c, err)
+		return
+	}
+	if reportCase == nil {
+		c.JSON(http.StatusNotFound, utilities.ErrorResponse{Error: "Case not found"})
+		return
+	}
+
+	query, reports := pendingReports(jc.DB.DB, targetType, targetID)
+	if err := query.Order("report_time ASC, id ASC").Find(reports).Error; err != nil {
+		utilities.RespondDBError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, reportCaseResponse{ReportCase: *reportCase, Reports: reports})
+}
+
+// ClaimReportCase assigns moderation case to current admin
+// @Summary Claim moderation case
+// @Description Claiming case already claimed by current admin has no effect.
+// @Tags Report
+// @Produce json
+// @Param Authorization header string true "Insert your access token" default(Bearer <your access token>)
+// @Param type path string true "Type of reported target (user or post)"
+// @Param target_id path string true "ID of reported user or post"
+// @Success 200 {object} model.ReportClaim "Claim of the case"
+// @Failure 400 {object} utilities.ErrorResponse "Invalid report type or target ID"
+// @Failure 401 {object} utilities.ErrorResponse "Invalid token"
+// @Failure 403 {object} utilities.ErrorResponse "User doesn't have permission to access"
+// @Failure 404 {object} utilities.ErrorResponse "Case not found"
+// @Failure 409 {object} utilities.ErrorResponse "Case is claimed by another admin"
+// @Failure 500 {object} utilities.ErrorResponse "Database error"
+// @Router /report/cases/{type}/{target_id}/claim [post]
+func (jc *ReportController) ClaimReportCase(c *gin.Context) {
+	user, err := utilities.ExtractUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, utilities.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	targetType, targetID, err := parseTarget(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utilities.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	reportCase, err := jc.findCase(targetType, targetID)
+	if err != nil {
+		utilities.RespondDBError(c, err)
+		return
+	}
+	if reportCase == nil {
+		c.JSON(http.StatusNotFound, utilities.ErrorResponse{Error: "Case not found"})
+		return
+	}
+
+	// Existing claim is kept, so concurrent claims can't take the case from each other
+	claim := model.ReportClaim{TargetType: targetType, TargetID: fmt.Sprint(targetID), AdminID: user.ID}
+	if err := jc.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(&claim).Error; err != nil {
+		utilities.RespondDBError(c, err)
+		return
+	}
+	if err := jc.DB.Where("target_type = ? AND target_id = ?", claim.TargetType, claim.TargetID).First(&claim).Error; err != nil {
+		utilities.RespondDBError(c, err)
+		return
+	}
+	if claim.AdminID != user.ID {
+		c.JSON(http.StatusConflict, utilities.ErrorResponse{Error: errCaseClaimed.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, claim)
+}
+
+// ReleaseReportCase removes claim of current admin from moderation case
+// @Summary Release moderation case
+// @Tags Report
+// @Produce json
+// @Param Authorization header string true "Insert your access token" default(Bearer <your access token>)
+// @Param type path string true "Type of reported target (user or post)"
+// @Param target_id path string true "ID of reported user or post"
+// @Success 200 {object} utilities.MessageResponse "Case released"
+// @Failure 400 {object} utilities.ErrorResponse "Invalid report type or target ID"
+// @Failure 401 {object} utilities.ErrorResponse "Invalid token"
+// @Failure 403 {object} utilities.ErrorResponse "User doesn't have permission to access"
+// @Failure 404 {object} utilities.ErrorResponse "Case is not claimed by current admin"
+// @Failure 500 {object} utilities.ErrorResponse "Database error"
+// @Router /report/cases/{type}/{target_id}/claim [delete]
+func (jc *ReportController) ReleaseReportCase(c *gin.Context) {
+	user, err := utilities.ExtractUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, utilities.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	targetType, targetID, err := parseTarget(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utilities.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	result := jc.DB.Where("target_type = ? AND target_id = ? AND admin_id = ?", targetType, fmt.Sprint(targetID), user.ID).
+		Delete(&model.ReportClaim{})
+	if result.Error != nil {
+		utilities.RespondDBError(c, result.Error)
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, utilities.ErrorResponse{Error: "Case is not claimed by you"})
+		return
+	}
+
+	c.JSON(http.StatusOK, utilities.MessageResponse{Message: "Case released"})
+}
+
+// ResolveReportCase resolves or rejects every pending report of moderation case at once
+// @Summary Resolve moderation case
+// @Description Every pending report on the target gets the status and admin note, and claim of the case is removed.
+// @Description Case claimed by another admin can't be resolved.
+// @Description Optional action is taken once on the author when status is resolved, recorded on every report
+// @Description and every reporter is notified.
+// @Tags Report
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Insert your access token" default(Bearer <your access token>)
+// @Param type path string true "Type of reported target (user or post)"
+// @Param target_id path string true "ID of reported user or post"
+// @Param decision body resolveCaseRequest true "Status ('resolved' or 'rejected') and admin note"
+// @Success 200 {object} object{message=string,resolved_reports=integer} "Case resolved"
+// @Failure 400 {object} utilities.ErrorResponse "Invalid request body, report type, target ID or action"
+// @Failure 401 {object} utilities.ErrorResponse "Invalid token"
+// @Failure 403 {object} utilities.ErrorResponse "User doesn't have permission to access"
+// @Failure 404 {object} utilities.ErrorResponse "Case not found"
+// @Failure 409 {object} utilities.ErrorResponse "Case is claimed by another admin"
+// @Failure 500 {object} utilities.ErrorResponse "Database error"
+// @Router /report/cases/{type}/{target_id} [put]
+func (jc *ReportController) ResolveReportCase(c *gin.Context) {
+	user, err := utilities.ExtractUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, utilities.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	targetType, targetID, err := parseTarget(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utilities.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	var req resolveCaseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utilities.ErrorResponse{
+			Error: fmt.Sprintf("Invalid request body: %s", err.Error()),
+		})
+		return
+	}
+
+	if req.Action != nil && req.Status != model.ReportStatusResolved {
+		c.JSON(http.StatusBadRequest, utilities.ErrorResponse{
+			Error: "Action can only be taken when report is resolved",
+		})
+		return
+	}
+
+	var resolved int64
+	var reporterIDs []uuid.UUID
+	var result actionResult
+	if err := jc.DB.Transaction(func(tx *gorm.DB) error {
+		var claim model.ReportClaim
+		err := tx.Where("target_type = ? AND target_id = ?", targetType, fmt.Sprint(targetID)).First(&claim).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		if err == nil && claim.AdminID != user.ID {
+			return errCaseClaimed
+		}
+
+		query, _ := pendingReports(tx, targetType, targetID)
+		var ids []uint
+		// Admin resolving the same case concurrently waits here, then finds no pending report
+		// instead of taking the action again
+		if err := query.Session(&gorm.Session{}).Clauses(clause.Locking{Strength: "UPDATE"}).
+			Order("id").Pluck("id", &ids).Error; err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			return errCaseNotFound
+		}
+		if err := query.Session(&gorm.Session{}).Distinct("reporter").Pluck("reporter", &reporterIDs).Error; err != nil {
+			return err
+		}
+
+		fields := map[string]interface{}{
+			"status":     req.Status,
+			"admin_note": req.AdminNote,
+		}
+		if req.Action != nil {
+			// Punishment is linked to the first report of the case
+			result, err = applyAction(tx, user, targetType, targetID, ids[0], *req.Action, req.AdminNote)
+			if err != nil {
+				return err
+			}
+			for k, v := range result.record(*req.Action, user.ID) {
+				fields[k] = v
+			}
+		}
+
+		updated := query.Where("id IN ?", ids).Updates(fields)
+		if updated.Error != nil {
+			return updated.Error
+		}
+		resolved = updated.RowsAffected
+
+		return tx.Where("target_type = ? AND target_id = ?", targetType, fmt.Sprint(targetID)).
+			Delete(&model.ReportClaim{}).Error
+	}); err != nil {
+		switch {
+		case errors.Is(err, errCaseClaimed):
+			c.JSON(http.StatusConflict, utilities.ErrorResponse{Error: err.Error()})
+		case errors.Is(err, errCaseNotFound):
+			c.JSON(http.StatusNotFound, utilities.ErrorResponse{Error: "Case not found"})
+		default:
+			respondActionError(c, err)
+		}
+		return
+	}
+
+	if req.Action != nil {
+		jc.notifyAction(reporterIDs, result, *req.Action, req.AdminNote)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":          fmt.Sprintf("Case %s", req.Status),
+		"resolved_reports": resolved,
+	})
+}
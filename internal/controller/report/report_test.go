@@ -2,7 +2,9 @@ package report
 
 import (
 	"HireMeMaybe-backend/internal/auth"
+	"HireMeMaybe-backend/internal/controller/jobpost"
 	"HireMeMaybe-backend/internal/database"
+	"HireMeMaybe-backend/internal/mail"
 	"HireMeMaybe-backend/internal/middleware"
 	"HireMeMaybe-backend/internal/model"
 	"HireMeMaybe-backend/internal/testutil"
@@ -373,3 +375,217 @@ func TestUpdateReportStatus_InvalidReportType(t *testing.T) {
 	assert.Equal(t, http.StatusBadRequest, rec.Code)
 	assert.Contains(t, updateResp["error"], "Invalid report type")
 }
+
+type recordingSender struct {
+	sent []mail.Message
+}
+
+func (s *recordingSender) Send(msg mail.Message) error {
+	s.sent = append(s.sent, msg)
+	return nil
+}
+
+func reportCaseRouter(sender mail.Sender) *gin.Engine {
+	r := gin.Default()
+	jc := NewReportController(testDB, sender)
+	r.PUT("/report/:type/:id", middleware.RequireAuth(testDB), middleware.CheckRole(model.RoleAdmin), jc.UpdateReportStatus)
+	r.GET("/report/cases", middleware.RequireAuth(testDB), middleware.CheckRole(model.RoleAdmin), jc.GetReportCases)
+	r.GET("/report/cases/:type/:target_id", middleware.RequireAuth(testDB), middleware.CheckRole(model.RoleAdmin), jc.GetReportCase)
+	r.PUT("/report/cases/:type/:target_id", middleware.RequireAuth(testDB), middleware.CheckRole(model.RoleAdmin), jc.ResolveReportCase)
+	r.POST("/report/cases/:type/:target_id/claim", middleware.RequireAuth(testDB), middleware.CheckRole(model.RoleAdmin), jc.ClaimReportCase)
+	r.DELETE("/report/cases/:type/:target_id/claim", middleware.RequireAuth(testDB), middleware.CheckRole(model.RoleAdmin), jc.ReleaseReportCase)
+	return r
+}
+
+// Helper: find case of target in moderation queue response.
+func findCase(resp map[string]interface{}, targetType string, targetID string) map[string]interface{} {
+	cases, _ := resp["cases"].([]interface{})
+	for _, c := range cases {
+		reportCase := c.(map[string]interface{})
+		if reportCase["target_type"] == targetType && reportCase["target_id"] == targetID {
+			return reportCase
+		}
+	}
+	return nil
+}
+
+func TestReportCase_GroupClaimAndResolve(t *testing.T) {
+	adminToken, err := auth.GetAccessToken(t, testDB, database.TestAdminUser.Username, database.TestSeedPassword)
+	assert.NoError(t, err)
+	r := reportCaseRouter(nil)
+
+	postID := strconv.FormatUint(uint64(database.TestJobPost3.ID), 10)
+	for _, reporter := range []model.User{database.TestUserCPSK1, database.TestUserCPSK1, database.TestUserCPSK2} {
+		report := model.ReportOnPost{
+			ReportedPostID: database.TestJobPost3.ID,
+			ReportCommon:   model.ReportCommon{Reporter: reporter.ID, Reason: "Scam"},
+		}
+		assert.NoError(t, testDB.Create(&report).Error)
+	}
+	t.Cleanup(func() {
+		testDB.Where("reported_post_id = ?", database.TestJobPost3.ID).Delete(&model.ReportOnPost{})
+		testDB.Where("target_type = ?", model.ReportTypePost).Delete(&model.ReportClaim{})
+	})
+
+	rec, resp := testutil.MakeJSONRequest(nil, adminToken, r, "/report/cases?type=post&limit=100", http.MethodGet)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	reportCase := findCase(resp, model.ReportTypePost, postID)
+	if assert.NotNil(t, reportCase, "Reports on the same post are one case") {
+		assert.EqualValues(t, 3, reportCase["report_count"])
+		assert.EqualValues(t, 2, reportCase["reporter_count"])
+		assert.GreaterOrEqual(t, reportCase["priority"], 2*reporterWeight+repeatWeight)
+		assert.Nil(t, reportCase["assignee_id"])
+	}
+
+	rec, _ = testutil.MakeJSONRequest(nil, adminToken, r, "/report/cases?sort=random", http.MethodGet)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	endpoint := "/report/cases/post/" + postID
+	rec, _ = testutil.MakeJSONRequest(nil, adminToken, r, endpoint+"/claim", http.MethodPost)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	rec, resp = testutil.MakeJSONRequest(nil, adminToken, r, "/report/cases?assignee=me&limit=100", http.MethodGet)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.NotNil(t, findCase(resp, model.ReportTypePost, postID))
+
+	rec, resp = testutil.MakeJSONRequest(nil, adminToken, r, endpoint, http.MethodGet)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Len(t, resp["reports"], 3)
+
+	rec, resp = testutil.MakeJSONRequest(gin.H{"status": "resolved", "admin_note": "Post removed"}, adminToken, r, endpoint, http.MethodPut)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.EqualValues(t, 3, resp["resolved_reports"])
+
+	var pending int64
+	testDB.Model(&model.ReportOnPost{}).Where("reported_post_id = ? AND status = ?", database.TestJobPost3.ID, model.ReportStatusPending).Count(&pending)
+	assert.Zero(t, pending, "Every grouped report is resolved")
+	rec, _ = testutil.MakeJSONRequest(nil, adminToken, r, endpoint, http.MethodGet)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestReportCase_ClaimedByAnotherAdmin(t *testing.T) {
+	adminToken, err := auth.GetAccessToken(t, testDB, database.TestAdminUser.Username, database.TestSeedPassword)
+	assert.NoError(t, err)
+	r := reportCaseRouter(nil)
+
+	report := model.ReportOnUser{
+		ReportedUserID: database.TestUserCompany2.ID,
+		ReportCommon:   model.ReportCommon{Reporter: database.TestUserCPSK2.ID, Reason: "Fake company"},
+	}
+	assert.NoError(t, testDB.Create(&report).Error)
+	claim := model.ReportClaim{TargetType: model.ReportTypeUser, TargetID: database.TestUserCompany2.ID.String(), AdminID: database.TestUserCPSK1.ID}
+	assert.NoError(t, testDB.Create(&claim).Error)
+	t.Cleanup(func() {
+		testDB.Where("id = ?", report.ID).Delete(&model.ReportOnUser{})
+		testDB.Where("target_type = ? AND target_id = ?", claim.TargetType, claim.TargetID).Delete(&model.ReportClaim{})
+	})
+
+	endpoint := "/report/cases/user/" + database.TestUserCompany2.ID.String()
+	rec, _ := testutil.MakeJSONRequest(nil, adminToken, r, endpoint+"/claim", http.MethodPost)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	rec, _ = testutil.MakeJSONRequest(nil, adminToken, r, endpoint+"/claim", http.MethodDelete)
+	assert.Equal(t, http.StatusNotFound, rec.Code, "Claim of another admin can't be released")
+	rec, _ = testutil.MakeJSONRequest(gin.H{"status": "rejected"}, adminToken, r, endpoint, http.MethodPut)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+
+	rec, _ = testutil.MakeJSONRequest(nil, adminToken, r, "/report/cases/user/not-a-uuid", http.MethodGet)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestUpdateReportStatus_SuspendAuthorAndNotifyReporter(t *testing.T) {
+	adminToken, err := auth.GetAccessToken(t, testDB, database.TestAdminUser.Username, database.TestSeedPassword)
+	assert.NoError(t, err)
+	sender := &recordingSender{}
+	r := reportCaseRouter(sender)
+
+	email := "report_reporter@example.com"
+	assert.NoError(t, testDB.Table("users").Where("id = ?", database.TestUserCPSK1.ID).Update("email", email).Error)
+	report := model.ReportOnUser{
+		ReportedUserID: database.TestUserCompany2.ID,
+		ReportCommon:   model.ReportCommon{Reporter: database.TestUserCPSK1.ID, Reason: "Asking for money"},
+	}
+	assert.NoError(t, testDB.Create(&report).Error)
+	t.Cleanup(func() {
+		testDB.Table("users").Where("id = ?", database.TestUserCPSK1.ID).Update("email", database.TestUserCPSK1.Email)
+		testDB.Model(&model.User{}).Where("id = ?", database.TestUserCompany2.ID).Update("punishment_id", nil)
+		testDB.Where("id = ?", report.ID).Delete(&model.ReportOnUser{})
+		testDB.Where("user_id = ?", database.TestUserCompany2.ID).Delete(&model.PunishmentStruct{})
+	})
+
+	endpoint := "/report/user/" + strconv.FormatUint(uint64(report.ID), 10)
+	rec, _ := testutil.MakeJSONRequest(gin.H{"status": "rejected", "action": gin.H{"type": "ban"}}, adminToken, r, endpoint, http.MethodPut)
+	assert.Equal(t, http.StatusBadRequest, rec.Code, "Action requires resolved status")
+	rec, _ = testutil.MakeJSONRequest(gin.H{"status": "resolved", "action": gin.H{"type": "hide_post"}}, adminToken, r, endpoint, http.MethodPut)
+	assert.Equal(t, http.StatusBadRequest, rec.Code, "User report can't hide post")
+	rec, _ = testutil.MakeJSONRequest(gin.H{"status": "resolved", "action": gin.H{"type": "suspend"}}, adminToken, r, endpoint, http.MethodPut)
+	assert.Equal(t, http.StatusBadRequest, rec.Code, "Suspend requires days")
+	assert.Empty(t, sender.sent)
+
+	rec, _ = testutil.MakeJSONRequest(gin.H{"status": "resolved", "admin_note": "Scam", "action": gin.H{"type": "suspend", "days": 7}},
+		adminToken, r, endpoint, http.MethodPut)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	assert.NoError(t, testDB.Where("id = ?", report.ID).First(&report).Error)
+	assert.Equal(t, model.ReportStatusResolved, report.Status)
+	if assert.NotNil(t, report.Action) && assert.NotNil(t, report.PunishmentID) {
+		assert.Equal(t, model.ReportActionSuspend, *report.Action)
+		assert.Equal(t, 7, *report.ActionDays)
+
+		var user model.User
+		assert.NoError(t, testDB.Preload("Punishment").Where("id = ?", database.TestUserCompany2.ID).First(&user).Error)
+		if assert.NotNil(t, user.Punishment) {
+			assert.Equal(t, *report.PunishmentID, user.Punishment.ID)
+			assert.Equal(t, model.SuspendPunishment, user.Punishment.PunishmentType)
+			assert.Equal(t, report.ID, *user.Punishment.ReportID)
+		}
+	}
+
+	if assert.Len(t, sender.sent, 1) {
+		assert.Equal(t, email, sender.sent[0].To)
+		assert.Contains(t, sender.sent[0].Body, "suspended for 7 days")
+	}
+
+	// Action is taken once per report
+	rec, _ = testutil.MakeJSONRequest(gin.H{"status": "resolved", "action": gin.H{"type": "ban"}}, adminToken, r, endpoint, http.MethodPut)
+	assert.Equal(t, http.StatusConflict, rec.Code, rec.Body.String())
+	assert.Len(t, sender.sent, 1)
+}
+
+func TestReportCase_ResolveWithHidePost(t *testing.T) {
+	adminToken, err := auth.GetAccessToken(t, testDB, database.TestAdminUser.Username, database.TestSeedPassword)
+	assert.NoError(t, err)
+	r := reportCaseRouter(&recordingSender{})
+	jc := &jobpost.JobPostController{DB: testDB}
+	r.GET("/jobpost/:id", middleware.RequireAuth(testDB), jc.GetPostByID)
+
+	for _, reporter := range []model.User{database.TestUserCPSK1, database.TestUserCPSK2} {
+		report := model.ReportOnPost{
+			ReportedPostID: database.TestJobPost2.ID,
+			ReportCommon:   model.ReportCommon{Reporter: reporter.ID, Reason: "Misleading salary"},
+		}
+		assert.NoError(t, testDB.Create(&report).Error)
+	}
+	t.Cleanup(func() {
+		testDB.Where("reported_post_id = ?", database.TestJobPost2.ID).Delete(&model.ReportOnPost{})
+		testDB.Model(&model.JobPost{}).Where("id = ?", database.TestJobPost2.ID).Update("status", model.JobPostStatusPublished)
+	})
+
+	postID := strconv.FormatUint(uint64(database.TestJobPost2.ID), 10)
+	rec, resp := testutil.MakeJSONRequest(gin.H{"status": "resolved", "action": gin.H{"type": "hide_post"}}, adminToken, r,
+		"/report/cases/post/"+postID, http.MethodPut)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.EqualValues(t, 2, resp["resolved_reports"])
+
+	var acted int64
+	testDB.Model(&model.ReportOnPost{}).Where("reported_post_id = ? AND action = ?", database.TestJobPost2.ID, model.ReportActionHidePost).Count(&acted)
+	assert.EqualValues(t, 2, acted, "Action is recorded on every grouped report")
+
+	cpskToken, err := auth.GetAccessToken(t, testDB, database.TestUserCPSK1.Username, database.TestSeedPassword)
+	assert.NoError(t, err)
+	rec, _ = testutil.MakeJSONRequest(nil, cpskToken, r, "/jobpost/"+postID, http.MethodGet)
+	assert.Equal(t, http.StatusNotFound, rec.Code, "Hidden post is not visible to others")
+
+	companyToken, err := auth.GetAccessToken(t, testDB, database.TestUserCompany1.Username, database.TestSeedPassword)
+	assert.NoError(t, err)
+	rec, _ = testutil.MakeJSONRequest(nil, companyToken, r, "/jobpost/"+postID, http.MethodGet)
+	assert.Equal(t, http.StatusOK, rec.Code, "Hidden post is visible to its company")
+}
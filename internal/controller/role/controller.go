@@ -0,0 +1,268 @@
+// Package role provides HTTP handlers for managing custom roles and their permissions.
+package role
+
+import (
+	"HireMeMaybe-backend/internal/database"
+	"HireMeMaybe-backend/internal/model"
+	"HireMeMaybe-backend/internal/policy"
+	"HireMeMaybe-backend/internal/utilities"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var roleNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_-]{0,31}$`)
+
+// RoleController handles custom role related endpoints
+type RoleController struct {
+	DB *database.DBinstanceStruct
+}
+
+// NewRoleController creates a new instance of RoleController
+func NewRoleController(db *database.DBinstanceStruct) *RoleController {
+	return &RoleController{
+		DB: db,
+	}
+}
+
+type roleInfo struct {
+	Name        string              `json:"name"`
+	Description string              `json:"description"`
+	Builtin     bool                `json:"builtin"`
+	Permissions []policy.Permission `json:"permissions"`
+}
+
+type rolesResponse struct {
+	Permissions []policy.Permission `json:"permissions"`
+	Roles       []roleInfo          `json:"roles"`
+}
+
+type roleRequest struct {
+	Description string              `json:"description"`
+	Permissions []policy.Permission `json:"permissions" binding:"required"`
+}
+
+// GetRoles function returns every known permission, built-in roles and custom roles
+// @Summary Get roles and their permissions
+// @Description Only user with role:manage permission can access this endpoint
+// @Tags Role
+// @Produce json
+// @Param Authorization header string true "Insert your access token" default(Bearer <your access token>)
+// @Success 200 {object} rolesResponse
+// @Failure 401 {object} utilities.ErrorResponse "Invalid token"
+// @Failure 403 {object} utilities.ErrorResponse "Do not have permission"
+// @Failure 500 {object} utilities.ErrorResponse "Database error"
+// @Router /roles [get]
+func (rc *RoleController) GetRoles(c *gin.Context) {
+	resp := rolesResponse{Permissions: policy.AllPermissions()}
+	for _, name := range []string{model.RoleAdmin, model.RoleCompany, model.RoleCPSK, model.RoleVisitor} {
+		resp.Roles = append(resp.Roles, roleInfo{
+			Name:        name,
+			Builtin:     true,
+			Permissions: policy.RolePermissions(name),
+		})
+	}
+
+	var custom []model.CustomRole
+	if err := rc.DB.Order("name").Find(&custom).Error; err != nil {
+		utilities.RespondDBError(c, err)
+		return
+	}
+	for _, role := range custom {
+		resp.Roles = append(resp.Roles, toRoleInfo(role))
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// PutRole function creates custom role or replaces description and permissions of existing one
+// @Summary Create or update custom role
+// @Description Only user with role:manage permission can access this endpoint
+// @Description Name must be lowercase letters, digits, '-' or '_' and not a built-in role
+// @Tags Role
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Insert your access token" default(Bearer <your access token>)
+// @Param name path string true "Role name" example(moderator)
+// @Param Role body roleRequest true "Permissions granted by the role"
+// @Success 200 {object} roleInfo
+// @Failure 400 {object} utilities.ErrorResponse "Invalid name or unknown permission"
+// @Failure 401 {object} utilities.ErrorResponse "Invalid token"
+// @Failure 403 {object} utilities.ErrorResponse "Do not have permission"
+// @Failure 500 {object} utilities.ErrorResponse "Database error"
+// @Router /roles/{name} [put]
+func (rc *RoleController) PutRole(c *gin.Context) {
+	name := strings.ToLower(strings.TrimSpace(c.Param("name")))
+	if !roleNamePattern.MatchString(name) || policy.IsBuiltinRole(name) {
+		c.JSON(http.StatusBadRequest, utilities.ErrorResponse{
+			Error: fmt.Sprintf("Invalid role name '%s'", name),
+		})
+		return
+	}
+
+	var req roleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utilities.ErrorResponse{
+			Error: fmt.Sprintf("Invalid request body: %s", err.Error()),
+		})
+		return
+	}
+
+	perms := make([]string, 0, len(req.Permissions))
+	for _, p := range req.Permissions {
+		if !policy.IsValid(p) {
+			c.JSON(http.StatusBadRequest, utilities.ErrorResponse{
+				Error: fmt.Sprintf("Unknown permission '%s'", p),
+			})
+			return
+		}
+		perms = append(perms, string(p))
+	}
+
+	role := model.CustomRole{
+		Name:        name,
+		Description: req.Description,
+		Permissions: perms,
+	}
+	if err := rc.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "name"}},
+		DoUpdates: clause.AssignmentColumns([]string{"description", "permissions", "updated_at"}),
+	}).Create(&role).Error; err != nil {
+		utilities.RespondDBError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toRoleInfo(role))
+}
+
+// DeleteRole function deletes custom role and removes it from every user
+// @Summary Delete custom role
+// @Description Only user with role:manage permission can access this endpoint
+// @Tags Role
+// @Produce json
+// @Param Authorization header string true "Insert your access token" default(Bearer <your access token>)
+// @Param name path string true "Role name"
+// @Success 200 {object} utilities.MessageResponse "Role deleted"
+// @Failure 401 {object} utilities.ErrorResponse "Invalid token"
+// @Failure 403 {object} utilities.ErrorResponse "Do not have permission"
+// @Failure 404 {object} utilities.ErrorResponse "Role not found"
+// @Failure 500 {object} utilities.ErrorResponse "Database error"
+// @Router /roles/{name} [delete]
+func (rc *RoleController) DeleteRole(c *gin.Context) {
+	result := rc.DB.Where("name = ?", c.Param("name")).Delete(&model.CustomRole{})
+	if result.Error != nil {
+		utilities.RespondDBError(c, result.Error)
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, utilities.ErrorResponse{Error: "Role not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, utilities.MessageResponse{Message: "Role deleted"})
+}
+
+// AssignRole function grants custom role to user
+// @Summary Assign custom role to user
+// @Description Only user with role:manage permission can access this endpoint
+// @Tags Role
+// @Produce json
+// @Param Authorization header string true "Insert your access token" default(Bearer <your access token>)
+// @Param name path string true "Role name"
+// @Param user_id path string true "ID of the user"
+// @Success 200 {object} utilities.MessageResponse "Role assigned"
+// @Failure 400 {object} utilities.ErrorResponse "Invalid user ID"
+// @Failure 401 {object} utilities.ErrorResponse "Invalid token"
+// @Failure 403 {object} utilities.ErrorResponse "Do not have permission"
+// @Failure 404 {object} utilities.ErrorResponse "Role or user not found"
+// @Failure 500 {object} utilities.ErrorResponse "Database error"
+// @Router /roles/{name}/users/{user_id} [put]
+func (rc *RoleController) AssignRole(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("user_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utilities.ErrorResponse{Error: "Invalid user ID"})
+		return
+	}
+
+	if err := rc.DB.Where("name = ?", c.Param("name")).First(&model.CustomRole{}).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, utilities.ErrorResponse{Error: "Role not found"})
+			return
+		}
+		utilities.RespondDBError(c, err)
+		return
+	}
+	if err := rc.DB.Select("id").Where("id = ?", userID).First(&model.User{}).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, utilities.ErrorResponse{Error: "User not found"})
+			return
+		}
+		utilities.RespondDBError(c, err)
+		return
+	}
+
+	if err := rc.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(&model.UserCustomRole{
+		UserID:   userID,
+		RoleName: c.Param("name"),
+	}).Error; err != nil {
+		utilities.RespondDBError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, utilities.MessageResponse{Message: "Role assigned"})
+}
+
+// UnassignRole function removes custom role from user
+// @Summary Remove custom role from user
+// @Description Only user with role:manage permission can access this endpoint
+// @Tags Role
+// @Produce json
+// @Param Authorization header string true "Insert your access token" default(Bearer <your access token>)
+// @Param name path string true "Role name"
+// @Param user_id path string true "ID of the user"
+// @Success 200 {object} utilities.MessageResponse "Role removed"
+// @Failure 400 {object} utilities.ErrorResponse "Invalid user ID"
+// @Failure 401 {object} utilities.ErrorResponse "Invalid token"
+// @Failure 403 {object} utilities.ErrorResponse "Do not have permission"
+// @Failure 404 {object} utilities.ErrorResponse "User doesn't have the role"
+// @Failure 500 {object} utilities.ErrorResponse "Database error"
+// @Router /roles/{name}/users/{user_id} [delete]
+func (rc *RoleController) UnassignRole(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("user_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utilities.ErrorResponse{Error: "Invalid user ID"})
+		return
+	}
+
+	result := rc.DB.Where("role_name = ? AND user_id = ?", c.Param("name"), userID).
+		Delete(&model.UserCustomRole{})
+	if result.Error != nil {
+		utilities.RespondDBError(c, result.Error)
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, utilities.ErrorResponse{Error: "User doesn't have the role"})
+		return
+	}
+
+	c.JSON(http.StatusOK, utilities.MessageResponse{Message: "Role removed"})
+}
+
+func toRoleInfo(role model.CustomRole) roleInfo {
+	perms := make([]policy.Permission, 0, len(role.Permissions))
+	for _, p := range role.Permissions {
+		perms = append(perms, policy.Permission(p))
+	}
+	return roleInfo{
+		Name:        role.Name,
+		Description: role.Description,
+		Permissions: perms,
+	}
+}
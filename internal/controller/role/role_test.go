@@ -0,0 +1,106 @@
+package role
+
+import (
+	"HireMeMaybe-backend/internal/auth"
+	"HireMeMaybe-backend/internal/database"
+	"HireMeMaybe-backend/internal/middleware"
+	"HireMeMaybe-backend/internal/model"
+	"HireMeMaybe-backend/internal/policy"
+	"HireMeMaybe-backend/internal/testutil"
+	"context"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/testcontainers/testcontainers-go"
+)
+
+var testDB *database.DBinstanceStruct
+
+func TestMain(m *testing.M) {
+	gin.SetMode(gin.TestMode)
+	var err error
+	var midTeardown func(context.Context, ...testcontainers.TerminateOption) error
+	midTeardown, testDB, err = database.GetTestDB()
+	if err != nil {
+		os.Exit(1)
+	}
+	m.Run()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if midTeardown != nil {
+		_ = midTeardown(ctx)
+	}
+}
+
+func roleRouter() *gin.Engine {
+	r := gin.Default()
+	rc := NewRoleController(testDB)
+	roleRoute := r.Group("/roles", middleware.RequireAuth(testDB), middleware.RequirePermission(testDB, policy.RoleManage))
+	roleRoute.GET("", rc.GetRoles)
+	roleRoute.PUT("/:name", rc.PutRole)
+	roleRoute.DELETE("/:name", rc.DeleteRole)
+	roleRoute.PUT("/:name/users/:user_id", rc.AssignRole)
+	roleRoute.DELETE("/:name/users/:user_id", rc.UnassignRole)
+	return r
+}
+
+func TestManageCustomRole(t *testing.T) {
+	adminToken, err := auth.GetAccessToken(t, testDB, database.TestAdminUser.Username, database.TestSeedPassword)
+	assert.NoError(t, err)
+	r := roleRouter()
+
+	rec, resp := testutil.MakeJSONRequest(gin.H{
+		"description": "Handle reports",
+		"permissions": []string{"report:view", "report:resolve"},
+	}, adminToken, r, "/roles/moderator", http.MethodPut)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, "moderator", resp["name"])
+
+	rec, _ = testutil.MakeJSONRequest(nil, adminToken, r, "/roles/moderator/users/"+database.TestUserCPSK1.ID.String(), http.MethodPut)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	perms, err := policy.Load(testDB.DB, database.TestUserCPSK1)
+	assert.NoError(t, err)
+	assert.True(t, perms.Has(policy.ReportResolve))
+	assert.True(t, perms.Has(policy.ApplicationApply), "Permissions of built-in role are kept")
+
+	rec, resp = testutil.MakeJSONRequest(nil, adminToken, r, "/roles", http.MethodGet)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Len(t, resp["roles"], 5)
+
+	rec, _ = testutil.MakeJSONRequest(nil, adminToken, r, "/roles/moderator/users/"+database.TestUserCPSK1.ID.String(), http.MethodDelete)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	perms, err = policy.Load(testDB.DB, database.TestUserCPSK1)
+	assert.NoError(t, err)
+	assert.False(t, perms.Has(policy.ReportResolve))
+
+	rec, _ = testutil.MakeJSONRequest(nil, adminToken, r, "/roles/moderator", http.MethodDelete)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	rec, _ = testutil.MakeJSONRequest(nil, adminToken, r, "/roles/moderator", http.MethodDelete)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestPutRole_Invalid(t *testing.T) {
+	adminToken, err := auth.GetAccessToken(t, testDB, database.TestAdminUser.Username, database.TestSeedPassword)
+	assert.NoError(t, err)
+	r := roleRouter()
+
+	rec, _ := testutil.MakeJSONRequest(gin.H{"permissions": []string{"report:view"}}, adminToken, r, "/roles/"+model.RoleAdmin, http.MethodPut)
+	assert.Equal(t, http.StatusBadRequest, rec.Code, "Built-in role can't be redefined")
+
+	rec, _ = testutil.MakeJSONRequest(gin.H{"permissions": []string{"report:fly"}}, adminToken, r, "/roles/moderator", http.MethodPut)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestManageCustomRole_Forbidden(t *testing.T) {
+	companyToken, err := auth.GetAccessToken(t, testDB, database.TestUserCompany1.Username, database.TestSeedPassword)
+	assert.NoError(t, err)
+
+	rec, resp := testutil.MakeJSONRequest(nil, companyToken, roleRouter(), "/roles", http.MethodGet)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	assert.Contains(t, resp["error"], "permission")
+}
@@ -4,6 +4,7 @@ package verification
 import (
 	"HireMeMaybe-backend/internal/database"
 	"HireMeMaybe-backend/internal/model"
+	"HireMeMaybe-backend/internal/policy"
 	"HireMeMaybe-backend/internal/utilities"
 	"errors"
 	"fmt"
@@ -47,11 +48,19 @@ type aiVerificationResponse struct {
 // @Router /company/ai-verify [post]
 func (jc *VerificationController) AIVerifyCompany(c *gin.Context) {
 	// Extract user from token (middleware already validated it's a company)
-	user, err := utilities.ExtractUser(c)
-	if err != nil {
+	if _, err := utilities.ExtractUser(c); err != nil {
 		c.JSON(http.StatusUnauthorized, utilities.ErrorResponse{Error: err.Error()})
 		return
 	}
+	org, err := policy.MembershipFromContext(c, jc.DB.DB)
+	if err != nil {
+		utilities.RespondDBError(c, err)
+		return
+	}
+	if !org.IsMember() {
+		c.JSON(http.StatusForbidden, utilities.ErrorResponse{Error: "User is not a member of any company organization"})
+		return
+	}
 
 	// Fetch company information with all necessary preloads
 	var company model.CompanyUser
@@ -60,7 +69,7 @@ func (jc *VerificationController) AIVerifyCompany(c *gin.Context) {
 		Preload("Logo").
 		Preload("Banner").
 		Preload("JobPost").
-		Where("user_id = ?", user.ID.String()).
+		Where("user_id = ?", org.CompanyID).
 		First(&company).Error
 
 	switch {
@@ -4,6 +4,7 @@ package database
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
@@ -12,12 +13,14 @@ import (
 	"sync"
 	"time"
 
+	"github.com/google/uuid"
 	// It's something abt database I don't know 😭
 	_ "github.com/jackc/pgx/v5/stdlib"
 	// Load .env file to environments
 	_ "github.com/joho/godotenv/autoload"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"HireMeMaybe-backend/internal/model"
 	"HireMeMaybe-backend/internal/utilities"
@@ -204,7 +207,19 @@ func (d *DBinstanceStruct) Migrate() error {
 	if err != nil {
 		return err
 	}
-	return nil
+
+	// Punishment created before history was kept only linked from user
+	if err := d.Exec(`UPDATE punishment_structs SET user_id = users.id FROM users
+		WHERE users.punishment_id = punishment_structs.id AND punishment_structs.user_id IS NULL`).Error; err != nil {
+		return err
+	}
+
+	if err := migrateJobPostSearch(d.DB); err != nil {
+		return err
+	}
+
+	_, err = CloseExpiredPosts(d.DB)
+	return err
 }
 
 // Health checks the health of the database connection by pinging the database.
@@ -285,11 +300,19 @@ func (d *DBinstanceStruct) installExtension() error {
 		return err
 	}
 	log.Println("uuid-ossp extension installed or already exists")
+
+	// Trigram matching for typo tolerant job post search
+	if err := d.WithContext(context.Background()).Exec(`CREATE EXTENSION IF NOT EXISTS pg_trgm;`).Error; err != nil {
+		return err
+	}
+	log.Println("pg_trgm extension installed or already exists")
 	return nil
 }
 
-// RemovePunishment removes punishment from user and delete punishment record from database
-func RemovePunishment(user model.User, db *DBinstanceStruct) (string, int, error) {
+// RemovePunishment recomputes active punishment of user if the current one has expired.
+// Expired punishment is kept in punishment history. Punishment of user is replaced by the recomputed one,
+// which is checked the same way, so error is returned if user still has other active punishment.
+func RemovePunishment(user *model.User, db *DBinstanceStruct) (string, int, error) {
 	if user.Punishment == nil {
 		return "", http.StatusOK, nil
 	}
@@ -306,20 +329,113 @@ func RemovePunishment(user model.User, db *DBinstanceStruct) (string, int, error
 			fmt.Errorf("unexpired punishment")
 	}
 
-	punishment := model.PunishmentStruct{}
-	punishmentID := user.PunishmentID
-	user.Punishment = nil
+	active, err := RefreshActivePunishment(db.DB, user.ID)
+	if err != nil {
+		return fmt.Sprintf("Failed to update user info: %s", err.Error()), http.StatusInternalServerError, err
+	}
+
+	user.Punishment = active
 	user.PunishmentID = nil
+	if active != nil {
+		user.PunishmentID = &active.ID
+	}
+	return RemovePunishment(user, db)
+}
 
-	if err := db.Save(&user).Error; err != nil {
-		return fmt.Sprintf("Failed to update user info: %s", err.Error()), http.StatusInternalServerError, err
+// ActivePunishment returns punishment of user that is neither lifted nor expired, nil if there is none.
+// Ban takes precedence over suspend, then permanent or longest punishment.
+func ActivePunishment(tx *gorm.DB, userID uuid.UUID) (*model.PunishmentStruct, error) {
+	var punishment model.PunishmentStruct
+	err := tx.Where("user_id = ? AND lifted_at IS NULL AND (punish_end IS NULL OR punish_end > ?)", userID, time.Now()).
+		Order(clause.Expr{SQL: "punishment_type = ? DESC, punish_end DESC NULLS FIRST, id DESC", Vars: []interface{}{model.BanPunishment}}).
+		First(&punishment).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
 	}
+	return &punishment, nil
+}
 
-	if punishmentID != nil {
-		if err := db.Where("id = ?", punishmentID).Delete(&punishment).Error; err != nil {
-			return fmt.Sprintf("Failed to delete punishment record: %s", err.Error()), http.StatusInternalServerError, err
-		}
+// RefreshActivePunishment points user to their active punishment and returns it
+func RefreshActivePunishment(tx *gorm.DB, userID uuid.UUID) (*model.PunishmentStruct, error) {
+	active, err := ActivePunishment(tx, userID)
+	if err != nil {
+		return nil, err
 	}
 
-	return "", http.StatusOK, nil
+	var punishmentID *int
+	if active != nil {
+		punishmentID = &active.ID
+	}
+	if err := tx.Model(&model.User{}).Where("id = ?", userID).Update("punishment_id", punishmentID).Error; err != nil {
+		return nil, err
+	}
+	return active, nil
+}
+
+// IssuePunishment adds punishment to punishment history of its user and recomputes active punishment
+func IssuePunishment(tx *gorm.DB, punishment *model.PunishmentStruct) error {
+	if punishment.UserID == nil {
+		return fmt.Errorf("punishment has no user")
+	}
+	if err := tx.Create(punishment).Error; err != nil {
+		return err
+	}
+	_, err := RefreshActivePunishment(tx, *punishment.UserID)
+	return err
+}
+
+// LiftPunishments marks every active punishment of user as lifted by admin and clears active punishment
+func LiftPunishments(tx *gorm.DB, user model.User, adminID uuid.UUID, reason string) error {
+	query := tx.Model(&model.PunishmentStruct{})
+	if user.PunishmentID != nil {
+		// Active punishment issued before history was kept may not have user ID
+		query = query.Where("user_id = ? OR id = ?", user.ID, *user.PunishmentID)
+	} else {
+		query = query.Where("user_id = ?", user.ID)
+	}
+	return liftPunishments(tx, query, user.ID, adminID, reason)
+}
+
+// LiftPunishment marks punishment of user as lifted by admin if it is still active and recomputes active punishment
+func LiftPunishment(tx *gorm.DB, punishmentID int, userID uuid.UUID, adminID uuid.UUID, reason string) error {
+	query := tx.Model(&model.PunishmentStruct{}).Where("id = ?", punishmentID)
+	return liftPunishments(tx, query, userID, adminID, reason)
+}
+
+func liftPunishments(tx *gorm.DB, query *gorm.DB, userID uuid.UUID, adminID uuid.UUID, reason string) error {
+	now := time.Now()
+	if err := query.Where("lifted_at IS NULL AND (punish_end IS NULL OR punish_end > ?)", now).
+		Updates(map[string]interface{}{
+			"lifted_at":    now,
+			"lifted_by_id": adminID,
+			"lift_reason":  reason,
+		}).Error; err != nil {
+		return err
+	}
+
+	_, err := RefreshActivePunishment(tx, userID)
+	return err
+}
+
+// CloseExpiredPosts closes every published job post that has expired and returns number of closed posts
+func CloseExpiredPosts(tx *gorm.DB) (int64, error) {
+	result := tx.Model(&model.JobPost{}).
+		Where("status = ? AND expiring <= ?", model.JobPostStatusPublished, time.Now()).
+		Update("status", model.JobPostStatusClosed)
+	return result.RowsAffected, result.Error
+}
+
+// CloseExpiredPostsEvery closes expired job posts every interval, it blocks so should be run in its own goroutine.
+func (d *DBinstanceStruct) CloseExpiredPostsEvery(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if _, err := CloseExpiredPosts(d.DB); err != nil {
+			log.Printf("failed to close expired job posts: %v", err)
+		}
+	}
 }
@@ -0,0 +1,53 @@
+package database
+
+import (
+	"gorm.io/gorm"
+)
+
+// Text search configurations used for job post search. Postgres has no Thai dictionary,
+// so thai is a copy of simple configuration that keeps words as they are written.
+const (
+	SearchConfigEnglish = "english"
+	SearchConfigThai    = "thai"
+)
+
+// jobPostSearchMigrations creates full-text search column of job post and indexes used by search.
+// search_vector weights title over tags, requirement and description.
+var jobPostSearchMigrations = []string{
+	`DO $$ BEGIN
+		IF NOT EXISTS (SELECT 1 FROM pg_ts_config WHERE cfgname = 'thai') THEN
+			CREATE TEXT SEARCH CONFIGURATION thai (COPY = simple);
+		END IF;
+	END $$`,
+
+	`CREATE OR REPLACE FUNCTION job_post_search_document(title text, description text, requirement text, tags text[])
+	RETURNS tsvector LANGUAGE sql IMMUTABLE PARALLEL SAFE AS $$
+		SELECT
+			setweight(to_tsvector('english', coalesce(title, '')) || to_tsvector('thai', coalesce(title, '')), 'A') ||
+			setweight(to_tsvector('english', coalesce(array_to_string(tags, ' '), '')) || to_tsvector('thai', coalesce(array_to_string(tags, ' '), '')), 'B') ||
+			setweight(to_tsvector('english', coalesce(requirement, '')) || to_tsvector('thai', coalesce(requirement, '')), 'C') ||
+			setweight(to_tsvector('english', coalesce(description, '')) || to_tsvector('thai', coalesce(description, '')), 'D')
+	$$`,
+
+	`ALTER TABLE job_posts ADD COLUMN IF NOT EXISTS search_vector tsvector
+		GENERATED ALWAYS AS (job_post_search_document(title, "desc", req, tags)) STORED`,
+
+	`CREATE INDEX IF NOT EXISTS idx_job_posts_search_vector ON job_posts USING GIN (search_vector)`,
+
+	// Trigram indexes for typo tolerant title matching and substring filters
+	`CREATE INDEX IF NOT EXISTS idx_job_posts_title_trgm ON job_posts USING GIN (title gin_trgm_ops)`,
+	`CREATE INDEX IF NOT EXISTS idx_job_posts_type_trgm ON job_posts USING GIN (type gin_trgm_ops)`,
+	`CREATE INDEX IF NOT EXISTS idx_job_posts_location_trgm ON job_posts USING GIN (location gin_trgm_ops)`,
+	`CREATE INDEX IF NOT EXISTS idx_company_users_name_trgm ON company_users USING GIN (name gin_trgm_ops)`,
+	`CREATE INDEX IF NOT EXISTS idx_company_users_industry_trgm ON company_users USING GIN (industry gin_trgm_ops)`,
+}
+
+// migrateJobPostSearch creates full-text search column and indexes of job post, it requires pg_trgm extension
+func migrateJobPostSearch(tx *gorm.DB) error {
+	for _, stmt := range jobPostSearchMigrations {
+		if err := tx.Exec(stmt).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+// Package mail contains sender used to deliver email to users
+package mail
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Supported value of MAIL_SENDER environment
+const (
+	SenderLog  = "log"
+	SenderFile = "file"
+)
+
+// Message is an email to be sent
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Sender delivers Message to its recipient.
+type Sender interface {
+	Send(msg Message) error
+}
+
+// LogSender writes every message to standard logger instead of sending it, useful for local development.
+type LogSender struct{}
+
+// Send logs the message.
+func (LogSender) Send(msg Message) error {
+	log.Printf("mail to %s: %s\n%s", msg.To, msg.Subject, msg.Body)
+	return nil
+}
+
+// FileSender writes every message to a new file in Dir.
+type FileSender struct {
+	Dir string
+	mu  sync.Mutex
+	seq int
+}
+
+// NewFileSender creates a new instance of FileSender writing to dir.
+func NewFileSender(dir string) *FileSender {
+	return &FileSender{Dir: dir}
+}
+
+// Send writes the message as a .eml file.
+func (s *FileSender) Send(msg Message) error {
+	if err := os.MkdirAll(s.Dir, 0o750); err != nil {
+		return fmt.Errorf("failed to create mail directory: %w", err)
+	}
+
+	s.mu.Lock()
+	s.seq++
+	name := fmt.Sprintf("%s-%d.eml", time.Now().UTC().Format("20060102T150405.000000000"), s.seq)
+	s.mu.Unlock()
+
+	content := fmt.Sprintf("To: %s\r\nSubject: %s\r\nDate: %s\r\n\r\n%s\r\n",
+		msg.To, msg.Subject, time.Now().UTC().Format(time.RFC1123Z), msg.Body)
+	return os.WriteFile(filepath.Join(s.Dir, name), []byte(content), 0o600)
+}
+
+// NewSenderFromEnv creates Sender chosen by MAIL_SENDER environment.
+// "log" (default) print messages to the log and "file" write them to MAIL_FILE_DIR ("log/mail" by default).
+func NewSenderFromEnv() (Sender, error) {
+	kind := strings.ToLower(strings.TrimSpace(os.Getenv("MAIL_SENDER")))
+
+	switch kind {
+	case "", SenderLog:
+		return LogSender{}, nil
+
+	case SenderFile:
+		dir := os.Getenv("MAIL_FILE_DIR")
+		if dir == "" {
+			dir = filepath.Join("log", "mail")
+		}
+		return NewFileSender(dir), nil
+
+	default:
+		return nil, fmt.Errorf("unknown MAIL_SENDER '%s'", kind)
+	}
+}
@@ -0,0 +1,52 @@
+package mail
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileSender(t *testing.T) {
+	dir := t.TempDir()
+	sender := NewFileSender(dir)
+
+	assert.NoError(t, sender.Send(Message{To: "a@example.com", Subject: "First", Body: "hello"}))
+	assert.NoError(t, sender.Send(Message{To: "b@example.com", Subject: "Second", Body: "world"}))
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.eml"))
+	assert.NoError(t, err)
+	if !assert.Len(t, files, 2) {
+		return
+	}
+
+	var contents []string
+	for _, f := range files {
+		b, err := os.ReadFile(f) // #nosec G304 -- file in test temp directory
+		assert.NoError(t, err)
+		contents = append(contents, string(b))
+	}
+	all := strings.Join(contents, "\n")
+	assert.Contains(t, all, "To: a@example.com")
+	assert.Contains(t, all, "Subject: Second")
+	assert.Contains(t, all, "world")
+}
+
+func TestNewSenderFromEnv(t *testing.T) {
+	t.Setenv("MAIL_SENDER", "")
+	sender, err := NewSenderFromEnv()
+	assert.NoError(t, err)
+	assert.IsType(t, LogSender{}, sender)
+
+	t.Setenv("MAIL_SENDER", "file")
+	t.Setenv("MAIL_FILE_DIR", t.TempDir())
+	sender, err = NewSenderFromEnv()
+	assert.NoError(t, err)
+	assert.IsType(t, &FileSender{}, sender)
+
+	t.Setenv("MAIL_SENDER", "carrier-pigeon")
+	_, err = NewSenderFromEnv()
+	assert.Error(t, err)
+}
@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"HireMeMaybe-backend/internal/audit"
+	"HireMeMaybe-backend/internal/database"
+	"HireMeMaybe-backend/internal/utilities"
+	"log"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Audit records successful request to the endpoint as action on the target in audit log.
+// State of the target is loaded before and after the handler to record what was changed.
+// Failing to write the log after response has been sent is only logged.
+func Audit(db *database.DBinstanceStruct, action string, target audit.Target) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id := target.ID(ctx)
+		before, err := target.Load(db.DB, ctx, id)
+		if err != nil {
+			utilities.RespondDBError(ctx, err)
+			ctx.Abort()
+			return
+		}
+
+		ctx.Next()
+
+		if status := ctx.Writer.Status(); status < 200 || status >= 300 {
+			return
+		}
+		if target.Skip != nil && target.Skip(db.DB, ctx, before) {
+			return
+		}
+
+		after, err := target.Load(db.DB, ctx, id)
+		if err == nil {
+			err = audit.Log(db.DB, ctx, action, target.Type, id, before, after)
+		}
+		if err != nil {
+			log.Printf("Failed to write audit log of %s on %s %s: %s", action, target.Type, id, err.Error())
+		}
+	}
+}
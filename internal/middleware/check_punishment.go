@@ -3,7 +3,9 @@ package middleware
 import (
 	"HireMeMaybe-backend/internal/database"
 	"HireMeMaybe-backend/internal/model"
+	"HireMeMaybe-backend/internal/policy"
 	"HireMeMaybe-backend/internal/utilities"
+	"fmt"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -35,7 +37,11 @@ func CheckPunishment(db *database.DBinstanceStruct, punishmentType string) gin.H
 			return
 		}
 
-		if msg, status, err := database.RemovePunishment(user, db); err != nil {
+		msg, status, err := database.RemovePunishment(&user, db)
+		// Later handlers see punishment recomputed from history
+		ctx.Set("user", user)
+		// Punishment replacing expired one only blocks if it has the same type
+		if err != nil && (status == http.StatusInternalServerError || user.Punishment.PunishmentType == punishmentType) {
 			ctx.AbortWithStatusJSON(status, utilities.ErrorResponse{
 				Error: msg,
 			})
@@ -45,3 +51,38 @@ func CheckPunishment(db *database.DBinstanceStruct, punishmentType string) gin.H
 		ctx.Next()
 	}
 }
+
+// CheckOrganizationPunishment rejects member acting for company organization whose company account
+// is banned or suspended, so members can't keep managing posts and applicants for it.
+// Punishment of the member themselves is checked by CheckPunishment.
+func CheckOrganizationPunishment(db *database.DBinstanceStruct) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		org, err := policy.MembershipFromContext(ctx, db.DB)
+		if err != nil {
+			utilities.RespondDBError(ctx, err)
+			ctx.Abort()
+			return
+		}
+		if !org.IsMember() {
+			ctx.Next()
+			return
+		}
+
+		punishment, err := database.ActivePunishment(db.DB, org.CompanyID)
+		if err != nil {
+			utilities.RespondDBError(ctx, err)
+			ctx.Abort()
+			return
+		}
+		if punishment != nil {
+			msg := fmt.Sprintf("Company of your organization is under %s punishment", punishment.PunishmentType)
+			if punishment.PunishEnd != nil {
+				msg += fmt.Sprintf(" until: %s", punishment.PunishEnd)
+			}
+			ctx.AbortWithStatusJSON(http.StatusForbidden, utilities.ErrorResponse{Error: msg})
+			return
+		}
+
+		ctx.Next()
+	}
+}
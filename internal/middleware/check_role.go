@@ -7,7 +7,8 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-// CheckRole will protect endpoint from user that is not a specific roles
+// CheckRole will protect endpoint from user that is not a specific roles.
+// New routes should use RequirePermission so custom roles are taken into account.
 func CheckRole(roles ...string) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		user, err := utilities.ExtractUser(ctx)
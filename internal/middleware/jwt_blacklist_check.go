@@ -9,7 +9,7 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-// JwtBlacklistCheck is a middleware that checks if the JWT token is blacklisted
+// JwtBlacklistCheck is a middleware that checks if the JWT ID (jti) of the token is blacklisted
 func JwtBlacklistCheck(bl auth.JwtBlacklistStore) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		tokenString, err := utilities.ExtractBearerToken(ctx)
@@ -20,7 +20,18 @@ func JwtBlacklistCheck(bl auth.JwtBlacklistStore) gin.HandlerFunc {
 			return
 		}
 
-		isBlacklisted, err := bl.IsBlacklisted(tokenString)
+		token, err := auth.ValidatedToken(tokenString)
+		if err != nil {
+			// Invalid token is rejected by RequireAuth
+			return
+		}
+
+		claims, ok := token.Claims.(*auth.Claims)
+		if !ok || claims.ID == "" {
+			return
+		}
+
+		isBlacklisted, err := bl.IsBlacklisted(claims.ID)
 
 		if err != nil {
 			ctx.AbortWithStatusJSON(http.StatusInternalServerError, utilities.ErrorResponse{
@@ -35,7 +46,5 @@ func JwtBlacklistCheck(bl auth.JwtBlacklistStore) gin.HandlerFunc {
 			})
 			return
 		}
-
-		fmt.Println("i'm here =================================")
 	}
 }
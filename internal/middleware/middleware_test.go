@@ -52,6 +52,16 @@ func protectedEngine() *gin.Engine {
 	return r
 }
 
+// tokenID returns jti claim of the given access token
+func tokenID(t *testing.T, tokenString string) string {
+	t.Helper()
+	token, err := auth.ValidatedToken(tokenString)
+	assert.NoError(t, err)
+	claims, ok := token.Claims.(*auth.Claims)
+	assert.True(t, ok)
+	return claims.ID
+}
+
 func checkUserHandler(c *gin.Context) {
 	u, exist := c.Get("user")
 	if !exist {
@@ -206,7 +216,7 @@ func TestRequireAuth_NoHeader(t *testing.T) {
 
 func TestRequireAuth_ExpiredToken(t *testing.T) {
 	engine := protectedEngine()
-	token, _, err := auth.GenerateTokenWithDuration(database.TestUserCPSK1.ID, -1*time.Minute, auth.JwtIssuer)
+	token, err := auth.GenerateTokenWithDuration(database.TestUserCPSK1.ID, -1*time.Minute, auth.JwtIssuer)
 	assert.NoError(t, err)
 
 	req, _ := http.NewRequest(http.MethodGet, "/protected", nil)
@@ -223,7 +233,7 @@ func TestRequireAuth_ExpiredToken(t *testing.T) {
 func TestRequireAuth_InvalidToken(t *testing.T) {
 	engine := protectedEngine()
 	// Create a valid token then corrupt it (signature mismatch)
-	validToken, _, err := auth.GenerateTokenWithDuration(database.TestUserCPSK1.ID, time.Hour, auth.JwtIssuer)
+	validToken, err := auth.GenerateTokenWithDuration(database.TestUserCPSK1.ID, time.Hour, auth.JwtIssuer)
 	assert.NoError(t, err)
 	invalid := validToken + "x"
 
@@ -241,7 +251,7 @@ func TestRequireAuth_InvalidToken(t *testing.T) {
 func TestRequireAuth_UnknownUser(t *testing.T) {
 	engine := protectedEngine()
 	randomID := uuid.New()
-	token, _, err := auth.GenerateTokenWithDuration(randomID, time.Hour, auth.JwtIssuer)
+	token, err := auth.GenerateTokenWithDuration(randomID, time.Hour, auth.JwtIssuer)
 	assert.NoError(t, err)
 
 	req, _ := http.NewRequest(http.MethodGet, "/protected", nil)
@@ -259,7 +269,7 @@ func TestRequireAuth_UnknownUser(t *testing.T) {
 
 func TestRequireAuth_InvalidIssuer(t *testing.T) {
 	engine := protectedEngine()
-	token, _, err := auth.GenerateTokenWithDuration(database.TestCPSK1.UserID, time.Hour, "invalid-issuer")
+	token, err := auth.GenerateTokenWithDuration(database.TestCPSK1.UserID, time.Hour, "invalid-issuer")
 	assert.NoError(t, err)
 
 	req, _ := http.NewRequest(http.MethodGet, "/protected", nil)
@@ -273,6 +283,52 @@ func TestRequireAuth_InvalidIssuer(t *testing.T) {
 	assert.Contains(t, body["error"], "Invalid token issuer")
 }
 
+func TestRequireAuth_TokenIssuedBeforeValidAfter(t *testing.T) {
+	engine := protectedEngine()
+	token, err := auth.GenerateTokenWithDuration(database.TestUserCPSK2.ID, time.Hour, auth.JwtIssuer)
+	assert.NoError(t, err)
+
+	cutoff := time.Now().Add(time.Minute)
+	assert.NoError(t, testDB.Model(&model.User{}).Where("id = ?", database.TestUserCPSK2.ID).Update("tokens_valid_after", cutoff).Error)
+	defer testDB.Model(&model.User{}).Where("id = ?", database.TestUserCPSK2.ID).Update("tokens_valid_after", nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/protected", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	rec := httptest.NewRecorder()
+	engine.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rec.Code, rec.Body.String())
+	var body map[string]interface{}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, "Token has been revoked", body["error"])
+}
+
+func TestRequireAuth_TokenIssuedInSameSecondAsValidAfter(t *testing.T) {
+	engine := protectedEngine()
+	defer testDB.Model(&model.User{}).Where("id = ?", database.TestUserCPSK2.ID).Update("tokens_valid_after", nil)
+
+	request := func(token string) int {
+		req, _ := http.NewRequest(http.MethodGet, "/protected", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		engine.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	// Token issued just before logging out from every session has iat in the same second as the cutoff
+	token, err := auth.GenerateTokenWithDuration(database.TestUserCPSK2.ID, time.Hour, auth.JwtIssuer)
+	assert.NoError(t, err)
+	assert.NoError(t, testDB.Model(&model.User{}).Where("id = ?", database.TestUserCPSK2.ID).Update("tokens_valid_after", time.Now()).Error)
+	assert.Equal(t, http.StatusUnauthorized, request(token))
+
+	// Token issued in a later second is accepted
+	cutoff := time.Now().Add(-time.Second)
+	assert.NoError(t, testDB.Model(&model.User{}).Where("id = ?", database.TestUserCPSK2.ID).Update("tokens_valid_after", cutoff).Error)
+	token, err = auth.GenerateTokenWithDuration(database.TestUserCPSK2.ID, time.Hour, auth.JwtIssuer)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, request(token))
+}
+
 func TestCheckRole_NoRequireAuthBefore(t *testing.T) {
 	engine := gin.New()
 	engine.GET("/need-role", CheckRole(model.RoleCPSK), getCheckRoleHandler("cpsk"))
@@ -645,7 +701,7 @@ func TestCheckPunishment_UserWithDifferentPunishmentType(t *testing.T) {
 		c.JSON(http.StatusOK, gin.H{"ok": true, "message": "Different punishment type"})
 	})
 
-	token, _, err := auth.GenerateStandardToken(testUser.ID)
+	token, _, err := auth.GenerateStandardToken(testDB, testUser.ID)
 	assert.NoError(t, err)
 
 	req, _ := http.NewRequest(http.MethodGet, "/check", nil)
@@ -702,7 +758,7 @@ func TestCheckPunishment_UserWithMatchingActivePunishment(t *testing.T) {
 		c.JSON(http.StatusOK, gin.H{"ok": true, "message": "Should not reach here"})
 	})
 
-	token, _, err := auth.GenerateStandardToken(testUser.ID)
+	token, _, err := auth.GenerateStandardToken(testDB, testUser.ID)
 	assert.NoError(t, err)
 
 	req, _ := http.NewRequest(http.MethodGet, "/check", nil)
@@ -759,7 +815,7 @@ func TestCheckPunishment_UserWithExpiredPunishment(t *testing.T) {
 		c.JSON(http.StatusOK, gin.H{"ok": true, "message": "Punishment expired and removed"})
 	})
 
-	token, _, err := auth.GenerateStandardToken(testUser.ID)
+	token, _, err := auth.GenerateStandardToken(testDB, testUser.ID)
 	assert.NoError(t, err)
 
 	req, _ := http.NewRequest(http.MethodGet, "/check", nil)
@@ -780,6 +836,52 @@ func TestCheckPunishment_UserWithExpiredPunishment(t *testing.T) {
 	testDB.Unscoped().Delete(&punishment)
 }
 
+func TestCheckPunishment_ExpiredPunishmentReplacedByActiveOne(t *testing.T) {
+	testUser := model.User{
+		Username: "test_replaced_ban_user",
+		Password: "hashed_password",
+		Role:     model.RoleCPSK,
+	}
+	if err := testDB.Create(&testUser).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	// Active ban issued while user still points to the expired one
+	expiredEnd := time.Now().Add(-time.Hour)
+	activeEnd := time.Now().Add(24 * time.Hour)
+	expired := model.PunishmentStruct{PunishmentType: "ban", PunishEnd: &expiredEnd, UserID: &testUser.ID}
+	active := model.PunishmentStruct{PunishmentType: "ban", PunishEnd: &activeEnd, UserID: &testUser.ID}
+	assert.NoError(t, testDB.Create(&expired).Error)
+	assert.NoError(t, testDB.Create(&active).Error)
+	assert.NoError(t, testDB.Model(&testUser).Update("punishment_id", expired.ID).Error)
+
+	engine := gin.New()
+	engine.GET("/check", RequireAuth(testDB), CheckPunishment(testDB, "ban"), func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"ok": true})
+	})
+
+	token, _, err := auth.GenerateStandardToken(testDB, testUser.ID)
+	assert.NoError(t, err)
+
+	req, _ := http.NewRequest(http.MethodGet, "/check", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	rec := httptest.NewRecorder()
+	engine.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusForbidden, rec.Code, rec.Body.String())
+
+	var refreshed model.User
+	assert.NoError(t, testDB.First(&refreshed, "id = ?", testUser.ID).Error)
+	if assert.NotNil(t, refreshed.PunishmentID) {
+		assert.Equal(t, active.ID, *refreshed.PunishmentID)
+	}
+
+	// Cleanup
+	testDB.Unscoped().Delete(&testUser)
+	testDB.Unscoped().Delete(&expired)
+	testDB.Unscoped().Delete(&active)
+}
+
 func TestJwtBlacklistCheck_NoHeader(t *testing.T) {
 	blacklistStore := auth.NewInMemoryBlacklistStore()
 	engine := gin.New()
@@ -815,7 +917,7 @@ func TestJwtBlacklistCheck_TokenNotBlacklisted(t *testing.T) {
 	engine := gin.New()
 	engine.GET("/protected", JwtBlacklistCheck(blacklistStore), checkUserHandler)
 
-	token, _, err := auth.GenerateStandardToken(database.TestUserCPSK1.ID)
+	token, _, err := auth.GenerateStandardToken(testDB, database.TestUserCPSK1.ID)
 	assert.NoError(t, err)
 
 	req, _ := http.NewRequest(http.MethodGet, "/protected", nil)
@@ -833,11 +935,11 @@ func TestJwtBlacklistCheck_TokenBlacklisted(t *testing.T) {
 	engine := gin.New()
 	engine.GET("/protected", JwtBlacklistCheck(blacklistStore), checkUserHandler)
 
-	token, _, err := auth.GenerateStandardToken(database.TestUserCPSK1.ID)
+	token, _, err := auth.GenerateStandardToken(testDB, database.TestUserCPSK1.ID)
 	assert.NoError(t, err)
 
 	// Add token to blacklist
-	err = blacklistStore.AddToBlacklist(token, time.Now().Add(time.Hour))
+	err = blacklistStore.AddToBlacklist(tokenID(t, token), time.Now().Add(time.Hour))
 	assert.NoError(t, err)
 
 	req, _ := http.NewRequest(http.MethodGet, "/protected", nil)
@@ -857,13 +959,13 @@ func TestJwtBlacklistCheck_MultipleTokens(t *testing.T) {
 	engine.GET("/protected", JwtBlacklistCheck(blacklistStore), checkUserHandler)
 
 	// Create multiple tokens
-	token1, _, err := auth.GenerateStandardToken(database.TestUserCPSK1.ID)
+	token1, _, err := auth.GenerateStandardToken(testDB, database.TestUserCPSK1.ID)
 	assert.NoError(t, err)
-	token2, _, err := auth.GenerateStandardToken(database.TestUserCompany1.ID)
+	token2, _, err := auth.GenerateStandardToken(testDB, database.TestUserCompany1.ID)
 	assert.NoError(t, err)
 
 	// Blacklist only token1
-	err = blacklistStore.AddToBlacklist(token1, time.Now().Add(time.Hour))
+	err = blacklistStore.AddToBlacklist(tokenID(t, token1), time.Now().Add(time.Hour))
 	assert.NoError(t, err)
 
 	// Test blacklisted token
@@ -886,3 +988,96 @@ func TestJwtBlacklistCheck_MultipleTokens(t *testing.T) {
 	// Should pass through (not return 401 for blacklist)
 	assert.NotEqual(t, http.StatusUnauthorized, rec2.Code)
 }
+
+func rateLimitedEngine(policies map[string]RateLimitPolicy, policyName string, user *model.User) *gin.Engine {
+	limiter := NewRateLimiter(policies, InMemoryStoreFactory)
+	engine := gin.New()
+	engine.GET("/limited", func(c *gin.Context) {
+		if user != nil {
+			c.Set("user", *user)
+		}
+		c.Next()
+	}, limiter.Middleware(policyName), func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"ok": true})
+	})
+	return engine
+}
+
+func TestRateLimit_HeadersAndRetryAfter(t *testing.T) {
+	policies := map[string]RateLimitPolicy{
+		"test": {Limit: 2, Window: time.Minute, PerIP: true},
+	}
+	engine := rateLimitedEngine(policies, "test", nil)
+
+	for i := 0; i < 2; i++ {
+		req, _ := http.NewRequest(http.MethodGet, "/limited", nil)
+		rec := httptest.NewRecorder()
+		engine.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "2", rec.Header().Get("RateLimit-Limit"))
+		assert.Equal(t, fmt.Sprint(1-i), rec.Header().Get("RateLimit-Remaining"))
+		assert.Equal(t, "2;w=60", rec.Header().Get("RateLimit-Policy"))
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, "/limited", nil)
+	rec := httptest.NewRecorder()
+	engine.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.NotEmpty(t, rec.Header().Get("Retry-After"))
+	assert.Equal(t, "0", rec.Header().Get("RateLimit-Remaining"))
+	var body map[string]interface{}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, "Too many requests. Please try again later.", body["error"])
+}
+
+func TestRateLimit_RoleLimit(t *testing.T) {
+	policies := map[string]RateLimitPolicy{
+		"test": {Limit: 1, Window: time.Minute, RoleLimits: map[string]uint{model.RoleAdmin: 3}},
+	}
+	admin := model.User{ID: uuid.New(), Role: model.RoleAdmin}
+	cpsk := model.User{ID: uuid.New(), Role: model.RoleCPSK}
+
+	adminEngine := rateLimitedEngine(policies, "test", &admin)
+	for i := 0; i < 3; i++ {
+		req, _ := http.NewRequest(http.MethodGet, "/limited", nil)
+		rec := httptest.NewRecorder()
+		adminEngine.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "3", rec.Header().Get("RateLimit-Limit"))
+	}
+
+	cpskEngine := rateLimitedEngine(policies, "test", &cpsk)
+	req, _ := http.NewRequest(http.MethodGet, "/limited", nil)
+	rec := httptest.NewRecorder()
+	cpskEngine.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	req, _ = http.NewRequest(http.MethodGet, "/limited", nil)
+	rec = httptest.NewRecorder()
+	cpskEngine.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+}
+
+func TestRateLimit_IPPolicyKeyedByIP(t *testing.T) {
+	policies := DefaultRateLimitPolicies(5, 20, 10, 1)
+	assert.True(t, policies[RateLimitPolicyIP].PerIP)
+
+	// Authenticated user is still limited by IP
+	user := model.User{ID: uuid.New(), Role: model.RoleAdmin}
+	engine := rateLimitedEngine(policies, RateLimitPolicyIP, &user)
+	req, _ := http.NewRequest(http.MethodGet, "/limited", nil)
+	rec := httptest.NewRecorder()
+	engine.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	req, _ = http.NewRequest(http.MethodGet, "/limited", nil)
+	rec = httptest.NewRecorder()
+	engine.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+}
+
+func TestRateLimit_UnknownPolicy(t *testing.T) {
+	limiter := NewRateLimiter(map[string]RateLimitPolicy{}, InMemoryStoreFactory)
+	assert.Panics(t, func() { limiter.Middleware("missing") })
+}
@@ -0,0 +1,188 @@
+package middleware
+
+import (
+	"HireMeMaybe-backend/internal/model"
+	"HireMeMaybe-backend/internal/utilities"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	ratelimit "github.com/JGLTechnologies/gin-rate-limit"
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+)
+
+// Name of rate limit policies used by routes
+const (
+	// RateLimitPolicyDefault apply to authenticated API routes, keyed by user
+	RateLimitPolicyDefault = "default"
+	// RateLimitPolicyAuth apply to login, register, password reset and 2FA verification routes, keyed by client IP
+	RateLimitPolicyAuth = "auth"
+	// RateLimitPolicyIP apply to authenticated API routes before RequireAuth, keyed by client IP
+	// so requests with invalid token are limited before reaching the database
+	RateLimitPolicyIP = "ip"
+)
+
+// RateLimitPolicy describe how many requests a client can make in each window.
+type RateLimitPolicy struct {
+	Limit  uint
+	Window time.Duration
+	// RoleLimits override Limit for authenticated user with the role
+	RoleLimits map[string]uint
+	// PerIP key every request by client IP even if user is authenticated
+	PerIP bool
+}
+
+// StoreFactory creates ratelimit.Store allowing limit requests every window.
+type StoreFactory func(limit uint, window time.Duration) ratelimit.Store
+
+// InMemoryStoreFactory creates process local stores.
+func InMemoryStoreFactory(limit uint, window time.Duration) ratelimit.Store {
+	return ratelimit.InMemoryStore(&ratelimit.InMemoryOptions{
+		Rate:  window,
+		Limit: limit,
+	})
+}
+
+// RedisStoreFactory creates stores shared by every replica connected to the same Redis server.
+func RedisStoreFactory(client *redis.Client) StoreFactory {
+	return func(limit uint, window time.Duration) ratelimit.Store {
+		return ratelimit.RedisStore(&ratelimit.RedisOptions{
+			Rate:        window,
+			Limit:       limit,
+			RedisClient: client,
+		})
+	}
+}
+
+// RateLimiter apply rate limit policies from a policy table.
+type RateLimiter struct {
+	Policies map[string]RateLimitPolicy
+	NewStore StoreFactory
+}
+
+// NewRateLimiter creates a new instance of RateLimiter with the provided policy table and store backend.
+func NewRateLimiter(policies map[string]RateLimitPolicy, newStore StoreFactory) *RateLimiter {
+	return &RateLimiter{
+		Policies: policies,
+		NewStore: newStore,
+	}
+}
+
+// NewRateLimiterFromEnv creates RateLimiter using policies from DefaultRateLimitPolicies and store
+// chosen by RATE_LIMIT_STORE environment ("memory" by default or "redis" using REDIS_URL).
+func NewRateLimiterFromEnv() (*RateLimiter, error) {
+	policies := DefaultRateLimitPolicies(
+		envUint("RATE_LIMIT_REQUESTS_PER_SECOND", 5),
+		envUint("RATE_LIMIT_ADMIN_REQUESTS_PER_SECOND", 20),
+		envUint("RATE_LIMIT_AUTH_REQUESTS_PER_MINUTE", 10),
+		envUint("RATE_LIMIT_IP_REQUESTS_PER_SECOND", 50),
+	)
+
+	kind := strings.ToLower(strings.TrimSpace(os.Getenv("RATE_LIMIT_STORE")))
+	switch kind {
+	case "", "memory":
+		return NewRateLimiter(policies, InMemoryStoreFactory), nil
+	case "redis":
+		opt, err := redis.ParseURL(os.Getenv("REDIS_URL"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid REDIS_URL: %w", err)
+		}
+		return NewRateLimiter(policies, RedisStoreFactory(redis.NewClient(opt))), nil
+	default:
+		return nil, fmt.Errorf("unknown RATE_LIMIT_STORE '%s'", kind)
+	}
+}
+
+// DefaultRateLimitPolicies returns policy table used by the API.
+// Routes accepting password or one-time code get strict per IP limit while admins get looser limit than other roles.
+// Per IP limit of authenticated routes is higher than per user limit as many users may share one IP.
+func DefaultRateLimitPolicies(reqPerSec uint, adminReqPerSec uint, authReqPerMin uint, ipReqPerSec uint) map[string]RateLimitPolicy {
+	return map[string]RateLimitPolicy{
+		RateLimitPolicyDefault: {
+			Limit:  reqPerSec,
+			Window: time.Second,
+			RoleLimits: map[string]uint{
+				model.RoleAdmin: adminReqPerSec,
+			},
+		},
+		RateLimitPolicyAuth: {
+			Limit:  authReqPerMin,
+			Window: time.Minute,
+			PerIP:  true,
+		},
+		RateLimitPolicyIP: {
+			Limit:  ipReqPerSec,
+			Window: time.Second,
+			PerIP:  true,
+		},
+	}
+}
+
+// Middleware creates rate limiter middleware for the named policy.
+// It should be placed after RequireAuth for role limits to take effect.
+func (rl *RateLimiter) Middleware(policyName string) gin.HandlerFunc {
+	policy, ok := rl.Policies[policyName]
+	if !ok {
+		panic(fmt.Sprintf("unknown rate limit policy '%s'", policyName))
+	}
+
+	baseStore := rl.NewStore(policy.Limit, policy.Window)
+	roleStores := make(map[string]ratelimit.Store, len(policy.RoleLimits))
+	for role, limit := range policy.RoleLimits {
+		roleStores[role] = rl.NewStore(limit, policy.Window)
+	}
+
+	return func(c *gin.Context) {
+		store := baseStore
+		key := "ip: " + c.ClientIP()
+		group := "any"
+
+		if user, err := utilities.ExtractUser(c); err == nil && !policy.PerIP {
+			key = "user: " + user.ID.String()
+			if roleStore, ok := roleStores[user.Role]; ok {
+				store = roleStore
+				group = user.Role
+			}
+		}
+
+		info := store.Limit(fmt.Sprintf("ratelimit:%s:%s:%s", policyName, group, key), c)
+		setRateLimitHeaders(c, info, policy.Window)
+
+		if info.RateLimited {
+			errorHandler(c, info)
+			return
+		}
+		c.Next()
+	}
+}
+
+// setRateLimitHeaders write RateLimit-* headers following IETF draft for rate limit header fields.
+func setRateLimitHeaders(c *gin.Context, info ratelimit.Info, window time.Duration) {
+	c.Header("RateLimit-Limit", strconv.FormatUint(uint64(info.Limit), 10))
+	c.Header("RateLimit-Remaining", strconv.FormatUint(uint64(info.RemainingHits), 10))
+	c.Header("RateLimit-Reset", strconv.Itoa(secondsUntil(info.ResetTime)))
+	c.Header("RateLimit-Policy", fmt.Sprintf("%d;w=%d", info.Limit, int(window.Seconds())))
+}
+
+func secondsUntil(t time.Time) int {
+	seconds := int(math.Ceil(time.Until(t).Seconds()))
+	if seconds < 0 {
+		return 0
+	}
+	return seconds
+}
+
+func envUint(name string, fallback uint) uint {
+	v, err := strconv.ParseUint(os.Getenv(name), 10, 64)
+	if err != nil || v == 0 {
+		return fallback
+	}
+	if v > uint64(^uint(0)) {
+		return ^uint(0)
+	}
+	return uint(v)
+}
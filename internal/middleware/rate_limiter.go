@@ -2,7 +2,7 @@ package middleware
 
 import (
 	"HireMeMaybe-backend/internal/utilities"
-	"os"
+	"net/http"
 	"strconv"
 	"time"
 
@@ -20,8 +20,9 @@ func keyFunc(c *gin.Context) string {
 }
 
 func errorHandler(c *gin.Context, info ratelimit.Info) {
-	c.AbortWithStatusJSON(429, gin.H{
-		"error": "Too many requests. Please try again later.",
+	c.Header("Retry-After", strconv.Itoa(secondsUntil(info.ResetTime)))
+	c.AbortWithStatusJSON(http.StatusTooManyRequests, utilities.ErrorResponse{
+		Error: "Too many requests. Please try again later.",
 	})
 }
 
@@ -36,33 +37,13 @@ func RateLimiterMiddleware(reqPerSec uint) gin.HandlerFunc {
 	return ratelimit.RateLimiter(store, &ratelimit.Options{
 		KeyFunc:      keyFunc,
 		ErrorHandler: errorHandler,
+		BeforeResponse: func(c *gin.Context, info ratelimit.Info) {
+			setRateLimitHeaders(c, info, time.Second)
+		},
 	})
 }
 
 // EnvRateLimitMiddleware creates a rate limiter middleware using the RATE_LIMIT_REQUESTS_PER_SECOND environment variable.
 func EnvRateLimitMiddleware() gin.HandlerFunc {
-
-	rateLimitString := os.Getenv("RATE_LIMIT_REQUESTS_PER_SECOND")
-	var rateLimit uint
-
-	if rateLimitString == "" {
-		rateLimit = 5
-	} else {
-		// Parse as unsigned, clamp to platform max, and ensure non-zero
-		if v, err := strconv.ParseUint(rateLimitString, 10, 64); err == nil {
-			maxUint := uint64(^uint(0))
-			if v == 0 {
-				rateLimit = 5
-			} else {
-				if v > maxUint {
-					v = maxUint
-				}
-				rateLimit = uint(v)
-			}
-		} else {
-			rateLimit = 5
-		}
-	}
-
-	return RateLimiterMiddleware(rateLimit)
+	return RateLimiterMiddleware(envUint("RATE_LIMIT_REQUESTS_PER_SECOND", 5))
 }
@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"HireMeMaybe-backend/internal/audit"
+	"regexp"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// RequestIDHeader is header carrying ID of the request, echoed back in the response
+const RequestIDHeader = "X-Request-ID"
+
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// RequestID assigns ID to every request, reusing X-Request-ID header from client or proxy if it is well-formed.
+// The ID is stored in context for audit log and returned in X-Request-ID response header.
+func RequestID() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id := ctx.GetHeader(RequestIDHeader)
+		if !requestIDPattern.MatchString(id) {
+			id = uuid.NewString()
+		}
+		ctx.Set(audit.RequestIDKey, id)
+		ctx.Header(RequestIDHeader, id)
+		ctx.Next()
+	}
+}
@@ -58,7 +58,7 @@ func RequireAuth(db *database.DBinstanceStruct) gin.HandlerFunc {
 			return
 		}
 
-		claims := token.Claims.(*jwt.RegisteredClaims)
+		claims := token.Claims.(*auth.Claims)
 		ctx.Set("claims", claims)
 
 		if claims.Issuer != auth.JwtIssuer {
@@ -87,6 +87,16 @@ func RequireAuth(db *database.DBinstanceStruct) gin.HandlerFunc {
 			return
 		}
 
+		// iat has whole second precision, so token issued in the same second as the cutoff is rejected
+		// as it may have been issued before the cutoff
+		if foundUser.TokensValidAfter != nil &&
+			(claims.IssuedAt == nil || !claims.IssuedAt.Time.After(*foundUser.TokensValidAfter)) {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, utilities.ErrorResponse{
+				Error: "Token has been revoked",
+			})
+			return
+		}
+
 		ctx.Set("user", foundUser)
 		ctx.Next()
 	}
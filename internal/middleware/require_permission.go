@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"HireMeMaybe-backend/internal/database"
+	"HireMeMaybe-backend/internal/policy"
+	"HireMeMaybe-backend/internal/utilities"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequirePermission will protect endpoint from user that has none of the permissions.
+// Permissions of the user are stored in context for ownership checks in the handler.
+func RequirePermission(db *database.DBinstanceStruct, perms ...policy.Permission) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		user, err := utilities.ExtractUser(ctx)
+		if err != nil {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, utilities.ErrorResponse{Error: err.Error()})
+			return
+		}
+
+		set, err := policy.Load(db.DB, user)
+		if err != nil {
+			utilities.RespondDBError(ctx, err)
+			ctx.Abort()
+			return
+		}
+		policy.SetContext(ctx, set)
+
+		if !set.Has(perms...) {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, utilities.ErrorResponse{
+				Error: "User doesn't have permission to access",
+			})
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Each status of punishment appeal
+var (
+	AppealStatusPending  = "pending"
+	AppealStatusAccepted = "accepted"
+	AppealStatusRejected = "rejected"
+)
+
+// PunishmentAppeal is gorm model for request of punished user to lift their punishment.
+// User can appeal each punishment once, accepted appeal lifts the punishment.
+type PunishmentAppeal struct {
+	ID           uint             `gorm:"primaryKey;autoIncrement" json:"id"`
+	PunishmentID int              `gorm:"not null;uniqueIndex" json:"punishment_id"`
+	Punishment   PunishmentStruct `gorm:"foreignKey:PunishmentID;constraint:OnDelete:CASCADE" json:"punishment"`
+	UserID       uuid.UUID        `gorm:"type:uuid;not null;index" json:"user_id"`
+	User         User             `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
+	Message      string           `gorm:"type:text;not null" json:"message"`
+	Status       string           `gorm:"type:text;not null;default:'pending';index;check:status IN ('pending', 'accepted', 'rejected')" json:"status"`
+	AdminNote    string           `gorm:"type:text" json:"admin_note"`
+	ReviewedByID *uuid.UUID       `gorm:"type:uuid" json:"reviewed_by"`
+	ReviewedAt   *time.Time       `json:"reviewed_at"`
+	CreatedAt    time.Time        `json:"created_at"`
+}
@@ -14,8 +14,44 @@ var (
 	ApplicationStatusInConsideration = "in consideration"
 	// ApplicationStatusRejected indicates that the application has been rejected
 	ApplicationStatusRejected = "rejected"
+	// ApplicationStatusInterview indicates that the applicant is invited to interview
+	ApplicationStatusInterview = "interview"
+	// ApplicationStatusOffer indicates that the company has made an offer to the applicant
+	ApplicationStatusOffer = "offer"
+	// ApplicationStatusHired indicates that the applicant has been hired
+	ApplicationStatusHired = "hired"
+	// ApplicationStatusWithdrawn indicates that the applicant has withdrawn the application
+	ApplicationStatusWithdrawn = "withdrawn"
 )
 
+// applicationTransitions maps each application status to the statuses it can move to.
+// Status that has no entry is a final status.
+var applicationTransitions = map[string][]string{
+	ApplicationStatusPending: {
+		ApplicationStatusInConsideration,
+		ApplicationStatusInterview,
+		ApplicationStatusRejected,
+		ApplicationStatusWithdrawn,
+	},
+	ApplicationStatusInConsideration: {
+		ApplicationStatusInterview,
+		ApplicationStatusOffer,
+		ApplicationStatusRejected,
+		ApplicationStatusWithdrawn,
+	},
+	ApplicationStatusInterview: {
+		ApplicationStatusInConsideration,
+		ApplicationStatusOffer,
+		ApplicationStatusRejected,
+		ApplicationStatusWithdrawn,
+	},
+	ApplicationStatusOffer: {
+		ApplicationStatusHired,
+		ApplicationStatusRejected,
+		ApplicationStatusWithdrawn,
+	},
+}
+
 // Application represents a job application record
 type Application struct {
 	ID        uint      `gorm:"primaryKey;autoIncrement;->" json:"id"`
@@ -33,8 +69,36 @@ type Application struct {
 	AnswerID *uint              `json:"answer_id"`
 	Answer   *ApplicationAnswer `gorm:"foreignKey:AnswerID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;" json:"answer"`
 
+	// Answers holds answer to each question of custom form of the job post
+	Answers []FormAnswer `gorm:"foreignKey:ApplicationID;constraint:OnDelete:CASCADE" json:"answers,omitempty"`
+
 	ResumeID *int `json:"resume_id" binding:"required"`
 	Resume   File `gorm:"foreignKey:ResumeID;references:ID" json:"-"`
+
+	// History holds every status change of the application
+	History []ApplicationHistory `gorm:"foreignKey:ApplicationID;constraint:OnDelete:CASCADE" json:"history,omitempty"`
+}
+
+// CanTransitionTo reports whether the application can move from its current status to given status
+func (a *Application) CanTransitionTo(status string) bool {
+	for _, next := range applicationTransitions[a.Status] {
+		if next == status {
+			return true
+		}
+	}
+	return false
+}
+
+// ApplicationHistory represents a status change of a job application
+type ApplicationHistory struct {
+	ID            uint      `gorm:"primaryKey;autoIncrement;->" json:"id"`
+	ApplicationID uint      `gorm:"not null;index" json:"application_id"`
+	FromStatus    string    `gorm:"type:text" json:"from_status"`
+	ToStatus      string    `gorm:"type:text;not null" json:"to_status"`
+	ActorID       uuid.UUID `gorm:"type:uuid;not null;index" json:"actor_id"`
+	Actor         User      `gorm:"foreignKey:ActorID;references:ID" json:"-"`
+	Note          string    `gorm:"type:text" json:"note"`
+	ChangedAt     time.Time `gorm:"autoCreateTime" json:"changed_at"`
 }
 
 // ApplicationAnswer represents additional answer for a job application
@@ -0,0 +1,223 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/lib/pq"
+)
+
+var (
+	// QuestionTypeShortText is a single line text answer
+	QuestionTypeShortText = "short_text"
+	// QuestionTypeLongText is a multi line text answer
+	QuestionTypeLongText = "long_text"
+	// QuestionTypeSingleChoice is exactly one of question options
+	QuestionTypeSingleChoice = "single_choice"
+	// QuestionTypeMultiChoice is any number of question options
+	QuestionTypeMultiChoice = "multi_choice"
+	// QuestionTypeNumber is a numeric answer
+	QuestionTypeNumber = "number"
+	// QuestionTypeFile is a file uploaded by the applicant
+	QuestionTypeFile = "file"
+)
+
+// QuestionTypes lists every type of application form question
+var QuestionTypes = []string{
+	QuestionTypeShortText,
+	QuestionTypeLongText,
+	QuestionTypeSingleChoice,
+	QuestionTypeMultiChoice,
+	QuestionTypeNumber,
+	QuestionTypeFile,
+}
+
+// Maximum length in characters of text answer
+const (
+	ShortTextMaxLength = 200
+	LongTextMaxLength  = 5000
+)
+
+// FormQuestion is a question of custom application form defined by company on a job post
+type FormQuestion struct {
+	ID       uint           `gorm:"primaryKey;autoIncrement" json:"id"`
+	PostID   uint           `gorm:"not null;index" json:"post_id"`
+	Position int            `gorm:"not null" json:"position"`
+	Label    string         `gorm:"type:text;not null" json:"label"`
+	Type     string         `gorm:"type:text;not null;check:type IN ('short_text', 'long_text', 'single_choice', 'multi_choice', 'number', 'file')" json:"type"`
+	Required bool           `gorm:"not null;default:false" json:"required"`
+	Options  pq.StringArray `gorm:"type:text[]" json:"options,omitempty"`
+}
+
+// IsChoice reports whether answer of the question is chosen from its options
+func (q *FormQuestion) IsChoice() bool {
+	return q.Type == QuestionTypeSingleChoice || q.Type == QuestionTypeMultiChoice
+}
+
+// Validate checks that the question is well defined
+func (q *FormQuestion) Validate() error {
+	if strings.TrimSpace(q.Label) == "" {
+		return fmt.Errorf("label is required")
+	}
+	valid := false
+	for _, t := range QuestionTypes {
+		if q.Type == t {
+			valid = true
+			break
+		}
+	}
+	if !valid {
+		return fmt.Errorf("invalid question type '%s'", q.Type)
+	}
+
+	if !q.IsChoice() {
+		if len(q.Options) > 0 {
+			return fmt.Errorf("options are only allowed for choice question")
+		}
+		return nil
+	}
+	if len(q.Options) == 0 {
+		return fmt.Errorf("choice question requires at least one option")
+	}
+	seen := map[string]bool{}
+	for _, option := range q.Options {
+		if strings.TrimSpace(option) == "" {
+			return fmt.Errorf("option can't be empty")
+		}
+		if seen[option] {
+			return fmt.Errorf("duplicate option '%s'", option)
+		}
+		seen[option] = true
+	}
+	return nil
+}
+
+// FormAnswer is answer of an application to a question of the job post form.
+// Only the field matching question type is set.
+type FormAnswer struct {
+	ID            uint           `gorm:"primaryKey;autoIncrement" json:"id"`
+	ApplicationID uint           `gorm:"not null;uniqueIndex:idx_form_answer_question" json:"application_id"`
+	QuestionID    uint           `gorm:"not null;uniqueIndex:idx_form_answer_question" json:"question_id"`
+	Question      FormQuestion   `gorm:"foreignKey:QuestionID;references:ID;constraint:OnDelete:CASCADE" json:"-"`
+	Text          *string        `gorm:"type:text" json:"text,omitempty"`
+	Number        *float64       `json:"number,omitempty"`
+	Choices       pq.StringArray `gorm:"type:text[]" json:"choices,omitempty"`
+	FileID        *int           `json:"file_id,omitempty"`
+	File          *File          `gorm:"foreignKey:FileID;references:ID" json:"-"`
+}
+
+// isEmpty reports whether the answer holds no value
+func (a *FormAnswer) isEmpty() bool {
+	return (a.Text == nil || strings.TrimSpace(*a.Text) == "") && a.Number == nil && len(a.Choices) == 0 && a.FileID == nil
+}
+
+// ValidateAnswer checks that the answer fits the question, answer to other type of question is rejected
+func (q *FormQuestion) ValidateAnswer(a *FormAnswer) error {
+	if a.isEmpty() {
+		if q.Required {
+			return fmt.Errorf("question '%s' is required", q.Label)
+		}
+		return nil
+	}
+
+	textSet, numberSet, choicesSet, fileSet := a.Text != nil, a.Number != nil, len(a.Choices) > 0, a.FileID != nil
+	switch q.Type {
+	case QuestionTypeShortText, QuestionTypeLongText:
+		if !textSet || numberSet || choicesSet || fileSet {
+			return fmt.Errorf("question '%s' requires text answer", q.Label)
+		}
+		limit := ShortTextMaxLength
+		if q.Type == QuestionTypeLongText {
+			limit = LongTextMaxLength
+		}
+		if utf8.RuneCountInString(*a.Text) > limit {
+			return fmt.Errorf("answer to question '%s' is longer than %d characters", q.Label, limit)
+		}
+		if q.Type == QuestionTypeShortText && strings.ContainsAny(*a.Text, "\r\n") {
+			return fmt.Errorf("answer to question '%s' must be a single line", q.Label)
+		}
+
+	case QuestionTypeNumber:
+		if !numberSet || textSet || choicesSet || fileSet {
+			return fmt.Errorf("question '%s' requires number answer", q.Label)
+		}
+
+	case QuestionTypeSingleChoice, QuestionTypeMultiChoice:
+		if !choicesSet || textSet || numberSet || fileSet {
+			return fmt.Errorf("question '%s' requires choices answer", q.Label)
+		}
+		if q.Type == QuestionTypeSingleChoice && len(a.Choices) != 1 {
+			return fmt.Errorf("question '%s' requires exactly one choice", q.Label)
+		}
+		chosen := map[string]bool{}
+		for _, choice := range a.Choices {
+			if chosen[choice] {
+				return fmt.Errorf("duplicate choice '%s' for question '%s'", choice, q.Label)
+			}
+			chosen[choice] = true
+			found := false
+			for _, option := range q.Options {
+				if option == choice {
+					found = true
+					break
+				}
+			}
+			if !found {
+				return fmt.Errorf("'%s' is not an option of question '%s'", choice, q.Label)
+			}
+		}
+
+	case QuestionTypeFile:
+		if !fileSet || textSet || numberSet || choicesSet {
+			return fmt.Errorf("question '%s' requires file answer", q.Label)
+		}
+	}
+	return nil
+}
+
+// ValidateFormAnswers checks answers against questions of the form.
+// Every answer must refer to a question of the form at most once, and every required question must be answered.
+func ValidateFormAnswers(questions []FormQuestion, answers []FormAnswer) error {
+	byID := map[uint]*FormQuestion{}
+	for i := range questions {
+		byID[questions[i].ID] = &questions[i]
+	}
+
+	answered := map[uint]bool{}
+	for i := range answers {
+		q, ok := byID[answers[i].QuestionID]
+		if !ok {
+			return fmt.Errorf("question %d is not in the form of this job post", answers[i].QuestionID)
+		}
+		if answered[q.ID] {
+			return fmt.Errorf("question '%s' is answered more than once", q.Label)
+		}
+		answered[q.ID] = true
+		if err := q.ValidateAnswer(&answers[i]); err != nil {
+			return err
+		}
+	}
+
+	for _, q := range questions {
+		if q.Required && !answered[q.ID] {
+			return fmt.Errorf("question '%s' is required", q.Label)
+		}
+	}
+	return nil
+}
+
+// AnswerText returns the answer as plain text, file answer is given as fileURL followed by file ID
+func (a *FormAnswer) AnswerText(fileURL string) string {
+	switch {
+	case a.Text != nil:
+		return *a.Text
+	case a.Number != nil:
+		return fmt.Sprint(*a.Number)
+	case len(a.Choices) > 0:
+		return strings.Join(a.Choices, "; ")
+	case a.FileID != nil:
+		return fmt.Sprintf("%s%d", fileURL, *a.FileID)
+	}
+	return ""
+}
@@ -0,0 +1,82 @@
+package model
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ErrAuditLogAppendOnly is returned when trying to update or delete audit log
+var ErrAuditLogAppendOnly = errors.New("audit log is append-only")
+
+// JSONDocument is raw JSON stored in jsonb column, empty document is stored as NULL
+type JSONDocument []byte
+
+// Value implements driver.Valuer
+func (j JSONDocument) Value() (driver.Value, error) {
+	if len(j) == 0 {
+		return nil, nil
+	}
+	return string(j), nil
+}
+
+// Scan implements sql.Scanner
+func (j *JSONDocument) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*j = nil
+	case []byte:
+		*j = append(JSONDocument(nil), v...)
+	case string:
+		*j = JSONDocument(v)
+	default:
+		return fmt.Errorf("cannot scan %T into JSONDocument", src)
+	}
+	return nil
+}
+
+// MarshalJSON returns the document as is, or null if it is empty
+func (j JSONDocument) MarshalJSON() ([]byte, error) {
+	if len(j) == 0 {
+		return []byte("null"), nil
+	}
+	return j, nil
+}
+
+// UnmarshalJSON stores copy of the document
+func (j *JSONDocument) UnmarshalJSON(data []byte) error {
+	*j = append(JSONDocument(nil), data...)
+	return nil
+}
+
+// AuditLog is gorm model for append-only record of privileged action.
+// Before and After are state of the target around the action, Diff holds only changed fields.
+// Actor is not a foreign key so the record outlives deleted users.
+type AuditLog struct {
+	ID         uint         `gorm:"primaryKey;autoIncrement" json:"id"`
+	ActorID    uuid.UUID    `gorm:"type:uuid;not null;index" json:"actor_id"`
+	ActorName  string       `gorm:"type:text" json:"actor_name"`
+	Action     string       `gorm:"type:text;not null;index" json:"action"`
+	TargetType string       `gorm:"type:text;not null;index:idx_audit_logs_target" json:"target_type"`
+	TargetID   string       `gorm:"type:text;not null;index:idx_audit_logs_target" json:"target_id"`
+	Before     JSONDocument `gorm:"type:jsonb" json:"before" swaggertype:"object"`
+	After      JSONDocument `gorm:"type:jsonb" json:"after" swaggertype:"object"`
+	Diff       JSONDocument `gorm:"type:jsonb" json:"diff" swaggertype:"object"`
+	IP         string       `gorm:"type:text" json:"ip"`
+	RequestID  string       `gorm:"type:text;index" json:"request_id"`
+	CreatedAt  time.Time    `gorm:"index" json:"created_at"`
+}
+
+// BeforeUpdate rejects every update of audit log
+func (a *AuditLog) BeforeUpdate(*gorm.DB) error {
+	return ErrAuditLogAppendOnly
+}
+
+// BeforeDelete rejects every deletion of audit log
+func (a *AuditLog) BeforeDelete(*gorm.DB) error {
+	return ErrAuditLogAppendOnly
+}
@@ -1,5 +1,7 @@
 package model
 
+import "github.com/google/uuid"
+
 // The File struct represents a file with an ID, content stored as bytes, and an extension.
 // @property {int} ID - The `ID` property in the `File` struct is an integer field that is marked as
 // the primary key in the database using the `gorm:"primaryKey"` tag. This means that the `ID` field
@@ -15,4 +17,6 @@ type File struct {
 	Content           []byte
 	Extension         string
 	StorageObjectName *string `gorm:"uniqueIndex"`
+	// UploaderID is set for file uploaded as answer to application form, so only the uploader can attach it
+	UploaderID *uuid.UUID `gorm:"type:uuid;index" json:"-"`
 }
@@ -0,0 +1,19 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UserIdentity is gorm model for store account of external OpenID Connect provider linked to user.
+// Google accounts are stored in User.GoogleID instead.
+type UserIdentity struct {
+	ID        uint      `gorm:"primaryKey;autoIncrement"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null;index"`
+	User      User      `gorm:"foreignKey:UserID;references:ID;constraint:OnDelete:CASCADE"`
+	Provider  string    `gorm:"type:text;not null;uniqueIndex:idx_identity_provider_subject"`
+	Subject   string    `gorm:"type:text;not null;uniqueIndex:idx_identity_provider_subject"`
+	Email     string    `gorm:"type:text"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
@@ -8,6 +8,48 @@ import (
 	"github.com/lib/pq"
 )
 
+// Lifecycle status of job post
+var (
+	// JobPostStatusDraft is post not yet published, only visible to its company
+	JobPostStatusDraft = "draft"
+	// JobPostStatusPublished is post listed and open for application until it expires
+	JobPostStatusPublished = "published"
+	// JobPostStatusClosed is post no longer open for application, set by its company or on expiry
+	JobPostStatusClosed = "closed"
+	// JobPostStatusHidden is post taken down by moderation, still visible to its company
+	JobPostStatusHidden = "hidden"
+	// JobPostStatusArchived is post deleted by its company or removed by moderation, only visible to admin
+	JobPostStatusArchived = "archived"
+)
+
+// JobPostStatuses lists every lifecycle status of job post
+var JobPostStatuses = []string{
+	JobPostStatusDraft,
+	JobPostStatusPublished,
+	JobPostStatusClosed,
+	JobPostStatusHidden,
+	JobPostStatusArchived,
+}
+
+// jobPostTransitions maps each job post status to the statuses it can move to by status update.
+// Archived is a final status, post is archived by deletion.
+var jobPostTransitions = map[string][]string{
+	JobPostStatusDraft: {
+		JobPostStatusPublished,
+	},
+	JobPostStatusPublished: {
+		JobPostStatusClosed,
+		JobPostStatusHidden,
+	},
+	JobPostStatusClosed: {
+		JobPostStatusPublished,
+		JobPostStatusHidden,
+	},
+	JobPostStatusHidden: {
+		JobPostStatusPublished,
+	},
+}
+
 // EditableJobPostInfo is part of job post that can be edited
 type EditableJobPostInfo struct {
 	Title         string         `gorm:"type:text" json:"title"`
@@ -31,6 +73,34 @@ type JobPost struct {
 	PostTime     time.Time     `gorm:"type:timestamp;default:CURRENT_TIMESTAMP;->" json:"post_time"`
 	Applications []Application `gorm:"foreignKey:PostID;constraint:OnDelete:CASCADE" json:"applications"`
 	DefaultForm  bool          `gorm:"type:boolean;default:true" json:"default_form"`
+	// Questions is custom application form of the post, asked in addition to default form if it is used
+	Questions []FormQuestion `gorm:"foreignKey:PostID;constraint:OnDelete:CASCADE" json:"questions,omitempty"`
+
+	// Status is lifecycle status of the post, only published post is listed and can be applied
+	Status string `gorm:"type:text;not null;default:'published';index;check:status IN ('draft', 'published', 'closed', 'hidden', 'archived')" json:"status"`
+}
+
+// CurrentStatus returns status of the post at time now, published post that has expired is closed
+// even before it is closed by database.CloseExpiredPosts.
+func (j *JobPost) CurrentStatus(now time.Time) string {
+	if j.Status == JobPostStatusPublished && j.Expiring != nil && !j.Expiring.After(now) {
+		return JobPostStatusClosed
+	}
+	return j.Status
+}
+
+// CanTransitionTo reports whether the post can move from its current status at time now to given status.
+// Post can only be published again if it has not expired.
+func (j *JobPost) CanTransitionTo(status string, now time.Time) bool {
+	if status == JobPostStatusPublished && j.Expiring != nil && !j.Expiring.After(now) {
+		return false
+	}
+	for _, next := range jobPostTransitions[j.CurrentStatus(now)] {
+		if next == status {
+			return true
+		}
+	}
+	return false
 }
 
 // JobPostResponse is the response struct for job post with user application status
@@ -41,7 +111,9 @@ type JobPostResponse struct {
 	PostTime      time.Time   `json:"post_time"`
 	UserApply     bool        `json:"user_apply"`
 	DefaultForm   bool        `json:"default_form"`
+	Status        string      `json:"status"`
 	EditableJobPostInfo
+	Questions []FormQuestion `json:"questions,omitempty"`
 }
 
 // ToJobPostResponse converts JobPost to JobPostResponse
@@ -70,6 +142,7 @@ func (j *JobPost) ToJobPostResponse(user User) (JobPostResponse, error) {
 		}
 	}
 	resp.UserApply = userApply
+	resp.Status = j.CurrentStatus(time.Now())
 
 	return resp, nil
 }
@@ -0,0 +1,13 @@
+package model
+
+import "time"
+
+// LoginLockout is gorm model for tracking failed local login attempts.
+// Identifier is either "username:<username>" or "ip:<client ip>".
+type LoginLockout struct {
+	Identifier   string     `gorm:"primaryKey;type:text" json:"identifier"`
+	FailedCount  int        `gorm:"not null;default:0" json:"failed_count"`
+	LockoutCount int        `gorm:"not null;default:0" json:"lockout_count"`
+	LastFailedAt time.Time  `json:"last_failed_at"`
+	LockedUntil  *time.Time `gorm:"index" json:"locked_until"`
+}
@@ -7,8 +7,9 @@ type AccessToken struct {
 
 // CPSKResponse struct holds the response data for CPSK student user login or registration
 type CPSKResponse struct {
-	User        CPSKUser `json:"user"`
-	AccessToken string   `json:"access_token"`
+	User         CPSKUser `json:"user"`
+	AccessToken  string   `json:"access_token"`
+	RefreshToken string   `json:"refresh_token"`
 }
 
 // SetAccessToken sets the access token in the CPSKResponse
@@ -18,8 +19,9 @@ func (r *CPSKResponse) SetAccessToken(accessToken string) {
 
 // CompanyResponse struct holds the response data for Company user login or registration
 type CompanyResponse struct {
-	User        CompanyUser `json:"user"`
-	AccessToken string      `json:"access_token"`
+	User         CompanyUser `json:"user"`
+	AccessToken  string      `json:"access_token"`
+	RefreshToken string      `json:"refresh_token"`
 }
 
 // SetAccessToken sets the access token in the CompanyResponse
@@ -29,8 +31,9 @@ func (r *CompanyResponse) SetAccessToken(accessToken string) {
 
 // VisitorResponse struct holds the response data for Visitor user login or registration
 type VisitorResponse struct {
-	User        VisitorUser `json:"user"`
-	AccessToken string      `json:"access_token"`
+	User         VisitorUser `json:"user"`
+	AccessToken  string      `json:"access_token"`
+	RefreshToken string      `json:"refresh_token"`
 }
 
 // SetAccessToken sets the access token in the VisitorResponse
@@ -0,0 +1,40 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Role of user within company organization
+const (
+	OrgRoleOwner     = "owner"
+	OrgRoleRecruiter = "recruiter"
+	OrgRoleViewer    = "viewer"
+)
+
+// OrganizationMember is gorm model for user belonging to hiring team of a company.
+// Organization is identified by CompanyUser.UserID and the company account is always its owner,
+// so only other members are stored. User can be member of one organization.
+type OrganizationMember struct {
+	UserID    uuid.UUID   `gorm:"type:uuid;primaryKey" json:"user_id"`
+	User      User        `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"user"`
+	CompanyID uuid.UUID   `gorm:"type:uuid;not null;index" json:"company_id"`
+	Company   CompanyUser `gorm:"foreignKey:CompanyID;references:UserID;constraint:OnDelete:CASCADE" json:"-"`
+	Role      string      `gorm:"type:text;not null;check:role IN ('owner', 'recruiter', 'viewer')" json:"role"`
+	CreatedAt time.Time   `json:"joined_at"`
+}
+
+// OrganizationInvitation is gorm model for pending invitation to join company organization.
+// Only SHA-256 hash of the invitation token is stored, the invitation is deleted once accepted.
+type OrganizationInvitation struct {
+	ID          uint        `gorm:"primaryKey;autoIncrement" json:"id"`
+	CompanyID   uuid.UUID   `gorm:"type:uuid;not null;index" json:"company_id"`
+	Company     CompanyUser `gorm:"foreignKey:CompanyID;references:UserID;constraint:OnDelete:CASCADE" json:"-"`
+	Email       string      `gorm:"type:text;not null" json:"email"`
+	Role        string      `gorm:"type:text;not null;check:role IN ('owner', 'recruiter', 'viewer')" json:"role"`
+	TokenHash   string      `gorm:"type:text;not null;uniqueIndex" json:"-"`
+	InvitedByID uuid.UUID   `gorm:"type:uuid;not null" json:"invited_by"`
+	ExpiresAt   time.Time   `gorm:"not null" json:"expires_at"`
+	CreatedAt   time.Time   `json:"created_at"`
+}
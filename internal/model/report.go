@@ -2,6 +2,7 @@ package model
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -13,6 +14,15 @@ var (
 	ReportStatusRejected = "rejected"
 )
 
+// Moderation action admin can take when resolving a report
+var (
+	ReportActionWarn       = "warn"
+	ReportActionHidePost   = "hide_post"
+	ReportActionDeletePost = "delete_post"
+	ReportActionSuspend    = "suspend"
+	ReportActionBan        = "ban"
+)
+
 // UpdateableReport defines the interface for reports that can have their status updated.
 type UpdateableReport interface {
 	UpdateStatus(newStatus string, adminNote string) error
@@ -45,6 +55,24 @@ type ReportCommon struct {
 	Status     string `gorm:"type:text;default:'pending';constraint:check(status in ('pending', 'resolved', 'rejected'))" json:"status"`
 
 	AdminNote string `gorm:"type:text" json:"admin_note"`
+
+	// Action is moderation action taken on author of reported user or post when the report was resolved.
+	// PunishmentID links punishment issued by suspend or ban action.
+	Action       *string    `gorm:"type:text;check:action IN ('warn', 'hide_post', 'delete_post', 'suspend', 'ban')" json:"action"`
+	ActionDays   *int       `json:"action_days"`
+	ActionByID   *uuid.UUID `gorm:"type:uuid" json:"action_by"`
+	ActionAt     *time.Time `json:"action_at"`
+	PunishmentID *int       `json:"punishment_id"`
+}
+
+// ReportClaim is gorm model for admin taking a moderation case.
+// Case is every pending report on the same target, identified by report type and ID of reported user or post.
+type ReportClaim struct {
+	TargetType string    `gorm:"type:text;primaryKey;check:target_type IN ('user', 'post')" json:"target_type"`
+	TargetID   string    `gorm:"type:text;primaryKey" json:"target_id"`
+	AdminID    uuid.UUID `gorm:"type:uuid;not null;index" json:"admin_id"`
+	Admin      User      `gorm:"foreignKey:AdminID;constraint:OnDelete:CASCADE" json:"-"`
+	ClaimedAt  time.Time `gorm:"autoCreateTime" json:"claimed_at"`
 }
 
 // UpdateStatus updates the status and admin note of the report.
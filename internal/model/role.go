@@ -0,0 +1,27 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// CustomRole is gorm model for role defined by admin, such as moderator.
+// Permissions of custom role are granted to assigned users on top of their built-in role.
+type CustomRole struct {
+	Name        string         `gorm:"primaryKey;type:text" json:"name"`
+	Description string         `json:"description"`
+	Permissions pq.StringArray `gorm:"type:text[]" json:"permissions"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+}
+
+// UserCustomRole is gorm model assigning custom role to user
+type UserCustomRole struct {
+	UserID     uuid.UUID  `gorm:"type:uuid;primaryKey" json:"user_id"`
+	User       User       `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
+	RoleName   string     `gorm:"primaryKey;type:text" json:"role_name"`
+	CustomRole CustomRole `gorm:"foreignKey:RoleName;references:Name;constraint:OnDelete:CASCADE" json:"-"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
@@ -0,0 +1,50 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RefreshToken is gorm model for store issued refresh token.
+// Only SHA-256 hash of the token is stored, tokens issued from the same login share FamilyID.
+type RefreshToken struct {
+	ID        uint      `gorm:"primaryKey;autoIncrement"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null;index"`
+	User      User      `gorm:"foreignKey:UserID;references:ID;constraint:OnDelete:CASCADE"`
+	FamilyID  uuid.UUID `gorm:"type:uuid;not null;index"`
+	TokenHash string    `gorm:"type:text;not null;uniqueIndex"`
+	ExpiresAt time.Time `gorm:"not null"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+	UsedAt    *time.Time
+	RevokedAt *time.Time
+}
+
+// RevokedToken is gorm model for store blacklisted access token ID (jti) until it expire.
+type RevokedToken struct {
+	JTI       string    `gorm:"primaryKey;type:text"`
+	ExpiresAt time.Time `gorm:"not null;index"`
+}
+
+// PasswordResetToken is gorm model for store hashed single use password reset token.
+type PasswordResetToken struct {
+	ID        uint      `gorm:"primaryKey;autoIncrement"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null;index"`
+	User      User      `gorm:"foreignKey:UserID;references:ID;constraint:OnDelete:CASCADE"`
+	TokenHash string    `gorm:"type:text;not null;uniqueIndex"`
+	ExpiresAt time.Time `gorm:"not null"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+	UsedAt    *time.Time
+}
+
+// EmailVerificationToken is gorm model for store hashed token proving ownership of Email.
+// Email is assigned to the user only after the token is used.
+type EmailVerificationToken struct {
+	ID        uint      `gorm:"primaryKey;autoIncrement"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null;index"`
+	User      User      `gorm:"foreignKey:UserID;references:ID;constraint:OnDelete:CASCADE"`
+	Email     string    `gorm:"type:text;not null"`
+	TokenHash string    `gorm:"type:text;not null;uniqueIndex"`
+	ExpiresAt time.Time `gorm:"not null"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
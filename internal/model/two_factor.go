@@ -0,0 +1,38 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TOTPCredential is gorm model for store TOTP secret of user.
+// Credential is not used for login until it is confirmed with a valid code.
+type TOTPCredential struct {
+	UserID    uuid.UUID `gorm:"type:uuid;primaryKey"`
+	User      User      `gorm:"foreignKey:UserID;references:ID;constraint:OnDelete:CASCADE"`
+	Secret    string    `gorm:"type:text;not null"`
+	Enabled   bool      `gorm:"not null;default:false"`
+	EnabledAt *time.Time
+	// LastUsedStep is the last accepted TOTP time step, used to reject replayed code
+	LastUsedStep int64
+}
+
+// RecoveryCode is gorm model for store hashed single use 2FA recovery code.
+type RecoveryCode struct {
+	ID       uint      `gorm:"primaryKey;autoIncrement"`
+	UserID   uuid.UUID `gorm:"type:uuid;not null;index"`
+	User     User      `gorm:"foreignKey:UserID;references:ID;constraint:OnDelete:CASCADE"`
+	CodeHash string    `gorm:"type:text;not null"`
+	UsedAt   *time.Time
+}
+
+// TwoFactorChallenge is gorm model for pending login waiting for second factor.
+type TwoFactorChallenge struct {
+	ID        uint      `gorm:"primaryKey;autoIncrement"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null;index"`
+	User      User      `gorm:"foreignKey:UserID;references:ID;constraint:OnDelete:CASCADE"`
+	TokenHash string    `gorm:"type:text;not null;uniqueIndex"`
+	ExpiresAt time.Time `gorm:"not null"`
+	Attempts  int       `gorm:"not null;default:0"`
+}
@@ -38,6 +38,8 @@ type GoogleUserInfo struct {
 	FirstName      string `json:"given_name"`
 	LastName       string `json:"family_name"`
 	Email          string `json:"email"`
+	EmailVerified  bool   `json:"email_verified"`
+	HostedDomain   string `json:"hd"`
 	ProfilePicture string `json:"picture"`
 }
 
@@ -69,18 +71,41 @@ type EditableVisitorInfo struct {
 	LastName  string `json:"last_name"`
 }
 
-// PunishmentStruct is for storing punishment detail like ban or suspend
+// Each type of report that punishment can be linked to
+var (
+	ReportTypeUser = "user"
+	ReportTypePost = "post"
+)
+
+// PunishmentStruct is for storing punishment detail like ban or suspend.
+// Every punishment issued to user is kept as history, lifted or expired one included.
+// UserID and admin IDs are not foreign keys so history outlives deleted users.
 type PunishmentStruct struct {
-	ID             int       `gorm:"primaryKey;autoIncrement;->" json:"-"`
+	ID             int        `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID         *uuid.UUID `gorm:"type:uuid;index" json:"user_id"`
 	PunishmentType string     `json:"type"`
 	PunishAt       *time.Time `json:"at"`
 	PunishEnd      *time.Time `json:"end"`
+	Reason         string     `gorm:"type:text" json:"reason"`
+	IssuedByID     *uuid.UUID `gorm:"type:uuid" json:"issued_by"`
+	ReportType     *string    `gorm:"type:text;check:report_type IN ('user', 'post')" json:"report_type"`
+	ReportID       *uint      `json:"report_id"`
+	LiftedAt       *time.Time `json:"lifted_at"`
+	LiftedByID     *uuid.UUID `gorm:"type:uuid" json:"lifted_by"`
+	LiftReason     string     `gorm:"type:text" json:"lift_reason"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// IsActive reports whether punishment is not lifted and not yet expired at given time
+func (p *PunishmentStruct) IsActive(now time.Time) bool {
+	return p.LiftedAt == nil && (p.PunishEnd == nil || p.PunishEnd.After(now))
 }
 
 // UserModel interface defines methods for user models
 type UserModel interface {
-	GetLoginResponse(string) interface{}
+	GetLoginResponse(accessToken string, refreshToken string) interface{}
 	GetID() uuid.UUID
+	GetRole() string
 	FillGoogleInfo(uInfo GoogleUserInfo)
 }
 
@@ -94,9 +119,16 @@ type User struct {
 	Username       string            `json:"username" gorm:"<-:create"`
 	Password       string            `json:"-"`
 	Role           string            `json:"role"`
+	// PunishmentID points to active punishment computed from punishment history of the user
 	PunishmentID   *int              `json:"-"`
-	Punishment     *PunishmentStruct `json:"punishment"`
+	Punishment     *PunishmentStruct `json:"punishment" gorm:"foreignKey:PunishmentID"`
 	ProfilePicture string            `json:"profile_picture"`
+
+	// EmailVerified is true when owner of the user proved they own Email
+	EmailVerified bool `json:"email_verified" gorm:"not null;default:false"`
+
+	// TokensValidAfter reject every access token issued before this time
+	TokensValidAfter *time.Time `json:"-"`
 }
 
 // GetID returns the user's UUID
@@ -107,6 +139,7 @@ func (u *User) GetID() uuid.UUID {
 // FillGoogleInfo fills the user struct with Google user info and assigns the role
 func (u *User) FillGoogleInfo(uInfo GoogleUserInfo, role string) {
 	u.Email = &uInfo.Email
+	u.EmailVerified = uInfo.EmailVerified
 	u.GoogleID = uInfo.GID
 	u.Username = uInfo.FirstName
 	u.ProfilePicture = uInfo.ProfilePicture
@@ -126,8 +159,8 @@ type CPSKUser struct {
 }
 
 // GetLoginResponse constructs the login response for CPSK user
-func (c *CPSKUser) GetLoginResponse(accessToken string) interface{} {
-	return CPSKResponse{User: *c, AccessToken: accessToken}
+func (c *CPSKUser) GetLoginResponse(accessToken string, refreshToken string) interface{} {
+	return CPSKResponse{User: *c, AccessToken: accessToken, RefreshToken: refreshToken}
 }
 
 // GetID returns the CPSK user's UUID
@@ -135,6 +168,11 @@ func (c *CPSKUser) GetID() uuid.UUID {
 	return c.User.GetID()
 }
 
+// GetRole returns role of CPSK user
+func (c *CPSKUser) GetRole() string {
+	return RoleCPSK
+}
+
 // FillGoogleInfo fills the CPSK user struct with Google user info
 func (c *CPSKUser) FillGoogleInfo(uInfo GoogleUserInfo) {
 	c.User.FillGoogleInfo(uInfo, RoleCPSK)
@@ -158,8 +196,8 @@ type CompanyUser struct {
 }
 
 // GetLoginResponse constructs the login response for Company user
-func (c *CompanyUser) GetLoginResponse(accessToken string) interface{} {
-	return &CompanyResponse{User: *c, AccessToken: accessToken}
+func (c *CompanyUser) GetLoginResponse(accessToken string, refreshToken string) interface{} {
+	return &CompanyResponse{User: *c, AccessToken: accessToken, RefreshToken: refreshToken}
 }
 
 // GetID returns the Company user's UUID
@@ -167,6 +205,11 @@ func (c *CompanyUser) GetID() uuid.UUID {
 	return c.User.GetID()
 }
 
+// GetRole returns role of Company user
+func (c *CompanyUser) GetRole() string {
+	return RoleCompany
+}
+
 // FillGoogleInfo fills the Company user struct with Google user info and sets verification status
 func (c *CompanyUser) FillGoogleInfo(uInfo GoogleUserInfo) {
 
@@ -187,8 +230,8 @@ type VisitorUser struct {
 }
 
 // GetLoginResponse constructs the login response for Visitor user
-func (v *VisitorUser) GetLoginResponse(accessToken string) interface{} {
-	return &VisitorResponse{User: *v, AccessToken: accessToken}
+func (v *VisitorUser) GetLoginResponse(accessToken string, refreshToken string) interface{} {
+	return &VisitorResponse{User: *v, AccessToken: accessToken, RefreshToken: refreshToken}
 }
 
 // GetID returns the Visitor user's UUID
@@ -196,6 +239,11 @@ func (v *VisitorUser) GetID() uuid.UUID {
 	return v.User.GetID()
 }
 
+// GetRole returns role of Visitor user
+func (v *VisitorUser) GetRole() string {
+	return RoleVisitor
+}
+
 // FillGoogleInfo fills the Visitor user struct with Google user info
 func (v *VisitorUser) FillGoogleInfo(uInfo GoogleUserInfo) {
 	v.User.FillGoogleInfo(uInfo, RoleVisitor)
@@ -13,9 +13,28 @@ func init() {
 		&JobPost{},
 		&Application{},
 		&ApplicationAnswer{},
+		&ApplicationHistory{},
+		&FormQuestion{},
+		&FormAnswer{},
 		&ReportOnPost{},
 		&ReportOnUser{},
+		&ReportClaim{},
 		&PunishmentStruct{},
 		&VisitorUser{},
+		&RefreshToken{},
+		&RevokedToken{},
+		&LoginLockout{},
+		&TOTPCredential{},
+		&RecoveryCode{},
+		&TwoFactorChallenge{},
+		&PasswordResetToken{},
+		&EmailVerificationToken{},
+		&UserIdentity{},
+		&CustomRole{},
+		&UserCustomRole{},
+		&OrganizationMember{},
+		&OrganizationInvitation{},
+		&AuditLog{},
+		&PunishmentAppeal{},
 	)
 }
@@ -0,0 +1,104 @@
+package policy
+
+import (
+	"HireMeMaybe-backend/internal/model"
+	"HireMeMaybe-backend/internal/utilities"
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// membershipContextKey is key of Membership of current user stored in gin context
+const membershipContextKey = "organization"
+
+// organizationRolePermissions is permissions granted to member by role within company organization.
+// Scope own of the permissions refers to resources of the organization.
+var organizationRolePermissions = map[string][]Permission{
+	model.OrgRoleOwner: {
+		JobPostCreate, JobPostEditOwn, JobPostDeleteOwn,
+		ApplicationViewOwn, ApplicationReviewOwn, CompanyProfile, OrganizationManage,
+	},
+	model.OrgRoleRecruiter: {
+		JobPostCreate, JobPostEditOwn, JobPostDeleteOwn,
+		ApplicationViewOwn, ApplicationReviewOwn,
+	},
+	model.OrgRoleViewer: {
+		ApplicationViewOwn,
+	},
+}
+
+// IsOrganizationRole reports whether the name is a role within company organization.
+func IsOrganizationRole(name string) bool {
+	_, ok := organizationRolePermissions[name]
+	return ok
+}
+
+// Membership is company organization the user acts for and the user's role in it.
+// Zero Membership means user is not in any organization.
+type Membership struct {
+	CompanyID uuid.UUID `json:"company_id"`
+	Role      string    `json:"role"`
+}
+
+// IsMember reports whether the membership refers to an organization.
+func (m Membership) IsMember() bool {
+	return m.CompanyID != uuid.Nil
+}
+
+// LoadMembership returns organization of the user. Company account is owner of its own organization.
+func LoadMembership(db *gorm.DB, user model.User) (Membership, error) {
+	if user.Role == model.RoleCompany {
+		return Membership{CompanyID: user.ID, Role: model.OrgRoleOwner}, nil
+	}
+
+	var member model.OrganizationMember
+	err := db.Where("user_id = ?", user.ID).First(&member).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return Membership{}, nil
+	}
+	if err != nil {
+		return Membership{}, err
+	}
+	return Membership{CompanyID: member.CompanyID, Role: member.Role}, nil
+}
+
+// MembershipFromContext returns organization of the current user, loading it from the database once per request.
+func MembershipFromContext(c *gin.Context, db *gorm.DB) (Membership, error) {
+	if v, ok := c.Get(membershipContextKey); ok {
+		if m, ok := v.(Membership); ok {
+			return m, nil
+		}
+	}
+
+	user, err := utilities.ExtractUser(c)
+	if err != nil {
+		return Membership{}, err
+	}
+
+	m, err := LoadMembership(db, user)
+	if err != nil {
+		return Membership{}, err
+	}
+	c.Set(membershipContextKey, m)
+	return m, nil
+}
+
+// CanOnCompany reports whether the current user can take the action on resource of companyID,
+// using permission own if user is member of the company organization or permission other otherwise.
+func CanOnCompany(c *gin.Context, db *gorm.DB, own Permission, other Permission, companyID uuid.UUID) (bool, error) {
+	perms, err := FromContext(c, db)
+	if err != nil {
+		return false, err
+	}
+	if perms.Has(other) {
+		return true, nil
+	}
+
+	m, err := MembershipFromContext(c, db)
+	if err != nil {
+		return false, err
+	}
+	return m.IsMember() && perms.CanOwned(m.CompanyID, own, other, companyID), nil
+}
@@ -0,0 +1,188 @@
+// Package policy defines permissions granted to each role and checks them against the current user.
+package policy
+
+import (
+	"HireMeMaybe-backend/internal/model"
+	"HireMeMaybe-backend/internal/utilities"
+	"sort"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Permission is an action user can take, written as resource:action or resource:action:scope.
+// Scope own only allows the action on resource owned by the user, scope any allows it on every resource.
+type Permission string
+
+// Each permission
+const (
+	JobPostCreate        Permission = "jobpost:create"
+	JobPostEditOwn       Permission = "jobpost:edit:own"
+	JobPostEditAny       Permission = "jobpost:edit:any"
+	JobPostDeleteOwn     Permission = "jobpost:delete:own"
+	JobPostDeleteAny     Permission = "jobpost:delete:any"
+	ApplicationApply     Permission = "application:apply"
+	ApplicationViewOwn   Permission = "application:view:own"
+	ApplicationViewAny   Permission = "application:view:any"
+	ApplicationReviewOwn Permission = "application:review:own"
+	ApplicationReviewAny Permission = "application:review:any"
+	CompanyProfile       Permission = "company:profile"
+	CompanyVerify        Permission = "company:verify"
+	CPSKProfile          Permission = "cpsk:profile"
+	ReportCreatePost     Permission = "report:create:post"
+	ReportView           Permission = "report:view"
+	ReportResolve        Permission = "report:resolve"
+	UserList             Permission = "user:list"
+	UserPunish           Permission = "user:punish"
+	LoginUnlock          Permission = "login:unlock"
+	RoleManage           Permission = "role:manage"
+	OrganizationManage   Permission = "organization:manage"
+	AuditView            Permission = "audit:view"
+)
+
+// permissionsContextKey is key of PermissionSet of current user stored in gin context
+const permissionsContextKey = "permissions"
+
+var allPermissions = []Permission{
+	JobPostCreate, JobPostEditOwn, JobPostEditAny, JobPostDeleteOwn, JobPostDeleteAny,
+	ApplicationApply, ApplicationViewOwn, ApplicationViewAny, ApplicationReviewOwn, ApplicationReviewAny,
+	CompanyProfile, CompanyVerify, CPSKProfile,
+	ReportCreatePost, ReportView, ReportResolve,
+	UserList, UserPunish, LoginUnlock, RoleManage, OrganizationManage, AuditView,
+}
+
+// rolePermissions is permissions granted by each built-in role
+var rolePermissions = map[string][]Permission{
+	model.RoleCPSK: {
+		ApplicationApply, CPSKProfile, ReportCreatePost,
+	},
+	model.RoleVisitor: {
+		ReportCreatePost,
+	},
+	model.RoleCompany: {
+		JobPostCreate, JobPostEditOwn, JobPostDeleteOwn,
+		ApplicationViewOwn, ApplicationReviewOwn, CompanyProfile, OrganizationManage,
+	},
+	model.RoleAdmin: {
+		JobPostEditAny, JobPostDeleteAny, CompanyVerify,
+		ReportView, ReportResolve, UserList, UserPunish, LoginUnlock, RoleManage, AuditView,
+	},
+}
+
+// AllPermissions returns every known permission.
+func AllPermissions() []Permission {
+	return append([]Permission(nil), allPermissions...)
+}
+
+// IsValid reports whether the permission is known.
+func IsValid(p Permission) bool {
+	for _, known := range allPermissions {
+		if p == known {
+			return true
+		}
+	}
+	return false
+}
+
+// IsBuiltinRole reports whether the name is one of built-in role stored in User.Role.
+func IsBuiltinRole(name string) bool {
+	_, ok := rolePermissions[name]
+	return ok
+}
+
+// RolePermissions returns permissions granted by the built-in role.
+func RolePermissions(role string) []Permission {
+	return append([]Permission(nil), rolePermissions[role]...)
+}
+
+// PermissionSet is set of permissions granted to user.
+type PermissionSet map[Permission]struct{}
+
+// Has reports whether the set contains any of the permissions.
+func (s PermissionSet) Has(perms ...Permission) bool {
+	for _, p := range perms {
+		if _, ok := s[p]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// List returns the permissions in the set, sorted.
+func (s PermissionSet) List() []Permission {
+	list := make([]Permission, 0, len(s))
+	for p := range s {
+		list = append(list, p)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i] < list[j] })
+	return list
+}
+
+// CanOwned reports whether user acting as actorID can take the action on resource owned by ownerID,
+// using permission own if actorID is the owner or permission other otherwise.
+func (s PermissionSet) CanOwned(actorID uuid.UUID, own Permission, other Permission, ownerID uuid.UUID) bool {
+	if s.Has(other) {
+		return true
+	}
+	return actorID == ownerID && s.Has(own)
+}
+
+// Load returns permissions of the user's built-in role, role within company organization
+// and custom roles assigned to the user.
+func Load(db *gorm.DB, user model.User) (PermissionSet, error) {
+	set := PermissionSet{}
+	for _, p := range rolePermissions[user.Role] {
+		set[p] = struct{}{}
+	}
+
+	m, err := LoadMembership(db, user)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range organizationRolePermissions[m.Role] {
+		set[p] = struct{}{}
+	}
+
+	var roles []model.CustomRole
+	err = db.Model(&model.CustomRole{}).
+		Joins("JOIN user_custom_roles ON user_custom_roles.role_name = custom_roles.name").
+		Where("user_custom_roles.user_id = ?", user.ID).
+		Find(&roles).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, role := range roles {
+		for _, p := range role.Permissions {
+			set[Permission(p)] = struct{}{}
+		}
+	}
+	return set, nil
+}
+
+// FromContext returns permissions of the current user, stored in context by RequirePermission
+// middleware or loaded from the database if the middleware was not used.
+func FromContext(c *gin.Context, db *gorm.DB) (PermissionSet, error) {
+	if v, ok := c.Get(permissionsContextKey); ok {
+		if set, ok := v.(PermissionSet); ok {
+			return set, nil
+		}
+	}
+
+	user, err := utilities.ExtractUser(c)
+	if err != nil {
+		return nil, err
+	}
+
+	set, err := Load(db, user)
+	if err != nil {
+		return nil, err
+	}
+	SetContext(c, set)
+	return set, nil
+}
+
+// SetContext stores permissions of the current user in context.
+func SetContext(c *gin.Context, set PermissionSet) {
+	c.Set(permissionsContextKey, set)
+}
@@ -0,0 +1,59 @@
+package policy
+
+import (
+	"HireMeMaybe-backend/internal/model"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRolePermissions(t *testing.T) {
+	for _, role := range []string{model.RoleAdmin, model.RoleCompany, model.RoleCPSK, model.RoleVisitor} {
+		assert.True(t, IsBuiltinRole(role))
+		for _, p := range RolePermissions(role) {
+			assert.True(t, IsValid(p), "%s of %s must be listed in allPermissions", p, role)
+		}
+	}
+	assert.False(t, IsBuiltinRole("moderator"))
+	assert.False(t, IsValid("jobpost:fly"))
+
+	assert.Contains(t, RolePermissions(model.RoleCompany), JobPostEditOwn)
+	assert.NotContains(t, RolePermissions(model.RoleCompany), JobPostEditAny)
+	assert.Contains(t, RolePermissions(model.RoleAdmin), ReportResolve)
+	assert.NotContains(t, RolePermissions(model.RoleVisitor), ApplicationApply)
+}
+
+func TestPermissionSetCanOwned(t *testing.T) {
+	owner := uuid.New()
+	other := uuid.New()
+
+	company := PermissionSet{JobPostEditOwn: {}}
+	assert.True(t, company.CanOwned(owner, JobPostEditOwn, JobPostEditAny, owner))
+	assert.False(t, company.CanOwned(other, JobPostEditOwn, JobPostEditAny, owner))
+
+	moderator := PermissionSet{JobPostEditAny: {}}
+	assert.True(t, moderator.CanOwned(other, JobPostEditOwn, JobPostEditAny, owner))
+
+	assert.False(t, PermissionSet{}.CanOwned(owner, JobPostEditOwn, JobPostEditAny, owner))
+	assert.True(t, moderator.Has(ReportView, JobPostEditAny))
+	assert.Equal(t, []Permission{JobPostEditAny, JobPostEditOwn}, PermissionSet{JobPostEditOwn: {}, JobPostEditAny: {}}.List())
+}
+
+func TestOrganizationRolePermissions(t *testing.T) {
+	for _, role := range []string{model.OrgRoleOwner, model.OrgRoleRecruiter, model.OrgRoleViewer} {
+		assert.True(t, IsOrganizationRole(role))
+		for _, p := range organizationRolePermissions[role] {
+			assert.True(t, IsValid(p), "%s of organization %s must be listed in allPermissions", p, role)
+		}
+	}
+	assert.False(t, IsOrganizationRole(model.RoleAdmin))
+
+	assert.Contains(t, organizationRolePermissions[model.OrgRoleOwner], OrganizationManage)
+	assert.NotContains(t, organizationRolePermissions[model.OrgRoleRecruiter], OrganizationManage)
+	assert.NotContains(t, organizationRolePermissions[model.OrgRoleViewer], JobPostEditOwn)
+	assert.Contains(t, RolePermissions(model.RoleCompany), OrganizationManage)
+
+	assert.False(t, Membership{}.IsMember())
+	assert.True(t, Membership{CompanyID: uuid.New(), Role: model.OrgRoleViewer}.IsMember())
+}
@@ -2,19 +2,26 @@
 package server
 
 import (
+	"HireMeMaybe-backend/internal/audit"
 	"HireMeMaybe-backend/internal/auth"
 	"HireMeMaybe-backend/internal/controller/admin"
+	"HireMeMaybe-backend/internal/controller/appeal"
 	"HireMeMaybe-backend/internal/controller/application"
 	"HireMeMaybe-backend/internal/controller/company"
 	"HireMeMaybe-backend/internal/controller/cpsk"
 	"HireMeMaybe-backend/internal/controller/file"
 	"HireMeMaybe-backend/internal/controller/jobpost"
+	"HireMeMaybe-backend/internal/controller/organization"
 	"HireMeMaybe-backend/internal/controller/punishment"
 	"HireMeMaybe-backend/internal/controller/report"
+	"HireMeMaybe-backend/internal/controller/role"
 	"HireMeMaybe-backend/internal/controller/verification"
 
+	"HireMeMaybe-backend/internal/mail"
 	"HireMeMaybe-backend/internal/middleware"
 	"HireMeMaybe-backend/internal/model"
+	"HireMeMaybe-backend/internal/policy"
+	"context"
 	"net/http"
 	"os"
 	"strings"
@@ -48,6 +55,8 @@ func (s *MyServer) RegisterRoutes() http.Handler {
 			"https://www.googleapis.com/auth/userinfo.email",
 			"https://www.googleapis.com/auth/userinfo.profile",
 			"https://www.googleapis.com/auth/userinfo.openid",
+			// ID token carrying nonce of the sign in is only returned for openid scope
+			"openid",
 		},
 		Endpoint:    google.Endpoint,
 		RedirectURL: os.Getenv("OAUTH_REDIRECT_URL"),
@@ -55,56 +64,143 @@ func (s *MyServer) RegisterRoutes() http.Handler {
 
 	cloudStorageClient, err := file.NewCloudStorageClient(os.Getenv("CLOUD_STORAGE_BUCKET"))
 
-	blackListStore := auth.NewInMemoryBlacklistStore()
-
 	if err != nil {
 		panic("Failed to create cloud storage client: " + err.Error())
 	}
 
+	blackListStore, err := auth.NewBlacklistStoreFromEnv(s.DB)
+	if err != nil {
+		panic("Failed to create JWT blacklist store: " + err.Error())
+	}
+
+	rateLimiter, err := middleware.NewRateLimiterFromEnv()
+	if err != nil {
+		panic("Failed to create rate limiter: " + err.Error())
+	}
+
+	mailer, err := mail.NewSenderFromEnv()
+	if err != nil {
+		panic("Failed to create mail sender: " + err.Error())
+	}
+
+	oidcProviders, err := auth.NewOIDCRegistryFromEnv(context.Background())
+	if err != nil {
+		panic("Failed to load OIDC providers: " + err.Error())
+	}
+
+	tokenKeys, err := auth.LoadKeySetFromEnv()
+	if err != nil {
+		panic("Failed to load JWT signing keys: " + err.Error())
+	}
+	auth.UseKeySet(tokenKeys)
+	if err := auth.CheckOAuthStateKey(); err != nil {
+		panic("Failed to load OAuth state key: " + err.Error())
+	}
+
 	gAuth := auth.NewOauthLoginHandler(s.DB, googleOauth, "https://www.googleapis.com/oauth2/v3/userinfo")
 	lAuth := auth.NewLocalAuthHandler(s.DB)
-	logoutController := auth.NewLogoutController(blackListStore)
+	oidcAuth := auth.NewOIDCLoginHandler(s.DB, oidcProviders)
+	refreshController := auth.NewRefreshController(s.DB)
+	logoutController := auth.NewLogoutController(blackListStore, refreshController.Store)
+	passwordController := auth.NewPasswordController(s.DB, mailer)
+	emailController := auth.NewEmailVerificationController(s.DB, mailer)
 
 	fileController := file.NewFileController(s.DB, cloudStorageClient)
 	companyController := company.NewCompanyController(s.DB)
 	adminController := admin.NewAdminController(s.DB)
+	appealController := appeal.NewAppealController(s.DB)
 	applicationController := application.NewApplicationController(s.DB)
 	cpskController := cpsk.NewCPSKController(s.DB)
 	jobPostController := jobpost.NewJobPostController(s.DB)
+	organizationController := organization.NewOrganizationController(s.DB, mailer)
 	punishmentController := punishment.NewPunishmentController(s.DB)
-	reportController := report.NewReportController(s.DB)
+	reportController := report.NewReportController(s.DB, mailer)
+	roleController := role.NewRoleController(s.DB)
 	verificationController := verification.NewVerificationController(s.DB)
 
 	r.Use(cors.New(cors.Config{
 		AllowOrigins:     allowOrgins, // Add your frontend URL
 		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS", "PATCH"},
-		AllowHeaders:     []string{"Accept", "Authorization", "Content-Type"},
+		AllowHeaders:     []string{"Accept", "Authorization", "Content-Type", middleware.RequestIDHeader},
+		ExposeHeaders:    []string{middleware.RequestIDHeader},
 		AllowCredentials: true, // Enable cookies/auth
 	}))
 
 	r.Use(middleware.SafeHeader())
+	r.Use(middleware.RequestID())
 
 	r.GET("/", s.HelloWorldHandler)
 	r.GET("/health", s.healthHandler)
+	r.GET("/.well-known/jwks.json", tokenKeys.JWKSHandler)
+	ipLimit := rateLimiter.Middleware(middleware.RateLimitPolicyIP)
+
 	v1 := r.Group("/api/v1")
-	// Apply rate limiting only to API routes (exclude Swagger and other non-API endpoints)
-	v1.Use(middleware.EnvRateLimitMiddleware())
 	{
 		authRoute := v1.Group("/auth")
 		{
-			authRoute.POST("google/cpsk", gAuth.CPSKGoogleLoginHandler)
-			authRoute.POST("google/company", gAuth.CompanyGoogleLoginHandler)
-			authRoute.POST("google/visitor", gAuth.VisitorGoogleLoginHandler)
-			authRoute.GET("google/callback", gAuth.Callback)
-
-			authRoute.POST("login", lAuth.LocalLoginHandler)
-			authRoute.POST("register", lAuth.LocalRegisterHandler)
-			authRoute.POST("logout", middleware.JwtBlacklistCheck(blackListStore), middleware.RequireAuth(s.DB), logoutController.LogoutHandler)
+			// Strict per IP limit for endpoints accepting password or one-time code
+			credentialLimit := rateLimiter.Middleware(middleware.RateLimitPolicyAuth)
+			authRoute.POST("login", credentialLimit, lAuth.LocalLoginHandler)
+			authRoute.POST("register", credentialLimit, lAuth.LocalRegisterHandler)
+			authRoute.POST("password/forgot", credentialLimit, passwordController.ForgotPasswordHandler)
+			authRoute.POST("password/reset", credentialLimit, passwordController.ResetPasswordHandler)
+			authRoute.POST("2fa/verify", credentialLimit, lAuth.VerifyTwoFactorHandler)
+
+			defaultLimit := rateLimiter.Middleware(middleware.RateLimitPolicyDefault)
+			publicAuth := authRoute.Group("", defaultLimit)
+			{
+				publicAuth.POST("google/cpsk", gAuth.CPSKGoogleLoginHandler)
+				publicAuth.POST("google/company", gAuth.CompanyGoogleLoginHandler)
+				publicAuth.POST("google/visitor", gAuth.VisitorGoogleLoginHandler)
+				publicAuth.GET("google/start", gAuth.StartHandler)
+				publicAuth.GET("google/callback", gAuth.Callback)
+				publicAuth.GET("oidc/providers", oidcAuth.ListProvidersHandler)
+				publicAuth.GET("oidc/:provider/:role/start", oidcAuth.StartHandler)
+				publicAuth.POST("oidc/:provider/:role", oidcAuth.LoginHandler)
+				publicAuth.POST("refresh", refreshController.RefreshHandler)
+				publicAuth.POST("email/verify", emailController.VerifyEmailHandler)
+			}
+
+			// Per IP limit before token check, per user limit placed after RequireAuth
+			userAuth := authRoute.Group("", ipLimit, middleware.JwtBlacklistCheck(blackListStore), middleware.RequireAuth(s.DB), defaultLimit)
+			{
+				userAuth.POST("password/change", passwordController.ChangePasswordHandler)
+				userAuth.POST("password/set", passwordController.SetPasswordHandler)
+				userAuth.POST("email", emailController.RequestEmailVerificationHandler)
+				userAuth.POST("link/google", gAuth.LinkGoogleHandler)
+				userAuth.DELETE("link/google", gAuth.UnlinkGoogleHandler)
+				userAuth.POST("2fa/enroll", lAuth.EnrollTwoFactorHandler)
+				userAuth.POST("2fa/confirm", lAuth.ConfirmTwoFactorHandler)
+				userAuth.POST("2fa/disable", lAuth.DisableTwoFactorHandler)
+				userAuth.POST("logout", logoutController.LogoutHandler)
+				userAuth.POST("logout/all", logoutController.LogoutAllHandler)
+			}
+		}
+		// Punished user can still appeal, so ban is not checked here
+		appealRoute := v1.Group("/appeal")
+		{
+			appealRoute.Use(
+				ipLimit,
+				middleware.JwtBlacklistCheck(blackListStore),
+				middleware.RequireAuth(s.DB),
+				rateLimiter.Middleware(middleware.RateLimitPolicyDefault),
+			)
+			appealRoute.POST("", appealController.SubmitAppeal)
+			appealRoute.GET("", appealController.GetMyAppeals)
 		}
+
 		// Any routes
 		needAuth := v1.Group("")
 		{
-			needAuth.Use(middleware.JwtBlacklistCheck(blackListStore), middleware.RequireAuth(s.DB), middleware.CheckPunishment(s.DB, model.BanPunishment))
+			needAuth.Use(
+				// Per IP limit before token check, so requests with invalid token are limited too
+				ipLimit,
+				middleware.JwtBlacklistCheck(blackListStore),
+				middleware.RequireAuth(s.DB),
+				// Per user limit, placed after RequireAuth so role limits apply
+				rateLimiter.Middleware(middleware.RateLimitPolicyDefault),
+				middleware.CheckPunishment(s.DB, model.BanPunishment),
+			)
 			fileRoute := needAuth.Group("/file")
 			{
 				fileRoute.GET(":id", fileController.GetFile)
@@ -113,20 +209,36 @@ func (s *MyServer) RegisterRoutes() http.Handler {
 			companyRoute := needAuth.Group("/company")
 			{
 				companyRoute.GET(":company_id", companyController.GetCompanyByID) // New route: same handler, different path
-				companyRoute.Use(middleware.CheckRole(model.RoleCompany))
-				companyRoute.PATCH("profile", companyController.EditCompanyProfile)
-				companyRoute.POST("profile/logo", middleware.SizeLimit(10<<20), fileController.UploadLogo)
-				companyRoute.POST("profile/banner", middleware.SizeLimit(10<<20), fileController.UploadBanner)
+				companyRoute.Use(middleware.RequirePermission(s.DB, policy.CompanyProfile))
+				companyRoute.PATCH("profile", middleware.CheckOrganizationPunishment(s.DB), companyController.EditCompanyProfile)
+				companyRoute.POST("profile/logo", middleware.CheckOrganizationPunishment(s.DB), middleware.SizeLimit(10<<20), fileController.UploadLogo)
+				companyRoute.POST("profile/banner", middleware.CheckOrganizationPunishment(s.DB), middleware.SizeLimit(10<<20), fileController.UploadBanner)
 				companyRoute.GET("myprofile", companyController.GetMyCompanyProfile)
 				companyRoute.POST("ai-verify", verificationController.AIVerifyCompany)
 			}
 
+			// Membership is checked by the handler
+			organizationRoute := needAuth.Group("/organization")
+			{
+				organizationRoute.GET("", organizationController.GetMyOrganization)
+				organizationRoute.POST("invitations/accept", organizationController.AcceptInvitation)
+				organizationRoute.DELETE("members/:user_id", organizationController.RemoveMember)
+				organizationRoute.Use(middleware.RequirePermission(s.DB, policy.OrganizationManage))
+				organizationRoute.GET("invitations", organizationController.GetInvitations)
+				organizationRoute.POST("invitations", organizationController.InviteMember)
+				organizationRoute.DELETE("invitations/:id", organizationController.RevokeInvitation)
+				organizationRoute.PATCH("members/:user_id", organizationController.UpdateMemberRole)
+			}
+
 			// Job post endpoints (company only)
 			jobPostRoute := needAuth.Group("/jobpost")
 			{
+				jobPostRoute.GET("/search", jobPostController.SearchPosts)
 				jobPostRoute.GET("/:id", jobPostController.GetPostByID)
 				jobPostRoute.GET("", jobPostController.GetPosts)
-				jobPostRoute.Use(middleware.CheckRole(model.RoleCompany), middleware.CheckPunishment(s.DB, model.SuspendPunishment))
+				jobPostRoute.GET("/:id/applications", middleware.RequirePermission(s.DB, policy.ApplicationViewOwn, policy.ApplicationViewAny), applicationController.GetPostApplications)
+				jobPostRoute.GET("/:id/applications/export", middleware.RequirePermission(s.DB, policy.ApplicationViewOwn, policy.ApplicationViewAny), applicationController.ExportPostAnswers)
+				jobPostRoute.Use(middleware.RequirePermission(s.DB, policy.JobPostCreate), middleware.CheckPunishment(s.DB, model.SuspendPunishment), middleware.CheckOrganizationPunishment(s.DB))
 				jobPostRoute.POST("", jobPostController.CreateJobPostHandler)
 
 			}
@@ -134,43 +246,69 @@ func (s *MyServer) RegisterRoutes() http.Handler {
 			// Reporting endpoints
 			reportRoute := needAuth.Group("/report")
 			{
-				reportRoute.PUT("/:type/:id", middleware.CheckRole(model.RoleAdmin), reportController.UpdateReportStatus)
-				reportRoute.GET("", middleware.CheckRole(model.RoleAdmin), reportController.GetReport)
+				reportRoute.PUT("/:type/:id", middleware.RequirePermission(s.DB, policy.ReportResolve), middleware.Audit(s.DB, audit.ActionUpdateReportStatus, audit.ReportTarget), reportController.UpdateReportStatus)
+				reportRoute.GET("", middleware.RequirePermission(s.DB, policy.ReportView), reportController.GetReport)
+				reportRoute.GET("/cases", middleware.RequirePermission(s.DB, policy.ReportView), reportController.GetReportCases)
+				reportRoute.GET("/cases/:type/:target_id", middleware.RequirePermission(s.DB, policy.ReportView), reportController.GetReportCase)
+				reportRoute.PUT("/cases/:type/:target_id", middleware.RequirePermission(s.DB, policy.ReportResolve), middleware.Audit(s.DB, audit.ActionResolveReportCase, audit.ReportCaseTarget), reportController.ResolveReportCase)
+				reportRoute.POST("/cases/:type/:target_id/claim", middleware.RequirePermission(s.DB, policy.ReportResolve), reportController.ClaimReportCase)
+				reportRoute.DELETE("/cases/:type/:target_id/claim", middleware.RequirePermission(s.DB, policy.ReportResolve), reportController.ReleaseReportCase)
 				reportRoute.POST("/user", reportController.CreateUserReport)
-				reportRoute.POST("/post", middleware.CheckRole(model.RoleCPSK, model.RoleVisitor), reportController.CreatePostReport)
+				reportRoute.POST("/post", middleware.RequirePermission(s.DB, policy.ReportCreatePost), reportController.CreatePostReport)
 			}
 
-			needCompanyAdmin := needAuth.Group("")
+			applicationRoute := needAuth.Group("/application")
 			{
-				needCompanyAdmin.Use(middleware.CheckRole(model.RoleAdmin, model.RoleCompany))
-				needCompanyAdmin.PATCH("jobpost/:id", jobPostController.EditJobPost)
-				needCompanyAdmin.DELETE("jobpost/:id", jobPostController.DeleteJobPost)
+				applicationRoute.PATCH(":id/status", middleware.RequirePermission(s.DB, policy.ApplicationReviewOwn, policy.ApplicationReviewAny), middleware.CheckPunishment(s.DB, model.SuspendPunishment), middleware.CheckOrganizationPunishment(s.DB), applicationController.UpdateApplicationStatus)
 			}
 
-			needAdmin := needAuth.Group("")
+			// Ownership of the post is checked by the handler, only changes by other than the owner are audited.
+			// Members can't change posts of banned or suspended company
+			needAuth.PATCH("jobpost/:id", middleware.RequirePermission(s.DB, policy.JobPostEditOwn, policy.JobPostEditAny), middleware.CheckOrganizationPunishment(s.DB), middleware.Audit(s.DB, audit.ActionEditJobPost, audit.JobPostTarget), jobPostController.EditJobPost)
+			needAuth.PUT("jobpost/:id/form", middleware.RequirePermission(s.DB, policy.JobPostEditOwn, policy.JobPostEditAny), middleware.CheckOrganizationPunishment(s.DB), middleware.Audit(s.DB, audit.ActionEditJobPostForm, audit.JobPostTarget), jobPostController.PutJobPostForm)
+			needAuth.PATCH("jobpost/:id/status", middleware.RequirePermission(s.DB, policy.JobPostEditOwn, policy.JobPostEditAny), middleware.CheckOrganizationPunishment(s.DB), middleware.Audit(s.DB, audit.ActionUpdateJobPostStatus, audit.JobPostTarget), jobPostController.UpdateJobPostStatus)
+			needAuth.DELETE("jobpost/:id", middleware.RequirePermission(s.DB, policy.JobPostDeleteOwn, policy.JobPostDeleteAny), middleware.Audit(s.DB, audit.ActionDeleteJobPost, audit.JobPostTarget), jobPostController.DeleteJobPost)
+
+			needAuth.GET("get-companies", middleware.RequirePermission(s.DB, policy.UserList), adminController.GetCompanies)
+			needAuth.GET("get-cpsk", middleware.RequirePermission(s.DB, policy.UserList), adminController.GetCPSK)
+			needAuth.GET("get-visitors", middleware.RequirePermission(s.DB, policy.UserList), adminController.GetVisitors)
+			needAuth.PATCH("verify-company/:company_id", middleware.RequirePermission(s.DB, policy.CompanyVerify), middleware.Audit(s.DB, audit.ActionVerifyCompany, audit.CompanyTarget), adminController.VerifyCompany)
+			needAuth.GET("punish/:user_id", middleware.RequirePermission(s.DB, policy.UserList, policy.UserPunish), punishmentController.GetPunishmentHistory)
+			needAuth.PUT("punish/:user_id", middleware.RequirePermission(s.DB, policy.UserPunish), middleware.Audit(s.DB, audit.ActionPunishUser, audit.UserTarget), punishmentController.PunishUser)
+			needAuth.DELETE("punish/:user_id", middleware.RequirePermission(s.DB, policy.UserPunish), middleware.Audit(s.DB, audit.ActionUnpunishUser, audit.UserTarget), punishmentController.DeletePunishmentRecord)
+			needAuth.GET("admin/appeals", middleware.RequirePermission(s.DB, policy.UserPunish), appealController.GetAppeals)
+			needAuth.PATCH("admin/appeals/:id", middleware.RequirePermission(s.DB, policy.UserPunish), middleware.Audit(s.DB, audit.ActionReviewAppeal, audit.AppealTarget), appealController.ReviewAppeal)
+			needAuth.GET("login-lockouts", middleware.RequirePermission(s.DB, policy.LoginUnlock), adminController.GetLoginLockouts)
+			needAuth.DELETE("login-lockouts", middleware.RequirePermission(s.DB, policy.LoginUnlock), adminController.UnlockLogin)
+			needAuth.GET("admin/audit", middleware.RequirePermission(s.DB, policy.AuditView), adminController.GetAuditLogs)
+
+			roleRoute := needAuth.Group("/roles")
 			{
-				needAdmin.Use(middleware.CheckRole(model.RoleAdmin))
-				needAdmin.GET("get-companies", adminController.GetCompanies)
-				needAdmin.GET("get-cpsk", adminController.GetCPSK)
-				needAdmin.GET("get-visitors", adminController.GetVisitors)
-				needAdmin.PATCH("verify-company/:company_id", adminController.VerifyCompany)
-				needAdmin.PUT("punish/:user_id", punishmentController.PunishUser)
-				needAdmin.DELETE("punish/:user_id", punishmentController.DeletePunishmentRecord)
+				roleRoute.Use(middleware.RequirePermission(s.DB, policy.RoleManage))
+				roleRoute.GET("", roleController.GetRoles)
+				roleRoute.PUT("/:name", roleController.PutRole)
+				roleRoute.DELETE("/:name", roleController.DeleteRole)
+				roleRoute.PUT("/:name/users/:user_id", roleController.AssignRole)
+				roleRoute.DELETE("/:name/users/:user_id", roleController.UnassignRole)
 			}
 
-			// CPSK routes: apply role check once for all CPSK endpoints
+			// CPSK routes: apply permission check once for all CPSK endpoints
 			needCPSK := needAuth.Group("")
 			{
-				needCPSK.Use(middleware.CheckRole(model.RoleCPSK))
+				needCPSK.Use(middleware.RequirePermission(s.DB, policy.CPSKProfile))
 				cpskRoute := needCPSK.Group("/cpsk")
 				{
 					cpskRoute.PATCH("profile", cpskController.EditCPSKProfile)
 					cpskRoute.GET("myprofile", cpskController.GetMyCPSKProfile)
 					cpskRoute.POST("profile/resume", middleware.SizeLimit(10<<20), fileController.UploadResume)
+					cpskRoute.GET("applications", applicationController.GetMyApplications)
+					cpskRoute.GET("applications/:id", applicationController.GetMyApplicationByID)
+					cpskRoute.POST("applications/:id/withdraw", applicationController.WithdrawApplication)
 				}
 
 				needCPSK.Use(middleware.CheckPunishment(s.DB, model.SuspendPunishment))
 				needCPSK.POST("application", applicationController.ApplicationHandler)
+				needCPSK.POST("application/files", middleware.SizeLimit(10<<20), fileController.UploadAnswerFile)
 			}
 
 		}
@@ -11,6 +11,9 @@ import (
 	"HireMeMaybe-backend/internal/database"
 )
 
+// expiredPostCloseInterval is how often published job posts that have expired are closed
+const expiredPostCloseInterval = time.Minute
+
 // MyServer is a struct that holds the server configuration and dependencies.
 type MyServer struct {
 	DB   *database.DBinstanceStruct
@@ -27,6 +30,8 @@ func NewServer() *http.Server {
 		log.Fatalf("Database failed to initialized: %s", err)
 	}
 
+	go db.CloseExpiredPostsEvery(expiredPostCloseInterval)
+
 	// Declare Server config
 	myServer := &MyServer{
 		DB:   db,
@@ -0,0 +1,52 @@
+package utilities
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultPageLimit = 20
+	maxPageLimit     = 100
+)
+
+// Pagination holds paging information parsed from "page" and "limit" query
+// and the total number of records matched by the query.
+type Pagination struct {
+	Page  int   `json:"page"`
+	Limit int   `json:"limit"`
+	Total int64 `json:"total"`
+}
+
+// ParsePagination reads "page" (default 1) and "limit" (default 20, max 100) from query string.
+func ParsePagination(c *gin.Context) (Pagination, error) {
+	p := Pagination{Page: 1, Limit: defaultPageLimit}
+
+	if rawPage := c.Query("page"); rawPage != "" {
+		page, err := strconv.Atoi(rawPage)
+		if err != nil || page < 1 {
+			return p, fmt.Errorf("page must be a positive integer")
+		}
+		p.Page = page
+	}
+
+	if rawLimit := c.Query("limit"); rawLimit != "" {
+		limit, err := strconv.Atoi(rawLimit)
+		if err != nil || limit < 1 {
+			return p, fmt.Errorf("limit must be a positive integer")
+		}
+		if limit > maxPageLimit {
+			limit = maxPageLimit
+		}
+		p.Limit = limit
+	}
+
+	return p, nil
+}
+
+// Offset returns number of records to skip for current page.
+func (p Pagination) Offset() int {
+	return (p.Page - 1) * p.Limit
+}
@@ -10,13 +10,14 @@ import (
 )
 
 // SimulateAPICall is a helper function to simulate an API call to a gin handler function.
-// It takes the handler function, route, HTTP method, and request body as parameters.
+// It takes the handler function, route, HTTP method, request body and cookies to send as parameters.
 // It returns the HTTP response recorder, parsed JSON response as a map, and any error encountered.
 func SimulateAPICall(
 	handlerFunc func(*gin.Context),
 	route string,
 	method string,
 	body interface{},
+	cookies ...*http.Cookie,
 ) (*httptest.ResponseRecorder, map[string]interface{}, error) {
 	b, err := json.Marshal(body)
 	if err != nil {
@@ -29,6 +30,9 @@ func SimulateAPICall(
 		return nil, nil, err
 	}
 	req.Header.Set("Content-Type", "application/json")
+	for _, cookie := range cookies {
+		req.AddCookie(cookie)
+	}
 	c.Request = req
 	handlerFunc(c)
 